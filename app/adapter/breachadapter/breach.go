@@ -0,0 +1,23 @@
+package breachadapter
+
+import (
+	"os"
+
+	"github.com/Acova/movie-collection/app/port"
+)
+
+// NewBreachedPasswordsFromEnv reads the breached passwords from the dataset in
+// BREACHED_PASSWORDS_DIR. Passwords are not checked against breaches when it
+// is not set.
+func NewBreachedPasswordsFromEnv() (port.BreachedPasswordRepository, error) {
+	dir := os.Getenv("BREACHED_PASSWORDS_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	dataset, err := NewDirectoryDataset(dir)
+	if err != nil {
+		return nil, err
+	}
+	return dataset, nil
+}
@@ -0,0 +1,77 @@
+package breachadapter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	hashPrefixPattern = regexp.MustCompile(`^[0-9A-F]{5}$`)
+	hashSuffixPattern = regexp.MustCompile(`^[0-9A-F]{35}$`)
+)
+
+// DirectoryDataset reads the SHA-1 hashes of breached passwords from a
+// directory with a file per hash prefix, as the Pwned Passwords downloader can
+// save them: <PREFIX>.txt, with a SUFFIX:COUNT line for each hash. Prefixes
+// without a file have no breached hashes, so that a partial dataset can be
+// used.
+type DirectoryDataset struct {
+	dir string
+}
+
+func NewDirectoryDataset(dir string) (*DirectoryDataset, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	return &DirectoryDataset{dir: dir}, nil
+}
+
+func (d *DirectoryDataset) GetBreachedHashSuffixes(prefix string) (map[string]int, error) {
+	prefix = strings.ToUpper(prefix)
+	if !hashPrefixPattern.MatchString(prefix) {
+		return nil, fmt.Errorf("invalid hash prefix %q", prefix)
+	}
+
+	path := filepath.Join(d.dir, prefix+".txt")
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	suffixes := map[string]int{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		suffix, countText, found := strings.Cut(line, ":")
+		suffix = strings.ToUpper(suffix)
+		count, err := strconv.Atoi(countText)
+		if !found || !hashSuffixPattern.MatchString(suffix) || err != nil || count < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid line %q", path, lineNumber, line)
+		}
+		suffixes[suffix] = count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return suffixes, nil
+}
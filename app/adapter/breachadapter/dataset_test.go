@@ -0,0 +1,74 @@
+package breachadapter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectoryDatasetGetBreachedHashSuffixes(t *testing.T) {
+	dir := t.TempDir()
+	content := "1E4C9B93F3F0682250B6CF8331B7EE68FD8:10434004\r\n" +
+		"1e4c9b93f3f0682250b6cf8331b7ee68fd9:3\r\n" +
+		"\r\n" +
+		"00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n"
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(content), 0o600); err != nil {
+		t.Fatalf("Could not write the dataset: %v", err)
+	}
+
+	dataset, err := NewDirectoryDataset(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	suffixes, err := dataset.GetBreachedHashSuffixes("5baa6")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := map[string]int{
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD8": 10434004,
+		"1E4C9B93F3F0682250B6CF8331B7EE68FD9": 3,
+		"00D4F6E8FA6EECAD2A3AA415EEC418D38EC": 0,
+	}
+	if len(suffixes) != len(expected) {
+		t.Fatalf("Expected %d suffixes, got %v", len(expected), suffixes)
+	}
+	for suffix, count := range expected {
+		if suffixes[suffix] != count {
+			t.Errorf("Expected %s to be seen %d times, got %d", suffix, count, suffixes[suffix])
+		}
+	}
+
+	// Prefixes without a file have no breached hashes
+	suffixes, err = dataset.GetBreachedHashSuffixes("FFFFF")
+	if err != nil || len(suffixes) != 0 {
+		t.Errorf("Expected no suffixes for a missing prefix, got %v and %v", suffixes, err)
+	}
+}
+
+func TestDirectoryDatasetRejectsInvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte("not a hash:1\n"), 0o600); err != nil {
+		t.Fatalf("Could not write the dataset: %v", err)
+	}
+	dataset, err := NewDirectoryDataset(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, prefix := range []string{"", "5BAA", "5BAA61", "../00", "GGGGG"} {
+		if _, err := dataset.GetBreachedHashSuffixes(prefix); err == nil {
+			t.Errorf("Expected an error for prefix %q", prefix)
+		}
+	}
+	if _, err := dataset.GetBreachedHashSuffixes("5BAA6"); err == nil {
+		t.Errorf("Expected an error for an invalid line")
+	}
+
+	if _, err := NewDirectoryDataset(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+	if _, err := NewDirectoryDataset(filepath.Join(dir, "5BAA6.txt")); err == nil {
+		t.Errorf("Expected an error for a file")
+	}
+}
@@ -0,0 +1,180 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpAccessTokenRequest creates a personal access token. Tokens without
+// ExpiresInDays do not expire.
+type HttpAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1,max=6,dive,oneof=movies:read movies:write lists:read lists:write library:read library:write"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// HttpAccessToken is a personal access token of the logged in user. Token is
+// only given when the token is created, as it cannot be shown again.
+type HttpAccessToken struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+func AccessTokenFromDomain(accessToken *domain.PersonalAccessToken) *HttpAccessToken {
+	httpAccessToken := &HttpAccessToken{
+		ID:        accessToken.ID,
+		Name:      accessToken.Name,
+		Scopes:    make([]string, len(accessToken.Scopes)),
+		CreatedAt: accessToken.CreatedAt,
+	}
+	for i, scope := range accessToken.Scopes {
+		httpAccessToken.Scopes[i] = string(scope)
+	}
+	if !accessToken.LastUsedAt.IsZero() {
+		httpAccessToken.LastUsedAt = &accessToken.LastUsedAt
+	}
+	if !accessToken.ExpiresAt.IsZero() {
+		httpAccessToken.ExpiresAt = &accessToken.ExpiresAt
+	}
+	return httpAccessToken
+}
+
+type HttpAccessTokenAdapter struct {
+	accessTokenService port.AccessTokenService
+}
+
+func NewHttpAccessTokenAdapter(accessTokenService port.AccessTokenService) *HttpAccessTokenAdapter {
+	return &HttpAccessTokenAdapter{
+		accessTokenService: accessTokenService,
+	}
+}
+
+// @Summary Create a personal access token
+// @Description Create a token for scripts and integrations to act as the logged in user, limited to the given scopes. The token is only shown in this response.
+// @Tags Access tokens
+// @Accept json
+// @Produce json
+// @Param token body HttpAccessTokenRequest true "Name, scopes and lifetime of the token"
+// @Success 201 {object} HttpAccessToken
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/tokens [post]
+// @Security ApiKeyAuth
+func (a *HttpAccessTokenAdapter) CreateAccessToken(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	request := HttpAccessTokenRequest{}
+	if err := context.BindJSON(&request); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopes := make([]domain.Scope, len(request.Scopes))
+	for i, scope := range request.Scopes {
+		scopes[i] = domain.Scope(scope)
+	}
+	expiresAt := time.Time{}
+	if request.ExpiresInDays > 0 {
+		expiresAt = time.Now().AddDate(0, 0, request.ExpiresInDays)
+	}
+
+	accessToken, token, err := a.accessTokenService.CreateAccessToken(user.ID, request.Name, scopes, expiresAt)
+	if errors.Is(err, domain.ErrInvalidScope) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpAccessToken := AccessTokenFromDomain(accessToken)
+	httpAccessToken.Token = token
+	context.IndentedJSON(http.StatusCreated, httpAccessToken)
+}
+
+// @Summary List the personal access tokens of the logged in user
+// @Description List the personal access tokens of the logged in user which have not been revoked, newest first. The tokens themselves are not shown.
+// @Tags Access tokens
+// @Accept json
+// @Produce json
+// @Success 200 {array} HttpAccessToken
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/tokens [get]
+// @Security ApiKeyAuth
+func (a *HttpAccessTokenAdapter) ListAccessTokens(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	accessTokens, err := a.accessTokenService.ListAccessTokens(user.ID)
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	httpAccessTokens := make([]*HttpAccessToken, len(accessTokens))
+	for i, accessToken := range accessTokens {
+		httpAccessTokens[i] = AccessTokenFromDomain(accessToken)
+	}
+
+	context.IndentedJSON(http.StatusOK, httpAccessTokens)
+}
+
+// @Summary Revoke a personal access token of the logged in user
+// @Description Revoke a personal access token of the logged in user, so that it cannot be used anymore
+// @Tags Access tokens
+// @Accept json
+// @Produce json
+// @Param tokenId path int true "Personal access token ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/tokens/{tokenId} [delete]
+// @Security ApiKeyAuth
+func (a *HttpAccessTokenAdapter) RevokeAccessToken(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	tokenID, err := strconv.ParseUint(context.Param("tokenId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = a.accessTokenService.RevokeAccessToken(user.ID, uint(tokenID))
+	if errors.Is(err, domain.ErrAccessTokenNotFound) {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Personal access token revoked"})
+}
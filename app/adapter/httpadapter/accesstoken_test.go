@@ -0,0 +1,119 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAccessTokenService := &mock.MockAccessTokenService{}
+	httpAdapter := NewHttpAccessTokenAdapter(mockAccessTokenService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"name": "backup script", "scopes": ["movies:read", "lists:read"], "expires_in_days": 30}`, http.StatusCreated},
+		{`{"name": "backup script", "scopes": ["movies:read"]}`, http.StatusCreated},
+		{`{"name": "backup script", "scopes": []}`, http.StatusBadRequest},
+		{`{"name": "backup script", "scopes": ["users:write"]}`, http.StatusBadRequest},
+		{`{"scopes": ["movies:read"]}`, http.StatusBadRequest},
+		{`{"name": "backup script", "scopes": ["movies:read"], "expires_in_days": 0.5}`, http.StatusBadRequest},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/tokens", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.CreateAccessToken(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+			continue
+		}
+		if testCase.expectedCode != http.StatusCreated {
+			continue
+		}
+
+		accessToken := &HttpAccessToken{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), accessToken); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if accessToken.Token == "" || accessToken.Name != "backup script" || len(accessToken.Scopes) == 0 {
+			t.Errorf("Expected the created token along with its name and scopes, got %+v", accessToken)
+		}
+	}
+
+	if mockAccessTokenService.AccessTokens[0].ExpiresAt.IsZero() || !mockAccessTokenService.AccessTokens[1].ExpiresAt.IsZero() {
+		t.Errorf("Expected only the first token to expire")
+	}
+}
+
+func TestListAccessTokensHidesTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAccessTokenService := &mock.MockAccessTokenService{}
+	mockAccessTokenService.CreateAccessToken(1, "first", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	mockAccessTokenService.CreateAccessToken(2, "other", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	httpAdapter := NewHttpAccessTokenAdapter(mockAccessTokenService)
+
+	request, _ := http.NewRequest("GET", "/user/me/tokens", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.ListAccessTokens(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+	accessTokens := []*HttpAccessToken{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &accessTokens); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(accessTokens) != 1 || accessTokens[0].Name != "first" || accessTokens[0].Token != "" {
+		t.Errorf("Expected only the first token, without the token itself, got %+v", accessTokens)
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockAccessTokenService := &mock.MockAccessTokenService{}
+	mockAccessTokenService.CreateAccessToken(1, "first", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	mockAccessTokenService.CreateAccessToken(2, "other", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	httpAdapter := NewHttpAccessTokenAdapter(mockAccessTokenService)
+
+	for _, testCase := range []struct {
+		tokenID      string
+		expectedCode int
+	}{
+		{"abc", http.StatusBadRequest},
+		{"2", http.StatusNotFound},
+		{"3", http.StatusNotFound},
+		{"1", http.StatusOK},
+		{"1", http.StatusNotFound},
+	} {
+		request, _ := http.NewRequest("DELETE", "/user/me/tokens/"+testCase.tokenID, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{{Key: "tokenId", Value: testCase.tokenID}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.RevokeAccessToken(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for token %s, but got %d", testCase.expectedCode, testCase.tokenID, mockResponseWriter.Code)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// tokenIDBytes is the amount of randomness in the ID of a token.
+const tokenIDBytes = 16
+
+type HttpRefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// HttpTwoFactorLoginRequest completes a login with the challenge token given by
+// /login and a TOTP or recovery code.
+type HttpTwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// HttpTokenResponse is a new access token, along with the refresh token to get
+// the next one with.
+type HttpTokenResponse struct {
+	Token        string `json:"token"`
+	Expire       string `json:"expire"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type HttpAuthAdapter struct {
+	jwtMiddleware          *jwt.GinJWTMiddleware
+	userService            port.UserService
+	tokenRevocationService port.TokenRevocationService
+	sessionService         port.SessionService
+	twoFactorService       port.TwoFactorService
+	loginThrottleService   port.LoginThrottleService
+	oidcService            port.OIDCService
+	// tokenLifetime is how long after it is first issued a token, refreshed
+	// as often as possible, can be used for.
+	tokenLifetime time.Duration
+}
+
+func NewHttpAuthAdapter(jwtMiddleware *jwt.GinJWTMiddleware, userService port.UserService, tokenRevocationService port.TokenRevocationService, sessionService port.SessionService, twoFactorService port.TwoFactorService, loginThrottleService port.LoginThrottleService, oidcService port.OIDCService) *HttpAuthAdapter {
+	return &HttpAuthAdapter{
+		jwtMiddleware:          jwtMiddleware,
+		userService:            userService,
+		tokenRevocationService: tokenRevocationService,
+		sessionService:         sessionService,
+		twoFactorService:       twoFactorService,
+		loginThrottleService:   loginThrottleService,
+		oidcService:            oidcService,
+		tokenLifetime:          jwtMiddleware.MaxRefresh + jwtMiddleware.Timeout,
+	}
+}
+
+// @Summary Refresh a session
+// @Description Exchange the refresh token given at login, or by the previous refresh, for a new access token and the next refresh token. Each refresh token can only be used once: using one again revokes its whole session. No authentication is needed.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body HttpRefreshRequest true "Refresh token"
+// @Success 200 {object} HttpTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /token/refresh [post]
+func (a *HttpAuthAdapter) RefreshSession(context *gin.Context) {
+	refresh := HttpRefreshRequest{}
+	if err := context.BindJSON(&refresh); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, refreshToken, err := a.sessionService.RefreshSession(refresh.RefreshToken, context.Request.UserAgent(), context.ClientIP())
+	if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.userService.GetUser(session.UserID)
+	if err != nil {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": domain.ErrInvalidRefreshToken.Error()})
+		return
+	}
+
+	a.respondWithToken(context, user, session, refreshToken)
+}
+
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token given by /login to users with two-factor authentication, along with a code of their authenticator app or one of their recovery codes, for an access token and a refresh token. Challenges expire after 5 minutes or 5 wrong codes. Wrong codes count as failed logins for the email of the challenge, which is throttled like /login. No authentication is needed.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param login body HttpTwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} HttpTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /login/2fa [post]
+func (a *HttpAuthAdapter) CompleteTwoFactorLogin(context *gin.Context) {
+	login := HttpTwoFactorLoginRequest{}
+	if err := context.BindJSON(&login); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	challengeUser, err := a.twoFactorService.ChallengeUser(login.ChallengeToken)
+	if errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Codes are guessed against the email of the challenge, so they are
+	// throttled along with its passwords
+	ipAddress := context.ClientIP()
+	userAgent := context.Request.UserAgent()
+	retryAt, err := a.loginThrottleService.CheckLogin(challengeUser.Email, ipAddress, userAgent)
+	if errors.Is(err, domain.ErrTooManyLoginAttempts) {
+		setRetryAfter(context, retryAt)
+		context.IndentedJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.twoFactorService.VerifyChallenge(login.ChallengeToken, login.Code)
+	if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		if err := a.loginThrottleService.RecordFailure(challengeUser.Email, ipAddress, userAgent, domain.LoginFailureInvalidTwoFactorCode); err != nil {
+			context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidTwoFactorChallenge) || errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := a.loginThrottleService.RecordSuccess(user.Email); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	session, refreshToken, err := a.sessionService.CreateSession(user.ID, context.Request.UserAgent(), context.ClientIP())
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.respondWithToken(context, user, session, refreshToken)
+}
+
+// respondWithToken issues an access token for a session of the user, and
+// responds with it along with the refresh token of the session.
+func (a *HttpAuthAdapter) respondWithToken(context *gin.Context, user *domain.User, session *domain.Session, refreshToken string) {
+	token, expire, err := a.jwtMiddleware.TokenGenerator(&sessionUser{User: user, SessionID: session.ID})
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &HttpTokenResponse{
+		Token:        token,
+		Expire:       expire.Format(time.RFC3339),
+		RefreshToken: refreshToken,
+	})
+}
+
+// @Summary Log out
+// @Description Revoke the token of the request, which can no longer be used nor refreshed, along with the session it was issued for. Other sessions of the user keep working.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /logout [post]
+// @Security ApiKeyAuth
+func (a *HttpAuthAdapter) Logout(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if sessionID := tokenSessionID(context); sessionID != 0 {
+		if err := a.sessionService.RevokeSession(user.ID, sessionID); err != nil {
+			context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	expiresAt := tokenIssuedAt(context).Add(a.tokenLifetime)
+	err := a.tokenRevocationService.RevokeToken(tokenID(context), expiresAt)
+	if errors.Is(err, domain.ErrTokenNotRevocable) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Logged out"})
+}
+
+// newTokenID returns a random ID for a new token, to revoke it with.
+func newTokenID() string {
+	// crypto/rand does not fail on the supported platforms
+	id, _ := util.GenerateToken(tokenIDBytes)
+	return id
+}
+
+// tokenID returns the ID of the token of the request. It is kept when the
+// token is refreshed, and is empty for tokens issued before tokens had an ID.
+func tokenID(c *gin.Context) string {
+	id, _ := jwt.ExtractClaims(c)["jti"].(string)
+	return id
+}
+
+// tokenSessionID returns the ID of the session the token of the request was
+// issued for, or 0 for tokens issued before sessions existed.
+func tokenSessionID(c *gin.Context) uint {
+	sessionID, _ := jwt.ExtractClaims(c)["sid"].(float64)
+	return uint(sessionID)
+}
@@ -0,0 +1,304 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// testAuthServer runs the login, OpenID Connect, refresh and logout routes,
+// along with a private route and a route personal access tokens can use,
+// against mock services.
+type testAuthServer struct {
+	t                      *testing.T
+	engine                 *gin.Engine
+	userService            *mock.MockUserService
+	tokenRevocationService *mock.MockTokenRevocationService
+	sessionService         *mock.MockSessionService
+	accessTokenService     *mock.MockAccessTokenService
+	twoFactorService       *mock.MockTwoFactorService
+	loginThrottleService   *mock.MockLoginThrottleService
+	oidcService            *mock.MockOIDCService
+}
+
+func newTestAuthServer(t *testing.T) *testAuthServer {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	server := &testAuthServer{
+		t: t,
+		userService: &mock.MockUserService{
+			Users: []*domain.User{{ID: 1, Email: "test@test.com", Name: "Test User", Password: "password", Role: domain.RoleUser}},
+		},
+		tokenRevocationService: &mock.MockTokenRevocationService{},
+		sessionService:         &mock.MockSessionService{},
+		loginThrottleService:   &mock.MockLoginThrottleService{},
+	}
+	server.accessTokenService = &mock.MockAccessTokenService{Users: server.userService.Users}
+	server.twoFactorService = &mock.MockTwoFactorService{Code: "123456", Users: server.userService.Users}
+	server.oidcService = &mock.MockOIDCService{Providers: []string{"company"}, Codes: map[string]uint{"code": 1}, Users: server.userService.Users}
+
+	jwtMiddleware, err := jwt.New(getJwtInitParams(server.userService, server.tokenRevocationService, server.sessionService, server.twoFactorService, server.loginThrottleService))
+	if err != nil {
+		t.Fatalf("Failed to create the JWT middleware: %v", err)
+	}
+	httpAuthAdapter := NewHttpAuthAdapter(jwtMiddleware, server.userService, server.tokenRevocationService, server.sessionService, server.twoFactorService, server.loginThrottleService, server.oidcService)
+
+	server.engine = gin.New()
+	server.engine.POST("/login", jwtMiddleware.LoginHandler)
+	server.engine.POST("/login/2fa", httpAuthAdapter.CompleteTwoFactorLogin)
+	server.engine.GET("/login/oidc", httpAuthAdapter.ListOIDCProviders)
+	server.engine.GET("/login/oidc/:provider", httpAuthAdapter.StartOIDCLogin)
+	server.engine.GET("/login/oidc/:provider/callback", httpAuthAdapter.CompleteOIDCLogin)
+	server.engine.POST("/token/refresh", httpAuthAdapter.RefreshSession)
+	server.engine.POST("/logout", jwtMiddleware.MiddlewareFunc(), httpAuthAdapter.Logout)
+	server.engine.GET("/private", jwtMiddleware.MiddlewareFunc(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	moviesRouterGroup := server.engine.Group("/movie", authenticate(jwtMiddleware, server.accessTokenService, domain.ScopeMoviesRead, domain.ScopeMoviesWrite))
+	moviesRouterGroup.Any("", func(c *gin.Context) {
+		if _, loggedIn := GetLoggedInUser(c); !loggedIn {
+			c.Status(http.StatusUnauthorized)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	accountRouterGroup := server.engine.Group("/user", authenticate(jwtMiddleware, server.accessTokenService, "", ""))
+	accountRouterGroup.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return server
+}
+
+func (s *testAuthServer) post(url string, body string) (int, *HttpTokenResponse) {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", url, bytes.NewBufferString(body))
+	s.engine.ServeHTTP(response, request)
+
+	tokens := &HttpTokenResponse{}
+	json.Unmarshal(response.Body.Bytes(), tokens)
+	return response.Code, tokens
+}
+
+func (s *testAuthServer) login() *HttpTokenResponse {
+	code, tokens := s.post("/login", `{"email": "test@test.com", "password": "password"}`)
+	if code != http.StatusOK || tokens.Token == "" || tokens.RefreshToken == "" {
+		s.t.Fatalf("Failed to log in, got status %d", code)
+	}
+	return tokens
+}
+
+func (s *testAuthServer) refresh(refreshToken string) (int, *HttpTokenResponse) {
+	return s.post("/token/refresh", `{"refresh_token": "`+refreshToken+`"}`)
+}
+
+func (s *testAuthServer) call(method string, url string, token string) int {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, url, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	s.engine.ServeHTTP(response, request)
+	return response.Code
+}
+
+func TestLogoutRevokesToken(t *testing.T) {
+	server := newTestAuthServer(t)
+	tokens := server.login()
+	otherTokens := server.login()
+
+	if code := server.call("GET", "/private?token="+tokens.Token, ""); code != http.StatusUnauthorized {
+		t.Errorf("Expected tokens in the query string to be ignored, but got status %d", code)
+	}
+	if code := server.call("GET", "/private", tokens.Token); code != http.StatusOK {
+		t.Fatalf("Expected the token to be accepted before logging out, but got status %d", code)
+	}
+	if code := server.call("POST", "/logout", tokens.Token); code != http.StatusOK {
+		t.Fatalf("Expected to log out, but got status %d", code)
+	}
+	if code := server.call("GET", "/private", tokens.Token); code != http.StatusForbidden {
+		t.Errorf("Expected the token to be rejected after logging out, but got status %d", code)
+	}
+	if code, _ := server.refresh(tokens.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("Expected the refresh token to be rejected after logging out, but got status %d", code)
+	}
+	if code := server.call("GET", "/private", otherTokens.Token); code != http.StatusOK {
+		t.Errorf("Expected other sessions to keep working, but got status %d", code)
+	}
+}
+
+func TestRefreshSession(t *testing.T) {
+	server := newTestAuthServer(t)
+	tokens := server.login()
+
+	code, refreshed := server.refresh(tokens.RefreshToken)
+	if code != http.StatusOK || refreshed.Token == "" || refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatalf("Expected a new access token and refresh token, but got status %d", code)
+	}
+	if code := server.call("GET", "/private", refreshed.Token); code != http.StatusOK {
+		t.Errorf("Expected the refreshed token to be accepted, but got status %d", code)
+	}
+
+	if code, _ := server.refresh(tokens.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("Expected a used refresh token to be rejected, but got status %d", code)
+	}
+	if code, _ := server.refresh("unknown-token"); code != http.StatusUnauthorized {
+		t.Errorf("Expected an unknown refresh token to be rejected, but got status %d", code)
+	}
+	if code, _ := server.post("/token/refresh", `{}`); code != http.StatusBadRequest {
+		t.Errorf("Expected a missing refresh token to be rejected, but got status %d", code)
+	}
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	server := newTestAuthServer(t)
+	server.twoFactorService.Enabled = map[uint]bool{1: true}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "test@test.com", "password": "password"}`))
+	server.engine.ServeHTTP(response, request)
+
+	challenge := map[string]interface{}{}
+	json.Unmarshal(response.Body.Bytes(), &challenge)
+	challengeToken, _ := challenge["challenge_token"].(string)
+	if response.Code != http.StatusOK || challenge["two_factor_required"] != true || challengeToken == "" {
+		t.Fatalf("Expected a challenge token, but got status %d and %v", response.Code, challenge)
+	}
+	if _, hasToken := challenge["token"]; hasToken || len(server.sessionService.Sessions) != 0 {
+		t.Errorf("Expected no token nor session before the challenge is completed")
+	}
+
+	if code, _ := server.post("/login/2fa", `{"challenge_token": "`+challengeToken+`", "code": "000000"}`); code != http.StatusUnauthorized {
+		t.Errorf("Expected a wrong code to be rejected, but got status %d", code)
+	}
+	if code, _ := server.post("/login/2fa", `{"challenge_token": "`+challengeToken+`"}`); code != http.StatusBadRequest {
+		t.Errorf("Expected a missing code to be rejected, but got status %d", code)
+	}
+
+	code, tokens := server.post("/login/2fa", `{"challenge_token": "`+challengeToken+`", "code": "123456"}`)
+	if code != http.StatusOK || tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Fatalf("Expected an access token and a refresh token, but got status %d", code)
+	}
+	if code := server.call("GET", "/private", tokens.Token); code != http.StatusOK {
+		t.Errorf("Expected the token to be accepted, but got status %d", code)
+	}
+
+	if code, _ := server.post("/login/2fa", `{"challenge_token": "`+challengeToken+`", "code": "123456"}`); code != http.StatusUnauthorized {
+		t.Errorf("Expected a used challenge to be rejected, but got status %d", code)
+	}
+}
+
+func TestTwoFactorLoginThrottling(t *testing.T) {
+	server := newTestAuthServer(t)
+	server.twoFactorService.Enabled = map[uint]bool{1: true}
+	server.loginThrottleService.LockoutFailures = 3
+
+	// Each login with the right password gives a new challenge, which must not
+	// start the count of wrong codes over
+	challengeToken := ""
+	for i := 1; i <= 3; i++ {
+		challengeToken = fmt.Sprintf("challenge-%d", i)
+		if code, _ := server.post("/login", `{"email": "test@test.com", "password": "password"}`); code != http.StatusOK {
+			t.Fatalf("Expected a challenge before the account is locked, but got status %d", code)
+		}
+		if code, _ := server.post("/login/2fa", `{"challenge_token": "`+challengeToken+`", "code": "000000"}`); code != http.StatusUnauthorized {
+			t.Fatalf("Expected a wrong code to be rejected, but got status %d", code)
+		}
+	}
+	if len(server.loginThrottleService.Failures) != 3 || server.loginThrottleService.Failures[0].Reason != domain.LoginFailureInvalidTwoFactorCode || server.loginThrottleService.Failures[0].Email != "test@test.com" {
+		t.Errorf("Expected the wrong codes to be recorded as failed logins, but got %v", server.loginThrottleService.Failures)
+	}
+
+	if code, _ := server.post("/login", `{"email": "test@test.com", "password": "password"}`); code != http.StatusTooManyRequests {
+		t.Errorf("Expected logins to be locked out after too many wrong codes, but got status %d", code)
+	}
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/login/2fa", bytes.NewBufferString(`{"challenge_token": "`+challengeToken+`", "code": "123456"}`))
+	server.engine.ServeHTTP(response, request)
+	if response.Code != http.StatusTooManyRequests || response.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected pending challenges to be locked out too, but got status %d", response.Code)
+	}
+}
+
+func TestRevokedSessionRejectsAccessTokens(t *testing.T) {
+	server := newTestAuthServer(t)
+	tokens := server.login()
+
+	server.sessionService.RevokeSession(1, 1)
+
+	if code := server.call("GET", "/private", tokens.Token); code != http.StatusForbidden {
+		t.Errorf("Expected the access token of a revoked session to be rejected, but got status %d", code)
+	}
+}
+
+func TestLogoutWithoutTokenID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtMiddleware := &jwt.GinJWTMiddleware{Timeout: time.Hour, MaxRefresh: time.Hour}
+	httpAdapter := NewHttpAuthAdapter(jwtMiddleware, &mock.MockUserService{}, &mock.MockTokenRevocationService{}, &mock.MockSessionService{}, &mock.MockTwoFactorService{}, &mock.MockLoginThrottleService{}, &mock.MockOIDCService{})
+
+	request, _ := http.NewRequest("POST", "/logout", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+	mockContext.Set("JWT_PAYLOAD", jwt.MapClaims{"orig_iat": float64(time.Now().Unix())})
+
+	httpAdapter.Logout(mockContext)
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+}
+
+func TestLogoutNotLoggedIn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtMiddleware := &jwt.GinJWTMiddleware{Timeout: time.Hour, MaxRefresh: time.Hour}
+	httpAdapter := NewHttpAuthAdapter(jwtMiddleware, &mock.MockUserService{}, &mock.MockTokenRevocationService{}, &mock.MockSessionService{}, &mock.MockTwoFactorService{}, &mock.MockLoginThrottleService{}, &mock.MockOIDCService{})
+
+	request, _ := http.NewRequest("POST", "/logout", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.Logout(mockContext)
+
+	if mockResponseWriter.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, but got %d", http.StatusUnauthorized, mockResponseWriter.Code)
+	}
+}
+
+func TestLoginThrottling(t *testing.T) {
+	server := newTestAuthServer(t)
+
+	for _, body := range []string{
+		`{"email": "test@test.com", "password": "wrong"}`,
+		`{"email": "unknown@test.com", "password": "password"}`,
+	} {
+		response := httptest.NewRecorder()
+		request, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(body))
+		server.engine.ServeHTTP(response, request)
+		if response.Code != http.StatusUnauthorized || !strings.Contains(response.Body.String(), domain.ErrInvalidCredentials.Error()) {
+			t.Errorf("Expected a failed login with %s to be rejected the same way, but got status %d and body %s", body, response.Code, response.Body.String())
+		}
+	}
+	if len(server.loginThrottleService.Failures) != 2 || server.loginThrottleService.Failures[0].Reason != domain.LoginFailureInvalidCredentials {
+		t.Errorf("Expected the failed logins to be recorded, but got %v", server.loginThrottleService.Failures)
+	}
+
+	server.loginThrottleService.RetryAt = map[string]time.Time{"test@test.com": time.Now().Add(30 * time.Second)}
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "test@test.com", "password": "password"}`))
+	server.engine.ServeHTTP(response, request)
+	if response.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected a throttled login to be rejected with status %d, but got %d", http.StatusTooManyRequests, response.Code)
+	}
+	if retryAfter := response.Header().Get("Retry-After"); retryAfter != "30" && retryAfter != "31" {
+		t.Errorf("Expected to be told to retry after 30 seconds, but got %q", retryAfter)
+	}
+}
@@ -0,0 +1,222 @@
+package httpadapter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpCreditAdapter struct {
+	personService port.PersonService
+	movieService  port.MovieService
+}
+
+// HttpCredit is a person's credit in a movie. Person is set when listing the
+// credits of a movie, and Movie when listing the filmography of a person.
+type HttpCredit struct {
+	ID           uint        `json:"id"`
+	PersonID     uint        `json:"person_id" binding:"required"`
+	Role         string      `json:"role" binding:"required,oneof=director actor writer composer"`
+	Character    string      `json:"character" binding:"max=100"`
+	BillingOrder int         `json:"billing_order" binding:"min=0"`
+	Person       *HttpPerson `json:"person,omitempty" binding:"-"`
+	Movie        *HttpMovie  `json:"movie,omitempty" binding:"-"`
+}
+
+func CreditFromDomain(credit *domain.MovieCredit) *HttpCredit {
+	httpCredit := &HttpCredit{
+		ID:           credit.ID,
+		PersonID:     credit.PersonID,
+		Role:         string(credit.Role),
+		Character:    credit.Character,
+		BillingOrder: credit.BillingOrder,
+	}
+	if credit.Person != nil {
+		httpCredit.Person = PersonFromDomain(credit.Person)
+	}
+	if credit.Movie != nil {
+		httpCredit.Movie = FromDomain(credit.Movie)
+	}
+	return httpCredit
+}
+
+func (c *HttpCredit) ToDomain() *domain.MovieCredit {
+	return &domain.MovieCredit{
+		ID:           c.ID,
+		PersonID:     c.PersonID,
+		Role:         domain.CreditRole(c.Role),
+		Character:    c.Character,
+		BillingOrder: c.BillingOrder,
+	}
+}
+
+func NewHttpCreditAdapter(personService port.PersonService, movieService port.MovieService) *HttpCreditAdapter {
+	return &HttpCreditAdapter{
+		personService: personService,
+		movieService:  movieService,
+	}
+}
+
+// @Summary List the credits of a movie
+// @Description List the cast and crew of a movie in billing order
+// @Tags Credits
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {array} HttpCredit
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/credits [get]
+// @Security ApiKeyAuth
+func (h *HttpCreditAdapter) ListMovieCredits(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := h.movieService.GetMovie(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	domainCredits, err := h.personService.ListMovieCredits(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	credits := make([]*HttpCredit, len(domainCredits))
+	for i, credit := range domainCredits {
+		credits[i] = CreditFromDomain(credit)
+	}
+
+	context.IndentedJSON(http.StatusOK, credits)
+}
+
+// @Summary Credit a person in a movie
+// @Description Add a director, actor, writer or composer credit to a movie owned by the logged in user, or any movie for moderators and admins
+// @Tags Credits
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param credit body HttpCredit true "Credit object"
+// @Success 201 {object} HttpCredit
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/credits [post]
+// @Security ApiKeyAuth
+func (h *HttpCreditAdapter) CreateCredit(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	movie, err := h.movieService.GetMovie(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if !canManageMovie(user, movie, domain.PermissionEditAnyMovie) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this movie"})
+		return
+	}
+
+	credit := HttpCredit{}
+	if err := context.BindJSON(&credit); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	person, err := h.personService.GetPerson(credit.PersonID)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Person `%d` does not exist", credit.PersonID)})
+		return
+	}
+
+	domainCredit := credit.ToDomain()
+	domainCredit.MovieID = movie.ID
+	if err := h.personService.CreateCredit(domainCredit); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	domainCredit.Person = person
+
+	context.IndentedJSON(http.StatusCreated, CreditFromDomain(domainCredit))
+}
+
+// @Summary Remove a credit from a movie
+// @Description Remove a credit from a movie owned by the logged in user, or any movie for moderators and admins
+// @Tags Credits
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param creditId path int true "Credit ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/credits/{creditId} [delete]
+// @Security ApiKeyAuth
+func (h *HttpCreditAdapter) DeleteCredit(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	creditID, err := strconv.ParseUint(context.Param("creditId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	movie, err := h.movieService.GetMovie(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	credit, err := h.personService.GetCredit(uint(creditID))
+	if err != nil || credit.MovieID != movie.ID {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": "Credit not found"})
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if !canManageMovie(user, movie, domain.PermissionEditAnyMovie) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this movie"})
+		return
+	}
+
+	if err := h.personService.DeleteCredit(credit); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Credit removed"})
+}
@@ -0,0 +1,164 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateCredit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+		},
+	}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", UserID: 1},
+		},
+	}
+
+	httpAdapter := NewHttpCreditAdapter(mockPersonService, mockMovieService)
+
+	for _, testCase := range []struct {
+		credit       HttpCredit
+		expectedCode int
+	}{
+		{HttpCredit{PersonID: 1, Role: "actor", Character: "Michael Corleone"}, http.StatusCreated},
+		{HttpCredit{PersonID: 1, Role: "producer"}, http.StatusBadRequest},
+		{HttpCredit{PersonID: 2, Role: "actor"}, http.StatusBadRequest},
+	} {
+		body, _ := json.Marshal(&testCase.credit)
+		request, _ := http.NewRequest("POST", "/movie/1/credits", bytes.NewBuffer(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.CreateCredit(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %+v, but got %d", testCase.expectedCode, testCase.credit, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockPersonService.Credits) != 1 {
+		t.Fatalf("Expected 1 credit, but got %d", len(mockPersonService.Credits))
+	}
+	if mockPersonService.Credits[0].MovieID != 1 {
+		t.Errorf("Expected credit for movie 1, but got %d", mockPersonService.Credits[0].MovieID)
+	}
+}
+
+func TestCreateCreditForbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+		},
+	}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", UserID: 2},
+		},
+	}
+
+	httpAdapter := NewHttpCreditAdapter(mockPersonService, mockMovieService)
+
+	body, _ := json.Marshal(&HttpCredit{PersonID: 1, Role: "actor"})
+	request, _ := http.NewRequest("POST", "/movie/1/credits", bytes.NewBuffer(body))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.CreateCredit(mockContext)
+
+	if mockResponseWriter.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, but got %d", http.StatusForbidden, mockResponseWriter.Code)
+	}
+	if len(mockPersonService.Credits) != 0 {
+		t.Errorf("Expected no credits, but got %d", len(mockPersonService.Credits))
+	}
+}
+
+func TestListMovieCredits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+			{ID: 2, Name: "Marlon Brando"},
+		},
+		Credits: []*domain.MovieCredit{
+			{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor, BillingOrder: 1},
+			{ID: 2, MovieID: 1, PersonID: 2, Role: domain.CreditRoleActor, BillingOrder: 0},
+		},
+	}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", UserID: 1},
+		},
+	}
+
+	httpAdapter := NewHttpCreditAdapter(mockPersonService, mockMovieService)
+
+	request, _ := http.NewRequest("GET", "/movie/1/credits", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	httpAdapter.ListMovieCredits(mockContext)
+
+	credits := []*HttpCredit{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &credits); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(credits) != 2 {
+		t.Fatalf("Expected 2 credits, but got %d", len(credits))
+	}
+	if credits[0].Person == nil || credits[0].Person.Name != "Marlon Brando" {
+		t.Errorf("Expected 'Marlon Brando' billed first, but got %+v", credits[0].Person)
+	}
+}
+
+func TestDeleteCreditOfAnotherMovie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		Credits: []*domain.MovieCredit{
+			{ID: 1, MovieID: 2, PersonID: 1, Role: domain.CreditRoleActor},
+		},
+	}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", UserID: 1},
+		},
+	}
+
+	httpAdapter := NewHttpCreditAdapter(mockPersonService, mockMovieService)
+
+	request, _ := http.NewRequest("DELETE", "/movie/1/credits/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}, gin.Param{Key: "creditId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.DeleteCredit(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+	if len(mockPersonService.Credits) != 1 {
+		t.Errorf("Expected the credit to be kept, but got %d credits", len(mockPersonService.Credits))
+	}
+}
@@ -0,0 +1,142 @@
+package httpadapter
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpFavouritesAdapter struct {
+	favouritesService port.FavouritesService
+	movieService      port.MovieService
+}
+
+func NewHttpFavouritesAdapter(favouritesService port.FavouritesService, movieService port.MovieService) *HttpFavouritesAdapter {
+	return &HttpFavouritesAdapter{
+		favouritesService: favouritesService,
+		movieService:      movieService,
+	}
+}
+
+// @Summary List favourite movies
+// @Description List the movies the logged in user has marked as favourites
+// @Tags Favourites
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of movies per page"
+// @Success 200 {object} HttpPage[HttpMovie]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/favourites [get]
+// @Security ApiKeyAuth
+func (h *HttpFavouritesAdapter) ListFavourites(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainMovies, total, err := h.favouritesService.ListFavourites(user.ID, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	movies := make([]*HttpMovie, len(domainMovies))
+	for i, movie := range domainMovies {
+		movies[i] = FromDomain(movie)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, movies, pagination, total))
+}
+
+// @Summary Add a favourite movie
+// @Description Add a movie from the catalogue to the logged in user's favourites
+// @Tags Favourites
+// @Accept json
+// @Produce json
+// @Param movieId path int true "Movie ID"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/favourites/{movieId} [post]
+// @Security ApiKeyAuth
+func (h *HttpFavouritesAdapter) AddFavourite(context *gin.Context) {
+	movieID, err := strconv.ParseUint(context.Param("movieId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if _, err := h.movieService.GetMovie(uint(movieID)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	favourite := &domain.Favourite{
+		UserID:  user.ID,
+		MovieID: uint(movieID),
+	}
+	if err := h.favouritesService.AddFavourite(favourite); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, gin.H{"status": "Movie added to favourites"})
+}
+
+// @Summary Remove a favourite movie
+// @Description Remove a movie from the logged in user's favourites
+// @Tags Favourites
+// @Accept json
+// @Produce json
+// @Param movieId path int true "Movie ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /user/me/favourites/{movieId} [delete]
+// @Security ApiKeyAuth
+func (h *HttpFavouritesAdapter) RemoveFavourite(context *gin.Context) {
+	movieID, err := strconv.ParseUint(context.Param("movieId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	favourite := &domain.Favourite{
+		UserID:  user.ID,
+		MovieID: uint(movieID),
+	}
+	if err := h.favouritesService.RemoveFavourite(favourite); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Movie removed from favourites"})
+}
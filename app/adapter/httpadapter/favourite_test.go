@@ -0,0 +1,136 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAddFavourite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavouritesService := &mock.MockFavouritesService{}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", UserID: 2},
+		},
+	}
+
+	httpAdapter := NewHttpFavouritesAdapter(mockFavouritesService, mockMovieService)
+
+	request, _ := http.NewRequest("POST", "/user/me/favourites/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "movieId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.AddFavourite(mockContext)
+
+	if mockResponseWriter.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, but got %d", http.StatusCreated, mockResponseWriter.Code)
+	}
+	if len(mockFavouritesService.Favourites) != 1 {
+		t.Fatalf("Expected 1 favourite, but got %d", len(mockFavouritesService.Favourites))
+	}
+	if mockFavouritesService.Favourites[0].UserID != 1 || mockFavouritesService.Favourites[0].MovieID != 1 {
+		t.Errorf("Expected favourite for user 1 and movie 1, but got %+v", mockFavouritesService.Favourites[0])
+	}
+}
+
+func TestAddFavouriteUnknownMovie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavouritesService := &mock.MockFavouritesService{}
+	mockMovieService := &mock.MockMovieService{}
+
+	httpAdapter := NewHttpFavouritesAdapter(mockFavouritesService, mockMovieService)
+
+	request, _ := http.NewRequest("POST", "/user/me/favourites/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "movieId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.AddFavourite(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+	if len(mockFavouritesService.Favourites) != 0 {
+		t.Errorf("Expected no favourites, but got %d", len(mockFavouritesService.Favourites))
+	}
+}
+
+func TestRemoveFavourite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavouritesService := &mock.MockFavouritesService{
+		Favourites: []*domain.Favourite{
+			{UserID: 1, MovieID: 1},
+		},
+	}
+
+	httpAdapter := NewHttpFavouritesAdapter(mockFavouritesService, &mock.MockMovieService{})
+
+	request, _ := http.NewRequest("DELETE", "/user/me/favourites/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "movieId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.RemoveFavourite(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Errorf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+	if len(mockFavouritesService.Favourites) != 0 {
+		t.Errorf("Expected no favourites, but got %d", len(mockFavouritesService.Favourites))
+	}
+}
+
+func TestListFavourites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockFavouritesService := &mock.MockFavouritesService{
+		Favourites: []*domain.Favourite{
+			{UserID: 1, MovieID: 1},
+			{UserID: 1, MovieID: 2},
+			{UserID: 2, MovieID: 1},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+			{ID: 2, Title: "The Matrix"},
+		},
+	}
+
+	httpAdapter := NewHttpFavouritesAdapter(mockFavouritesService, &mock.MockMovieService{})
+
+	request, _ := http.NewRequest("GET", "/user/me/favourites?page=2&page_size=1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.ListFavourites(mockContext)
+
+	page := &HttpPage[HttpMovie]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 2 {
+		t.Errorf("Expected total to be 2, but got %d", page.Total)
+	}
+	if page.Page != 2 || page.PageSize != 1 {
+		t.Errorf("Expected page 2 with size 1, but got page %d with size %d", page.Page, page.PageSize)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("Expected 1 movie, but got %d", len(page.Items))
+	}
+	if page.Items[0].Title != "The Matrix" {
+		t.Errorf("Expected title to be 'The Matrix', but got '%s'", page.Items[0].Title)
+	}
+}
@@ -0,0 +1,138 @@
+package httpadapter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// parseMovieFilter reads the movie listing filters from the query string and
+// validates their values and ranges.
+func parseMovieFilter(context *gin.Context) (port.MovieFilter, error) {
+	filter := port.MovieFilter{
+		Title:    context.Query("title"),
+		Director: context.Query("director"),
+		Genre:    context.Query("genre"),
+		Cast:     context.Query("cast"),
+	}
+
+	var err error
+	if fuzzy := context.Query("fuzzy"); fuzzy != "" {
+		if filter.Fuzzy, err = strconv.ParseBool(fuzzy); err != nil {
+			return filter, fmt.Errorf("invalid fuzzy `%s`, must be true or false", fuzzy)
+		}
+	}
+	if filter.ReleaseYearFrom, err = queryInt(context, "release_year_from"); err != nil {
+		return filter, err
+	}
+	if filter.ReleaseYearTo, err = queryInt(context, "release_year_to"); err != nil {
+		return filter, err
+	}
+	if filter.RatingMin, err = queryFloat(context, "rating_min"); err != nil {
+		return filter, err
+	}
+	if filter.RatingMax, err = queryFloat(context, "rating_max"); err != nil {
+		return filter, err
+	}
+	if filter.DurationMin, err = queryInt(context, "duration_min"); err != nil {
+		return filter, err
+	}
+	if filter.DurationMax, err = queryInt(context, "duration_max"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedAfter, err = queryTime(context, "created_after"); err != nil {
+		return filter, err
+	}
+
+	userID, err := queryInt(context, "user_id")
+	if err != nil {
+		return filter, err
+	}
+	if userID != nil {
+		if *userID < 1 {
+			return filter, fmt.Errorf("invalid user_id `%d`", *userID)
+		}
+		id := uint(*userID)
+		filter.UserID = &id
+	}
+
+	if filter.RatingMin != nil && (*filter.RatingMin < 0 || *filter.RatingMin > 10) {
+		return filter, fmt.Errorf("rating_min must be between 0 and 10")
+	}
+	if filter.RatingMax != nil && (*filter.RatingMax < 0 || *filter.RatingMax > 10) {
+		return filter, fmt.Errorf("rating_max must be between 0 and 10")
+	}
+	if filter.DurationMin != nil && *filter.DurationMin < 0 {
+		return filter, fmt.Errorf("duration_min must not be negative")
+	}
+	if filter.DurationMax != nil && *filter.DurationMax < 0 {
+		return filter, fmt.Errorf("duration_max must not be negative")
+	}
+	if filter.ReleaseYearFrom != nil && filter.ReleaseYearTo != nil && *filter.ReleaseYearFrom > *filter.ReleaseYearTo {
+		return filter, fmt.Errorf("release_year_from must not be greater than release_year_to")
+	}
+	if filter.RatingMin != nil && filter.RatingMax != nil && *filter.RatingMin > *filter.RatingMax {
+		return filter, fmt.Errorf("rating_min must not be greater than rating_max")
+	}
+	if filter.DurationMin != nil && filter.DurationMax != nil && *filter.DurationMin > *filter.DurationMax {
+		return filter, fmt.Errorf("duration_min must not be greater than duration_max")
+	}
+
+	return filter, nil
+}
+
+func queryInt(context *gin.Context, key string) (*int, error) {
+	value := context.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s `%s`, must be an integer", key, value)
+	}
+	return &parsed, nil
+}
+
+func queryFloat(context *gin.Context, key string) (*float64, error) {
+	value := context.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	// NaN would pass any range check, as every comparison with it is false
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return nil, fmt.Errorf("invalid %s `%s`, must be a number", key, value)
+	}
+	return &parsed, nil
+}
+
+// queryTime accepts either a full RFC 3339 timestamp or a plain date.
+func queryTime(context *gin.Context, key string) (*time.Time, error) {
+	value := context.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := parseTime(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// parseTime accepts either a full RFC 3339 timestamp or a plain date, taken
+// as midnight UTC.
+func parseTime(key string, value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s `%s`, must be an RFC 3339 timestamp or a YYYY-MM-DD date", key, value)
+}
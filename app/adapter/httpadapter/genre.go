@@ -0,0 +1,108 @@
+package httpadapter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpGenreAdapter struct {
+	genreService port.GenreService
+}
+
+type HttpGenre struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name" binding:"required,min=1,max=50"`
+	MovieCount int64  `json:"movie_count"`
+}
+
+func GenreFromDomain(genre *domain.Genre) *HttpGenre {
+	return &HttpGenre{
+		ID:         genre.ID,
+		Name:       genre.Name,
+		MovieCount: genre.MovieCount,
+	}
+}
+
+func (g *HttpGenre) ToDomain() *domain.Genre {
+	return &domain.Genre{
+		ID:   g.ID,
+		Name: g.Name,
+	}
+}
+
+func NewHttpGenreAdapter(genreService port.GenreService) *HttpGenreAdapter {
+	return &HttpGenreAdapter{
+		genreService: genreService,
+	}
+}
+
+// @Summary List genres
+// @Description List every genre along with the number of movies in it
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Success 200 {array} HttpGenre
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /genre [get]
+// @Security ApiKeyAuth
+func (h *HttpGenreAdapter) ListGenres(context *gin.Context) {
+	domainGenres, err := h.genreService.ListGenres()
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	genres := make([]*HttpGenre, len(domainGenres))
+	for i, genre := range domainGenres {
+		genres[i] = GenreFromDomain(genre)
+	}
+
+	context.IndentedJSON(http.StatusOK, genres)
+}
+
+// @Summary Create a genre
+// @Description Add a new genre that movies can be assigned to
+// @Tags Genres
+// @Accept json
+// @Produce json
+// @Param genre body HttpGenre true "Genre object"
+// @Success 201 {object} HttpGenre
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /genre [post]
+// @Security ApiKeyAuth
+func (h *HttpGenreAdapter) CreateGenre(context *gin.Context) {
+	genre := HttpGenre{}
+	if err := context.BindJSON(&genre); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The unique index on names tells about existing genres, even ones created
+	// concurrently
+	domainGenre := genre.ToDomain()
+	err := h.genreService.CreateGenre(domainGenre)
+	if errors.Is(err, domain.ErrBlankGenreName) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrGenreExists) {
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Genre `%s` already exists", genre.Name)})
+		return
+	}
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, GenreFromDomain(domainGenre))
+}
@@ -0,0 +1,102 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestListGenres(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockGenreService := &mock.MockGenreService{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Drama"},
+			{ID: 2, Name: "Sci-Fi"},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", Genres: []domain.Genre{{ID: 1, Name: "Drama"}}},
+		},
+	}
+
+	httpAdapter := NewHttpGenreAdapter(mockGenreService)
+
+	request, _ := http.NewRequest("GET", "/genre", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListGenres(mockContext)
+
+	genres := []*HttpGenre{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &genres); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(genres) != 2 {
+		t.Fatalf("Expected 2 genres, but got %d", len(genres))
+	}
+	if genres[0].Name != "Drama" || genres[0].MovieCount != 1 {
+		t.Errorf("Expected 'Drama' with 1 movie, but got '%s' with %d", genres[0].Name, genres[0].MovieCount)
+	}
+	if genres[1].Name != "Sci-Fi" || genres[1].MovieCount != 0 {
+		t.Errorf("Expected 'Sci-Fi' with 0 movies, but got '%s' with %d", genres[1].Name, genres[1].MovieCount)
+	}
+}
+
+func TestCreateGenre(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockGenreService := &mock.MockGenreService{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Drama"},
+		},
+	}
+
+	httpAdapter := NewHttpGenreAdapter(mockGenreService)
+
+	for name, expectedCode := range map[string]int{
+		"Thriller": http.StatusCreated,
+		"drama":    http.StatusConflict,
+		"":         http.StatusBadRequest,
+		"   ":      http.StatusBadRequest,
+	} {
+		body, _ := json.Marshal(&HttpGenre{Name: name})
+		request, _ := http.NewRequest("POST", "/genre", bytes.NewBuffer(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.CreateGenre(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for '%s', but got %d", expectedCode, name, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockGenreService.Genres) != 2 {
+		t.Errorf("Expected 2 genres, but got %d", len(mockGenreService.Genres))
+	}
+}
+
+func TestCreateGenreRequiresPermission(t *testing.T) {
+	router := newTestRouter(t)
+	userToken := router.login("user@test.com")
+	moderatorToken := router.login("moderator@test.com")
+
+	if code := router.call("GET", "/genre", userToken, ""); code != http.StatusOK {
+		t.Errorf("Expected users to list genres, but got status %d", code)
+	}
+	if code := router.call("POST", "/genre", userToken, `{"name": "Drama"}`); code != http.StatusForbidden {
+		t.Errorf("Expected status %d for a user, but got %d", http.StatusForbidden, code)
+	}
+	if code := router.call("POST", "/genre", moderatorToken, `{"name": "Drama"}`); code != http.StatusCreated {
+		t.Errorf("Expected status %d for a moderator, but got %d", http.StatusCreated, code)
+	}
+	if len(router.genreService.Genres) != 1 {
+		t.Errorf("Expected 1 genre, but got %d", len(router.genreService.Genres))
+	}
+}
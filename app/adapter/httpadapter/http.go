@@ -1,7 +1,12 @@
 package httpadapter
 
 import (
+	"errors"
+	"math"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
@@ -15,18 +20,45 @@ import (
 )
 
 type HttpServices struct {
-	UserService  port.UserService
-	MovieService port.MovieService
+	UserService            port.UserService
+	MovieService           port.MovieService
+	FavouritesService      port.FavouritesService
+	GenreService           port.GenreService
+	PersonService          port.PersonService
+	ReviewService          port.ReviewService
+	ViewingService         port.ViewingService
+	WatchlistService       port.WatchlistService
+	MovieListService       port.MovieListService
+	PasswordService        port.PasswordResetService
+	VerificationService    port.EmailVerificationService
+	TokenRevocationService port.TokenRevocationService
+	SessionService         port.SessionService
+	AccessTokenService     port.AccessTokenService
+	TwoFactorService       port.TwoFactorService
+	LoginThrottleService   port.LoginThrottleService
+	OIDCService            port.OIDCService
 }
 
 func StartHttpServer(services *HttpServices) {
-	httpUserAdapter := NewHttpUserAdapter(services.UserService)
+	engine := NewHttpRouter(services)
+	engine.Run("0.0.0.0:8080")
+}
+
+// NewHttpRouter returns the engine serving every route of the API.
+func NewHttpRouter(services *HttpServices) *gin.Engine {
+	httpUserAdapter := NewHttpUserAdapter(services.UserService, services.VerificationService)
 
 	// Create a new Gin engine
 	engine := gin.Default()
 
+	// Logins are throttled by client IP address, so it is only taken from the
+	// X-Forwarded-For header when the request comes from a trusted proxy
+	if err := engine.SetTrustedProxies(trustedProxies()); err != nil {
+		panic("Invalid TRUSTED_PROXIES: " + err.Error())
+	}
+
 	// Middleware to handle JWT
-	jwtMiddleware, err := jwt.New(getJwtInitParams(services.UserService))
+	jwtMiddleware, err := jwt.New(getJwtInitParams(services.UserService, services.TokenRevocationService, services.SessionService, services.TwoFactorService, services.LoginThrottleService))
 
 	if err != nil {
 		panic("JWT middleware initialization failed: " + err.Error())
@@ -37,29 +69,150 @@ func StartHttpServer(services *HttpServices) {
 	// Swagger documentation route
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Login route
+	// Login routes
+	httpAuthAdapter := NewHttpAuthAdapter(jwtMiddleware, services.UserService, services.TokenRevocationService, services.SessionService, services.TwoFactorService, services.LoginThrottleService, services.OIDCService)
 	engine.POST("/login", jwtMiddleware.LoginHandler)
+	engine.POST("/login/2fa", httpAuthAdapter.CompleteTwoFactorLogin)
+	engine.GET("/login/oidc", httpAuthAdapter.ListOIDCProviders)
+	engine.GET("/login/oidc/:provider", httpAuthAdapter.StartOIDCLogin)
+	engine.GET("/login/oidc/:provider/callback", httpAuthAdapter.CompleteOIDCLogin)
 
-	// User registration route
+	// User registration routes
 	engine.POST("/user", httpUserAdapter.CreateUser)
+	engine.GET("/user/verify", httpUserAdapter.VerifyEmail)
+
+	// Password reset routes
+	httpPasswordAdapter := NewHttpPasswordAdapter(services.PasswordService)
+	engine.POST("/password/forgot", httpPasswordAdapter.ForgotPassword)
+	engine.POST("/password/reset", httpPasswordAdapter.ResetPassword)
 
 	// Refresh route
 	engine.GET("/refresh_token", jwtMiddleware.MiddlewareFunc(), jwtMiddleware.RefreshHandler)
 
-	// User routes
-	usersRouterGroup := engine.Group("/user", jwtMiddleware.MiddlewareFunc())
-	usersRouterGroup.GET("", httpUserAdapter.ListUsers)
+	// Refresh token and logout routes
+	engine.POST("/token/refresh", httpAuthAdapter.RefreshSession)
+	engine.POST("/logout", jwtMiddleware.MiddlewareFunc(), httpAuthAdapter.Logout)
+
+	// User routes. Personal access tokens cannot manage accounts, only the
+	// library of their user.
+	usersRouterGroup := engine.Group("/user", authenticate(jwtMiddleware, services.AccessTokenService, "", ""))
+	usersRouterGroup.GET("", requirePermission(domain.PermissionManageUsers), httpUserAdapter.ListUsers)
+	usersRouterGroup.GET("/me", httpUserAdapter.GetProfile)
+	usersRouterGroup.PATCH("/me", httpUserAdapter.UpdateProfile)
+	usersRouterGroup.DELETE("/me", httpUserAdapter.DeleteAccount)
+	usersRouterGroup.POST("/me/password", httpUserAdapter.ChangePassword)
+	usersRouterGroup.POST("/me/verification", httpUserAdapter.ResendVerification)
+
+	// Session routes
+	httpSessionAdapter := NewHttpSessionAdapter(services.SessionService)
+	usersRouterGroup.GET("/me/sessions", httpSessionAdapter.ListSessions)
+	usersRouterGroup.DELETE("/me/sessions/:sessionId", httpSessionAdapter.RevokeSession)
+
+	// Personal access token routes
+	httpAccessTokenAdapter := NewHttpAccessTokenAdapter(services.AccessTokenService)
+	usersRouterGroup.GET("/me/tokens", httpAccessTokenAdapter.ListAccessTokens)
+	usersRouterGroup.POST("/me/tokens", httpAccessTokenAdapter.CreateAccessToken)
+	usersRouterGroup.DELETE("/me/tokens/:tokenId", httpAccessTokenAdapter.RevokeAccessToken)
+
+	// Two-factor authentication routes
+	httpTwoFactorAdapter := NewHttpTwoFactorAdapter(services.TwoFactorService)
+	usersRouterGroup.GET("/me/2fa", httpTwoFactorAdapter.GetTwoFactor)
+	usersRouterGroup.POST("/me/2fa", httpTwoFactorAdapter.StartEnrolment)
+	usersRouterGroup.POST("/me/2fa/confirm", httpTwoFactorAdapter.ConfirmEnrolment)
+	usersRouterGroup.DELETE("/me/2fa", httpTwoFactorAdapter.DisableTwoFactor)
+	usersRouterGroup.POST("/me/2fa/recovery-codes", httpTwoFactorAdapter.RegenerateRecoveryCodes)
+	usersRouterGroup.GET("/:id", httpUserAdapter.GetUser)
+	usersRouterGroup.PUT("/:id/role", requirePermission(domain.PermissionManageUsers), httpUserAdapter.SetUserRole)
+	usersRouterGroup.POST("/:id/disable", requirePermission(domain.PermissionManageUsers), httpUserAdapter.DisableUser)
+	usersRouterGroup.POST("/:id/enable", requirePermission(domain.PermissionManageUsers), httpUserAdapter.EnableUser)
+
+	// Library routes
+	libraryRouterGroup := engine.Group("/user", authenticate(jwtMiddleware, services.AccessTokenService, domain.ScopeLibraryRead, domain.ScopeLibraryWrite))
+
+	// Favourites routes
+	httpFavouritesAdapter := NewHttpFavouritesAdapter(services.FavouritesService, services.MovieService)
+	libraryRouterGroup.GET("/me/favourites", httpFavouritesAdapter.ListFavourites)
+	libraryRouterGroup.POST("/me/favourites/:movieId", httpFavouritesAdapter.AddFavourite)
+	libraryRouterGroup.DELETE("/me/favourites/:movieId", httpFavouritesAdapter.RemoveFavourite)
+
+	// Review routes
+	httpReviewAdapter := NewHttpReviewAdapter(services.ReviewService, services.MovieService)
+	libraryRouterGroup.GET("/me/reviews", httpReviewAdapter.ListUserReviews)
+	libraryRouterGroup.GET("/:id/reviews", httpReviewAdapter.ListUserReviews)
+
+	// Viewing routes
+	httpViewingAdapter := NewHttpViewingAdapter(services.ViewingService, services.MovieService)
+	libraryRouterGroup.GET("/me/viewings", httpViewingAdapter.ListViewings)
+	libraryRouterGroup.POST("/me/viewings", httpViewingAdapter.LogViewing)
+	libraryRouterGroup.PUT("/me/viewings/:viewingId", httpViewingAdapter.UpdateViewing)
+	libraryRouterGroup.DELETE("/me/viewings/:viewingId", httpViewingAdapter.DeleteViewing)
+	libraryRouterGroup.GET("/me/diary", httpViewingAdapter.GetDiary)
+
+	// Watchlist routes
+	httpWatchlistAdapter := NewHttpWatchlistAdapter(services.WatchlistService, services.MovieService)
+	libraryRouterGroup.GET("/me/watchlist", httpWatchlistAdapter.ListWatchlist)
+	libraryRouterGroup.PUT("/me/watchlist/order", httpWatchlistAdapter.ReorderWatchlist)
+	libraryRouterGroup.POST("/me/watchlist/:movieId", httpWatchlistAdapter.AddToWatchlist)
+	libraryRouterGroup.PUT("/me/watchlist/:movieId", httpWatchlistAdapter.UpdateWatchlistEntry)
+	libraryRouterGroup.DELETE("/me/watchlist/:movieId", httpWatchlistAdapter.RemoveFromWatchlist)
+
+	// Login audit routes
+	httpLoginFailureAdapter := NewHttpLoginFailureAdapter(services.LoginThrottleService)
+	loginFailuresRouterGroup := engine.Group("/login-failures", authenticate(jwtMiddleware, services.AccessTokenService, "", ""), requirePermission(domain.PermissionManageUsers))
+	loginFailuresRouterGroup.GET("", httpLoginFailureAdapter.ListLoginFailures)
 
 	// Movie routes
-	httpMovieAdapter := NewHttpMovieAdapter(services.MovieService)
-	moviesRouterGroup := engine.Group("/movie", jwtMiddleware.MiddlewareFunc())
-	moviesRouterGroup.POST("", httpMovieAdapter.CreateMovie)
+	httpMovieAdapter := NewHttpMovieAdapter(services.MovieService, services.GenreService)
+	moviesRouterGroup := engine.Group("/movie", authenticate(jwtMiddleware, services.AccessTokenService, domain.ScopeMoviesRead, domain.ScopeMoviesWrite))
+	moviesRouterGroup.POST("", requireVerifiedEmail(services.UserService, emailVerificationRequired()), httpMovieAdapter.CreateMovie)
 	moviesRouterGroup.GET("", httpMovieAdapter.ListMovies)
+	moviesRouterGroup.GET("/search", httpMovieAdapter.SearchMovies)
+	moviesRouterGroup.GET("/autocomplete", httpMovieAdapter.AutocompleteMovies)
 	moviesRouterGroup.GET("/:id", httpMovieAdapter.GetMovie)
 	moviesRouterGroup.PUT("/:id", httpMovieAdapter.UpdateMovie)
 	moviesRouterGroup.DELETE("/:id", httpMovieAdapter.DeleteMovie)
 
-	engine.Run("0.0.0.0:8080")
+	// Credit routes
+	httpCreditAdapter := NewHttpCreditAdapter(services.PersonService, services.MovieService)
+	moviesRouterGroup.GET("/:id/credits", httpCreditAdapter.ListMovieCredits)
+	moviesRouterGroup.POST("/:id/credits", httpCreditAdapter.CreateCredit)
+	moviesRouterGroup.DELETE("/:id/credits/:creditId", httpCreditAdapter.DeleteCredit)
+
+	moviesRouterGroup.GET("/:id/reviews", httpReviewAdapter.ListMovieReviews)
+	moviesRouterGroup.POST("/:id/reviews", httpReviewAdapter.CreateReview)
+	moviesRouterGroup.PUT("/:id/reviews", httpReviewAdapter.UpdateReview)
+	moviesRouterGroup.DELETE("/:id/reviews", httpReviewAdapter.DeleteReview)
+
+	// Genre routes. Genres are shared by every movie, so only users allowed to
+	// edit any movie can add them.
+	httpGenreAdapter := NewHttpGenreAdapter(services.GenreService)
+	genresRouterGroup := engine.Group("/genre", authenticate(jwtMiddleware, services.AccessTokenService, domain.ScopeMoviesRead, domain.ScopeMoviesWrite))
+	genresRouterGroup.GET("", httpGenreAdapter.ListGenres)
+	genresRouterGroup.POST("", requirePermission(domain.PermissionEditAnyMovie), httpGenreAdapter.CreateGenre)
+
+	// Person routes. People are shared by the movies of every user, so only
+	// users allowed to edit any movie can change or delete them.
+	httpPersonAdapter := NewHttpPersonAdapter(services.PersonService)
+	peopleRouterGroup := engine.Group("/person", authenticate(jwtMiddleware, services.AccessTokenService, domain.ScopeMoviesRead, domain.ScopeMoviesWrite))
+	peopleRouterGroup.POST("", httpPersonAdapter.CreatePerson)
+	peopleRouterGroup.GET("", httpPersonAdapter.ListPeople)
+	peopleRouterGroup.GET("/:id", httpPersonAdapter.GetPerson)
+	peopleRouterGroup.PUT("/:id", requirePermission(domain.PermissionEditAnyMovie), httpPersonAdapter.UpdatePerson)
+	peopleRouterGroup.DELETE("/:id", requirePermission(domain.PermissionEditAnyMovie), httpPersonAdapter.DeletePerson)
+	peopleRouterGroup.GET("/:id/movies", httpPersonAdapter.ListPersonMovies)
+
+	// Movie list routes
+	httpMovieListAdapter := NewHttpMovieListAdapter(services.MovieListService, services.MovieService)
+	engine.GET("/list/shared/:token", httpMovieListAdapter.GetSharedList)
+	listsRouterGroup := engine.Group("/list", authenticate(jwtMiddleware, services.AccessTokenService, domain.ScopeListsRead, domain.ScopeListsWrite))
+	listsRouterGroup.POST("", httpMovieListAdapter.CreateList)
+	listsRouterGroup.GET("", httpMovieListAdapter.ListLists)
+	listsRouterGroup.GET("/:id", httpMovieListAdapter.GetList)
+	listsRouterGroup.PUT("/:id", httpMovieListAdapter.UpdateList)
+	listsRouterGroup.DELETE("/:id", httpMovieListAdapter.DeleteList)
+	listsRouterGroup.POST("/:id/share-link", httpMovieListAdapter.RegenerateShareLink)
+
+	return engine
 }
 
 type LoginForm struct {
@@ -67,7 +220,32 @@ type LoginForm struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func getJwtInitParams(userService port.UserService) *jwt.GinJWTMiddleware {
+// sessionUser is a user along with the session a token is issued for.
+type sessionUser struct {
+	*domain.User
+	SessionID uint
+}
+
+// twoFactorChallenge is what the authenticator gives for a user with two-factor
+// authentication, who gets a challenge token instead of an access token.
+type twoFactorChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// refreshTokenKey is where the login handler finds the refresh token of the
+// session started by the authenticator.
+const refreshTokenKey = "refresh_token"
+
+// twoFactorChallengeKey is where the login handler finds the challenge the
+// authenticator gave instead of starting a session.
+const twoFactorChallengeKey = "two_factor_challenge"
+
+// loginRetryAtKey is where the login handler finds when a login held back by
+// the authenticator can be tried again.
+const loginRetryAtKey = "login_retry_at"
+
+func getJwtInitParams(userService port.UserService, tokenRevocationService port.TokenRevocationService, sessionService port.SessionService, twoFactorService port.TwoFactorService, loginThrottleService port.LoginThrottleService) *jwt.GinJWTMiddleware {
 	return &jwt.GinJWTMiddleware{
 		Realm:       "movie-collection",
 		Key:         []byte(os.Getenv("JWT_SECRET_KEY")),
@@ -81,45 +259,165 @@ func getJwtInitParams(userService port.UserService) *jwt.GinJWTMiddleware {
 			}
 			userEmail := loginForm.Email
 			userPassword := loginForm.Password
+			ipAddress := c.ClientIP()
+			userAgent := c.Request.UserAgent()
+
+			retryAt, err := loginThrottleService.CheckLogin(userEmail, ipAddress, userAgent)
+			if errors.Is(err, domain.ErrTooManyLoginAttempts) {
+				c.Set(loginRetryAtKey, retryAt)
+			}
+			if err != nil {
+				return nil, err
+			}
 
-			return userService.GetLoginUser(userEmail, userPassword)
+			// Wrong passwords and unknown emails fail alike, and take as long
+			user, err := userService.GetLoginUser(userEmail, userPassword)
+			if err != nil {
+				reason := domain.LoginFailureInvalidCredentials
+				if errors.Is(err, domain.ErrUserDisabled) {
+					reason = domain.LoginFailureDisabled
+				}
+				if err := loginThrottleService.RecordFailure(userEmail, ipAddress, userAgent, reason); err != nil {
+					return nil, err
+				}
+				return nil, err
+			}
+
+			// Users with two-factor authentication get a session, and their
+			// count of failures starts over, once they complete the challenge
+			// at /login/2fa
+			twoFactorEnabled, err := twoFactorService.TwoFactorEnabled(user.ID)
+			if err != nil {
+				return nil, err
+			}
+			if twoFactorEnabled {
+				challengeToken, expiresAt, err := twoFactorService.CreateChallenge(user.ID)
+				if err != nil {
+					return nil, err
+				}
+				challenge := &twoFactorChallenge{Token: challengeToken, ExpiresAt: expiresAt}
+				c.Set(twoFactorChallengeKey, challenge)
+				return challenge, nil
+			}
+
+			if err := loginThrottleService.RecordSuccess(userEmail); err != nil {
+				return nil, err
+			}
+			session, refreshToken, err := sessionService.CreateSession(user.ID, userAgent, ipAddress)
+			if err != nil {
+				return nil, err
+			}
+			c.Set(refreshTokenKey, refreshToken)
+
+			return &sessionUser{User: user, SessionID: session.ID}, nil
+		},
+		LoginResponse: func(c *gin.Context, code int, token string, expire time.Time) {
+			// The token issued for a challenge is empty and thrown away
+			if value, ok := c.Get(twoFactorChallengeKey); ok {
+				challenge := value.(*twoFactorChallenge)
+				c.JSON(code, gin.H{
+					"code":                code,
+					"two_factor_required": true,
+					"challenge_token":     challenge.Token,
+					"expire":              challenge.ExpiresAt.Format(time.RFC3339),
+				})
+				return
+			}
+			c.JSON(code, gin.H{
+				"code":          code,
+				"token":         token,
+				"expire":        expire.Format(time.RFC3339),
+				"refresh_token": c.GetString(refreshTokenKey),
+			})
 		},
 		Authorizator: func(data interface{}, c *gin.Context) bool {
-			if _, ok := data.(*domain.User); ok {
-				return true
+			user, ok := data.(*domain.User)
+			if !ok {
+				return false
+			}
+
+			// Disabled users and revoked tokens are rejected, including when
+			// refreshing a token.
+			currentUser, err := userService.AuthorizeToken(user.ID, tokenIssuedAt(c))
+			if err != nil {
+				return false
+			}
+			// The role in the token is the one the user had when logging in,
+			// so permissions are checked against their current role instead
+			user.Role = currentUser.Role
+			if !user.Role.Valid() {
+				return false
 			}
-			return false
+			if err := tokenRevocationService.CheckToken(tokenID(c)); err != nil {
+				return false
+			}
+			return sessionService.CheckSession(tokenSessionID(c)) == nil
 		},
 		Unauthorized: func(c *gin.Context, code int, message string) {
+			if value, ok := c.Get(loginRetryAtKey); ok {
+				setRetryAfter(c, value.(time.Time))
+				code = http.StatusTooManyRequests
+			}
 			c.JSON(code, gin.H{
 				"code":    code,
 				"message": message,
 			})
 		},
-		TokenLookup:   "header: Authorization, query: token, cookie: jwt",
+		TokenLookup:   "header: Authorization",
 		TokenHeadName: "Bearer",
 		TimeFunc:      time.Now,
 		PayloadFunc: func(data interface{}) jwt.MapClaims {
+			if user, ok := data.(*sessionUser); ok {
+				return jwt.MapClaims{
+					"id":    user.ID,
+					"name":  user.Name,
+					"email": user.Email,
+					"role":  string(user.Role),
+					"jti":   newTokenID(),
+					"sid":   user.SessionID,
+				}
+			}
 			if user, ok := data.(*domain.User); ok {
 				return jwt.MapClaims{
 					"id":    user.ID,
 					"name":  user.Name,
 					"email": user.Email,
+					"role":  string(user.Role),
+					"jti":   newTokenID(),
 				}
 			}
 			return jwt.MapClaims{}
 		},
 		IdentityHandler: func(c *gin.Context) interface{} {
 			claims := jwt.ExtractClaims(c)
+			role, _ := claims["role"].(string)
+			if role == "" {
+				role = string(domain.RoleUser) // Tokens issued before roles existed
+			}
 			return &domain.User{
 				ID:    uint(claims["id"].(float64)),
 				Email: claims["email"].(string),
 				Name:  claims["name"].(string),
+				Role:  domain.Role(role),
 			}
 		},
 	}
 }
 
+// setRetryAfter tells a throttled client how many seconds to wait before
+// logging in again.
+func setRetryAfter(c *gin.Context, retryAt time.Time) {
+	retryAfter := int(math.Ceil(time.Until(retryAt).Seconds()))
+	c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+}
+
+// tokenIssuedAt returns when the token of the request was first issued. It is
+// kept when the token is refreshed.
+func tokenIssuedAt(c *gin.Context) time.Time {
+	issuedAt, _ := jwt.ExtractClaims(c)["orig_iat"].(float64)
+	return time.Unix(int64(issuedAt), 0)
+}
+
 func handleMiddleware(authMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		err := authMiddleware.MiddlewareInit()
@@ -129,6 +427,107 @@ func handleMiddleware(authMiddleware *jwt.GinJWTMiddleware) gin.HandlerFunc {
 	}
 }
 
+// authenticate lets the request through with either a JWT or a personal
+// access token. Personal access tokens need readScope to read and writeScope
+// for everything else, so that routes without scopes only accept JWTs.
+func authenticate(jwtMiddleware *jwt.GinJWTMiddleware, accessTokenService port.AccessTokenService, readScope domain.Scope, writeScope domain.Scope) gin.HandlerFunc {
+	jwtHandler := jwtMiddleware.MiddlewareFunc()
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found || !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+			jwtHandler(c)
+			return
+		}
+
+		user, accessToken, err := accessTokenService.AuthenticateAccessToken(token)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+
+		scope := writeScope
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			scope = readScope
+		}
+		if scope == "" || !accessToken.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This personal access token is not allowed to access this resource"})
+			return
+		}
+
+		c.Set(jwtMiddleware.IdentityKey, user)
+		c.Next()
+	}
+}
+
+// requirePermission only lets the logged in user through when their role has
+// the given permission.
+func requirePermission(permission domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, loggedIn := GetLoggedInUser(c)
+		if !loggedIn {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+		if !user.Role.Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to access this resource"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// trustedProxies returns the proxies in TRUSTED_PROXIES, separated by commas,
+// or none when it is not set.
+func trustedProxies() []string {
+	value := strings.TrimSpace(os.Getenv("TRUSTED_PROXIES"))
+	if value == "" {
+		return nil
+	}
+
+	proxies := []string{}
+	for _, proxy := range strings.Split(value, ",") {
+		proxies = append(proxies, strings.TrimSpace(proxy))
+	}
+	return proxies
+}
+
+// emailVerificationRequired reports whether REQUIRE_EMAIL_VERIFICATION asks
+// for users to verify their email before adding movies.
+func emailVerificationRequired() bool {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	return required
+}
+
+// requireVerifiedEmail only lets the logged in user through when their email
+// is verified, or when verification is not required.
+func requireVerifiedEmail(userService port.UserService, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !required {
+			c.Next()
+			return
+		}
+
+		loggedInUser, loggedIn := GetLoggedInUser(c)
+		if !loggedIn {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		// The token does not say whether the email was verified since it was
+		// issued
+		user, err := userService.GetUser(loggedInUser.ID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+		if !user.EmailVerified() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You need to verify your email first"})
+			return
+		}
+		c.Next()
+	}
+}
+
 func GetLoggedInUser(c *gin.Context) (*domain.User, bool) {
 	userValue, exists := c.Get("id")
 	if !exists {
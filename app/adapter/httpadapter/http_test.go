@@ -0,0 +1,268 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// testRouter serves every route of the API against mock services, with a plain
+// user and a moderator who can log in with the password "password".
+type testRouter struct {
+	t             *testing.T
+	engine        *gin.Engine
+	userService   *mock.MockUserService
+	genreService  *mock.MockGenreService
+	personService *mock.MockPersonService
+}
+
+func newTestRouter(t *testing.T) *testRouter {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	users := []*domain.User{
+		{ID: 1, Email: "user@test.com", Name: "Test User", Password: "password", Role: domain.RoleUser},
+		{ID: 2, Email: "moderator@test.com", Name: "Test Moderator", Password: "password", Role: domain.RoleModerator},
+	}
+	router := &testRouter{
+		t:             t,
+		userService:   &mock.MockUserService{Users: users},
+		genreService:  &mock.MockGenreService{},
+		personService: &mock.MockPersonService{},
+	}
+	router.engine = NewHttpRouter(&HttpServices{
+		UserService:            router.userService,
+		MovieService:           &mock.MockMovieService{},
+		FavouritesService:      &mock.MockFavouritesService{},
+		GenreService:           router.genreService,
+		PersonService:          router.personService,
+		ReviewService:          &mock.MockReviewService{},
+		ViewingService:         &mock.MockViewingService{},
+		WatchlistService:       &mock.MockWatchlistService{},
+		MovieListService:       &mock.MockMovieListService{},
+		PasswordService:        &mock.MockPasswordResetService{},
+		VerificationService:    &mock.MockEmailVerificationService{},
+		TokenRevocationService: &mock.MockTokenRevocationService{},
+		SessionService:         &mock.MockSessionService{},
+		AccessTokenService:     &mock.MockAccessTokenService{Users: users},
+		TwoFactorService:       &mock.MockTwoFactorService{Users: users},
+		LoginThrottleService:   &mock.MockLoginThrottleService{},
+		OIDCService:            &mock.MockOIDCService{Users: users},
+	})
+	return router
+}
+
+// login returns an access token for the user with the given email.
+func (r *testRouter) login(email string) string {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("POST", "/login", bytes.NewBufferString(`{"email": "`+email+`", "password": "password"}`))
+	r.engine.ServeHTTP(response, request)
+
+	tokens := &HttpTokenResponse{}
+	json.Unmarshal(response.Body.Bytes(), tokens)
+	if response.Code != http.StatusOK || tokens.Token == "" {
+		r.t.Fatalf("Failed to log in as %s, got status %d", email, response.Code)
+	}
+	return tokens.Token
+}
+
+func (r *testRouter) call(method string, url string, token string, body string) int {
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+	request.Header.Set("Authorization", "Bearer "+token)
+	r.engine.ServeHTTP(response, request)
+	return response.Code
+}
+
+func TestDemotedUserLosesPermissions(t *testing.T) {
+	router := newTestRouter(t)
+	moderatorToken := router.login("moderator@test.com")
+
+	if code := router.call("POST", "/genre", moderatorToken, `{"name": "Drama"}`); code != http.StatusCreated {
+		t.Fatalf("Expected status %d before the demotion, but got %d", http.StatusCreated, code)
+	}
+
+	if err := router.userService.SetUserRole(2, domain.RoleUser); err != nil {
+		t.Fatalf("Failed to demote the moderator: %v", err)
+	}
+	if code := router.call("POST", "/genre", moderatorToken, `{"name": "Thriller"}`); code != http.StatusForbidden {
+		t.Errorf("Expected status %d after the demotion, but got %d", http.StatusForbidden, code)
+	}
+	if code := router.call("GET", "/genre", moderatorToken, ""); code != http.StatusOK {
+		t.Errorf("Expected the demoted user to stay logged in, but got status %d", code)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for role, expectedCode := range map[domain.Role]int{
+		domain.RoleAdmin:     http.StatusOK,
+		domain.RoleModerator: http.StatusForbidden,
+		domain.RoleUser:      http.StatusForbidden,
+		"":                   http.StatusForbidden,
+	} {
+		request, _ := http.NewRequest("GET", "/user", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User", Role: role})
+
+		requirePermission(domain.PermissionManageUsers)(mockContext)
+		if !mockContext.IsAborted() {
+			mockContext.Status(http.StatusOK)
+		}
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for role '%s', but got %d", expectedCode, role, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestRequirePermissionNotLoggedIn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request, _ := http.NewRequest("GET", "/user", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	requirePermission(domain.PermissionManageUsers)(mockContext)
+
+	if mockResponseWriter.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, but got %d", http.StatusUnauthorized, mockResponseWriter.Code)
+	}
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", EmailVerifiedAt: time.Now()},
+			{ID: 2, Email: "pending@test.com", Name: "Pending User"},
+		},
+	}
+
+	for _, testCase := range []struct {
+		userID       uint
+		required     bool
+		expectedCode int
+	}{
+		{1, true, http.StatusOK},
+		{2, true, http.StatusForbidden},
+		{2, false, http.StatusOK},
+	} {
+		request, _ := http.NewRequest("POST", "/movie", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: testCase.userID, Role: domain.RoleUser})
+
+		requireVerifiedEmail(mockUserService, testCase.required)(mockContext)
+		if !mockContext.IsAborted() {
+			mockContext.Status(http.StatusOK)
+		}
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for user %d, but got %d", testCase.expectedCode, testCase.userID, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestAuthorizatorRejectsDisabledUsersAndRevokedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	revokedAt := time.Now().Add(-time.Hour)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Role: domain.RoleUser, TokensRevokedAt: revokedAt},
+			{ID: 2, Email: "disabled@test.com", Name: "Disabled User", Role: domain.RoleUser, DisableDate: revokedAt},
+		},
+	}
+	authorizator := getJwtInitParams(mockUserService, &mock.MockTokenRevocationService{}, &mock.MockSessionService{}, &mock.MockTwoFactorService{}, &mock.MockLoginThrottleService{}).Authorizator
+
+	for _, testCase := range []struct {
+		userID   uint
+		issuedAt time.Time
+		expected bool
+	}{
+		{1, time.Now(), true},
+		{1, revokedAt.Add(-time.Minute), false},
+		{2, time.Now(), false},
+		{3, time.Now(), false},
+	} {
+		mockContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		mockContext.Set("JWT_PAYLOAD", jwt.MapClaims{"orig_iat": float64(testCase.issuedAt.Unix())})
+
+		user := &domain.User{ID: testCase.userID, Role: domain.RoleUser}
+		if authorized := authorizator(user, mockContext); authorized != testCase.expected {
+			t.Errorf("Expected authorization of user %d with a token issued at %v to be %v", testCase.userID, testCase.issuedAt, testCase.expected)
+		}
+	}
+}
+
+func TestAuthorizatorRejectsRevokedTokenIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{{ID: 1, Email: "test@test.com", Name: "Test User", Role: domain.RoleUser}},
+	}
+	mockTokenRevocationService := &mock.MockTokenRevocationService{
+		Revoked: map[string]time.Time{"revoked-token-id": time.Now().Add(time.Hour)},
+	}
+	authorizator := getJwtInitParams(mockUserService, mockTokenRevocationService, &mock.MockSessionService{}, &mock.MockTwoFactorService{}, &mock.MockLoginThrottleService{}).Authorizator
+
+	for tokenID, expected := range map[string]bool{
+		"":                 true,
+		"token-id":         true,
+		"revoked-token-id": false,
+	} {
+		mockContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		mockContext.Set("JWT_PAYLOAD", jwt.MapClaims{"orig_iat": float64(time.Now().Unix()), "jti": tokenID})
+
+		user := &domain.User{ID: 1, Role: domain.RoleUser}
+		if authorized := authorizator(user, mockContext); authorized != expected {
+			t.Errorf("Expected authorization of a token with ID '%s' to be %v", tokenID, expected)
+		}
+	}
+}
+
+func TestAuthenticateAcceptsAccessTokensWithinTheirScopes(t *testing.T) {
+	server := newTestAuthServer(t)
+	server.accessTokenService.Tokens = map[string]*domain.PersonalAccessToken{
+		"mcpat_read":    {ID: 1, UserID: 1, Scopes: []domain.Scope{domain.ScopeMoviesRead}},
+		"mcpat_write":   {ID: 2, UserID: 1, Scopes: []domain.Scope{domain.ScopeMoviesRead, domain.ScopeMoviesWrite}},
+		"mcpat_lists":   {ID: 3, UserID: 1, Scopes: []domain.Scope{domain.ScopeListsRead, domain.ScopeListsWrite}},
+		"mcpat_expired": {ID: 4, UserID: 1, Scopes: []domain.Scope{domain.ScopeMoviesRead}, ExpiresAt: time.Now().Add(-time.Minute)},
+	}
+	tokens := server.login()
+
+	for _, testCase := range []struct {
+		method       string
+		url          string
+		token        string
+		expectedCode int
+	}{
+		{"GET", "/movie", "mcpat_read", http.StatusOK},
+		{"POST", "/movie", "mcpat_read", http.StatusForbidden},
+		{"POST", "/movie", "mcpat_write", http.StatusOK},
+		{"DELETE", "/movie", "mcpat_write", http.StatusOK},
+		{"GET", "/movie", "mcpat_lists", http.StatusForbidden},
+		{"GET", "/movie", "mcpat_expired", http.StatusUnauthorized},
+		{"GET", "/movie", "mcpat_unknown", http.StatusUnauthorized},
+		{"GET", "/movie", tokens.Token, http.StatusOK},
+		{"POST", "/movie", tokens.Token, http.StatusOK},
+		{"GET", "/movie", "", http.StatusUnauthorized},
+		{"GET", "/user/me", "mcpat_write", http.StatusForbidden},
+		{"GET", "/user/me", tokens.Token, http.StatusOK},
+	} {
+		if code := server.call(testCase.method, testCase.url, testCase.token); code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s %s with '%s', but got %d", testCase.expectedCode, testCase.method, testCase.url, testCase.token, code)
+		}
+	}
+}
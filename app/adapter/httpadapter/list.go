@@ -0,0 +1,432 @@
+package httpadapter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpMovieListAdapter struct {
+	listService  port.MovieListService
+	movieService port.MovieService
+}
+
+type HttpMovieList struct {
+	ID          uint                  `json:"id"`
+	UserID      uint                  `json:"user_id"`
+	Title       string                `json:"title" binding:"required,max=200"`
+	Description string                `json:"description" binding:"max=2000"`
+	Visibility  string                `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+	ShareLink   string                `json:"share_link,omitempty"`
+	EntryCount  int64                 `json:"entry_count"`
+	Entries     []*HttpMovieListEntry `json:"entries,omitempty" binding:"max=500,dive"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+type HttpMovieListEntry struct {
+	MovieID  uint       `json:"movie_id" binding:"required"`
+	Position int        `json:"position"`
+	Notes    string     `json:"notes" binding:"max=1000"`
+	Movie    *HttpMovie `json:"movie,omitempty" binding:"-"`
+}
+
+func (l *HttpMovieList) ToDomain() *domain.MovieList {
+	entries := make([]domain.MovieListEntry, len(l.Entries))
+	for i, entry := range l.Entries {
+		entries[i] = domain.MovieListEntry{
+			MovieID: entry.MovieID,
+			Notes:   entry.Notes,
+		}
+	}
+
+	return &domain.MovieList{
+		ID:          l.ID,
+		UserID:      l.UserID,
+		Title:       l.Title,
+		Description: l.Description,
+		Visibility:  domain.ListVisibility(l.Visibility),
+		Entries:     entries,
+	}
+}
+
+// MovieListFromDomain converts a list for its HTTP representation. The share
+// link is left out, as only the owner of the list may see it.
+func MovieListFromDomain(list *domain.MovieList) *HttpMovieList {
+	entries := make([]*HttpMovieListEntry, len(list.Entries))
+	for i, entry := range list.Entries {
+		entries[i] = &HttpMovieListEntry{
+			MovieID:  entry.MovieID,
+			Position: entry.Position,
+			Notes:    entry.Notes,
+		}
+		if entry.Movie != nil {
+			entries[i].Movie = FromDomain(entry.Movie)
+		}
+	}
+
+	entryCount := list.EntryCount
+	if len(entries) > 0 {
+		entryCount = int64(len(entries))
+	}
+
+	return &HttpMovieList{
+		ID:          list.ID,
+		UserID:      list.UserID,
+		Title:       list.Title,
+		Description: list.Description,
+		Visibility:  string(list.Visibility),
+		EntryCount:  entryCount,
+		Entries:     entries,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+// ownMovieListFromDomain converts a list for its owner, including the share
+// link of unlisted and public lists.
+func ownMovieListFromDomain(list *domain.MovieList) *HttpMovieList {
+	httpList := MovieListFromDomain(list)
+	if list.Visibility != domain.ListVisibilityPrivate {
+		httpList.ShareLink = "/list/shared/" + list.ShareToken
+	}
+	return httpList
+}
+
+func NewHttpMovieListAdapter(listService port.MovieListService, movieService port.MovieService) *HttpMovieListAdapter {
+	return &HttpMovieListAdapter{
+		listService:  listService,
+		movieService: movieService,
+	}
+}
+
+// @Summary Create a movie list
+// @Description Create a list of movies owned by the logged in user. Entries keep the order they are given in. Lists are private unless another visibility is given.
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param list body HttpMovieList true "Movie list object"
+// @Success 201 {object} HttpMovieList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /list [post]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) CreateList(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	list := HttpMovieList{}
+	if err := context.BindJSON(&list); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainList := list.ToDomain()
+	if !h.resolveEntryMovies(context, domainList) {
+		return
+	}
+
+	domainList.ID = 0
+	domainList.UserID = user.ID
+	if err := h.listService.CreateList(domainList); err != nil {
+		respondListError(context, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, ownMovieListFromDomain(domainList))
+}
+
+// @Summary List movie lists
+// @Description List the lists of the logged in user and the public lists of everyone else, most recently updated first
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param user_id query int false "Only list the lists of this user"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of lists per page"
+// @Success 200 {object} HttpPage[HttpMovieList]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /list [get]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) ListLists(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := port.MovieListFilter{VisibleTo: user.ID}
+	if value := context.Query("user_id"); value != "" {
+		ownerID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid user_id `%s`, must be an integer", value)})
+			return
+		}
+		owner := uint(ownerID)
+		filter.OwnerID = &owner
+	}
+
+	domainLists, total, err := h.listService.ListLists(filter, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	lists := make([]*HttpMovieList, len(domainLists))
+	for i, list := range domainLists {
+		if list.UserID == user.ID {
+			lists[i] = ownMovieListFromDomain(list)
+		} else {
+			lists[i] = MovieListFromDomain(list)
+		}
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, lists, pagination, total))
+}
+
+// @Summary Get a movie list
+// @Description Get a list with its movies. Lists that are not public can only be seen by their owner, or through their share link when unlisted.
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} HttpMovieList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /list/{id} [get]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) GetList(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	list, ok := h.getVisibleList(context, user)
+	if !ok {
+		return
+	}
+
+	if list.UserID == user.ID {
+		context.IndentedJSON(http.StatusOK, ownMovieListFromDomain(list))
+		return
+	}
+	context.IndentedJSON(http.StatusOK, MovieListFromDomain(list))
+}
+
+// @Summary Get a shared movie list
+// @Description Get an unlisted or public list through its share link. No authentication is needed.
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} HttpMovieList
+// @Failure 404 {object} map[string]string
+// @Router /list/shared/{token} [get]
+func (h *HttpMovieListAdapter) GetSharedList(context *gin.Context) {
+	list, err := h.listService.GetSharedList(context.Param("token"))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, MovieListFromDomain(list))
+}
+
+// @Summary Update a movie list
+// @Description Update a list owned by the logged in user. The entries are replaced by the given ones, in their order.
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param list body HttpMovieList true "Updated movie list object"
+// @Success 200 {object} HttpMovieList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /list/{id} [put]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) UpdateList(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	listToUpdate, ok := h.getVisibleList(context, user)
+	if !ok {
+		return
+	}
+
+	if listToUpdate.UserID != user.ID {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this list"})
+		return
+	}
+
+	updatedList := HttpMovieList{}
+	if err := context.BindJSON(&updatedList); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedDomainList := updatedList.ToDomain()
+	if !h.resolveEntryMovies(context, updatedDomainList) {
+		return
+	}
+
+	updatedDomainList.ID = listToUpdate.ID
+	updatedDomainList.UserID = listToUpdate.UserID // Preserve the owner
+	updatedDomainList.ShareToken = listToUpdate.ShareToken
+	updatedDomainList.CreatedAt = listToUpdate.CreatedAt
+	if err := h.listService.UpdateList(updatedDomainList); err != nil {
+		respondListError(context, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, ownMovieListFromDomain(updatedDomainList))
+}
+
+// @Summary Delete a movie list
+// @Description Delete a list owned by the logged in user
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /list/{id} [delete]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) DeleteList(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	listToDelete, ok := h.getVisibleList(context, user)
+	if !ok {
+		return
+	}
+
+	if listToDelete.UserID != user.ID {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete this list"})
+		return
+	}
+
+	if err := h.listService.DeleteList(listToDelete); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "List deleted successfully"})
+}
+
+// @Summary Regenerate the share link of a movie list
+// @Description Replace the share link of a list owned by the logged in user, so that the previous link stops working
+// @Tags Lists
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} HttpMovieList
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /list/{id}/share-link [post]
+// @Security ApiKeyAuth
+func (h *HttpMovieListAdapter) RegenerateShareLink(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	list, ok := h.getVisibleList(context, user)
+	if !ok {
+		return
+	}
+
+	if list.UserID != user.ID {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this list"})
+		return
+	}
+
+	if err := h.listService.RegenerateShareToken(list); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, ownMovieListFromDomain(list))
+}
+
+// getVisibleList loads the list of the `id` path parameter, responding with a
+// 404 when it does not exist or the user is not allowed to see it, so that the
+// existence of private and unlisted lists is not revealed.
+func (h *HttpMovieListAdapter) getVisibleList(context *gin.Context, user *domain.User) (*domain.MovieList, bool) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	list, err := h.listService.GetList(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return nil, false
+	}
+
+	if list.UserID != user.ID && list.Visibility != domain.ListVisibilityPublic {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		return nil, false
+	}
+
+	return list, true
+}
+
+// resolveEntryMovies loads the movie of every entry of a list, responding with
+// a 400 when one of them does not exist.
+func (h *HttpMovieListAdapter) resolveEntryMovies(context *gin.Context, list *domain.MovieList) bool {
+	for i := range list.Entries {
+		movie, err := h.movieService.GetMovie(list.Entries[i].MovieID)
+		if err != nil {
+			context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Movie `%d` does not exist", list.Entries[i].MovieID)})
+			return false
+		}
+		list.Entries[i].Movie = movie
+	}
+	return true
+}
+
+func respondListError(context *gin.Context, err error) {
+	if errors.Is(err, domain.ErrDuplicateListEntry) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	context.AbortWithError(http.StatusInternalServerError, err)
+}
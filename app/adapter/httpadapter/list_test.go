@@ -0,0 +1,255 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+			{ID: 2, Title: "The Matrix"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, mockMovieService)
+
+	for name, expectedCode := range map[string]int{
+		`{"title": "Mind benders", "visibility": "unlisted", "entries": [{"movie_id": 2, "notes": "First"}, {"movie_id": 1}]}`: http.StatusCreated,
+		`{"title": "Twice", "entries": [{"movie_id": 1}, {"movie_id": 1}]}`:                                                    http.StatusBadRequest,
+		`{"title": "Unknown", "entries": [{"movie_id": 3}]}`:                                                                   http.StatusBadRequest,
+		`{"title": "Hidden", "visibility": "secret"}`:                                                                          http.StatusBadRequest,
+		`{"description": "No title"}`:                                                                                          http.StatusBadRequest,
+	} {
+		request, _ := http.NewRequest("POST", "/list", bytes.NewBufferString(name))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.CreateList(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", expectedCode, name, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockListService.Lists) != 1 {
+		t.Fatalf("Expected 1 list, but got %d", len(mockListService.Lists))
+	}
+	list := mockListService.Lists[0]
+	if list.UserID != 1 || list.Visibility != domain.ListVisibilityUnlisted {
+		t.Errorf("Expected an unlisted list of user 1, but got %+v", list)
+	}
+	if len(list.Entries) != 2 || list.Entries[0].MovieID != 2 || list.Entries[0].Notes != "First" {
+		t.Errorf("Expected 'The Matrix' first with its notes, but got %+v", list.Entries)
+	}
+}
+
+func TestGetList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 1, Title: "Mine", Visibility: domain.ListVisibilityPrivate, ShareToken: "mine"},
+			{ID: 2, UserID: 2, Title: "Public", Visibility: domain.ListVisibilityPublic, ShareToken: "public"},
+			{ID: 3, UserID: 2, Title: "Unlisted", Visibility: domain.ListVisibilityUnlisted, ShareToken: "unlisted"},
+			{ID: 4, UserID: 2, Title: "Private", Visibility: domain.ListVisibilityPrivate, ShareToken: "private"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, &mock.MockMovieService{})
+
+	for id, expectedCode := range map[string]int{
+		"1": http.StatusOK,
+		"2": http.StatusOK,
+		"3": http.StatusNotFound,
+		"4": http.StatusNotFound,
+		"5": http.StatusNotFound,
+	} {
+		request, _ := http.NewRequest("GET", "/list/"+id, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.GetList(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for list %s, but got %d", expectedCode, id, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestGetSharedList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 2, Title: "Unlisted", Visibility: domain.ListVisibilityUnlisted, ShareToken: "unlisted"},
+			{ID: 2, UserID: 2, Title: "Private", Visibility: domain.ListVisibilityPrivate, ShareToken: "private"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, &mock.MockMovieService{})
+
+	for token, expectedCode := range map[string]int{
+		"unlisted": http.StatusOK,
+		"private":  http.StatusNotFound,
+		"unknown":  http.StatusNotFound,
+	} {
+		request, _ := http.NewRequest("GET", "/list/shared/"+token, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "token", Value: token}}
+
+		httpAdapter.GetSharedList(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for token %s, but got %d", expectedCode, token, mockResponseWriter.Code)
+		}
+		if expectedCode == http.StatusOK {
+			list := &HttpMovieList{}
+			if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), list); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if list.ShareLink != "" {
+				t.Errorf("Expected the share link to be hidden, but got '%s'", list.ShareLink)
+			}
+		}
+	}
+}
+
+func TestUpdateList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 1, Title: "Mine", Visibility: domain.ListVisibilityPrivate, ShareToken: "mine"},
+			{ID: 2, UserID: 2, Title: "Public", Visibility: domain.ListVisibilityPublic, ShareToken: "public"},
+		},
+	}
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, mockMovieService)
+
+	for id, expectedCode := range map[string]int{
+		"1": http.StatusOK,
+		"2": http.StatusForbidden,
+	} {
+		body, _ := json.Marshal(&HttpMovieList{
+			Title:      "Renamed",
+			Visibility: "public",
+			Entries:    []*HttpMovieListEntry{{MovieID: 1}},
+		})
+		request, _ := http.NewRequest("PUT", "/list/"+id, bytes.NewBuffer(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.UpdateList(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for list %s, but got %d", expectedCode, id, mockResponseWriter.Code)
+		}
+	}
+
+	list := mockListService.Lists[0]
+	if list.Title != "Renamed" || list.Visibility != domain.ListVisibilityPublic || list.ShareToken != "mine" {
+		t.Errorf("Expected the list to be renamed and made public with its token kept, but got %+v", list)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].MovieID != 1 {
+		t.Errorf("Expected the entries to be replaced, but got %+v", list.Entries)
+	}
+	if mockListService.Lists[1].Title != "Public" {
+		t.Errorf("Expected the list of another user to be left untouched, but got '%s'", mockListService.Lists[1].Title)
+	}
+}
+
+func TestDeleteList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 2, Title: "Public", Visibility: domain.ListVisibilityPublic},
+			{ID: 2, UserID: 1, Title: "Mine", Visibility: domain.ListVisibilityPrivate},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, &mock.MockMovieService{})
+
+	for _, testCase := range []struct {
+		id           string
+		expectedCode int
+	}{
+		{"1", http.StatusForbidden},
+		{"2", http.StatusOK},
+		{"2", http.StatusNotFound},
+	} {
+		request, _ := http.NewRequest("DELETE", "/list/"+testCase.id, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: testCase.id}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.DeleteList(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for list %s, but got %d", testCase.expectedCode, testCase.id, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockListService.Lists) != 1 {
+		t.Errorf("Expected 1 list left, but got %d", len(mockListService.Lists))
+	}
+}
+
+func TestListLists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockListService := &mock.MockMovieListService{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 1, Title: "Mine", Visibility: domain.ListVisibilityUnlisted, ShareToken: "mine", Entries: []domain.MovieListEntry{{MovieID: 1}}},
+			{ID: 2, UserID: 2, Title: "Public", Visibility: domain.ListVisibilityPublic, ShareToken: "public"},
+			{ID: 3, UserID: 2, Title: "Private", Visibility: domain.ListVisibilityPrivate, ShareToken: "private"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieListAdapter(mockListService, &mock.MockMovieService{})
+
+	request, _ := http.NewRequest("GET", "/list", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.ListLists(mockContext)
+
+	page := &HttpPage[HttpMovieList]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 2 || len(page.Items) != 2 {
+		t.Fatalf("Expected 2 lists, but got %d", page.Total)
+	}
+	if page.Items[0].ShareLink != "/list/shared/mine" || page.Items[0].EntryCount != 1 {
+		t.Errorf("Expected the own list with its share link and 1 entry, but got %+v", page.Items[0])
+	}
+	if page.Items[1].ShareLink != "" {
+		t.Errorf("Expected the share link of other users' lists to be hidden, but got '%s'", page.Items[1].ShareLink)
+	}
+}
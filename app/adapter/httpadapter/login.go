@@ -0,0 +1,83 @@
+package httpadapter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpLoginFailure is a failed login attempt, kept to audit attacks on the
+// login.
+type HttpLoginFailure struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func LoginFailureFromDomain(failure *domain.LoginFailure) *HttpLoginFailure {
+	return &HttpLoginFailure{
+		ID:        failure.ID,
+		Email:     failure.Email,
+		IPAddress: failure.IPAddress,
+		UserAgent: failure.UserAgent,
+		Reason:    string(failure.Reason),
+		CreatedAt: failure.CreatedAt,
+	}
+}
+
+type HttpLoginFailureAdapter struct {
+	loginThrottleService port.LoginThrottleService
+}
+
+func NewHttpLoginFailureAdapter(loginThrottleService port.LoginThrottleService) *HttpLoginFailureAdapter {
+	return &HttpLoginFailureAdapter{
+		loginThrottleService: loginThrottleService,
+	}
+}
+
+// @Summary List failed logins
+// @Description List the failed login attempts, most recent first. Only available to admins.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param email query string false "Only list failed logins for this email"
+// @Param ip query string false "Only list failed logins from this IP address"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of failed logins per page"
+// @Success 200 {object} HttpPage[HttpLoginFailure]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /login-failures [get]
+// @Security ApiKeyAuth
+func (a *HttpLoginFailureAdapter) ListLoginFailures(context *gin.Context) {
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := port.LoginFailureFilter{
+		Email:     context.Query("email"),
+		IPAddress: context.Query("ip"),
+	}
+	domainFailures, total, err := a.loginThrottleService.ListLoginFailures(filter, pagination)
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	failures := make([]*HttpLoginFailure, len(domainFailures))
+	for i, failure := range domainFailures {
+		failures[i] = LoginFailureFromDomain(failure)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, failures, pagination, total))
+}
@@ -0,0 +1,60 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestListLoginFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLoginThrottleService := &mock.MockLoginThrottleService{
+		Failures: []*domain.LoginFailure{
+			{ID: 1, Email: "test@test.com", IPAddress: "10.0.0.1", Reason: domain.LoginFailureInvalidCredentials},
+			{ID: 2, Email: "other@test.com", IPAddress: "10.0.0.1", Reason: domain.LoginFailureThrottled},
+			{ID: 3, Email: "test@test.com", IPAddress: "10.0.0.2", Reason: domain.LoginFailureDisabled},
+		},
+	}
+	httpAdapter := NewHttpLoginFailureAdapter(mockLoginThrottleService)
+
+	tests := []struct {
+		url           string
+		expectedCode  int
+		expectedTotal int64
+	}{
+		{"/login-failures", http.StatusOK, 3},
+		{"/login-failures?email=test@test.com", http.StatusOK, 2},
+		{"/login-failures?email=test@test.com&ip=10.0.0.2", http.StatusOK, 1},
+		{"/login-failures?page=0", http.StatusBadRequest, 0},
+	}
+
+	for _, test := range tests {
+		request, _ := http.NewRequest("GET", test.url, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.ListLoginFailures(mockContext)
+
+		if mockResponseWriter.Code != test.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", test.expectedCode, test.url, mockResponseWriter.Code)
+			continue
+		}
+		if test.expectedCode != http.StatusOK {
+			continue
+		}
+
+		page := &HttpPage[HttpLoginFailure]{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if page.Total != test.expectedTotal || int64(len(page.Items)) != test.expectedTotal {
+			t.Errorf("Expected %d failed logins for %s, but got %d", test.expectedTotal, test.url, page.Total)
+		}
+	}
+}
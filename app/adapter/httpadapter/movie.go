@@ -1,9 +1,12 @@
 package httpadapter
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
 	"github.com/Acova/movie-collection/app/port"
@@ -12,33 +15,47 @@ import (
 
 type HttpMovieAdapter struct {
 	movieService port.MovieService
+	genreService port.GenreService
 }
 
 type HttpMovie struct {
-	ID          uint    `json:"id"`
-	Title       string  `json:"title" binding:"required,min=1,max=100"`
-	Director    string  `json:"director" binding:"max=50"`
-	Synopsis    string  `json:"synopsis" binding:"max=500"`
-	ReleaseYear int     `json:"release_year"`
-	Cast        string  `json:"cast" binding:"max=200"`
-	Genre       string  `json:"genre" binding:"max=50"`
-	Rating      float64 `json:"rating" binding:"min=0,max=10"`
-	Duration    int     `json:"duration" binding:"min=0"`
-	PosterURL   string  `json:"poster_url"`
+	ID          uint      `json:"id"`
+	Title       string    `json:"title" binding:"required,min=1,max=100"`
+	Director    string    `json:"director" binding:"max=50"`
+	Synopsis    string    `json:"synopsis" binding:"max=500"`
+	ReleaseYear int       `json:"release_year"`
+	Cast        string    `json:"cast" binding:"max=200"`
+	Genres      []string  `json:"genres" binding:"max=10,dive,min=1,max=50"`
+	Rating      float64   `json:"rating" binding:"min=0,max=10"`
+	Duration    int       `json:"duration" binding:"min=0"`
+	PosterURL   string    `json:"poster_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	// ReviewCount and AverageScore summarize the reviews of the movie's
+	// viewers, and are ignored when creating or updating a movie.
+	ReviewCount  int64   `json:"review_count"`
+	AverageScore float64 `json:"average_score"`
 }
 
 func FromDomain(movie *domain.Movie) *HttpMovie {
+	genres := make([]string, len(movie.Genres))
+	for i, genre := range movie.Genres {
+		genres[i] = genre.Name
+	}
+
 	return &HttpMovie{
-		ID:          movie.ID,
-		Title:       movie.Title,
-		Director:    movie.Director,
-		Synopsis:    movie.Synopsis,
-		ReleaseYear: movie.ReleaseYear,
-		Cast:        movie.Cast,
-		Genre:       movie.Genre,
-		Rating:      movie.Rating,
-		Duration:    movie.Duration,
-		PosterURL:   movie.PosterURL,
+		ID:           movie.ID,
+		Title:        movie.Title,
+		Director:     movie.Director,
+		Synopsis:     movie.Synopsis,
+		ReleaseYear:  movie.ReleaseYear,
+		Cast:         movie.Cast,
+		Genres:       genres,
+		Rating:       movie.Rating,
+		Duration:     movie.Duration,
+		PosterURL:    movie.PosterURL,
+		CreatedAt:    movie.CreatedAt,
+		ReviewCount:  movie.ReviewCount,
+		AverageScore: movie.AverageScore,
 	}
 }
 
@@ -50,16 +67,51 @@ func (h *HttpMovie) ToDomain() *domain.Movie {
 		Synopsis:    h.Synopsis,
 		ReleaseYear: h.ReleaseYear,
 		Cast:        h.Cast,
-		Genre:       h.Genre,
 		Rating:      h.Rating,
 		Duration:    h.Duration,
 		PosterURL:   h.PosterURL,
 	}
 }
 
-func NewHttpMovieAdapter(movieService port.MovieService) *HttpMovieAdapter {
+type HttpMovieSearchResult struct {
+	Movie             *HttpMovie `json:"movie"`
+	Rank              float64    `json:"rank"`
+	TitleHighlight    string     `json:"title_highlight"`
+	SynopsisHighlight string     `json:"synopsis_highlight"`
+}
+
+func SearchResultFromDomain(result *domain.MovieSearchResult) *HttpMovieSearchResult {
+	return &HttpMovieSearchResult{
+		Movie:             FromDomain(result.Movie),
+		Rank:              result.Rank,
+		TitleHighlight:    result.TitleHighlight,
+		SynopsisHighlight: result.SynopsisHighlight,
+	}
+}
+
+type HttpMovieSuggestion struct {
+	ID          uint   `json:"id"`
+	Title       string `json:"title"`
+	ReleaseYear int    `json:"release_year"`
+}
+
+func SuggestionFromDomain(movie *domain.Movie) *HttpMovieSuggestion {
+	return &HttpMovieSuggestion{
+		ID:          movie.ID,
+		Title:       movie.Title,
+		ReleaseYear: movie.ReleaseYear,
+	}
+}
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+)
+
+func NewHttpMovieAdapter(movieService port.MovieService, genreService port.GenreService) *HttpMovieAdapter {
 	return &HttpMovieAdapter{
 		movieService: movieService,
+		genreService: genreService,
 	}
 }
 
@@ -85,13 +137,17 @@ func (h *HttpMovieAdapter) CreateMovie(context *gin.Context) {
 		return
 	}
 
-	existingMovie, err := h.movieService.ListMovies(map[string]string{"title": movie.Title})
+	_, existingMovies, err := h.movieService.ListMovies(
+		port.MovieFilter{ExactTitle: movie.Title},
+		port.Pagination{Page: 1, PageSize: 1},
+		port.Sort{},
+	)
 	if err != nil {
 		context.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
-	if len(existingMovie) > 0 {
+	if existingMovies > 0 {
 		context.IndentedJSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Movie with name `%s` already exists", movie.Title)})
 		return
 	}
@@ -102,8 +158,15 @@ func (h *HttpMovieAdapter) CreateMovie(context *gin.Context) {
 		return
 	}
 
+	genres, err := h.genreService.ResolveGenres(movie.Genres)
+	if err != nil {
+		respondGenreError(context, err)
+		return
+	}
+
 	domainMovie := movie.ToDomain()
 	domainMovie.UserID = user.ID
+	domainMovie.Genres = genres
 	err = h.movieService.CreateMovie(domainMovie)
 	if err != nil {
 		context.AbortWithError(http.StatusInternalServerError, err)
@@ -113,36 +176,53 @@ func (h *HttpMovieAdapter) CreateMovie(context *gin.Context) {
 }
 
 // @Summary List movies
-// @Description List all movies with optional filters
+// @Description List movies with optional filters, paginated and sorted
 // @Tags Movies
 // @Accept json
 // @Produce json
 // @Param title query string false "Filter by movie title"
 // @Param director query string false "Filter by movie director"
-// @Param genre query string false "Filter by movie genre"
+// @Param genre query string false "Filter by genre name"
 // @Param cast query string false "Filter by movie cast"
-// @Success 200 {array} HttpMovie
+// @Param fuzzy query bool false "Match misspelled titles and directors"
+// @Param release_year_from query int false "Minimum release year"
+// @Param release_year_to query int false "Maximum release year"
+// @Param rating_min query number false "Minimum rating"
+// @Param rating_max query number false "Maximum rating"
+// @Param duration_min query int false "Minimum duration in minutes"
+// @Param duration_max query int false "Maximum duration in minutes"
+// @Param user_id query int false "Filter by the ID of the user who added the movie"
+// @Param created_after query string false "Only movies added after this date (RFC 3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of movies per page"
+// @Param sort query string false "Sort field" Enums(title, release_year, rating, duration, created_at)
+// @Param order query string false "Sort direction" Enums(asc, desc)
+// @Success 200 {object} HttpPage[HttpMovie]
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /movies [get]
 // @Security ApiKeyAuth
 func (h *HttpMovieAdapter) ListMovies(context *gin.Context) {
-	filter := make(map[string]string)
-	if title := context.Query("title"); title != "" {
-		filter["title"] = "%" + title + "%"
-	}
-	if director := context.Query("director"); director != "" {
-		filter["director"] = "%" + director + "%"
+	filter, err := parseMovieFilter(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if genre := context.Query("genre"); genre != "" {
-		filter["genre"] = "%" + genre + "%"
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if cast := context.Query("cast"); cast != "" {
-		filter["cast"] = "%" + cast + "%"
+
+	sort, err := parseSort(context, port.MovieSortFields, port.Sort{Field: "created_at", Direction: port.SortDescending})
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	domainMovies, err := h.movieService.ListMovies(filter)
+	domainMovies, total, err := h.movieService.ListMovies(filter, pagination, sort)
 	if err != nil {
 		context.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -153,7 +233,92 @@ func (h *HttpMovieAdapter) ListMovies(context *gin.Context) {
 		movies[i] = FromDomain(movie)
 	}
 
-	context.IndentedJSON(http.StatusOK, movies)
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, movies, pagination, total))
+}
+
+// @Summary Search movies
+// @Description Full-text search across the title, synopsis, cast and director of every movie, ordered by relevance
+// @Tags Movies
+// @Accept json
+// @Produce json
+// @Param q query string true "Search query"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of results per page"
+// @Success 200 {object} HttpPage[HttpMovieSearchResult]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/search [get]
+// @Security ApiKeyAuth
+func (h *HttpMovieAdapter) SearchMovies(context *gin.Context) {
+	query := strings.TrimSpace(context.Query("q"))
+	if query == "" {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The search query `q` is required"})
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainResults, total, err := h.movieService.SearchMovies(query, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	results := make([]*HttpMovieSearchResult, len(domainResults))
+	for i, result := range domainResults {
+		results[i] = SearchResultFromDomain(result)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, results, pagination, total))
+}
+
+// @Summary Autocomplete movie titles
+// @Description Suggest movies for a partially typed title, tolerating typos
+// @Tags Movies
+// @Accept json
+// @Produce json
+// @Param prefix query string true "Beginning of the title"
+// @Param limit query int false "Maximum number of suggestions"
+// @Success 200 {array} HttpMovieSuggestion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/autocomplete [get]
+// @Security ApiKeyAuth
+func (h *HttpMovieAdapter) AutocompleteMovies(context *gin.Context) {
+	prefix := strings.TrimSpace(context.Query("prefix"))
+	if prefix == "" {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The `prefix` query parameter is required"})
+		return
+	}
+
+	limit := defaultAutocompleteLimit
+	if value := context.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxAutocompleteLimit {
+			context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid limit `%s`, must be between 1 and %d", value, maxAutocompleteLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	domainMovies, err := h.movieService.AutocompleteMovies(prefix, limit)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	suggestions := make([]*HttpMovieSuggestion, len(domainMovies))
+	for i, movie := range domainMovies {
+		suggestions[i] = SuggestionFromDomain(movie)
+	}
+
+	context.IndentedJSON(http.StatusOK, suggestions)
 }
 
 // @Summary Get a movie by ID
@@ -186,7 +351,7 @@ func (h *HttpMovieAdapter) GetMovie(context *gin.Context) {
 }
 
 // @Summary Update a movie
-// @Description Update details of a specific movie by its ID
+// @Description Update details of a specific movie by its ID. Only the user who added it, moderators and admins can update a movie.
 // @Tags Movies
 // @Accept json
 // @Produce json
@@ -219,7 +384,7 @@ func (h *HttpMovieAdapter) UpdateMovie(context *gin.Context) {
 		return
 	}
 
-	if movieToUpdate.UserID != user.ID {
+	if !canManageMovie(user, movieToUpdate, domain.PermissionEditAnyMovie) {
 		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this movie"})
 		return
 	}
@@ -230,9 +395,19 @@ func (h *HttpMovieAdapter) UpdateMovie(context *gin.Context) {
 		return
 	}
 
+	genres, err := h.genreService.ResolveGenres(updatedMovie.Genres)
+	if err != nil {
+		respondGenreError(context, err)
+		return
+	}
+
 	updatedDomainMovie := updatedMovie.ToDomain()
+	updatedDomainMovie.Genres = genres
 	updatedDomainMovie.ID = uint(id)
 	updatedDomainMovie.UserID = movieToUpdate.UserID // Preserve the user ID
+	updatedDomainMovie.CreatedAt = movieToUpdate.CreatedAt
+	updatedDomainMovie.ReviewCount = movieToUpdate.ReviewCount
+	updatedDomainMovie.AverageScore = movieToUpdate.AverageScore
 	if err := h.movieService.UpdateMovie(updatedDomainMovie); err != nil {
 		context.AbortWithError(http.StatusInternalServerError, err)
 		return
@@ -242,7 +417,7 @@ func (h *HttpMovieAdapter) UpdateMovie(context *gin.Context) {
 }
 
 // @Summary Delete a movie
-// @Description Delete a specific movie by its ID
+// @Description Delete a specific movie by its ID. Only the user who added it and admins can delete a movie.
 // @Tags Movies
 // @Accept json
 // @Produce json
@@ -274,7 +449,7 @@ func (h *HttpMovieAdapter) DeleteMovie(context *gin.Context) {
 		return
 	}
 
-	if movie.UserID != user.ID {
+	if !canManageMovie(user, movie, domain.PermissionDeleteAnyMovie) {
 		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete this movie"})
 		return
 	}
@@ -286,3 +461,20 @@ func (h *HttpMovieAdapter) DeleteMovie(context *gin.Context) {
 
 	context.IndentedJSON(http.StatusOK, gin.H{"status": "Movie deleted"})
 }
+
+// canManageMovie reports whether the user added the movie, or has a role with
+// the permission to do so on anyone's movies.
+func canManageMovie(user *domain.User, movie *domain.Movie, permission domain.Permission) bool {
+	return movie.UserID == user.ID || user.Role.Can(permission)
+}
+
+// respondGenreError reports movies referencing unknown genres as a bad
+// request, and any other error as an internal one.
+func respondGenreError(context *gin.Context, err error) {
+	var unknownGenresError *domain.UnknownGenresError
+	if errors.As(err, &unknownGenresError) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	context.AbortWithError(http.StatusInternalServerError, err)
+}
@@ -19,7 +19,7 @@ func TestHttpMovieReturnsDomainMovie(t *testing.T) {
 		Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 		ReleaseYear: 2010,
 		Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-		Genre:       "Science Fiction",
+		Genres:      []string{"Science Fiction"},
 		Rating:      8.8,
 		Duration:    148,
 		PosterURL:   "https://example.com/inception.jpg",
@@ -29,7 +29,7 @@ func TestHttpMovieReturnsDomainMovie(t *testing.T) {
 
 	if domainMovie.Title != movie.Title || domainMovie.Director != movie.Director ||
 		domainMovie.Synopsis != movie.Synopsis || domainMovie.ReleaseYear != movie.ReleaseYear ||
-		domainMovie.Cast != movie.Cast || domainMovie.Genre != movie.Genre ||
+		domainMovie.Cast != movie.Cast ||
 		domainMovie.Rating != movie.Rating || domainMovie.Duration != movie.Duration ||
 		domainMovie.PosterURL != movie.PosterURL {
 		t.Errorf("Expected domain movie to be %+v, but got %+v", movie.ToDomain(), domainMovie)
@@ -42,7 +42,7 @@ func TestCreateMovie(t *testing.T) {
 		Movies: []*domain.Movie{},
 	}
 
-	httpAdapter := NewHttpMovieAdapter(mockMovieService)
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
 
 	movie := &HttpMovie{
 		ID:          1,
@@ -51,7 +51,7 @@ func TestCreateMovie(t *testing.T) {
 		Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 		ReleaseYear: 2010,
 		Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-		Genre:       "Science Fiction",
+		Genres:      []string{"Science Fiction"},
 		Rating:      8.8,
 		Duration:    148,
 		PosterURL:   "https://example.com/inception.jpg",
@@ -94,8 +94,8 @@ func TestCreateMovie(t *testing.T) {
 	if mockMovieService.Movies[0].Cast != "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page" {
 		t.Errorf("Expected cast to be 'Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page', but got '%s'", mockMovieService.Movies[0].Cast)
 	}
-	if mockMovieService.Movies[0].Genre != "Science Fiction" {
-		t.Errorf("Expected genre to be 'Science Fiction', but got '%s'", mockMovieService.Movies[0].Genre)
+	if len(mockMovieService.Movies[0].Genres) != 1 || mockMovieService.Movies[0].Genres[0].Name != "Science Fiction" {
+		t.Errorf("Expected genres to be ['Science Fiction'], but got %v", mockMovieService.Movies[0].Genres)
 	}
 	if mockMovieService.Movies[0].Rating != 8.8 {
 		t.Errorf("Expected rating to be 8.8, but got %f", mockMovieService.Movies[0].Rating)
@@ -119,7 +119,7 @@ func TestListMovies(t *testing.T) {
 				Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 				ReleaseYear: 2010,
 				Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-				Genre:       "Science Fiction",
+				Genres:      []domain.Genre{{ID: 1, Name: "Science Fiction"}},
 				Rating:      8.8,
 				Duration:    148,
 				PosterURL:   "https://example.com/inception.jpg",
@@ -127,7 +127,7 @@ func TestListMovies(t *testing.T) {
 		},
 	}
 
-	httpAdapter := NewHttpMovieAdapter(mockMovieService)
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
 
 	request, _ := http.NewRequest("GET", "/movie", nil)
 	mockResponseWriter := httptest.NewRecorder()
@@ -142,10 +142,14 @@ func TestListMovies(t *testing.T) {
 	mockContext.Set("id", loginUser)
 
 	httpAdapter.ListMovies(mockContext)
-	movies := []*HttpMovie{}
-	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &movies); err != nil {
+	page := &HttpPage[HttpMovie]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
 		t.Errorf("Failed to unmarshal response: %v", err)
 	}
+	if page.Total != 1 {
+		t.Errorf("Expected total to be 1, but got %d", page.Total)
+	}
+	movies := page.Items
 	if len(movies) != 1 {
 		t.Errorf("Expected 1 movie, but got %d", len(movies))
 	}
@@ -164,8 +168,8 @@ func TestListMovies(t *testing.T) {
 	if movies[0].Cast != "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page" {
 		t.Errorf("Expected cast to be 'Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page', but got '%s'", movies[0].Cast)
 	}
-	if movies[0].Genre != "Science Fiction" {
-		t.Errorf("Expected genre to be 'Science Fiction', but got '%s'", movies[0].Genre)
+	if len(movies[0].Genres) != 1 || movies[0].Genres[0] != "Science Fiction" {
+		t.Errorf("Expected genres to be ['Science Fiction'], but got %v", movies[0].Genres)
 	}
 	if movies[0].Rating != 8.8 {
 		t.Errorf("Expected rating to be 8.8, but got %f", movies[0].Rating)
@@ -178,6 +182,240 @@ func TestListMovies(t *testing.T) {
 	}
 }
 
+func TestListMoviesPaginationAndSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", Rating: 8.8},
+			{ID: 2, Title: "The Matrix", Rating: 8.7},
+			{ID: 3, Title: "The Godfather", Rating: 9.2},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie?sort=rating&order=desc&page=2&page_size=1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListMovies(mockContext)
+
+	page := &HttpPage[HttpMovie]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 3 {
+		t.Errorf("Expected total to be 3, but got %d", page.Total)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("Expected 1 movie, but got %d", len(page.Items))
+	}
+	if page.Items[0].Title != "Inception" {
+		t.Errorf("Expected title to be 'Inception', but got '%s'", page.Items[0].Title)
+	}
+	if page.Next != "/movie?order=desc&page=3&page_size=1&sort=rating" {
+		t.Errorf("Unexpected next link '%s'", page.Next)
+	}
+	if page.Prev != "/movie?order=desc&page=1&page_size=1&sort=rating" {
+		t.Errorf("Unexpected prev link '%s'", page.Prev)
+	}
+}
+
+func TestListMoviesInvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpMovieAdapter(&mock.MockMovieService{}, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie?sort=poster_url", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListMovies(mockContext)
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+}
+
+func TestListMoviesFiltered(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", ReleaseYear: 2010, Duration: 148, UserID: 1},
+			{ID: 2, Title: "The Matrix", ReleaseYear: 1999, Duration: 136, UserID: 2},
+			{ID: 3, Title: "The Godfather", ReleaseYear: 1972, Duration: 175, UserID: 1},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie?release_year_to=2000&duration_max=150&title=matrix", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListMovies(mockContext)
+
+	page := &HttpPage[HttpMovie]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("Expected total to be 1, but got %d", page.Total)
+	}
+	if page.Items[0].Title != "The Matrix" {
+		t.Errorf("Expected title to be 'The Matrix', but got '%s'", page.Items[0].Title)
+	}
+}
+
+func TestListMoviesInvalidFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpMovieAdapter(&mock.MockMovieService{}, newMockGenreService())
+
+	for _, query := range []string{
+		"release_year_from=abc",
+		"rating_min=11",
+		"rating_min=NaN",
+		"rating_max=nan",
+		"rating_min=Inf",
+		"rating_max=-Inf",
+		"rating_min=9&rating_max=8",
+		"duration_min=-1",
+		"user_id=0",
+		"created_after=yesterday",
+	} {
+		request, _ := http.NewRequest("GET", "/movie?"+query, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.ListMovies(mockContext)
+
+		if mockResponseWriter.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for `%s`, but got %d", http.StatusBadRequest, query, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestSearchMovies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", Director: "Christopher Nolan"},
+			{ID: 2, Title: "The Godfather", Director: "Francis Ford Coppola", Cast: "Marlon Brando, Al Pacino"},
+			{ID: 3, Title: "The Godfather Part II", Director: "Francis Ford Coppola", Cast: "Al Pacino, Robert De Niro"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie/search?q=godfathers+niro", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.SearchMovies(mockContext)
+
+	page := &HttpPage[HttpMovieSearchResult]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 2 {
+		t.Fatalf("Expected total to be 2, but got %d", page.Total)
+	}
+	if page.Items[0].Movie.Title != "The Godfather Part II" {
+		t.Errorf("Expected best match to be 'The Godfather Part II', but got '%s'", page.Items[0].Movie.Title)
+	}
+	if page.Items[0].TitleHighlight != "The <b>Godfather</b> Part II" {
+		t.Errorf("Unexpected title highlight '%s'", page.Items[0].TitleHighlight)
+	}
+}
+
+func TestSearchMoviesRequiresQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpMovieAdapter(&mock.MockMovieService{}, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie/search?q=", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.SearchMovies(mockContext)
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+}
+
+func TestListMoviesFuzzy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", Director: "Francis Ford Coppola"},
+			{ID: 2, Title: "The Matrix", Director: "The Wachowskis"},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	for query, expected := range map[string]int64{
+		"/movie?title=Godfahter":                                0,
+		"/movie?title=Godfahter&fuzzy=true":                     1,
+		"/movie?director=Copola&fuzzy=true":                     1,
+		"/movie?title=Godfahter&director=Wachowskis&fuzzy=true": 0,
+	} {
+		request, _ := http.NewRequest("GET", query, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.ListMovies(mockContext)
+
+		page := &HttpPage[HttpMovie]{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if page.Total != expected {
+			t.Errorf("Expected %d movies for `%s`, but got %d", expected, query, page.Total)
+		}
+	}
+}
+
+func TestAutocompleteMovies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", ReleaseYear: 1972},
+			{ID: 2, Title: "The Matrix", ReleaseYear: 1999},
+			{ID: 3, Title: "Godzilla", ReleaseYear: 1954},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie/autocomplete?prefix=Godf", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.AutocompleteMovies(mockContext)
+
+	suggestions := []*HttpMovieSuggestion{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &suggestions); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(suggestions) != 2 {
+		t.Fatalf("Expected 2 suggestions, but got %d", len(suggestions))
+	}
+	if suggestions[0].Title != "The Godfather" {
+		t.Errorf("Expected first suggestion to be 'The Godfather', but got '%s'", suggestions[0].Title)
+	}
+	if suggestions[1].Title != "Godzilla" {
+		t.Errorf("Expected second suggestion to be 'Godzilla', but got '%s'", suggestions[1].Title)
+	}
+}
+
 func TestGetMovie(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockMovieService := &mock.MockMovieService{
@@ -189,7 +427,7 @@ func TestGetMovie(t *testing.T) {
 				Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 				ReleaseYear: 2010,
 				Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-				Genre:       "Science Fiction",
+				Genres:      []domain.Genre{{ID: 1, Name: "Science Fiction"}},
 				Rating:      8.8,
 				Duration:    148,
 				PosterURL:   "https://example.com/inception.jpg",
@@ -197,7 +435,7 @@ func TestGetMovie(t *testing.T) {
 		},
 	}
 
-	httpAdapter := NewHttpMovieAdapter(mockMovieService)
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
 
 	request, _ := http.NewRequest("GET", "/movie/1", nil)
 	mockResponseWriter := httptest.NewRecorder()
@@ -233,8 +471,8 @@ func TestGetMovie(t *testing.T) {
 	if movie.Cast != "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page" {
 		t.Errorf("Expected cast to be 'Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page', but got '%s'", movie.Cast)
 	}
-	if movie.Genre != "Science Fiction" {
-		t.Errorf("Expected genre to be 'Science Fiction', but got '%s'", movie.Genre)
+	if len(movie.Genres) != 1 || movie.Genres[0] != "Science Fiction" {
+		t.Errorf("Expected genres to be ['Science Fiction'], but got %v", movie.Genres)
 	}
 	if movie.Rating != 8.8 {
 		t.Errorf("Expected rating to be 8.8, but got %f", movie.Rating)
@@ -257,7 +495,7 @@ func TestUpdateMovie(t *testing.T) {
 				Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 				ReleaseYear: 2010,
 				Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-				Genre:       "Science Fiction",
+				Genres:      []domain.Genre{{ID: 1, Name: "Science Fiction"}},
 				Rating:      8.8,
 				Duration:    148,
 				PosterURL:   "https://example.com/inception.jpg",
@@ -273,13 +511,13 @@ func TestUpdateMovie(t *testing.T) {
 		Synopsis:    "An updated synopsis for Inception.",
 		ReleaseYear: 2010,
 		Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-		Genre:       "Science Fiction",
+		Genres:      []string{"Science Fiction"},
 		Rating:      9.0,
 		Duration:    150,
 		PosterURL:   "https://example.com/inception_updated.jpg",
 	}
 
-	httpAdapter := NewHttpMovieAdapter(mockMovieService)
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
 
 	body, err := json.Marshal(movie)
 	if err != nil {
@@ -316,6 +554,46 @@ func TestUpdateMovie(t *testing.T) {
 	}
 }
 
+func TestUpdateMovieOfAnotherUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for role, expectedCode := range map[domain.Role]int{
+		domain.RoleUser:      http.StatusForbidden,
+		domain.RoleModerator: http.StatusOK,
+		domain.RoleAdmin:     http.StatusOK,
+	} {
+		mockMovieService := &mock.MockMovieService{
+			Movies: []*domain.Movie{
+				{ID: 1, Title: "Inception", Director: "Christopher Nolan", ReleaseYear: 2010, UserID: 2},
+			},
+		}
+
+		httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+		body, _ := json.Marshal(&HttpMovie{
+			Title:       "Inception Updated",
+			Director:    "Christopher Nolan",
+			ReleaseYear: 2010,
+			Genres:      []string{"Science Fiction"},
+		})
+		request, _ := http.NewRequest("PUT", "/movie/1", bytes.NewBuffer(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User", Role: role})
+
+		httpAdapter.UpdateMovie(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for role '%s', but got %d", expectedCode, role, mockResponseWriter.Code)
+		}
+		if expectedCode == http.StatusOK && mockMovieService.Movies[0].UserID != 2 {
+			t.Errorf("Expected the movie to keep its owner, but got user %d", mockMovieService.Movies[0].UserID)
+		}
+	}
+}
+
 func TestDeleteMovie(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockMovieService := &mock.MockMovieService{
@@ -327,7 +605,7 @@ func TestDeleteMovie(t *testing.T) {
 				Synopsis:    "A thief who steals corporate secrets through the use of dream-sharing technology is given the inverse task of planting an idea into the mind of a CEO.",
 				ReleaseYear: 2010,
 				Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt, Ellen Page",
-				Genre:       "Science Fiction",
+				Genres:      []domain.Genre{{ID: 1, Name: "Science Fiction"}},
 				Rating:      8.8,
 				Duration:    148,
 				PosterURL:   "https://example.com/inception.jpg",
@@ -336,7 +614,7 @@ func TestDeleteMovie(t *testing.T) {
 		},
 	}
 
-	httpAdapter := NewHttpMovieAdapter(mockMovieService)
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
 
 	request, _ := http.NewRequest("DELETE", "/movie/1", nil)
 	mockResponseWriter := httptest.NewRecorder()
@@ -357,3 +635,107 @@ func TestDeleteMovie(t *testing.T) {
 		t.Errorf("Expected no movies in service after deletion, but got %d", len(mockMovieService.Movies))
 	}
 }
+
+func newMockGenreService() *mock.MockGenreService {
+	return &mock.MockGenreService{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Science Fiction"},
+			{ID: 2, Name: "Drama"},
+		},
+	}
+}
+
+func TestCreateMovieUnknownGenre(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	movie := &HttpMovie{
+		Title:  "Inception",
+		Genres: []string{"Science Fiction", "Sci-Fy"},
+	}
+
+	body, err := json.Marshal(movie)
+	if err != nil {
+		t.Fatalf("Failed to marshal movie: %v", err)
+	}
+
+	request, _ := http.NewRequest("POST", "/movie", bytes.NewBuffer(body))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.CreateMovie(mockContext)
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+	if len(mockMovieService.Movies) != 0 {
+		t.Errorf("Expected no movies in service, but got %d", len(mockMovieService.Movies))
+	}
+}
+
+func TestListMoviesByGenre(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", Genres: []domain.Genre{{ID: 1, Name: "Science Fiction"}}},
+			{ID: 2, Title: "The Godfather", Genres: []domain.Genre{{ID: 2, Name: "Drama"}}},
+		},
+	}
+
+	httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+	request, _ := http.NewRequest("GET", "/movie?genre=drama", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListMovies(mockContext)
+
+	page := &HttpPage[HttpMovie]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("Expected total to be 1, but got %d", page.Total)
+	}
+	if page.Items[0].Title != "The Godfather" {
+		t.Errorf("Expected title to be 'The Godfather', but got '%s'", page.Items[0].Title)
+	}
+}
+
+func TestDeleteMovieOfAnotherUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for role, expectedCode := range map[domain.Role]int{
+		domain.RoleUser:      http.StatusForbidden,
+		domain.RoleModerator: http.StatusForbidden,
+		domain.RoleAdmin:     http.StatusOK,
+	} {
+		mockMovieService := &mock.MockMovieService{
+			Movies: []*domain.Movie{
+				{ID: 1, Title: "Inception", UserID: 2},
+			},
+		}
+
+		httpAdapter := NewHttpMovieAdapter(mockMovieService, newMockGenreService())
+
+		request, _ := http.NewRequest("DELETE", "/movie/1", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User", Role: role})
+
+		httpAdapter.DeleteMovie(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for role '%s', but got %d", expectedCode, role, mockResponseWriter.Code)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpOIDCProviders are the OpenID Connect providers users can log in with.
+type HttpOIDCProviders struct {
+	Providers []string `json:"providers"`
+}
+
+// HttpTwoFactorChallengeResponse is what users with two-factor authentication
+// get instead of a token, to complete the login at /login/2fa.
+type HttpTwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	Expire            string `json:"expire"`
+}
+
+// @Summary List the login providers
+// @Description List the OpenID Connect providers users can log in with, besides their password. No authentication is needed.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} HttpOIDCProviders
+// @Router /login/oidc [get]
+func (a *HttpAuthAdapter) ListOIDCProviders(context *gin.Context) {
+	context.IndentedJSON(http.StatusOK, &HttpOIDCProviders{Providers: a.oidcService.ProviderNames()})
+}
+
+// @Summary Log in with a provider
+// @Description Redirect to an OpenID Connect provider to sign in there. The provider sends the user back to the redirect URL configured for it, with the code and state to complete the login with. No authentication is needed.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /login/oidc/{provider} [get]
+func (a *HttpAuthAdapter) StartOIDCLogin(context *gin.Context) {
+	authorizationURL, err := a.oidcService.StartLogin(context.Param("provider"))
+	if errors.Is(err, domain.ErrOIDCProviderNotFound) {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.Redirect(http.StatusFound, authorizationURL)
+}
+
+// @Summary Complete a login with a provider
+// @Description Exchange the code and state an OpenID Connect provider sent the user back with for an access token and a refresh token. The first login of a user links them to the account with their email, or creates one, provided the provider has verified the email. Users with two-factor authentication get a challenge token instead, in the same format as /login, to complete the login at /login/2fa. No authentication is needed.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code given by the provider"
+// @Param state query string true "State given by the provider"
+// @Success 200 {object} HttpTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /login/oidc/{provider}/callback [get]
+func (a *HttpAuthAdapter) CompleteOIDCLogin(context *gin.Context) {
+	// The provider sends the user back with an error when they did not sign in
+	if providerError := context.Query("error"); providerError != "" {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": domain.ErrOIDCLoginFailed.Error() + ": " + providerError})
+		return
+	}
+
+	code := context.Query("code")
+	state := context.Query("state")
+	if code == "" || state == "" {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": "code and state are required"})
+		return
+	}
+
+	user, err := a.oidcService.CompleteLogin(context.Param("provider"), state, code)
+	if errors.Is(err, domain.ErrOIDCProviderNotFound) {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidOIDCState) || errors.Is(err, domain.ErrOIDCLoginFailed) || errors.Is(err, domain.ErrOIDCEmailNotVerified) || errors.Is(err, domain.ErrUserDisabled) {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	twoFactorEnabled, err := a.twoFactorService.TwoFactorEnabled(user.ID)
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if twoFactorEnabled {
+		challengeToken, expiresAt, err := a.twoFactorService.CreateChallenge(user.ID)
+		if err != nil {
+			context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		context.IndentedJSON(http.StatusOK, &HttpTwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			Expire:            expiresAt.Format(time.RFC3339),
+		})
+		return
+	}
+
+	session, refreshToken, err := a.sessionService.CreateSession(user.ID, context.Request.UserAgent(), context.ClientIP())
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	a.respondWithToken(context, user, session, refreshToken)
+}
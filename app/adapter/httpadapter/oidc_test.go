@@ -0,0 +1,110 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListOIDCProviders(t *testing.T) {
+	server := newTestAuthServer(t)
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/login/oidc", nil)
+	server.engine.ServeHTTP(response, request)
+
+	providers := &HttpOIDCProviders{}
+	json.Unmarshal(response.Body.Bytes(), providers)
+	if response.Code != http.StatusOK || len(providers.Providers) != 1 || providers.Providers[0] != "company" {
+		t.Errorf("Expected the company provider, but got status %d and %v", response.Code, providers.Providers)
+	}
+}
+
+func TestStartOIDCLogin(t *testing.T) {
+	server := newTestAuthServer(t)
+
+	tests := []struct {
+		url              string
+		expectedCode     int
+		expectedLocation string
+	}{
+		{"/login/oidc/company", http.StatusFound, "https://company.example.com/authorize"},
+		{"/login/oidc/other", http.StatusNotFound, ""},
+	}
+
+	for _, test := range tests {
+		response := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", test.url, nil)
+		server.engine.ServeHTTP(response, request)
+
+		if response.Code != test.expectedCode || response.Header().Get("Location") != test.expectedLocation {
+			t.Errorf("Expected status %d and location %q for %s, but got %d and %q", test.expectedCode, test.expectedLocation, test.url, response.Code, response.Header().Get("Location"))
+		}
+	}
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	server := newTestAuthServer(t)
+
+	tests := []struct {
+		url          string
+		expectedCode int
+	}{
+		{"/login/oidc/company/callback?code=code&state=state", http.StatusOK},
+		{"/login/oidc/company/callback?code=wrong&state=state", http.StatusUnauthorized},
+		{"/login/oidc/company/callback?error=access_denied&state=state", http.StatusUnauthorized},
+		{"/login/oidc/company/callback?code=code", http.StatusBadRequest},
+		{"/login/oidc/other/callback?code=code&state=state", http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		response := httptest.NewRecorder()
+		request, _ := http.NewRequest("GET", test.url, nil)
+		server.engine.ServeHTTP(response, request)
+
+		if response.Code != test.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", test.expectedCode, test.url, response.Code)
+		}
+	}
+
+	tokens := &HttpTokenResponse{}
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/login/oidc/company/callback?code=code&state=state", nil)
+	server.engine.ServeHTTP(response, request)
+	json.Unmarshal(response.Body.Bytes(), tokens)
+	if tokens.Token == "" || tokens.RefreshToken == "" {
+		t.Fatalf("Expected an access token and a refresh token, but got %s", response.Body.String())
+	}
+	if code := server.call("GET", "/private", tokens.Token); code != http.StatusOK {
+		t.Errorf("Expected the token to be accepted, but got status %d", code)
+	}
+
+	server.userService.Users[0].DisableDate = time.Now()
+	if code := server.call("GET", "/login/oidc/company/callback?code=code&state=state", ""); code != http.StatusUnauthorized {
+		t.Errorf("Expected a disabled user to be rejected, but got status %d", code)
+	}
+}
+
+func TestCompleteOIDCLoginAsksForTwoFactorCode(t *testing.T) {
+	server := newTestAuthServer(t)
+	server.twoFactorService.Enabled = map[uint]bool{1: true}
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest("GET", "/login/oidc/company/callback?code=code&state=state", nil)
+	server.engine.ServeHTTP(response, request)
+
+	challenge := &HttpTwoFactorChallengeResponse{}
+	json.Unmarshal(response.Body.Bytes(), challenge)
+	if response.Code != http.StatusOK || !challenge.TwoFactorRequired || challenge.ChallengeToken == "" {
+		t.Fatalf("Expected a challenge token, but got status %d and %s", response.Code, response.Body.String())
+	}
+	if len(server.sessionService.Sessions) != 0 {
+		t.Errorf("Expected no session before the challenge is completed")
+	}
+
+	if code, tokens := server.post("/login/2fa", `{"challenge_token": "`+challenge.ChallengeToken+`", "code": "123456"}`); code != http.StatusOK || tokens.Token == "" {
+		t.Errorf("Expected to complete the login, but got status %d", code)
+	}
+}
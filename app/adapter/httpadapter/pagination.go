@@ -0,0 +1,120 @@
+package httpadapter
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpPage is a page of a listing, along with links to the next and previous
+// pages when there are any.
+type HttpPage[T any] struct {
+	Items    []*T   `json:"items"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+	Total    int64  `json:"total"`
+	Next     string `json:"next,omitempty"`
+	Prev     string `json:"prev,omitempty"`
+}
+
+func NewHttpPage[T any](requestURL *url.URL, items []*T, pagination port.Pagination, total int64) *HttpPage[T] {
+	page := &HttpPage[T]{
+		Items:    items,
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Total:    total,
+	}
+
+	page.Next, page.Prev = pageLinks(requestURL, pagination, len(items), total)
+	return page
+}
+
+// pageLinks returns the links to the next and previous pages, leaving them
+// empty when there is no such page.
+func pageLinks(requestURL *url.URL, pagination port.Pagination, count int, total int64) (next, prev string) {
+	if int64(pagination.Offset()+count) < total {
+		next = pageLink(requestURL, pagination.Page+1)
+	}
+	if pagination.Page > 1 {
+		prev = pageLink(requestURL, pagination.Page-1)
+	}
+	return next, prev
+}
+
+// pageLink returns the request path and query with the `page` parameter
+// replaced by the given page number.
+func pageLink(requestURL *url.URL, page int) string {
+	query := requestURL.Query()
+	query.Set("page", strconv.Itoa(page))
+
+	link := url.URL{
+		Path:     requestURL.Path,
+		RawQuery: query.Encode(),
+	}
+	return link.String()
+}
+
+// maxPageOffset is the largest number of rows a page may skip.
+const maxPageOffset = math.MaxInt32 - 1
+
+// parsePagination reads the `page` and `page_size` query parameters, falling
+// back to the first page and the default page size when they are missing.
+func parsePagination(context *gin.Context) (port.Pagination, error) {
+	pagination := port.Pagination{
+		Page:     1,
+		PageSize: port.DefaultPageSize,
+	}
+
+	if page := context.Query("page"); page != "" {
+		value, err := strconv.Atoi(page)
+		if err != nil || value < 1 {
+			return pagination, fmt.Errorf("invalid page `%s`", page)
+		}
+		pagination.Page = value
+	}
+
+	if pageSize := context.Query("page_size"); pageSize != "" {
+		value, err := strconv.Atoi(pageSize)
+		if err != nil || value < 1 || value > port.MaxPageSize {
+			return pagination, fmt.Errorf("invalid page_size `%s`, must be between 1 and %d", pageSize, port.MaxPageSize)
+		}
+		pagination.PageSize = value
+	}
+
+	// Pages are capped so that the offset neither overflows nor makes the
+	// database skip an unreasonable number of rows
+	if maxPage := maxPageOffset/pagination.PageSize + 1; pagination.Page > maxPage {
+		return pagination, fmt.Errorf("invalid page `%d`, must be at most %d", pagination.Page, maxPage)
+	}
+
+	return pagination, nil
+}
+
+// parseSort reads the `sort` and `order` query parameters. The sort field must
+// be one of the allowed fields, and the order either `asc` or `desc`.
+func parseSort(context *gin.Context, allowedFields []string, defaultSort port.Sort) (port.Sort, error) {
+	sort := defaultSort
+
+	if field := context.Query("sort"); field != "" {
+		if !slices.Contains(allowedFields, field) {
+			return sort, fmt.Errorf("invalid sort `%s`, must be one of %s", field, strings.Join(allowedFields, ", "))
+		}
+		sort.Field = field
+	}
+
+	if order := context.Query("order"); order != "" {
+		direction := port.SortDirection(strings.ToLower(order))
+		if direction != port.SortAscending && direction != port.SortDescending {
+			return sort, fmt.Errorf("invalid order `%s`, must be asc or desc", order)
+		}
+		sort.Direction = direction
+	}
+
+	return sort, nil
+}
@@ -0,0 +1,38 @@
+package httpadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for query, expectError := range map[string]bool{
+		"":                                     false,
+		"page=2&page_size=50":                  false,
+		"page=107374183&page_size=20":          false,
+		"page=107374184&page_size=20":          true,
+		"page=2147483647&page_size=1":          false,
+		"page=9223372036854775807":             true,
+		"page=4611686018427387904&page_size=2": true,
+		"page=0":                               true,
+		"page_size=101":                        true,
+	} {
+		request, _ := http.NewRequest("GET", "/movie?"+query, nil)
+		mockContext, _ := gin.CreateTestContext(httptest.NewRecorder())
+		mockContext.Request = request
+
+		pagination, err := parsePagination(mockContext)
+		if (err != nil) != expectError {
+			t.Errorf("Expected an error for `%s` to be %v, but got %v", query, expectError, err)
+			continue
+		}
+		if err == nil && (pagination.Offset() < 0 || pagination.Offset() >= maxPageOffset+1) {
+			t.Errorf("Expected a bounded offset for `%s`, but got %d", query, pagination.Offset())
+		}
+	}
+}
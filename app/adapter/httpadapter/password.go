@@ -0,0 +1,123 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpPasswordForgot struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type HttpPasswordReset struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// HttpPasswordRejection is a reason a password was rejected.
+type HttpPasswordRejection struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// HttpPasswordPolicyError is the response to a password that does not meet the
+// password policy, with every reason it does not.
+type HttpPasswordPolicyError struct {
+	Error   string                  `json:"error"`
+	Reasons []HttpPasswordRejection `json:"reasons"`
+}
+
+type HttpPasswordAdapter struct {
+	passwordResetService port.PasswordResetService
+}
+
+func NewHttpPasswordAdapter(passwordResetService port.PasswordResetService) *HttpPasswordAdapter {
+	return &HttpPasswordAdapter{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// @Summary Request a password reset
+// @Description Email a password reset link, valid for an hour, to the user with the given email. The response is the same whether the email is registered or not. No authentication is needed.
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param forgot body HttpPasswordForgot true "Email of the account"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /password/forgot [post]
+func (a *HttpPasswordAdapter) ForgotPassword(context *gin.Context) {
+	forgot := HttpPasswordForgot{}
+	if err := context.BindJSON(&forgot); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := a.passwordResetService.RequestPasswordReset(forgot.Email); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusAccepted, gin.H{"status": "If the email is registered, a reset link has been sent"})
+}
+
+// @Summary Reset a password
+// @Description Choose a new password with the token of a reset link. Each token can only be used once, and using it invalidates every other reset token of the user. Every JWT token issued to the user until now is revoked. No authentication is needed.
+// @Tags Password
+// @Accept json
+// @Produce json
+// @Param reset body HttpPasswordReset true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} HttpPasswordPolicyError
+// @Failure 500 {object} map[string]string
+// @Router /password/reset [post]
+func (a *HttpPasswordAdapter) ResetPassword(context *gin.Context) {
+	reset := HttpPasswordReset{}
+	if err := context.BindJSON(&reset); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := a.passwordResetService.ResetPassword(reset.Token, reset.NewPassword)
+	if errors.Is(err, domain.ErrInvalidResetToken) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if respondPasswordPolicyError(context, err) {
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Password changed"})
+}
+
+// respondPasswordPolicyError reports a password that does not meet the
+// password policy as a bad request, along with the reasons, and returns
+// whether err was about one.
+func respondPasswordPolicyError(context *gin.Context, err error) bool {
+	var policyError *domain.PasswordPolicyError
+	if !errors.As(err, &policyError) {
+		return false
+	}
+
+	reasons := make([]HttpPasswordRejection, len(policyError.Rejections))
+	for i, rejection := range policyError.Rejections {
+		reasons[i] = HttpPasswordRejection{
+			Code:    string(rejection.Code),
+			Message: rejection.Message,
+		}
+	}
+	context.IndentedJSON(http.StatusBadRequest, &HttpPasswordPolicyError{
+		Error:   domain.ErrPasswordRejected.Error(),
+		Reasons: reasons,
+	})
+	return true
+}
@@ -0,0 +1,124 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPasswordResetService := &mock.MockPasswordResetService{}
+
+	httpAdapter := NewHttpPasswordAdapter(mockPasswordResetService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{}`, http.StatusBadRequest},
+		{`{"email": "not an email"}`, http.StatusBadRequest},
+		{`{"email": "test@test.com"}`, http.StatusAccepted},
+		{`{"email": "unknown@test.com"}`, http.StatusAccepted},
+	} {
+		request, _ := http.NewRequest("POST", "/password/forgot", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.ForgotPassword(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockPasswordResetService.Requests) != 2 {
+		t.Errorf("Expected 2 reset requests, but got %d", len(mockPasswordResetService.Requests))
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPasswordResetService := &mock.MockPasswordResetService{
+		Tokens:         map[string]uint{"valid-token": 1},
+		PasswordPolicy: shortPasswordPolicy(),
+	}
+
+	httpAdapter := NewHttpPasswordAdapter(mockPasswordResetService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"token": "valid-token", "new_password": "short"}`, http.StatusBadRequest},
+		{`{"token": "wrong-token", "new_password": "newpassword"}`, http.StatusBadRequest},
+		{`{"token": "valid-token", "new_password": "newpassword"}`, http.StatusOK},
+		{`{"token": "valid-token", "new_password": "otherpassword"}`, http.StatusBadRequest},
+	} {
+		request, _ := http.NewRequest("POST", "/password/reset", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.ResetPassword(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if mockPasswordResetService.Passwords[1] != "newpassword" {
+		t.Errorf("Expected the password of user 1 to be 'newpassword', but got '%s'", mockPasswordResetService.Passwords[1])
+	}
+}
+
+// shortPasswordPolicy rejects the password "short" as too short.
+func shortPasswordPolicy() *mock.MockPasswordPolicyService {
+	return &mock.MockPasswordPolicyService{
+		Rejections: map[string][]domain.PasswordRejection{
+			"short": {{Code: domain.PasswordTooShort, Message: "password must be at least 8 characters long"}},
+		},
+	}
+}
+
+func TestRespondPasswordPolicyError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+
+	err := &domain.PasswordPolicyError{Rejections: []domain.PasswordRejection{
+		{Code: domain.PasswordTooWeak, Message: "password is too easy to guess"},
+		{Code: domain.PasswordBreached, Message: "password has appeared in a data breach"},
+	}}
+	if !respondPasswordPolicyError(mockContext, err) {
+		t.Fatalf("Expected the policy error to be responded to")
+	}
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+	response := HttpPasswordPolicyError{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not decode the response: %v", err)
+	}
+	if response.Error != domain.ErrPasswordRejected.Error() {
+		t.Errorf("Expected the error %q, but got %q", domain.ErrPasswordRejected.Error(), response.Error)
+	}
+	if len(response.Reasons) != 2 || response.Reasons[0].Code != "too_weak" || response.Reasons[1].Code != "breached" {
+		t.Errorf("Expected the too_weak and breached reasons, but got %+v", response.Reasons)
+	}
+	if response.Reasons[0].Message != "password is too easy to guess" {
+		t.Errorf("Expected the message of the reason, but got %q", response.Reasons[0].Message)
+	}
+
+	if respondPasswordPolicyError(mockContext, domain.ErrWrongPassword) || respondPasswordPolicyError(mockContext, nil) {
+		t.Errorf("Expected other errors to be left alone")
+	}
+}
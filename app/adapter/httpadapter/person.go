@@ -0,0 +1,256 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpPersonAdapter struct {
+	personService port.PersonService
+}
+
+type HttpPerson struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	Biography string `json:"biography" binding:"max=2000"`
+}
+
+func PersonFromDomain(person *domain.Person) *HttpPerson {
+	return &HttpPerson{
+		ID:        person.ID,
+		Name:      person.Name,
+		Biography: person.Biography,
+	}
+}
+
+func (p *HttpPerson) ToDomain() *domain.Person {
+	return &domain.Person{
+		ID:        p.ID,
+		Name:      p.Name,
+		Biography: p.Biography,
+	}
+}
+
+func NewHttpPersonAdapter(personService port.PersonService) *HttpPersonAdapter {
+	return &HttpPersonAdapter{
+		personService: personService,
+	}
+}
+
+// @Summary Create a person
+// @Description Add a new person who can be credited in movies
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param person body HttpPerson true "Person object"
+// @Success 201 {object} HttpPerson
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /person [post]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) CreatePerson(context *gin.Context) {
+	person := HttpPerson{}
+	if err := context.BindJSON(&person); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainPerson := person.ToDomain()
+	if err := h.personService.CreatePerson(domainPerson); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, PersonFromDomain(domainPerson))
+}
+
+// @Summary List people
+// @Description List people sorted by name, optionally filtered by a part of their name
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param name query string false "Filter by name (partial match)"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of people per page"
+// @Success 200 {object} HttpPage[HttpPerson]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /person [get]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) ListPeople(context *gin.Context) {
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainPeople, total, err := h.personService.ListPeople(context.Query("name"), pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	people := make([]*HttpPerson, len(domainPeople))
+	for i, person := range domainPeople {
+		people[i] = PersonFromDomain(person)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, people, pagination, total))
+}
+
+// @Summary Get a person
+// @Description Get details of a specific person by their ID
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} HttpPerson
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /person/{id} [get]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) GetPerson(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	person, err := h.personService.GetPerson(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, PersonFromDomain(person))
+}
+
+// @Summary Update a person
+// @Description Update details of a specific person by their ID
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Param person body HttpPerson true "Updated person object"
+// @Success 200 {object} HttpPerson
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /person/{id} [put]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) UpdatePerson(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := h.personService.GetPerson(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	updatedPerson := HttpPerson{}
+	if err := context.BindJSON(&updatedPerson); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainPerson := updatedPerson.ToDomain()
+	domainPerson.ID = uint(id)
+	if err := h.personService.UpdatePerson(domainPerson); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, PersonFromDomain(domainPerson))
+}
+
+// @Summary Delete a person
+// @Description Delete a specific person by their ID, once they are no longer credited in any movie
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /person/{id} [delete]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) DeletePerson(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	person, err := h.personService.GetPerson(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if err := h.personService.DeletePerson(person); err != nil {
+		if errors.Is(err, domain.ErrPersonHasCredits) {
+			context.IndentedJSON(http.StatusConflict, gin.H{"error": "Person is still credited in movies, remove their credits first"})
+			return
+		}
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Person deleted"})
+}
+
+// @Summary Get a person's filmography
+// @Description List the movies a person is credited in, newest first, along with their role
+// @Tags People
+// @Accept json
+// @Produce json
+// @Param id path int true "Person ID"
+// @Success 200 {array} HttpCredit
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /person/{id}/movies [get]
+// @Security ApiKeyAuth
+func (h *HttpPersonAdapter) ListPersonMovies(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := h.personService.GetPerson(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	domainCredits, err := h.personService.ListPersonCredits(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	credits := make([]*HttpCredit, len(domainCredits))
+	for i, credit := range domainCredits {
+		credits[i] = CreditFromDomain(credit)
+	}
+
+	context.IndentedJSON(http.StatusOK, credits)
+}
@@ -0,0 +1,194 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreatePerson(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{}
+
+	httpAdapter := NewHttpPersonAdapter(mockPersonService)
+
+	for name, expectedCode := range map[string]int{
+		"Al Pacino": http.StatusCreated,
+		"":          http.StatusBadRequest,
+	} {
+		body, _ := json.Marshal(&HttpPerson{Name: name})
+		request, _ := http.NewRequest("POST", "/person", bytes.NewBuffer(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.CreatePerson(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for '%s', but got %d", expectedCode, name, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockPersonService.People) != 1 {
+		t.Errorf("Expected 1 person, but got %d", len(mockPersonService.People))
+	}
+}
+
+func TestListPeople(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People: []*domain.Person{
+			{ID: 1, Name: "Marlon Brando"},
+			{ID: 2, Name: "Al Pacino"},
+		},
+	}
+
+	httpAdapter := NewHttpPersonAdapter(mockPersonService)
+
+	request, _ := http.NewRequest("GET", "/person?name=pacino", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.ListPeople(mockContext)
+
+	page := HttpPage[HttpPerson]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 1 || len(page.Items) != 1 {
+		t.Fatalf("Expected 1 person, but got %d", len(page.Items))
+	}
+	if page.Items[0].Name != "Al Pacino" {
+		t.Errorf("Expected 'Al Pacino', but got '%s'", page.Items[0].Name)
+	}
+}
+
+func TestUpdatePersonNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{}
+
+	httpAdapter := NewHttpPersonAdapter(mockPersonService)
+
+	body, _ := json.Marshal(&HttpPerson{Name: "Al Pacino"})
+	request, _ := http.NewRequest("PUT", "/person/1", bytes.NewBuffer(body))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	httpAdapter.UpdatePerson(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+}
+
+func TestPersonWritesRequirePermission(t *testing.T) {
+	router := newTestRouter(t)
+	router.personService.People = []*domain.Person{
+		{ID: 1, Name: "Al Pacino"},
+		{ID: 2, Name: "Marlon Brando"},
+	}
+	router.personService.Credits = []*domain.MovieCredit{
+		{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor},
+	}
+	router.personService.Movies = []*domain.Movie{{ID: 1, Title: "The Godfather"}}
+	userToken := router.login("user@test.com")
+	moderatorToken := router.login("moderator@test.com")
+
+	for _, testCase := range []struct {
+		method       string
+		url          string
+		token        string
+		expectedCode int
+	}{
+		{"GET", "/person/1", userToken, http.StatusOK},
+		{"PUT", "/person/1", userToken, http.StatusForbidden},
+		{"DELETE", "/person/2", userToken, http.StatusForbidden},
+		{"PUT", "/person/1", moderatorToken, http.StatusOK},
+		{"DELETE", "/person/1", moderatorToken, http.StatusConflict},
+		{"DELETE", "/person/2", moderatorToken, http.StatusOK},
+	} {
+		if code := router.call(testCase.method, testCase.url, testCase.token, `{"name": "Al Pacino"}`); code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s %s, but got %d", testCase.expectedCode, testCase.method, testCase.url, code)
+		}
+	}
+
+	if len(router.personService.People) != 1 || len(router.personService.Credits) != 1 {
+		t.Errorf("Expected the credited person and their credit to be kept, but got %d people and %d credits", len(router.personService.People), len(router.personService.Credits))
+	}
+}
+
+func TestDeletePersonWithCredits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People:  []*domain.Person{{ID: 1, Name: "Al Pacino"}},
+		Credits: []*domain.MovieCredit{{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor}},
+		Movies:  []*domain.Movie{{ID: 1, Title: "The Godfather"}},
+	}
+
+	httpAdapter := NewHttpPersonAdapter(mockPersonService)
+
+	request, _ := http.NewRequest("DELETE", "/person/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	httpAdapter.DeletePerson(mockContext)
+
+	if mockResponseWriter.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, but got %d", http.StatusConflict, mockResponseWriter.Code)
+	}
+	if len(mockPersonService.People) != 1 {
+		t.Errorf("Expected the person to be kept, but got %d people", len(mockPersonService.People))
+	}
+}
+
+func TestListPersonMovies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockPersonService := &mock.MockPersonService{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", ReleaseYear: 1972},
+			{ID: 2, Title: "Heat", ReleaseYear: 1995},
+		},
+		Credits: []*domain.MovieCredit{
+			{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor, Character: "Michael Corleone"},
+			{ID: 2, MovieID: 2, PersonID: 1, Role: domain.CreditRoleActor, Character: "Vincent Hanna"},
+		},
+	}
+
+	httpAdapter := NewHttpPersonAdapter(mockPersonService)
+
+	request, _ := http.NewRequest("GET", "/person/1/movies", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+	httpAdapter.ListPersonMovies(mockContext)
+
+	credits := []*HttpCredit{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &credits); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(credits) != 2 {
+		t.Fatalf("Expected 2 credits, but got %d", len(credits))
+	}
+	if credits[0].Movie == nil || credits[0].Movie.Title != "Heat" {
+		t.Errorf("Expected 'Heat' first, but got %+v", credits[0].Movie)
+	}
+	if credits[0].Character != "Vincent Hanna" || credits[0].Role != "actor" {
+		t.Errorf("Expected actor credit as 'Vincent Hanna', but got %s as '%s'", credits[0].Role, credits[0].Character)
+	}
+}
@@ -0,0 +1,338 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpReviewAdapter struct {
+	reviewService port.ReviewService
+	movieService  port.MovieService
+}
+
+// HttpReview is a user's review of a movie. WatchedDate is formatted as
+// YYYY-MM-DD and is empty when unknown. Movie is only set when listing the
+// reviews of a user.
+type HttpReview struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	UserName    string     `json:"user_name,omitempty"`
+	MovieID     uint       `json:"movie_id"`
+	Score       *float64   `json:"score" binding:"required,min=0,max=10"`
+	Text        string     `json:"text" binding:"max=2000"`
+	WatchedDate string     `json:"watched_date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Movie       *HttpMovie `json:"movie,omitempty" binding:"-"`
+}
+
+func ReviewFromDomain(review *domain.Review) *HttpReview {
+	score := review.Score
+	httpReview := &HttpReview{
+		ID:        review.ID,
+		UserID:    review.UserID,
+		MovieID:   review.MovieID,
+		Score:     &score,
+		Text:      review.Text,
+		CreatedAt: review.CreatedAt,
+		UpdatedAt: review.UpdatedAt,
+	}
+	if !review.WatchedDate.IsZero() {
+		httpReview.WatchedDate = review.WatchedDate.Format(time.DateOnly)
+	}
+	if review.User != nil {
+		httpReview.UserName = review.User.Name
+	}
+	if review.Movie != nil {
+		httpReview.Movie = FromDomain(review.Movie)
+	}
+	return httpReview
+}
+
+// ToDomain converts a validated review, whose watched date is already known
+// to be well formed.
+func (r *HttpReview) ToDomain() *domain.Review {
+	review := &domain.Review{
+		ID:      r.ID,
+		UserID:  r.UserID,
+		MovieID: r.MovieID,
+		Score:   *r.Score,
+		Text:    r.Text,
+	}
+	if r.WatchedDate != "" {
+		review.WatchedDate, _ = time.Parse(time.DateOnly, r.WatchedDate)
+	}
+	return review
+}
+
+func NewHttpReviewAdapter(reviewService port.ReviewService, movieService port.MovieService) *HttpReviewAdapter {
+	return &HttpReviewAdapter{
+		reviewService: reviewService,
+		movieService:  movieService,
+	}
+}
+
+// @Summary List the reviews of a movie
+// @Description List the reviews users wrote about a movie, newest first
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of reviews per page"
+// @Success 200 {object} HttpPage[HttpReview]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/reviews [get]
+// @Security ApiKeyAuth
+func (h *HttpReviewAdapter) ListMovieReviews(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.movieService.GetMovie(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	domainReviews, total, err := h.reviewService.ListMovieReviews(uint(id), pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	reviews := make([]*HttpReview, len(domainReviews))
+	for i, review := range domainReviews {
+		reviews[i] = ReviewFromDomain(review)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, reviews, pagination, total))
+}
+
+// @Summary List the reviews of a user
+// @Description List the reviews written by a user, newest first. Without a user ID, list the logged in user's reviews.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of reviews per page"
+// @Success 200 {object} HttpPage[HttpReview]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/{id}/reviews [get]
+// @Security ApiKeyAuth
+func (h *HttpReviewAdapter) ListUserReviews(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	userID := user.ID
+	if context.Param("id") != "" {
+		id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+		if err != nil {
+			context.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		userID = uint(id)
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainReviews, total, err := h.reviewService.ListUserReviews(userID, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	reviews := make([]*HttpReview, len(domainReviews))
+	for i, review := range domainReviews {
+		reviews[i] = ReviewFromDomain(review)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, reviews, pagination, total))
+}
+
+// @Summary Review a movie
+// @Description Add the logged in user's review of a movie. Each user reviews a movie at most once.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review body HttpReview true "Review object"
+// @Success 201 {object} HttpReview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/reviews [post]
+// @Security ApiKeyAuth
+func (h *HttpReviewAdapter) CreateReview(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if _, err := h.movieService.GetMovie(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	review := HttpReview{}
+	if err := bindReview(context, &review); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, err := h.reviewService.GetReview(uint(id), user.ID); err == nil {
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": "You have already reviewed this movie"})
+		return
+	}
+
+	domainReview := review.ToDomain()
+	domainReview.ID = 0
+	domainReview.MovieID = uint(id)
+	domainReview.UserID = user.ID
+	if err := h.reviewService.CreateReview(domainReview); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, ReviewFromDomain(domainReview))
+}
+
+// @Summary Update a review
+// @Description Update the logged in user's review of a movie
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review body HttpReview true "Updated review object"
+// @Success 200 {object} HttpReview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/reviews [put]
+// @Security ApiKeyAuth
+func (h *HttpReviewAdapter) UpdateReview(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	reviewToUpdate, err := h.reviewService.GetReview(uint(id), user.ID)
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	updatedReview := HttpReview{}
+	if err := bindReview(context, &updatedReview); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedDomainReview := updatedReview.ToDomain()
+	updatedDomainReview.ID = reviewToUpdate.ID
+	updatedDomainReview.MovieID = reviewToUpdate.MovieID
+	updatedDomainReview.UserID = reviewToUpdate.UserID
+	updatedDomainReview.CreatedAt = reviewToUpdate.CreatedAt
+	updatedDomainReview.UpdatedAt = time.Now()
+	if err := h.reviewService.UpdateReview(updatedDomainReview); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, ReviewFromDomain(updatedDomainReview))
+}
+
+// @Summary Delete a review
+// @Description Delete the logged in user's review of a movie
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /movie/{id}/reviews [delete]
+// @Security ApiKeyAuth
+func (h *HttpReviewAdapter) DeleteReview(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	review, err := h.reviewService.GetReview(uint(id), user.ID)
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(review); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Review deleted"})
+}
+
+// bindReview binds a review from the request body, rejecting watched dates in
+// the future.
+func bindReview(context *gin.Context, review *HttpReview) error {
+	if err := context.ShouldBindJSON(review); err != nil {
+		return err
+	}
+	if review.WatchedDate != "" && review.ToDomain().WatchedDate.After(time.Now()) {
+		return errors.New("watched_date cannot be in the future")
+	}
+	return nil
+}
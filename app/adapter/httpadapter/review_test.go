@@ -0,0 +1,167 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", UserID: 2},
+		},
+	}
+	mockReviewService := &mock.MockReviewService{Movies: mockMovieService.Movies}
+
+	httpAdapter := NewHttpReviewAdapter(mockReviewService, mockMovieService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"score": 11}`, http.StatusBadRequest},
+		{`{"text": "No score"}`, http.StatusBadRequest},
+		{`{"score": 8, "watched_date": "2999-01-01"}`, http.StatusBadRequest},
+		{`{"score": 8, "watched_date": "01/05/2024"}`, http.StatusBadRequest},
+		{`{"score": 0, "text": "Not for me", "watched_date": "2024-05-01"}`, http.StatusCreated},
+		{`{"score": 8}`, http.StatusConflict},
+	} {
+		request, _ := http.NewRequest("POST", "/movie/1/reviews", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.CreateReview(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockReviewService.Reviews) != 1 {
+		t.Fatalf("Expected 1 review, but got %d", len(mockReviewService.Reviews))
+	}
+	review := mockReviewService.Reviews[0]
+	if review.UserID != 1 || review.MovieID != 1 || review.Score != 0 {
+		t.Errorf("Expected a score of 0 by user 1 for movie 1, but got %+v", review)
+	}
+	if review.WatchedDate.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("Expected watched date 2024-05-01, but got %v", review.WatchedDate)
+	}
+	if mockMovieService.Movies[0].ReviewCount != 1 {
+		t.Errorf("Expected the movie to have 1 review, but got %d", mockMovieService.Movies[0].ReviewCount)
+	}
+}
+
+func TestUpdateReviewNotReviewed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", UserID: 2},
+		},
+	}
+	mockReviewService := &mock.MockReviewService{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 2, MovieID: 1, Score: 8},
+		},
+	}
+
+	httpAdapter := NewHttpReviewAdapter(mockReviewService, mockMovieService)
+
+	request, _ := http.NewRequest("PUT", "/movie/1/reviews", bytes.NewBufferString(`{"score": 2}`))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.UpdateReview(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+	if mockReviewService.Reviews[0].Score != 8 {
+		t.Errorf("Expected the other user's review to be unchanged, but got score %f", mockReviewService.Reviews[0].Score)
+	}
+}
+
+func TestUpdateReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockReviewService := &mock.MockReviewService{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 1, MovieID: 1, Score: 8},
+		},
+	}
+
+	httpAdapter := NewHttpReviewAdapter(mockReviewService, mockMovieService)
+
+	request, _ := http.NewRequest("PUT", "/movie/1/reviews", bytes.NewBufferString(`{"score": 9.5, "text": "Even better the second time"}`))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.UpdateReview(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Errorf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+	if mockReviewService.Reviews[0].Score != 9.5 {
+		t.Errorf("Expected score 9.5, but got %f", mockReviewService.Reviews[0].Score)
+	}
+}
+
+func TestListUserReviews(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockReviewService := &mock.MockReviewService{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 1, MovieID: 1, Score: 8},
+			{ID: 2, UserID: 2, MovieID: 1, Score: 6},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+		},
+	}
+
+	httpAdapter := NewHttpReviewAdapter(mockReviewService, mockMovieService)
+
+	for userID, expectedScore := range map[string]float64{"": 8, "2": 6} {
+		request, _ := http.NewRequest("GET", "/user/"+userID+"/reviews", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		if userID != "" {
+			mockContext.Params = gin.Params{gin.Param{Key: "id", Value: userID}}
+		}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.ListUserReviews(mockContext)
+
+		page := HttpPage[HttpReview]{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(page.Items) != 1 {
+			t.Fatalf("Expected 1 review for user '%s', but got %d", userID, len(page.Items))
+		}
+		if *page.Items[0].Score != expectedScore {
+			t.Errorf("Expected score %f for user '%s', but got %f", expectedScore, userID, *page.Items[0].Score)
+		}
+		if page.Items[0].Movie == nil || page.Items[0].Movie.Title != "Inception" {
+			t.Errorf("Expected the reviewed movie, but got %+v", page.Items[0].Movie)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpSession is a device or script the user logged in from. Current tells
+// the session of the token of the request apart.
+type HttpSession struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func SessionFromDomain(session *domain.Session, currentSessionID uint) *HttpSession {
+	return &HttpSession{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+		Current:    session.ID == currentSessionID,
+	}
+}
+
+type HttpSessionAdapter struct {
+	sessionService port.SessionService
+}
+
+func NewHttpSessionAdapter(sessionService port.SessionService) *HttpSessionAdapter {
+	return &HttpSessionAdapter{
+		sessionService: sessionService,
+	}
+}
+
+// @Summary List the sessions of the logged in user
+// @Description List the devices and scripts the logged in user is logged in from, most recently used first
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Success 200 {array} HttpSession
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/sessions [get]
+// @Security ApiKeyAuth
+func (a *HttpSessionAdapter) ListSessions(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessions, err := a.sessionService.ListSessions(user.ID)
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	currentSessionID := tokenSessionID(context)
+	httpSessions := make([]*HttpSession, len(sessions))
+	for i, session := range sessions {
+		httpSessions[i] = SessionFromDomain(session, currentSessionID)
+	}
+
+	context.IndentedJSON(http.StatusOK, httpSessions)
+}
+
+// @Summary Revoke a session of the logged in user
+// @Description Log the logged in user out of one of their sessions. Neither its refresh token nor its access tokens can be used anymore.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param sessionId path int true "Session ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/sessions/{sessionId} [delete]
+// @Security ApiKeyAuth
+func (a *HttpSessionAdapter) RevokeSession(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(context.Param("sessionId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = a.sessionService.RevokeSession(user.ID, uint(sessionID))
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Session revoked"})
+}
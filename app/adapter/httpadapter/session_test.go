@@ -0,0 +1,88 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestListSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSessionService := &mock.MockSessionService{}
+	mockSessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	mockSessionService.CreateSession(1, "Firefox", "127.0.0.2")
+	mockSessionService.CreateSession(2, "curl/8.0", "127.0.0.3")
+
+	httpAdapter := NewHttpSessionAdapter(mockSessionService)
+
+	request, _ := http.NewRequest("GET", "/user/me/sessions", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+	mockContext.Set("JWT_PAYLOAD", jwt.MapClaims{"sid": float64(2)})
+
+	httpAdapter.ListSessions(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+
+	sessions := []*HttpSession{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, but got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		if session.Current != (session.ID == 2) {
+			t.Errorf("Expected only session 2 to be the current one, but got %+v", session)
+		}
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockSessionService := &mock.MockSessionService{}
+	mockSessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	mockSessionService.CreateSession(2, "curl/8.0", "127.0.0.2")
+
+	httpAdapter := NewHttpSessionAdapter(mockSessionService)
+
+	for _, testCase := range []struct {
+		sessionID    string
+		expectedCode int
+	}{
+		{"abc", http.StatusBadRequest},
+		{"2", http.StatusNotFound},
+		{"3", http.StatusNotFound},
+		{"1", http.StatusOK},
+	} {
+		request, _ := http.NewRequest("DELETE", "/user/me/sessions/"+testCase.sessionID, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{{Key: "sessionId", Value: testCase.sessionID}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.RevokeSession(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for session %s, but got %d", testCase.expectedCode, testCase.sessionID, mockResponseWriter.Code)
+		}
+	}
+
+	if mockSessionService.Sessions[0].Active(mockSessionService.Sessions[0].LastUsedAt) {
+		t.Errorf("Expected session 1 to be revoked")
+	}
+	if !mockSessionService.Sessions[1].Active(mockSessionService.Sessions[1].LastUsedAt) {
+		t.Errorf("Expected session 2 to stay active")
+	}
+}
@@ -0,0 +1,230 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+// HttpTwoFactorStatus tells whether the logged in user has two-factor
+// authentication, and how many of their recovery codes are left.
+type HttpTwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// HttpTOTPEnrolment is the secret to add to an authenticator app, either typed
+// in or scanned from a QR code of the provisioning URI.
+type HttpTOTPEnrolment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// HttpTwoFactorCode is a code of the authenticator app of the logged in user,
+// or one of their recovery codes.
+type HttpTwoFactorCode struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// HttpRecoveryCodes are the recovery codes of the logged in user, which are
+// only shown once.
+type HttpRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type HttpTwoFactorAdapter struct {
+	twoFactorService port.TwoFactorService
+}
+
+func NewHttpTwoFactorAdapter(twoFactorService port.TwoFactorService) *HttpTwoFactorAdapter {
+	return &HttpTwoFactorAdapter{
+		twoFactorService: twoFactorService,
+	}
+}
+
+// @Summary Get the two-factor authentication of the logged in user
+// @Description Tell whether the logged in user has two-factor authentication, and how many of their recovery codes are left
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Success 200 {object} HttpTwoFactorStatus
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/2fa [get]
+// @Security ApiKeyAuth
+func (a *HttpTwoFactorAdapter) GetTwoFactor(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	enabled, err := a.twoFactorService.TwoFactorEnabled(user.ID)
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := &HttpTwoFactorStatus{Enabled: enabled}
+	if enabled {
+		status.RecoveryCodesLeft, err = a.twoFactorService.CountRecoveryCodes(user.ID)
+		if err != nil {
+			context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	context.IndentedJSON(http.StatusOK, status)
+}
+
+// @Summary Start enrolling in two-factor authentication
+// @Description Get a new TOTP secret for the logged in user to add to their authenticator app. It is not asked for until confirmed with a code at /user/me/2fa/confirm. Starting again replaces the pending secret.
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Success 201 {object} HttpTOTPEnrolment
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/2fa [post]
+// @Security ApiKeyAuth
+func (a *HttpTwoFactorAdapter) StartEnrolment(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	enrolment, err := a.twoFactorService.StartEnrolment(user.ID)
+	if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusCreated, &HttpTOTPEnrolment{
+		Secret:          enrolment.Secret,
+		ProvisioningURI: enrolment.ProvisioningURI,
+	})
+}
+
+// @Summary Confirm enrolling in two-factor authentication
+// @Description Enable two-factor authentication with a code of the pending TOTP secret of the logged in user, and get their recovery codes. They are only shown in this response.
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Param code body HttpTwoFactorCode true "Code of the authenticator app"
+// @Success 200 {object} HttpRecoveryCodes
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/2fa/confirm [post]
+// @Security ApiKeyAuth
+func (a *HttpTwoFactorAdapter) ConfirmEnrolment(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	code := HttpTwoFactorCode{}
+	if err := context.BindJSON(&code); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := a.twoFactorService.ConfirmEnrolment(user.ID, code.Code)
+	if err != nil {
+		respondTwoFactorError(context, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &HttpRecoveryCodes{RecoveryCodes: recoveryCodes})
+}
+
+// @Summary Disable two-factor authentication
+// @Description Turn two-factor authentication off for the logged in user, confirming it with a code of their authenticator app or one of their recovery codes
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Param code body HttpTwoFactorCode true "Code of the authenticator app, or a recovery code"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/2fa [delete]
+// @Security ApiKeyAuth
+func (a *HttpTwoFactorAdapter) DisableTwoFactor(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	code := HttpTwoFactorCode{}
+	if err := context.BindJSON(&code); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := a.twoFactorService.DisableTwoFactor(user.ID, code.Code); err != nil {
+		respondTwoFactorError(context, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Two-factor authentication disabled"})
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes of the logged in user, confirming it with a code of their authenticator app or one of their recovery codes. The new codes are only shown in this response.
+// @Tags Two-factor authentication
+// @Accept json
+// @Produce json
+// @Param code body HttpTwoFactorCode true "Code of the authenticator app, or a recovery code"
+// @Success 200 {object} HttpRecoveryCodes
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/2fa/recovery-codes [post]
+// @Security ApiKeyAuth
+func (a *HttpTwoFactorAdapter) RegenerateRecoveryCodes(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	code := HttpTwoFactorCode{}
+	if err := context.BindJSON(&code); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recoveryCodes, err := a.twoFactorService.RegenerateRecoveryCodes(user.ID, code.Code)
+	if err != nil {
+		respondTwoFactorError(context, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, &HttpRecoveryCodes{RecoveryCodes: recoveryCodes})
+}
+
+func respondTwoFactorError(context *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTwoFactorNotEnabled), errors.Is(err, domain.ErrTwoFactorAlreadyEnabled), errors.Is(err, domain.ErrTwoFactorNotStarted):
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
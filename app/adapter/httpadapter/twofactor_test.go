@@ -0,0 +1,159 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpTwoFactorAdapter(&mock.MockTwoFactorService{Enabled: map[uint]bool{1: true}})
+
+	for _, testCase := range []struct {
+		userID                    uint
+		expectedEnabled           bool
+		expectedRecoveryCodesLeft int64
+	}{
+		{1, true, 10},
+		{2, false, 0},
+	} {
+		request, _ := http.NewRequest("GET", "/user/me/2fa", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: testCase.userID, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.GetTwoFactor(mockContext)
+
+		status := &HttpTwoFactorStatus{}
+		json.Unmarshal(mockResponseWriter.Body.Bytes(), status)
+		if mockResponseWriter.Code != http.StatusOK || status.Enabled != testCase.expectedEnabled || status.RecoveryCodesLeft != testCase.expectedRecoveryCodesLeft {
+			t.Errorf("Expected enabled %v with %d recovery codes for user %d, but got status %d and %+v", testCase.expectedEnabled, testCase.expectedRecoveryCodesLeft, testCase.userID, mockResponseWriter.Code, status)
+		}
+	}
+}
+
+func TestStartEnrolment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpTwoFactorAdapter(&mock.MockTwoFactorService{Enabled: map[uint]bool{1: true}})
+
+	for _, testCase := range []struct {
+		userID       uint
+		expectedCode int
+	}{
+		{1, http.StatusConflict},
+		{2, http.StatusCreated},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/2fa", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: testCase.userID, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.StartEnrolment(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for user %d, but got %d", testCase.expectedCode, testCase.userID, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestConfirmEnrolment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockTwoFactorService := &mock.MockTwoFactorService{Code: "123456"}
+	httpAdapter := NewHttpTwoFactorAdapter(mockTwoFactorService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{}`, http.StatusBadRequest},
+		{`{"code": "000000"}`, http.StatusBadRequest},
+		{`{"code": "123456"}`, http.StatusOK},
+		{`{"code": "123456"}`, http.StatusConflict},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/2fa/confirm", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.ConfirmEnrolment(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+		if mockResponseWriter.Code == http.StatusOK {
+			recoveryCodes := &HttpRecoveryCodes{}
+			json.Unmarshal(mockResponseWriter.Body.Bytes(), recoveryCodes)
+			if len(recoveryCodes.RecoveryCodes) == 0 {
+				t.Errorf("Expected the recovery codes, but got none")
+			}
+		}
+	}
+
+	if !mockTwoFactorService.Enabled[1] {
+		t.Errorf("Expected two-factor authentication to be enabled")
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockTwoFactorService := &mock.MockTwoFactorService{Code: "123456", Enabled: map[uint]bool{1: true}}
+	httpAdapter := NewHttpTwoFactorAdapter(mockTwoFactorService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"code": "000000"}`, http.StatusBadRequest},
+		{`{"code": "123456"}`, http.StatusOK},
+		{`{"code": "123456"}`, http.StatusConflict},
+	} {
+		request, _ := http.NewRequest("DELETE", "/user/me/2fa", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.DisableTwoFactor(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpAdapter := NewHttpTwoFactorAdapter(&mock.MockTwoFactorService{Code: "123456", Enabled: map[uint]bool{1: true}})
+
+	for _, testCase := range []struct {
+		userID       uint
+		body         string
+		expectedCode int
+	}{
+		{1, `{"code": "000000"}`, http.StatusBadRequest},
+		{1, `{"code": "123456"}`, http.StatusOK},
+		{2, `{"code": "123456"}`, http.StatusConflict},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/2fa/recovery-codes", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: testCase.userID, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.RegenerateRecoveryCodes(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for user %d with %s, but got %d", testCase.expectedCode, testCase.userID, testCase.body, mockResponseWriter.Code)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package httpadapter
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
 	"github.com/Acova/movie-collection/app/port"
@@ -10,13 +13,14 @@ import (
 )
 
 type HttpUserAdapter struct {
-	userService port.UserService
+	userService         port.UserService
+	verificationService port.EmailVerificationService
 }
 
 type HttpUser struct {
 	Email    string `json:"email" binding:"required,email"`
 	Name     string `json:"name" binding:"required,min=5,max=20"`
-	Password string `json:"password" binding:"required,min=8,max=40"`
+	Password string `json:"password" binding:"required"`
 }
 
 func (u *HttpUser) ToDomain() *domain.User {
@@ -27,38 +31,176 @@ func (u *HttpUser) ToDomain() *domain.User {
 	}
 }
 
-func NewHttpUserAdapter(userService port.UserService) *HttpUserAdapter {
+// HttpUserProfile is the account of a user as seen by themselves and by
+// admins. The password hash never leaves the server.
+type HttpUserProfile struct {
+	ID            uint       `json:"id"`
+	Email         string     `json:"email"`
+	Name          string     `json:"name"`
+	Role          string     `json:"role"`
+	RegisterDate  time.Time  `json:"register_date"`
+	DisableDate   *time.Time `json:"disable_date,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+}
+
+func UserProfileFromDomain(user *domain.User) *HttpUserProfile {
+	profile := &HttpUserProfile{
+		ID:            user.ID,
+		Email:         user.Email,
+		Name:          user.Name,
+		Role:          string(user.Role),
+		RegisterDate:  user.RegisterDate,
+		EmailVerified: user.EmailVerified(),
+	}
+	if user.Disabled() {
+		profile.DisableDate = &user.DisableDate
+	}
+	return profile
+}
+
+// HttpPublicUser is what any user can see of another one.
+type HttpPublicUser struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	RegisterDate time.Time `json:"register_date"`
+}
+
+func PublicUserFromDomain(user *domain.User) *HttpPublicUser {
+	return &HttpPublicUser{
+		ID:           user.ID,
+		Name:         user.Name,
+		RegisterDate: user.RegisterDate,
+	}
+}
+
+// HttpUserUpdate holds the profile fields to change, leaving out the ones to
+// keep.
+type HttpUserUpdate struct {
+	Email *string `json:"email" binding:"omitempty,email"`
+	Name  *string `json:"name" binding:"omitempty,min=5,max=20"`
+}
+
+type HttpPasswordChange struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// HttpAccountDeletion confirms the deletion of an account with its password,
+// and chooses what happens to the movies of the user.
+type HttpAccountDeletion struct {
+	Password   string `json:"password" binding:"required"`
+	Movies     string `json:"movies" binding:"required,oneof=delete reassign"`
+	ReassignTo uint   `json:"reassign_to" binding:"required_if=Movies reassign"`
+}
+
+type HttpUserRole struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+func NewHttpUserAdapter(userService port.UserService, verificationService port.EmailVerificationService) *HttpUserAdapter {
 	return &HttpUserAdapter{
-		userService: userService,
+		userService:         userService,
+		verificationService: verificationService,
 	}
 }
 
-// @Summary List all users
-// @Description List all users in the system
+// @Summary List users
+// @Description List the users in the system. Only available to admins.
 // @Tags User
 // @Accept json
 // @Produce json
-// @Success 200 {array} domain.User
+// @Param q query string false "Only list users whose email or name contain this text"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of users per page"
+// @Success 200 {object} HttpPage[HttpUserProfile]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /user [get]
 // @Security ApiKeyAuth
 func (a *HttpUserAdapter) ListUsers(context *gin.Context) {
-	users, err := a.userService.ListUsers()
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainUsers, total, err := a.userService.ListUsers(context.Query("q"), pagination)
 	if err != nil {
 		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	context.IndentedJSON(http.StatusOK, users)
+
+	users := make([]*HttpUserProfile, len(domainUsers))
+	for i, user := range domainUsers {
+		users[i] = UserProfileFromDomain(user)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, users, pagination, total))
+}
+
+// @Summary Get the logged in user
+// @Description Get the profile of the logged in user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {object} HttpUserProfile
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /user/me [get]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) GetProfile(context *gin.Context) {
+	loggedInUser, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	user, err := a.userService.GetUser(loggedInUser.ID)
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, UserProfileFromDomain(user))
+}
+
+// @Summary Get a user
+// @Description Get the public profile of a user
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} HttpPublicUser
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /user/{id} [get]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) GetUser(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := a.userService.GetUser(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, PublicUserFromDomain(user))
 }
 
 // @Summary Create a new user
-// @Description Create a new user in the system
+// @Description Create a new user in the system. Its password must meet the password policy, and is rejected with the reasons it does not otherwise. Its email is pending verification until the link emailed to it is followed.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param user body HttpUser true "User data"
 // @Success 201 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} HttpPasswordPolicyError
 // @Failure 500 {object} map[string]string
 // @Router /user [post]
 func (a *HttpUserAdapter) CreateUser(context *gin.Context) {
@@ -69,16 +211,380 @@ func (a *HttpUserAdapter) CreateUser(context *gin.Context) {
 		return
 	}
 
-	existingUser, _ := a.userService.GetUserByEmail(user.Email)
-	if existingUser != nil {
+	if _, err := a.userService.GetUserByEmail(user.Email); err == nil {
 		context.IndentedJSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("User with email `%s` already exists", user.Email)})
 		return
 	}
 
-	err := a.userService.CreateUser(user.ToDomain())
+	newUser := user.ToDomain()
+	err := a.userService.CreateUser(newUser)
+	if respondPasswordPolicyError(context, err) {
+		return
+	}
 	if err != nil {
 		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create user: %v", err)})
 		return
 	}
-	context.IndentedJSON(http.StatusCreated, gin.H{"status": "User created"})
+
+	// The user can ask for a new verification email once logged in
+	if err := a.verificationService.SendVerification(newUser); err != nil {
+		context.IndentedJSON(http.StatusCreated, gin.H{"status": "User created, but the verification email could not be sent"})
+		return
+	}
+	context.IndentedJSON(http.StatusCreated, gin.H{"status": "User created, check your email to verify it"})
+}
+
+// @Summary Verify an email
+// @Description Verify the email of a user with the token of the link emailed to them. No authentication is needed.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/verify [get]
+func (a *HttpUserAdapter) VerifyEmail(context *gin.Context) {
+	token := context.Query("token")
+	if token == "" {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": "The token query parameter is required"})
+		return
+	}
+
+	err := a.verificationService.VerifyEmail(token)
+	if errors.Is(err, domain.ErrInvalidVerificationToken) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Email verified"})
+}
+
+// @Summary Resend the verification email
+// @Description Email a new verification link to the logged in user, whose email must still be pending verification
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 202 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/verification [post]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) ResendVerification(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	err := a.verificationService.ResendVerification(user.ID)
+	if errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusAccepted, gin.H{"status": "Verification email sent"})
+}
+
+// @Summary Change the role of a user
+// @Description Make a user a regular user, a moderator or an admin. Only available to admins, who cannot change their own role. The new role applies from the next login of the user.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body HttpUserRole true "New role"
+// @Success 200 {object} HttpUserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/{id}/role [put]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) SetUserRole(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	loggedInUser, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if loggedInUser.ID == uint(id) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change your own role"})
+		return
+	}
+
+	role := HttpUserRole{}
+	if err := context.BindJSON(&role); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.userService.GetUser(uint(id))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if err := a.userService.SetUserRole(user.ID, domain.Role(role.Role)); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	user.Role = domain.Role(role.Role)
+
+	context.IndentedJSON(http.StatusOK, UserProfileFromDomain(user))
+}
+
+// @Summary Disable a user
+// @Description Prevent a user from logging in, and revoke every token issued to them. Only available to admins, who cannot disable themselves.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} HttpUserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/{id}/disable [post]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) DisableUser(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	loggedInUser, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if loggedInUser.ID == uint(id) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "You are not allowed to disable yourself"})
+		return
+	}
+
+	if _, err := a.userService.GetUser(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if err := a.userService.DisableUser(uint(id)); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.userService.GetUser(uint(id))
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, UserProfileFromDomain(user))
+}
+
+// @Summary Enable a user
+// @Description Let a disabled user log in again. The tokens revoked when they were disabled stay revoked. Only available to admins.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} HttpUserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/{id}/enable [post]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) EnableUser(context *gin.Context) {
+	id, err := strconv.ParseUint(context.Param("id"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := a.userService.GetUser(uint(id)); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if err := a.userService.EnableUser(uint(id)); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.userService.GetUser(uint(id))
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, UserProfileFromDomain(user))
+}
+
+// @Summary Update the logged in user
+// @Description Change the email or name of the logged in user. Fields left out are kept. A new email is pending verification until the link emailed to it is followed. The token keeps the previous values until the next login.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param user body HttpUserUpdate true "Profile fields to change"
+// @Success 200 {object} HttpUserProfile
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me [patch]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) UpdateProfile(context *gin.Context) {
+	loggedInUser, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	update := HttpUserUpdate{}
+	if err := context.BindJSON(&update); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := a.userService.GetUser(loggedInUser.ID)
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	emailChanged := update.Email != nil && *update.Email != user.Email
+	if emailChanged {
+		if existingUser, err := a.userService.GetUserByEmail(*update.Email); err == nil && existingUser.ID != user.ID {
+			context.IndentedJSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("User with email `%s` already exists", *update.Email)})
+			return
+		}
+		user.Email = *update.Email
+		user.EmailVerifiedAt = time.Time{} // The new email has to be verified
+	}
+	if update.Name != nil {
+		user.Name = *update.Name
+	}
+
+	if err := a.userService.UpdateProfile(user); err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if emailChanged {
+		// The user can ask for a new verification email if this one fails
+		a.verificationService.SendVerification(user)
+	}
+
+	context.IndentedJSON(http.StatusOK, UserProfileFromDomain(user))
+}
+
+// @Summary Change the password of the logged in user
+// @Description Replace the password of the logged in user, confirming it with the current one. The new password must meet the password policy, and is rejected with the reasons it does not otherwise. Every token issued to the user until now is revoked, so they need to log in again.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param password body HttpPasswordChange true "Current and new passwords"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} HttpPasswordPolicyError
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/password [post]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) ChangePassword(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	passwordChange := HttpPasswordChange{}
+	if err := context.BindJSON(&passwordChange); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := a.userService.ChangePassword(user.ID, passwordChange.CurrentPassword, passwordChange.NewPassword)
+	if errors.Is(err, domain.ErrWrongPassword) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+	if respondPasswordPolicyError(context, err) {
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Password changed"})
+}
+
+// @Summary Delete the logged in user
+// @Description Delete the account of the logged in user, confirming it with their password. Their movies are either deleted or given to another user. Their favourites, reviews, viewings, watchlist and lists are deleted.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param deletion body HttpAccountDeletion true "Password and what to do with the movies"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me [delete]
+// @Security ApiKeyAuth
+func (a *HttpUserAdapter) DeleteAccount(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	deletion := HttpAccountDeletion{}
+	if err := context.BindJSON(&deletion); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var reassignMoviesTo *uint
+	if deletion.Movies == "reassign" {
+		reassignMoviesTo = &deletion.ReassignTo
+	}
+
+	err := a.userService.DeleteAccount(user.ID, deletion.Password, reassignMoviesTo)
+	if errors.Is(err, domain.ErrWrongPassword) {
+		context.IndentedJSON(http.StatusForbidden, gin.H{"error": "Password is incorrect"})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidMovieReassignment) {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		context.IndentedJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "User deleted"})
 }
@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
 	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,33 +32,104 @@ func TestListUsers(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockUserService := mock.MockUserService{
 		Users: []*domain.User{
-			{Email: "test@example.com", Name: "Test User", Password: "password123"},
+			{ID: 1, Email: "test@example.com", Name: "Test User", Password: "password123", Role: domain.RoleAdmin},
+			{ID: 2, Email: "other@example.com", Name: "Other User", Password: "password456", Role: domain.RoleUser},
 		},
 	}
 
-	httpAdapter := NewHttpUserAdapter(&mockUserService)
+	httpAdapter := NewHttpUserAdapter(&mockUserService, &mock.MockEmailVerificationService{})
 
-	request, _ := http.NewRequest("GET", "/users", nil)
+	request, _ := http.NewRequest("GET", "/user?q=OTHER", nil)
 	mockResponseWriter := httptest.NewRecorder()
 	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
 	mockContext.Request = request
 
 	httpAdapter.ListUsers(mockContext)
-	users := []*domain.User{}
-	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &users); err != nil {
-		t.Errorf("Failed to unmarshal response: %v", err)
+
+	if bytes.Contains(mockResponseWriter.Body.Bytes(), []byte("password")) {
+		t.Errorf("Expected no password in the response, but got %s", mockResponseWriter.Body.String())
+	}
+
+	page := &HttpPage[HttpUserProfile]{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), page); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if page.Total != 1 || len(page.Items) != 1 {
+		t.Fatalf("Expected 1 user, but got %d", page.Total)
+	}
+	if page.Items[0].Email != "other@example.com" {
+		t.Errorf("Expected email to be 'other@example.com', but got '%s'", page.Items[0].Email)
+	}
+	if page.Items[0].Name != "Other User" {
+		t.Errorf("Expected name to be 'Other User', but got '%s'", page.Items[0].Name)
+	}
+	if page.Items[0].Role != "user" {
+		t.Errorf("Expected role to be 'user', but got '%s'", page.Items[0].Role)
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "hash", Role: domain.RoleUser},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	request, _ := http.NewRequest("GET", "/user/me", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.GetProfile(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
 	}
-	if len(users) != 1 {
-		t.Errorf("Expected 1 user, but got %d", len(users))
+	if bytes.Contains(mockResponseWriter.Body.Bytes(), []byte("hash")) {
+		t.Errorf("Expected no password in the response, but got %s", mockResponseWriter.Body.String())
 	}
-	if users[0].Email != "test@example.com" {
-		t.Errorf("Expected email to be 'test@example.com', but got '%s'", users[0].Email)
+
+	profile := &HttpUserProfile{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), profile); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
-	if users[0].Name != "Test User" {
-		t.Errorf("Expected name to be 'Test User', but got '%s'", users[0].Name)
+	if profile.ID != 1 || profile.Email != "test@test.com" || profile.Role != "user" {
+		t.Errorf("Expected the profile of user 1, but got %+v", profile)
 	}
-	if users[0].Password != "password123" {
-		t.Errorf("Expected password to be 'password123', but got '%s'", users[0].Password)
+}
+
+func TestGetUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 2, Email: "other@test.com", Name: "Other User", Password: "hash"},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	for id, expectedCode := range map[string]int{
+		"2": http.StatusOK,
+		"3": http.StatusNotFound,
+	} {
+		request, _ := http.NewRequest("GET", "/user/"+id, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+
+		httpAdapter.GetUser(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for user %s, but got %d", expectedCode, id, mockResponseWriter.Code)
+		}
+		if bytes.Contains(mockResponseWriter.Body.Bytes(), []byte("@test.com")) {
+			t.Errorf("Expected the email to be hidden, but got %s", mockResponseWriter.Body.String())
+		}
 	}
 }
 
@@ -66,7 +139,9 @@ func TestCreateUser(t *testing.T) {
 		Users: []*domain.User{},
 	}
 
-	httpAdapter := NewHttpUserAdapter(mockUserService)
+	mockVerificationService := &mock.MockEmailVerificationService{}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, mockVerificationService)
 
 	user := &HttpUser{
 		Email:    "newuser@example.com",
@@ -99,4 +174,375 @@ func TestCreateUser(t *testing.T) {
 	if mockUserService.Users[0].Password != user.Password {
 		t.Errorf("Expected password to be '%s', but got '%s'", user.Password, mockUserService.Users[0].Password)
 	}
+
+	if mockUserService.Users[0].EmailVerified() {
+		t.Errorf("Expected the email to be pending verification")
+	}
+
+	if len(mockVerificationService.Sent) != 1 {
+		t.Errorf("Expected a verification email to be sent, but got %d", len(mockVerificationService.Sent))
+	}
+}
+
+func TestCreateUserWithUserService(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{{ID: 1, Email: "existing@example.com", Name: "Existing User"}},
+	}
+	userService := service.NewUserService(mockUserRepository, &mock.MockPasswordPolicyService{})
+
+	httpAdapter := NewHttpUserAdapter(userService, &mock.MockEmailVerificationService{})
+
+	for email, expectedCode := range map[string]int{
+		"newuser@example.com":  http.StatusCreated,
+		"existing@example.com": http.StatusConflict,
+	} {
+		body := `{"email": "` + email + `", "name": "New User", "password": "newpassword123"}`
+		request, _ := http.NewRequest("POST", "/user", bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/json")
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.CreateUser(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for '%s', but got %d", expectedCode, email, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockUserRepository.Users) != 2 {
+		t.Errorf("Expected 2 users, but got %d", len(mockUserRepository.Users))
+	}
+}
+
+func TestCreateUserWithRejectedPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users:          []*domain.User{},
+		PasswordPolicy: shortPasswordPolicy(),
+	}
+	mockVerificationService := &mock.MockEmailVerificationService{}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, mockVerificationService)
+
+	body := `{"email": "newuser@example.com", "name": "New User", "password": "short"}`
+	request, _ := http.NewRequest("POST", "/user", bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/json")
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+
+	httpAdapter.CreateUser(mockContext)
+
+	if mockResponseWriter.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, but got %d", http.StatusBadRequest, mockResponseWriter.Code)
+	}
+	response := HttpPasswordPolicyError{}
+	if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Could not decode the response: %v", err)
+	}
+	if len(response.Reasons) != 1 || response.Reasons[0].Code != string(domain.PasswordTooShort) {
+		t.Errorf("Expected the too_short reason, but got %+v", response.Reasons)
+	}
+	if len(mockUserService.Users) != 0 || len(mockVerificationService.Sent) != 0 {
+		t.Errorf("Expected no user to be created")
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "admin@test.com", Name: "Admin User", Role: domain.RoleAdmin},
+			{ID: 2, Email: "other@test.com", Name: "Other User", Role: domain.RoleUser},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	for _, testCase := range []struct {
+		id           string
+		body         string
+		expectedCode int
+	}{
+		{"2", `{"role": "moderator"}`, http.StatusOK},
+		{"2", `{"role": "superuser"}`, http.StatusBadRequest},
+		{"1", `{"role": "user"}`, http.StatusForbidden},
+		{"3", `{"role": "admin"}`, http.StatusNotFound},
+	} {
+		request, _ := http.NewRequest("PUT", "/user/"+testCase.id+"/role", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: testCase.id}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "admin@test.com", Name: "Admin User", Role: domain.RoleAdmin})
+
+		httpAdapter.SetUserRole(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for user %s with %s, but got %d", testCase.expectedCode, testCase.id, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if mockUserService.Users[0].Role != domain.RoleAdmin {
+		t.Errorf("Expected the admin to keep their role, but got '%s'", mockUserService.Users[0].Role)
+	}
+	if mockUserService.Users[1].Role != domain.RoleModerator {
+		t.Errorf("Expected the other user to become a moderator, but got '%s'", mockUserService.Users[1].Role)
+	}
+}
+
+func TestDisableUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "admin@test.com", Name: "Admin User", Role: domain.RoleAdmin},
+			{ID: 2, Email: "other@test.com", Name: "Other User", Role: domain.RoleUser},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	for _, testCase := range []struct {
+		id           string
+		expectedCode int
+	}{
+		{"2", http.StatusOK},
+		{"1", http.StatusForbidden},
+		{"3", http.StatusNotFound},
+	} {
+		request, _ := http.NewRequest("POST", "/user/"+testCase.id+"/disable", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "id", Value: testCase.id}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "admin@test.com", Name: "Admin User", Role: domain.RoleAdmin})
+
+		httpAdapter.DisableUser(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for user %s, but got %d", testCase.expectedCode, testCase.id, mockResponseWriter.Code)
+		}
+		if testCase.expectedCode == http.StatusOK {
+			profile := &HttpUserProfile{}
+			if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), profile); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if profile.DisableDate == nil {
+				t.Errorf("Expected the disable date to be set")
+			}
+		}
+	}
+
+	if mockUserService.Users[0].Disabled() || !mockUserService.Users[1].Disabled() {
+		t.Errorf("Expected only the other user to be disabled")
+	}
+}
+
+func TestEnableUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 2, Email: "other@test.com", Name: "Other User", Role: domain.RoleUser, DisableDate: time.Now()},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	request, _ := http.NewRequest("POST", "/user/2/enable", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "admin@test.com", Name: "Admin User", Role: domain.RoleAdmin})
+
+	httpAdapter.EnableUser(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Errorf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+	if mockUserService.Users[0].Disabled() {
+		t.Errorf("Expected the user to be enabled")
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", EmailVerifiedAt: time.Now()},
+			{ID: 2, Email: "taken@test.com", Name: "Other User"},
+		},
+	}
+	mockVerificationService := &mock.MockEmailVerificationService{}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, mockVerificationService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"email": "taken@test.com"}`, http.StatusConflict},
+		{`{"email": "not an email"}`, http.StatusBadRequest},
+		{`{"name": "Bob"}`, http.StatusBadRequest},
+		{`{"name": "Renamed User"}`, http.StatusOK},
+		{`{"email": "new@test.com"}`, http.StatusOK},
+	} {
+		request, _ := http.NewRequest("PATCH", "/user/me", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.UpdateProfile(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if mockUserService.Users[0].Email != "new@test.com" || mockUserService.Users[0].Name != "Renamed User" {
+		t.Errorf("Expected the email and name to be updated, but got %+v", mockUserService.Users[0])
+	}
+	if mockUserService.Users[0].EmailVerified() {
+		t.Errorf("Expected the new email to be pending verification")
+	}
+	if len(mockVerificationService.Sent) != 1 || mockVerificationService.Sent[0] != 1 {
+		t.Errorf("Expected a verification email to be sent to the new email, but got %v", mockVerificationService.Sent)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockVerificationService := &mock.MockEmailVerificationService{
+		Tokens: map[string]uint{"valid-token": 1},
+	}
+
+	httpAdapter := NewHttpUserAdapter(&mock.MockUserService{}, mockVerificationService)
+
+	for _, testCase := range []struct {
+		url          string
+		expectedCode int
+	}{
+		{"/user/verify", http.StatusBadRequest},
+		{"/user/verify?token=wrong-token", http.StatusBadRequest},
+		{"/user/verify?token=valid-token", http.StatusOK},
+		{"/user/verify?token=valid-token", http.StatusBadRequest},
+	} {
+		request, _ := http.NewRequest("GET", testCase.url, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+
+		httpAdapter.VerifyEmail(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.url, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockVerificationService.Verified) != 1 || mockVerificationService.Verified[0] != 1 {
+		t.Errorf("Expected user 1 to be verified, but got %v", mockVerificationService.Verified)
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockVerificationService := &mock.MockEmailVerificationService{Verified: []uint{2}}
+
+	httpAdapter := NewHttpUserAdapter(&mock.MockUserService{}, mockVerificationService)
+
+	for userID, expectedCode := range map[uint]int{
+		1: http.StatusAccepted,
+		2: http.StatusConflict,
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/verification", nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: userID, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.ResendVerification(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for user %d, but got %d", expectedCode, userID, mockResponseWriter.Code)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "oldpassword"},
+		},
+		PasswordPolicy: shortPasswordPolicy(),
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"current_password": "wrongpassword", "new_password": "newpassword"}`, http.StatusForbidden},
+		{`{"current_password": "oldpassword", "new_password": "short"}`, http.StatusBadRequest},
+		{`{"current_password": "oldpassword", "new_password": "newpassword"}`, http.StatusOK},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/password", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.ChangePassword(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if mockUserService.Users[0].Password != "newpassword" {
+		t.Errorf("Expected the password to be changed, but got '%s'", mockUserService.Users[0].Password)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockUserService := &mock.MockUserService{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "password"},
+			{ID: 2, Email: "other@test.com", Name: "Other User", Password: "password"},
+		},
+	}
+
+	httpAdapter := NewHttpUserAdapter(mockUserService, &mock.MockEmailVerificationService{})
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"password": "wrongpassword", "movies": "delete"}`, http.StatusForbidden},
+		{`{"password": "password", "movies": "keep"}`, http.StatusBadRequest},
+		{`{"password": "password", "movies": "reassign"}`, http.StatusBadRequest},
+		{`{"password": "password", "movies": "reassign", "reassign_to": 3}`, http.StatusBadRequest},
+		{`{"password": "password", "movies": "reassign", "reassign_to": 2}`, http.StatusOK},
+	} {
+		request, _ := http.NewRequest("DELETE", "/user/me", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.DeleteAccount(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockUserService.Users) != 1 || mockUserService.Users[0].ID != 2 {
+		t.Errorf("Expected only the other user to be left, but got %d users", len(mockUserService.Users))
+	}
 }
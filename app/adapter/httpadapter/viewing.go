@@ -0,0 +1,355 @@
+package httpadapter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpViewingAdapter struct {
+	viewingService port.ViewingService
+	movieService   port.MovieService
+}
+
+// HttpViewing is a single time the logged in user watched a movie. WatchedAt
+// accepts an RFC 3339 timestamp or a YYYY-MM-DD date, and defaults to now.
+type HttpViewing struct {
+	ID        uint       `json:"id"`
+	MovieID   uint       `json:"movie_id" binding:"required"`
+	WatchedAt string     `json:"watched_at"`
+	Location  string     `json:"location" binding:"max=100"`
+	Rewatch   bool       `json:"rewatch"`
+	Notes     string     `json:"notes" binding:"max=1000"`
+	CreatedAt time.Time  `json:"created_at"`
+	Movie     *HttpMovie `json:"movie,omitempty" binding:"-"`
+}
+
+func ViewingFromDomain(viewing *domain.Viewing) *HttpViewing {
+	httpViewing := &HttpViewing{
+		ID:        viewing.ID,
+		MovieID:   viewing.MovieID,
+		WatchedAt: viewing.WatchedAt.Format(time.RFC3339),
+		Location:  viewing.Location,
+		Rewatch:   viewing.Rewatch,
+		Notes:     viewing.Notes,
+		CreatedAt: viewing.CreatedAt,
+	}
+	if viewing.Movie != nil {
+		httpViewing.Movie = FromDomain(viewing.Movie)
+	}
+	return httpViewing
+}
+
+func (v *HttpViewing) ToDomain() (*domain.Viewing, error) {
+	viewing := &domain.Viewing{
+		ID:       v.ID,
+		MovieID:  v.MovieID,
+		Location: v.Location,
+		Rewatch:  v.Rewatch,
+		Notes:    v.Notes,
+	}
+	if v.WatchedAt != "" {
+		watchedAt, err := parseTime("watched_at", v.WatchedAt)
+		if err != nil {
+			return nil, err
+		}
+		if watchedAt.After(time.Now()) {
+			return nil, errors.New("watched_at cannot be in the future")
+		}
+		viewing.WatchedAt = watchedAt
+	}
+	return viewing, nil
+}
+
+type HttpDiaryDay struct {
+	Date     string         `json:"date"`
+	Count    int            `json:"count"`
+	Viewings []*HttpViewing `json:"viewings"`
+}
+
+type HttpDiary struct {
+	Year  int             `json:"year"`
+	Month int             `json:"month"`
+	Days  []*HttpDiaryDay `json:"days"`
+}
+
+func NewHttpViewingAdapter(viewingService port.ViewingService, movieService port.MovieService) *HttpViewingAdapter {
+	return &HttpViewingAdapter{
+		viewingService: viewingService,
+		movieService:   movieService,
+	}
+}
+
+// @Summary List viewings
+// @Description List the movies the logged in user watched, most recent first
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Param movie_id query int false "Only list the viewings of this movie"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of viewings per page"
+// @Success 200 {object} HttpPage[HttpViewing]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/viewings [get]
+// @Security ApiKeyAuth
+func (h *HttpViewingAdapter) ListViewings(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := port.ViewingFilter{}
+	movieID, err := queryInt(context, "movie_id")
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if movieID != nil {
+		id := uint(*movieID)
+		filter.MovieID = &id
+	}
+
+	domainViewings, total, err := h.viewingService.ListViewings(user.ID, filter, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	viewings := make([]*HttpViewing, len(domainViewings))
+	for i, viewing := range domainViewings {
+		viewings[i] = ViewingFromDomain(viewing)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, viewings, pagination, total))
+}
+
+// @Summary Log a viewing
+// @Description Log that the logged in user watched a movie. The viewing is flagged as a rewatch when they had already watched it before.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Param viewing body HttpViewing true "Viewing object"
+// @Success 201 {object} HttpViewing
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/viewings [post]
+// @Security ApiKeyAuth
+func (h *HttpViewingAdapter) LogViewing(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	viewing := HttpViewing{}
+	if err := context.BindJSON(&viewing); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainViewing, err := viewing.ToDomain()
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movie, err := h.movieService.GetMovie(viewing.MovieID)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Movie `%d` does not exist", viewing.MovieID)})
+		return
+	}
+
+	domainViewing.ID = 0
+	domainViewing.UserID = user.ID
+	if err := h.viewingService.LogViewing(domainViewing); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	domainViewing.Movie = movie
+
+	context.IndentedJSON(http.StatusCreated, ViewingFromDomain(domainViewing))
+}
+
+// @Summary Update a viewing
+// @Description Update a viewing logged by the logged in user. The movie of a viewing cannot be changed.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Param viewingId path int true "Viewing ID"
+// @Param viewing body HttpViewing true "Updated viewing object"
+// @Success 200 {object} HttpViewing
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/viewings/{viewingId} [put]
+// @Security ApiKeyAuth
+func (h *HttpViewingAdapter) UpdateViewing(context *gin.Context) {
+	viewingToUpdate, ok := h.getOwnViewing(context)
+	if !ok {
+		return
+	}
+
+	updatedViewing := HttpViewing{MovieID: viewingToUpdate.MovieID}
+	if err := context.BindJSON(&updatedViewing); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedDomainViewing, err := updatedViewing.ToDomain()
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updatedDomainViewing.ID = viewingToUpdate.ID
+	updatedDomainViewing.UserID = viewingToUpdate.UserID
+	updatedDomainViewing.MovieID = viewingToUpdate.MovieID // The movie cannot be changed
+	updatedDomainViewing.CreatedAt = viewingToUpdate.CreatedAt
+	if updatedDomainViewing.WatchedAt.IsZero() {
+		updatedDomainViewing.WatchedAt = viewingToUpdate.WatchedAt
+	}
+	if err := h.viewingService.UpdateViewing(updatedDomainViewing); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, ViewingFromDomain(updatedDomainViewing))
+}
+
+// @Summary Delete a viewing
+// @Description Delete a viewing logged by the logged in user
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Param viewingId path int true "Viewing ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/viewings/{viewingId} [delete]
+// @Security ApiKeyAuth
+func (h *HttpViewingAdapter) DeleteViewing(context *gin.Context) {
+	viewing, ok := h.getOwnViewing(context)
+	if !ok {
+		return
+	}
+
+	if err := h.viewingService.DeleteViewing(viewing); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Viewing deleted"})
+}
+
+// @Summary Get the watch diary
+// @Description Get the viewings of the logged in user during a month, grouped by day (in UTC). Defaults to the current month.
+// @Tags Viewings
+// @Accept json
+// @Produce json
+// @Param year query int false "Year"
+// @Param month query int false "Month, from 1 to 12"
+// @Success 200 {object} HttpDiary
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/diary [get]
+// @Security ApiKeyAuth
+func (h *HttpViewingAdapter) GetDiary(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	now := time.Now().UTC()
+	year, month := now.Year(), int(now.Month())
+	if value, err := queryInt(context, "year"); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if value != nil {
+		year = *value
+	}
+	if value, err := queryInt(context, "month"); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if value != nil {
+		month = *value
+	}
+	if year < 1 || year > 9999 {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid year `%d`", year)})
+		return
+	}
+	if month < 1 || month > 12 {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid month `%d`, must be between 1 and 12", month)})
+		return
+	}
+
+	domainDays, err := h.viewingService.GetDiary(user.ID, year, time.Month(month))
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	diary := &HttpDiary{
+		Year:  year,
+		Month: month,
+		Days:  make([]*HttpDiaryDay, len(domainDays)),
+	}
+	for i, domainDay := range domainDays {
+		day := &HttpDiaryDay{
+			Date:     domainDay.Date.Format(time.DateOnly),
+			Count:    len(domainDay.Viewings),
+			Viewings: make([]*HttpViewing, len(domainDay.Viewings)),
+		}
+		for j, viewing := range domainDay.Viewings {
+			day.Viewings[j] = ViewingFromDomain(viewing)
+		}
+		diary.Days[i] = day
+	}
+
+	context.IndentedJSON(http.StatusOK, diary)
+}
+
+// getOwnViewing loads the viewing in the `viewingId` path parameter, making
+// sure it belongs to the logged in user. It writes the error response and
+// returns false otherwise.
+func (h *HttpViewingAdapter) getOwnViewing(context *gin.Context) (*domain.Viewing, bool) {
+	viewingID, err := strconv.ParseUint(context.Param("viewingId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	viewing, err := h.viewingService.GetViewing(uint(viewingID))
+	if err != nil || viewing.UserID != user.ID {
+		context.IndentedJSON(http.StatusNotFound, gin.H{"error": "Viewing not found"})
+		return nil, false
+	}
+
+	return viewing, true
+}
@@ -0,0 +1,176 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogViewing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", UserID: 2},
+		},
+	}
+	mockViewingService := &mock.MockViewingService{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 1, MovieID: 1, WatchedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	httpAdapter := NewHttpViewingAdapter(mockViewingService, mockMovieService)
+
+	for _, testCase := range []struct {
+		body         string
+		expectedCode int
+	}{
+		{`{"watched_at": "2026-10-01"}`, http.StatusBadRequest},
+		{`{"movie_id": 2}`, http.StatusBadRequest},
+		{`{"movie_id": 1, "watched_at": "yesterday"}`, http.StatusBadRequest},
+		{`{"movie_id": 1, "watched_at": "2999-01-01"}`, http.StatusBadRequest},
+		{`{"movie_id": 1, "watched_at": "2026-10-01T21:30:00Z", "location": "Cinema"}`, http.StatusCreated},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/viewings", bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.LogViewing(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", testCase.expectedCode, testCase.body, mockResponseWriter.Code)
+		}
+		if mockResponseWriter.Code == http.StatusCreated {
+			viewing := HttpViewing{}
+			if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &viewing); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if !viewing.Rewatch {
+				t.Errorf("Expected the viewing to be a rewatch")
+			}
+			if viewing.Movie == nil || viewing.Movie.Title != "Inception" {
+				t.Errorf("Expected the watched movie, but got %+v", viewing.Movie)
+			}
+		}
+	}
+
+	if len(mockViewingService.Viewings) != 2 {
+		t.Errorf("Expected 2 viewings, but got %d", len(mockViewingService.Viewings))
+	}
+}
+
+func TestUpdateViewingOfAnotherUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockViewingService := &mock.MockViewingService{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 2, MovieID: 1, Notes: "Mine"},
+		},
+	}
+
+	httpAdapter := NewHttpViewingAdapter(mockViewingService, mockMovieService)
+
+	request, _ := http.NewRequest("PUT", "/user/me/viewings/1", bytes.NewBufferString(`{"notes": "Not yours"}`))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "viewingId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.UpdateViewing(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+	if mockViewingService.Viewings[0].Notes != "Mine" {
+		t.Errorf("Expected the viewing to be unchanged, but got notes '%s'", mockViewingService.Viewings[0].Notes)
+	}
+}
+
+func TestUpdateViewing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	watchedAt := time.Date(2026, 10, 1, 21, 30, 0, 0, time.UTC)
+	mockMovieService := &mock.MockMovieService{}
+	mockViewingService := &mock.MockViewingService{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 1, MovieID: 1, WatchedAt: watchedAt},
+		},
+	}
+
+	httpAdapter := NewHttpViewingAdapter(mockViewingService, mockMovieService)
+
+	request, _ := http.NewRequest("PUT", "/user/me/viewings/1", bytes.NewBufferString(`{"movie_id": 5, "notes": "Fell asleep"}`))
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "viewingId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.UpdateViewing(mockContext)
+
+	if mockResponseWriter.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, mockResponseWriter.Code)
+	}
+	viewing := mockViewingService.Viewings[0]
+	if viewing.Notes != "Fell asleep" || viewing.MovieID != 1 {
+		t.Errorf("Expected only the notes to change, but got %+v", viewing)
+	}
+	if !viewing.WatchedAt.Equal(watchedAt) {
+		t.Errorf("Expected the watched date to be kept, but got %v", viewing.WatchedAt)
+	}
+}
+
+func TestGetDiary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockViewingService := &mock.MockViewingService{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 1, MovieID: 1, WatchedAt: time.Date(2026, 10, 5, 21, 0, 0, 0, time.UTC)},
+			{ID: 2, UserID: 1, MovieID: 2, WatchedAt: time.Date(2026, 10, 5, 15, 0, 0, 0, time.UTC)},
+			{ID: 3, UserID: 1, MovieID: 3, WatchedAt: time.Date(2026, 9, 30, 15, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	httpAdapter := NewHttpViewingAdapter(mockViewingService, mockMovieService)
+
+	for query, expectedCode := range map[string]int{
+		"year=2026&month=10":  http.StatusOK,
+		"year=2026&month=13":  http.StatusBadRequest,
+		"year=2026&month=oct": http.StatusBadRequest,
+	} {
+		request, _ := http.NewRequest("GET", "/user/me/diary?"+query, nil)
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.GetDiary(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for '%s', but got %d", expectedCode, query, mockResponseWriter.Code)
+		}
+		if mockResponseWriter.Code != http.StatusOK {
+			continue
+		}
+
+		diary := HttpDiary{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &diary); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(diary.Days) != 1 {
+			t.Fatalf("Expected 1 day, but got %d", len(diary.Days))
+		}
+		if diary.Days[0].Date != "2026-10-05" || diary.Days[0].Count != 2 {
+			t.Errorf("Expected 2 viewings on 2026-10-05, but got %d on %s", diary.Days[0].Count, diary.Days[0].Date)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package httpadapter
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/gin-gonic/gin"
+)
+
+type HttpWatchlistAdapter struct {
+	watchlistService port.WatchlistService
+	movieService     port.MovieService
+}
+
+type HttpWatchlistEntry struct {
+	MovieID  uint       `json:"movie_id"`
+	Position int        `json:"position"`
+	Priority string     `json:"priority" binding:"omitempty,oneof=low normal high"`
+	AddedAt  time.Time  `json:"added_at"`
+	Movie    *HttpMovie `json:"movie,omitempty" binding:"-"`
+}
+
+func WatchlistEntryFromDomain(entry *domain.WatchlistEntry) *HttpWatchlistEntry {
+	httpEntry := &HttpWatchlistEntry{
+		MovieID:  entry.MovieID,
+		Position: entry.Position,
+		Priority: string(entry.Priority),
+		AddedAt:  entry.AddedAt,
+	}
+	if entry.Movie != nil {
+		httpEntry.Movie = FromDomain(entry.Movie)
+	}
+	return httpEntry
+}
+
+type HttpWatchlistOrder struct {
+	MovieIDs []uint `json:"movie_ids" binding:"required"`
+}
+
+func NewHttpWatchlistAdapter(watchlistService port.WatchlistService, movieService port.MovieService) *HttpWatchlistAdapter {
+	return &HttpWatchlistAdapter{
+		watchlistService: watchlistService,
+		movieService:     movieService,
+	}
+}
+
+// @Summary List the watchlist
+// @Description List the movies the logged in user wants to watch, in their order
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of movies per page"
+// @Success 200 {object} HttpPage[HttpWatchlistEntry]
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/watchlist [get]
+// @Security ApiKeyAuth
+func (h *HttpWatchlistAdapter) ListWatchlist(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	pagination, err := parsePagination(context)
+	if err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domainEntries, total, err := h.watchlistService.ListWatchlist(user.ID, pagination)
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	entries := make([]*HttpWatchlistEntry, len(domainEntries))
+	for i, entry := range domainEntries {
+		entries[i] = WatchlistEntryFromDomain(entry)
+	}
+
+	context.IndentedJSON(http.StatusOK, NewHttpPage(context.Request.URL, entries, pagination, total))
+}
+
+// @Summary Add a movie to the watchlist
+// @Description Add a movie at the end of the logged in user's watchlist. The request body is optional.
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param movieId path int true "Movie ID"
+// @Param entry body HttpWatchlistEntry false "Watchlist entry, only the priority is used"
+// @Success 201 {object} HttpWatchlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/watchlist/{movieId} [post]
+// @Security ApiKeyAuth
+func (h *HttpWatchlistAdapter) AddToWatchlist(context *gin.Context) {
+	movieID, err := strconv.ParseUint(context.Param("movieId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	entry := HttpWatchlistEntry{}
+	if context.Request.ContentLength != 0 {
+		if err := context.BindJSON(&entry); err != nil {
+			context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	movie, err := h.movieService.GetMovie(uint(movieID))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	if _, err := h.watchlistService.GetWatchlistEntry(user.ID, uint(movieID)); err == nil {
+		context.IndentedJSON(http.StatusConflict, gin.H{"error": "Movie already in watchlist"})
+		return
+	}
+
+	domainEntry := &domain.WatchlistEntry{
+		UserID:   user.ID,
+		MovieID:  uint(movieID),
+		Priority: domain.WatchlistPriority(entry.Priority),
+	}
+	if err := h.watchlistService.AddToWatchlist(domainEntry); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	domainEntry.Movie = movie
+
+	context.IndentedJSON(http.StatusCreated, WatchlistEntryFromDomain(domainEntry))
+}
+
+// @Summary Update a watchlist entry
+// @Description Change the priority of a movie in the logged in user's watchlist
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param movieId path int true "Movie ID"
+// @Param entry body HttpWatchlistEntry true "Watchlist entry, only the priority is used"
+// @Success 200 {object} HttpWatchlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/watchlist/{movieId} [put]
+// @Security ApiKeyAuth
+func (h *HttpWatchlistAdapter) UpdateWatchlistEntry(context *gin.Context) {
+	movieID, err := strconv.ParseUint(context.Param("movieId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	domainEntry, err := h.watchlistService.GetWatchlistEntry(user.ID, uint(movieID))
+	if err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	entry := HttpWatchlistEntry{}
+	if err := context.BindJSON(&entry); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if entry.Priority == "" {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": "priority is required"})
+		return
+	}
+
+	domainEntry.Priority = domain.WatchlistPriority(entry.Priority)
+	if err := h.watchlistService.UpdateWatchlistEntry(domainEntry); err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, WatchlistEntryFromDomain(domainEntry))
+}
+
+// @Summary Remove a movie from the watchlist
+// @Description Remove a movie from the logged in user's watchlist, moving the ones after it up
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param movieId path int true "Movie ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /user/me/watchlist/{movieId} [delete]
+// @Security ApiKeyAuth
+func (h *HttpWatchlistAdapter) RemoveFromWatchlist(context *gin.Context) {
+	movieID, err := strconv.ParseUint(context.Param("movieId"), 10, 64)
+	if err != nil {
+		context.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	entry := &domain.WatchlistEntry{
+		UserID:  user.ID,
+		MovieID: uint(movieID),
+	}
+	if err := h.watchlistService.RemoveFromWatchlist(entry); err != nil {
+		context.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+
+	context.IndentedJSON(http.StatusOK, gin.H{"status": "Movie removed from watchlist"})
+}
+
+// @Summary Reorder the watchlist
+// @Description Set the order of the logged in user's watchlist, listing every movie in it exactly once
+// @Tags Watchlist
+// @Accept json
+// @Produce json
+// @Param order body HttpWatchlistOrder true "Movie IDs in their new order"
+// @Success 200 {array} HttpWatchlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /user/me/watchlist/order [put]
+// @Security ApiKeyAuth
+func (h *HttpWatchlistAdapter) ReorderWatchlist(context *gin.Context) {
+	user, loggedIn := GetLoggedInUser(context)
+	if !loggedIn {
+		context.IndentedJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	order := HttpWatchlistOrder{}
+	if err := context.BindJSON(&order); err != nil {
+		context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.watchlistService.ReorderWatchlist(user.ID, order.MovieIDs); err != nil {
+		if errors.Is(err, domain.ErrInvalidWatchlistOrder) {
+			context.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	domainEntries, _, err := h.watchlistService.ListWatchlist(user.ID, port.Pagination{})
+	if err != nil {
+		context.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	entries := make([]*HttpWatchlistEntry, len(domainEntries))
+	for i, entry := range domainEntries {
+		entries[i] = WatchlistEntryFromDomain(entry)
+	}
+
+	context.IndentedJSON(http.StatusOK, entries)
+}
@@ -0,0 +1,120 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAddToWatchlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", UserID: 2},
+			{ID: 2, Title: "The Godfather", UserID: 2},
+		},
+	}
+	mockWatchlistService := &mock.MockWatchlistService{}
+
+	httpAdapter := NewHttpWatchlistAdapter(mockWatchlistService, mockMovieService)
+
+	for _, testCase := range []struct {
+		movieID      string
+		body         string
+		expectedCode int
+	}{
+		{"1", "", http.StatusCreated},
+		{"2", `{"priority": "high"}`, http.StatusCreated},
+		{"1", "", http.StatusConflict},
+		{"3", "", http.StatusNotFound},
+		{"2", `{"priority": "urgent"}`, http.StatusBadRequest},
+	} {
+		request, _ := http.NewRequest("POST", "/user/me/watchlist/"+testCase.movieID, bytes.NewBufferString(testCase.body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Params = gin.Params{gin.Param{Key: "movieId", Value: testCase.movieID}}
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.AddToWatchlist(mockContext)
+
+		if mockResponseWriter.Code != testCase.expectedCode {
+			t.Errorf("Expected status %d for movie %s with '%s', but got %d", testCase.expectedCode, testCase.movieID, testCase.body, mockResponseWriter.Code)
+		}
+	}
+
+	if len(mockWatchlistService.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, but got %d", len(mockWatchlistService.Entries))
+	}
+	if mockWatchlistService.Entries[1].Position != 2 || mockWatchlistService.Entries[1].Priority != domain.WatchlistPriorityHigh {
+		t.Errorf("Expected a high priority entry at position 2, but got %+v", mockWatchlistService.Entries[1])
+	}
+}
+
+func TestReorderWatchlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockWatchlistService := &mock.MockWatchlistService{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1},
+			{UserID: 1, MovieID: 2, Position: 2},
+		},
+	}
+
+	httpAdapter := NewHttpWatchlistAdapter(mockWatchlistService, mockMovieService)
+
+	for body, expectedCode := range map[string]int{
+		`{"movie_ids": [2, 3]}`: http.StatusBadRequest,
+		`{"movie_ids": [2, 1]}`: http.StatusOK,
+	} {
+		request, _ := http.NewRequest("PUT", "/user/me/watchlist/order", bytes.NewBufferString(body))
+		mockResponseWriter := httptest.NewRecorder()
+		mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+		mockContext.Request = request
+		mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+		httpAdapter.ReorderWatchlist(mockContext)
+
+		if mockResponseWriter.Code != expectedCode {
+			t.Errorf("Expected status %d for %s, but got %d", expectedCode, body, mockResponseWriter.Code)
+		}
+		if mockResponseWriter.Code != http.StatusOK {
+			continue
+		}
+
+		entries := []*HttpWatchlistEntry{}
+		if err := json.Unmarshal(mockResponseWriter.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(entries) != 2 || entries[0].MovieID != 2 || entries[0].Position != 1 {
+			t.Errorf("Expected movie 2 first, but got %+v", entries)
+		}
+	}
+}
+
+func TestRemoveFromWatchlistNotInList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockMovieService := &mock.MockMovieService{}
+	mockWatchlistService := &mock.MockWatchlistService{}
+
+	httpAdapter := NewHttpWatchlistAdapter(mockWatchlistService, mockMovieService)
+
+	request, _ := http.NewRequest("DELETE", "/user/me/watchlist/1", nil)
+	mockResponseWriter := httptest.NewRecorder()
+	mockContext, _ := gin.CreateTestContext(mockResponseWriter)
+	mockContext.Request = request
+	mockContext.Params = gin.Params{gin.Param{Key: "movieId", Value: "1"}}
+	mockContext.Set("id", &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"})
+
+	httpAdapter.RemoveFromWatchlist(mockContext)
+
+	if mockResponseWriter.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, but got %d", http.StatusNotFound, mockResponseWriter.Code)
+	}
+}
@@ -0,0 +1,45 @@
+package mailadapter
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// FileMailer writes mails to a file instead of sending them, for development.
+type FileMailer struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewFileMailer appends mails to the file at path, which is created if needed.
+// With an empty path the mails are written to the log.
+func NewFileMailer(path string) (*FileMailer, error) {
+	if path == "" {
+		return &FileMailer{writer: log.Writer()}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{writer: file}, nil
+}
+
+func (m *FileMailer) Send(mail *domain.Mail) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, err := fmt.Fprintf(
+		m.writer,
+		"----- %s -----\nTo: %s\nSubject: %s\n\n%s\n",
+		time.Now().Format(time.RFC3339), mail.To, mail.Subject, mail.Body,
+	)
+	return err
+}
@@ -0,0 +1,40 @@
+package mailadapter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestFileMailerSend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	mailer, err := NewFileMailer(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mails := []*domain.Mail{
+		{To: "test@test.com", Subject: "First", Body: "First body"},
+		{To: "test2@test.com", Subject: "Second", Body: "Second body"},
+	}
+	for _, mail := range mails {
+		if err := mailer.Send(mail); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Could not read the mail file: %v", err)
+	}
+	for _, mail := range mails {
+		for _, expected := range []string{"To: " + mail.To, "Subject: " + mail.Subject, mail.Body} {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("Expected the mail file to contain %q, got %q", expected, content)
+			}
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package mailadapter
+
+import (
+	"os"
+
+	"github.com/Acova/movie-collection/app/port"
+)
+
+// NewMailerFromEnv sends mails through the SMTP server in SMTP_HOST when it is
+// set. Otherwise mails are written to MAIL_FILE, or to the log when it is not
+// set either, which is enough for development.
+func NewMailerFromEnv() (port.Mailer, error) {
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		return NewSmtpMailer(
+			host,
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	}
+
+	return NewFileMailer(os.Getenv("MAIL_FILE"))
+}
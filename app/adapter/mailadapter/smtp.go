@@ -0,0 +1,67 @@
+package mailadapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+const defaultSmtpPort = "587"
+
+// SmtpMailer sends mails through an SMTP server. The server is only
+// authenticated against when a username is given.
+type SmtpMailer struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+func NewSmtpMailer(host string, port string, username string, password string, from string) (*SmtpMailer, error) {
+	if host == "" {
+		return nil, errors.New("smtp host is required")
+	}
+	if from == "" {
+		return nil, errors.New("mail sender is required")
+	}
+	if port == "" {
+		port = defaultSmtpPort
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SmtpMailer{
+		address: net.JoinHostPort(host, port),
+		auth:    auth,
+		from:    from,
+	}, nil
+}
+
+func (m *SmtpMailer) Send(mail *domain.Mail) error {
+	return smtp.SendMail(m.address, m.auth, m.from, []string{mail.To}, m.message(mail))
+}
+
+// message builds a plain text message with CRLF line endings.
+func (m *SmtpMailer) message(mail *domain.Mail) []byte {
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", m.from)
+	fmt.Fprintf(&message, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+
+	body := strings.ReplaceAll(mail.Body, "\r\n", "\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return message.Bytes()
+}
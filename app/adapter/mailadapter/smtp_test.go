@@ -0,0 +1,130 @@
+package mailadapter
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSmtpSink accepts a single SMTP session on a local port and sends the
+// mail it receives on the returned channel.
+func startSmtpSink(t *testing.T) (string, string, <-chan receivedMail) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Could not start the SMTP sink: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan receivedMail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		mail := receivedMail{}
+
+		reply("220 localhost ESMTP sink")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			upperCommand := strings.ToUpper(command)
+
+			switch {
+			case strings.HasPrefix(upperCommand, "EHLO"), strings.HasPrefix(upperCommand, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(upperCommand, "MAIL FROM:"):
+				mail.from = strings.Trim(command[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(upperCommand, "RCPT TO:"):
+				mail.to = append(mail.to, strings.Trim(command[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case upperCommand == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				mail.data = data.String()
+				reply("250 OK")
+			case upperCommand == "QUIT":
+				reply("221 Bye")
+				received <- mail
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSmtpMailerSend(t *testing.T) {
+	host, port, received := startSmtpSink(t)
+
+	mailer, err := NewSmtpMailer(host, port, "", "", "noreply@movies.test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = mailer.Send(&domain.Mail{
+		To:      "test@test.com",
+		Subject: "Reset your password",
+		Body:    "Hello,\nfollow this link.",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	mail := <-received
+	if mail.from != "noreply@movies.test" {
+		t.Errorf("Expected sender 'noreply@movies.test', got '%s'", mail.from)
+	}
+	if len(mail.to) != 1 || mail.to[0] != "test@test.com" {
+		t.Errorf("Expected recipient 'test@test.com', got %v", mail.to)
+	}
+	for _, expected := range []string{
+		"From: noreply@movies.test\r\n",
+		"To: test@test.com\r\n",
+		"Subject: Reset your password\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nHello,\r\nfollow this link.",
+	} {
+		if !strings.Contains(mail.data, expected) {
+			t.Errorf("Expected the message to contain %q, got %q", expected, mail.data)
+		}
+	}
+}
+
+func TestNewSmtpMailerRequiresHostAndSender(t *testing.T) {
+	if _, err := NewSmtpMailer("", "25", "", "", "noreply@movies.test"); err == nil {
+		t.Errorf("Expected an error without host")
+	}
+	if _, err := NewSmtpMailer("localhost", "25", "", "", ""); err == nil {
+		t.Errorf("Expected an error without sender")
+	}
+}
@@ -0,0 +1,118 @@
+package oidcadapter
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// minRSAKeyBits is the smallest RSA key ID tokens are accepted from.
+const minRSAKeyBits = 2048
+
+// jsonWebKeySet is the document of the jwks_uri of a provider, with the keys
+// it signs ID tokens with (RFC 7517).
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// signingKeys returns the public keys of a key set that are used for
+// signatures, by key ID. Keys of other types are skipped, as providers may
+// publish keys the app does not need.
+func (s *jsonWebKeySet) signingKeys() (map[string]crypto.PublicKey, error) {
+	keys := map[string]crypto.PublicKey{}
+	for _, key := range s.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		var publicKey crypto.PublicKey
+		var err error
+		switch key.Kty {
+		case "RSA":
+			publicKey, err = key.rsaPublicKey()
+		case "EC":
+			publicKey, err = key.ecdsaPublicKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", key.Kid, err)
+		}
+		keys[key.Kid] = publicKey
+	}
+	return keys, nil
+}
+
+func (k *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := decodeKeyInt(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeKeyInt(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if n.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA key of %d bits is too short", n.BitLen())
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func (k *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+
+	x, err := decodeKeyInt(k.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeKeyInt(k.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on the curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// decodeKeyInt decodes an unsigned big-endian integer of a key, encoded in
+// unpadded base64url.
+func decodeKeyInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("missing key parameter")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
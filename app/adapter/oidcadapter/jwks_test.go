@@ -0,0 +1,58 @@
+package oidcadapter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+)
+
+func TestSigningKeys(t *testing.T) {
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	encode := base64.RawURLEncoding.EncodeToString
+
+	keySet := &jsonWebKeySet{Keys: []jsonWebKey{
+		{Kty: "EC", Kid: "ec", Crv: "P-256", X: encode(ecdsaKey.X.Bytes()), Y: encode(ecdsaKey.Y.Bytes())},
+		{Kty: "RSA", Kid: "rsa", Use: "sig", N: encode(rsaKey.N.Bytes()), E: "AQAB"},
+		{Kty: "RSA", Kid: "encryption", Use: "enc", N: encode(rsaKey.N.Bytes()), E: "AQAB"},
+		{Kty: "oct", Kid: "symmetric"},
+	}}
+	keys, err := keySet.signingKeys()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected only the 2 signing keys, got %d", len(keys))
+	}
+	if key, ok := keys["ec"].(*ecdsa.PublicKey); !ok || !key.Equal(&ecdsaKey.PublicKey) {
+		t.Errorf("Expected the EC key, got %v", keys["ec"])
+	}
+	if key, ok := keys["rsa"].(*rsa.PublicKey); !ok || !key.Equal(&rsaKey.PublicKey) {
+		t.Errorf("Expected the RSA key, got %v", keys["rsa"])
+	}
+}
+
+func TestSigningKeysRejectsInvalidKeys(t *testing.T) {
+	shortKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	encode := base64.RawURLEncoding.EncodeToString
+
+	tests := []struct {
+		name string
+		key  jsonWebKey
+	}{
+		{"Short RSA key", jsonWebKey{Kty: "RSA", N: encode(shortKey.N.Bytes()), E: "AQAB"}},
+		{"Missing exponent", jsonWebKey{Kty: "RSA", N: encode(shortKey.N.Bytes())}},
+		{"Unknown curve", jsonWebKey{Kty: "EC", Crv: "P-192", X: "AQ", Y: "AQ"}},
+		{"Point off the curve", jsonWebKey{Kty: "EC", Crv: "P-256", X: "AQ", Y: "AQ"}},
+	}
+
+	for _, test := range tests {
+		keySet := &jsonWebKeySet{Keys: []jsonWebKey{test.key}}
+		if _, err := keySet.signingKeys(); err == nil {
+			t.Errorf("%s: expected the key to be rejected", test.name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package oidcadapter
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/Acova/movie-collection/app/port"
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// NewProvidersFromEnv returns the providers named in OIDC_PROVIDERS, separated
+// by commas, by name. Each of them is configured with OIDC_<NAME>_ISSUER,
+// OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL
+// and OIDC_<NAME>_SCOPES, with the name in upper case and dashes as
+// underscores. No providers are configured when OIDC_PROVIDERS is not set.
+func NewProvidersFromEnv() (map[string]port.OIDCProvider, error) {
+	providers := map[string]port.OIDCProvider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !providerNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid OpenID Connect provider name %q", name)
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider, err := NewProvider(ProviderConfig{
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", name, err)
+		}
+		providers[name] = provider
+	}
+	return providers, nil
+}
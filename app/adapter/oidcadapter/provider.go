@@ -0,0 +1,336 @@
+package oidcadapter
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// requestTimeout is how long a request to a provider can take.
+	requestTimeout = 10 * time.Second
+	// maxResponseBytes is the largest response read from a provider.
+	maxResponseBytes = 1 << 20
+	// keysRefreshInterval is how often the keys of a provider are fetched
+	// again at most, when an ID token is signed with an unknown key.
+	keysRefreshInterval = time.Minute
+	// clockSkew is how far the clock of a provider can be off when checking
+	// when an ID token was issued and expires.
+	clockSkew = time.Minute
+)
+
+// defaultScopes are asked for when a provider is not configured with scopes.
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// signingMethods are the algorithms ID tokens are accepted with. Symmetric ones
+// are left out, as they would be keyed with the client secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// ProviderConfig is the client registration of the app at an OpenID Connect
+// provider. Public clients have no ClientSecret.
+type ProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// providerMetadata is the discovery document of a provider, of which only the
+// fields the app uses are read.
+type providerMetadata struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// idTokenClaims are the claims of an ID token the app checks or uses.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string    `json:"azp"`
+	Nonce           string    `json:"nonce"`
+	Email           string    `json:"email"`
+	EmailVerified   claimBool `json:"email_verified"`
+	Name            string    `json:"name"`
+}
+
+// claimBool is a boolean claim, which some providers give as a string.
+type claimBool bool
+
+func (b *claimBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case bool:
+		*b = claimBool(value)
+	case string:
+		*b = claimBool(value == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// Provider logs users in through an OpenID Connect provider with the
+// authorization code flow and PKCE. Its discovery document is only fetched
+// when it is first used, so that the app starts while the provider is down,
+// and its keys are fetched again when it rotates them.
+type Provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	metadataMutex sync.Mutex
+	metadata      *providerMetadata
+
+	keysMutex     sync.Mutex
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(config ProviderConfig, client *http.Client) (*Provider, error) {
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("an OpenID Connect provider needs an issuer, a client ID and a redirect URL")
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultScopes
+	}
+	if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+
+	return &Provider{
+		config: config,
+		client: client,
+	}, nil
+}
+
+func (p *Provider) AuthorizationURL(state string, nonce string, codeChallenge string) (string, error) {
+	metadata, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	authorizationURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+
+	return authorizationURL.String(), nil
+}
+
+func (p *Provider) Exchange(code string, codeVerifier string, nonce string) (*domain.OIDCClaims, error) {
+	metadata, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	request, err := http.NewRequest("POST", metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// The credentials are form encoded first (RFC 6749, section 2.3.1)
+		request.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	tokens := &tokenResponse{}
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, tokens); err != nil && response.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if response.StatusCode >= 400 && response.StatusCode < 500 {
+		return nil, fmt.Errorf("%w: %s %s", domain.ErrOIDCLoginFailed, tokens.Error, tokens.ErrorDescription)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint answered with status %d", response.StatusCode)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: no ID token was given", domain.ErrOIDCLoginFailed)
+	}
+
+	return p.validateIDToken(metadata, tokens.IDToken, nonce)
+}
+
+// validateIDToken checks that an ID token is signed by the provider, issued by
+// it for this client and the nonce of the login, and not expired (OpenID
+// Connect Core, section 3.1.3.7).
+func (p *Provider) validateIDToken(metadata *providerMetadata, idToken string, nonce string) (*domain.OIDCClaims, error) {
+	claims := &idTokenClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return p.key(metadata, keyID)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrOIDCLoginFailed, err)
+	}
+
+	now := time.Now()
+	var problem string
+	switch {
+	case !claims.VerifyIssuer(metadata.Issuer, true):
+		problem = "the ID token was issued by someone else"
+	case !claims.VerifyAudience(p.config.ClientID, true):
+		problem = "the ID token was issued for another client"
+	case len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID:
+		problem = "the ID token was issued for another party"
+	case !claims.VerifyExpiresAt(now.Add(-clockSkew), true):
+		problem = "the ID token has expired"
+	case !claims.VerifyIssuedAt(now.Add(clockSkew), false):
+		problem = "the ID token was issued in the future"
+	case claims.Nonce != nonce:
+		problem = "the ID token was issued for another login"
+	case claims.Subject == "":
+		problem = "the ID token has no subject"
+	}
+	if problem != "" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrOIDCLoginFailed, problem)
+	}
+
+	return &domain.OIDCClaims{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// discover returns the discovery document of the provider, fetching it the
+// first time.
+func (p *Provider) discover() (*providerMetadata, error) {
+	p.metadataMutex.Lock()
+	defer p.metadataMutex.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	metadata := &providerMetadata{}
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(discoveryURL, metadata); err != nil {
+		return nil, fmt.Errorf("discovering %s: %w", p.config.Issuer, err)
+	}
+	if metadata.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("discovering %s: the provider calls itself %s", p.config.Issuer, metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("discovering %s: missing endpoints", p.config.Issuer)
+	}
+	if len(metadata.CodeChallengeMethodsSupported) > 0 && !slices.Contains(metadata.CodeChallengeMethodsSupported, "S256") {
+		return nil, fmt.Errorf("discovering %s: PKCE with S256 is not supported", p.config.Issuer)
+	}
+
+	p.metadata = metadata
+	return metadata, nil
+}
+
+// key returns the key of the provider with an ID, or its only key when the ID
+// is empty. The keys are fetched again for unknown IDs, as the provider may
+// have rotated them, though not more than once per keysRefreshInterval.
+func (p *Provider) key(metadata *providerMetadata, keyID string) (crypto.PublicKey, error) {
+	p.keysMutex.Lock()
+	defer p.keysMutex.Unlock()
+
+	if key := p.findKey(keyID); key != nil {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", keyID)
+	}
+
+	keySet := &jsonWebKeySet{}
+	if err := p.getJSON(metadata.JWKSURI, keySet); err != nil {
+		return nil, fmt.Errorf("fetching the signing keys: %w", err)
+	}
+	keys, err := keySet.signingKeys()
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key := p.findKey(keyID); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", keyID)
+}
+
+func (p *Provider) findKey(keyID string) crypto.PublicKey {
+	if keyID == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return p.keys[keyID]
+}
+
+func (p *Provider) getJSON(url string, value interface{}) error {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered with status %d", url, response.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(response.Body, maxResponseBytes)).Decode(value)
+}
@@ -0,0 +1,331 @@
+package oidcadapter
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/util"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// testAuthorization is what the test issuer keeps of a code it gave.
+type testAuthorization struct {
+	codeChallenge string
+	nonce         string
+	redirectURL   string
+}
+
+// testIssuer is a local OpenID Connect provider. It hands out codes for the
+// authorization URLs it is given as if the user signed in, and exchanges them
+// for ID tokens signed with its RSA key, checking the client secret and the
+// PKCE code verifier.
+type testIssuer struct {
+	t              *testing.T
+	server         *httptest.Server
+	key            *rsa.PrivateKey
+	keyID          string
+	clientSecret   string
+	authorizations map[string]testAuthorization
+	keyFetches     int
+	// tamper changes the claims of the next ID tokens before they are signed.
+	tamper func(claims jwt.MapClaims)
+	// sign signs the next ID tokens instead of the key of the issuer.
+	sign func(token *jwt.Token) (string, error)
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{
+		t:              t,
+		key:            generateTestKey(t),
+		keyID:          "key-1",
+		clientSecret:   "client secret",
+		authorizations: map[string]testAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                           issuer.server.URL,
+			"authorization_endpoint":           issuer.server.URL + "/authorize?prompt=login",
+			"token_endpoint":                   issuer.server.URL + "/token",
+			"jwks_uri":                         issuer.server.URL + "/keys",
+			"code_challenge_methods_supported": []string{"plain", "S256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.keyFetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": issuer.keyID,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func generateTestKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate a key: %v", err)
+	}
+	return key
+}
+
+func (i *testIssuer) provider() *Provider {
+	provider, err := NewProvider(ProviderConfig{
+		Issuer:       i.server.URL,
+		ClientID:     "movie-collection",
+		ClientSecret: i.clientSecret,
+		RedirectURL:  "http://localhost:8080/login/oidc/test/callback",
+	}, i.server.Client())
+	if err != nil {
+		i.t.Fatalf("Failed to create the provider: %v", err)
+	}
+	return provider
+}
+
+// authorize signs the user in at an authorization URL, and returns the code
+// the issuer sends them back with.
+func (i *testIssuer) authorize(authorizationURL string) string {
+	parsedURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		i.t.Fatalf("Invalid authorization URL %s: %v", authorizationURL, err)
+	}
+	query := parsedURL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != "movie-collection" || query.Get("code_challenge_method") != "S256" || query.Get("prompt") != "login" {
+		i.t.Fatalf("Unexpected authorization URL %s", authorizationURL)
+	}
+
+	code := "code-" + query.Get("state")
+	i.authorizations[code] = testAuthorization{
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		redirectURL:   query.Get("redirect_uri"),
+	}
+	return code
+}
+
+func (i *testIssuer) token(w http.ResponseWriter, r *http.Request) {
+	fail := func(status int, code string) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != "movie-collection" || clientSecret != url.QueryEscape(i.clientSecret) {
+		fail(http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	authorization, ok := i.authorizations[r.PostFormValue("code")]
+	delete(i.authorizations, r.PostFormValue("code"))
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != authorization.redirectURL || util.PKCECodeChallenge(r.PostFormValue("code_verifier")) != authorization.codeChallenge {
+		fail(http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            i.server.URL,
+		"aud":            "movie-collection",
+		"sub":            "subject",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          "test@test.com",
+		"email_verified": true,
+		"name":           "Test User",
+	}
+	if i.tamper != nil {
+		i.tamper(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.keyID
+	var idToken string
+	var err error
+	if i.sign != nil {
+		idToken, err = i.sign(token)
+	} else {
+		idToken, err = token.SignedString(i.key)
+	}
+	if err != nil {
+		fail(http.StatusInternalServerError, "server_error")
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "access token", "token_type": "Bearer", "id_token": idToken})
+}
+
+// login logs in with the issuer through the provider.
+func (i *testIssuer) login(provider *Provider) (*domain.OIDCClaims, error) {
+	codeVerifier, _ := util.GenerateToken(32)
+	authorizationURL, err := provider.AuthorizationURL("state", "nonce", util.PKCECodeChallenge(codeVerifier))
+	if err != nil {
+		i.t.Fatalf("Failed to get the authorization URL: %v", err)
+	}
+	return provider.Exchange(i.authorize(authorizationURL), codeVerifier, "nonce")
+}
+
+func TestProviderLogin(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+
+	claims, err := issuer.login(provider)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectedClaims := domain.OIDCClaims{Subject: "subject", Email: "test@test.com", EmailVerified: true, Name: "Test User"}
+	if *claims != expectedClaims {
+		t.Errorf("Expected the claims %+v, got %+v", expectedClaims, claims)
+	}
+
+	if _, err := issuer.login(provider); err != nil {
+		t.Fatalf("Expected a second login to work, got %v", err)
+	}
+	if issuer.keyFetches != 1 {
+		t.Errorf("Expected the keys to be fetched once, got %d", issuer.keyFetches)
+	}
+}
+
+func TestProviderChecksCodeVerifier(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+
+	codeVerifier, _ := util.GenerateToken(32)
+	authorizationURL, _ := provider.AuthorizationURL("state", "nonce", util.PKCECodeChallenge(codeVerifier))
+	code := issuer.authorize(authorizationURL)
+
+	if _, err := provider.Exchange(code, "another verifier", "nonce"); !errors.Is(err, domain.ErrOIDCLoginFailed) || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Expected the code to be rejected, got %v", err)
+	}
+}
+
+func TestProviderRejectsInvalidIDTokens(t *testing.T) {
+	otherKey := generateTestKey(t)
+
+	tests := []struct {
+		name   string
+		tamper func(claims jwt.MapClaims)
+		sign   func(token *jwt.Token) (string, error)
+	}{
+		{"Other issuer", func(claims jwt.MapClaims) { claims["iss"] = "https://other.example.com" }, nil},
+		{"Other audience", func(claims jwt.MapClaims) { claims["aud"] = "other-client" }, nil},
+		{"Other authorized party", func(claims jwt.MapClaims) {
+			claims["aud"] = []string{"movie-collection", "other-client"}
+			claims["azp"] = "other-client"
+		}, nil},
+		{"Expired", func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-2 * time.Minute).Unix() }, nil},
+		{"No expiry", func(claims jwt.MapClaims) { delete(claims, "exp") }, nil},
+		{"Issued in the future", func(claims jwt.MapClaims) { claims["iat"] = time.Now().Add(time.Hour).Unix() }, nil},
+		{"Other nonce", func(claims jwt.MapClaims) { claims["nonce"] = "other nonce" }, nil},
+		{"No subject", func(claims jwt.MapClaims) { delete(claims, "sub") }, nil},
+		{"Other key", nil, func(token *jwt.Token) (string, error) { return token.SignedString(otherKey) }},
+		{"Client secret", nil, func(token *jwt.Token) (string, error) {
+			token.Method = jwt.SigningMethodHS256
+			token.Header["alg"] = "HS256"
+			return token.SignedString([]byte("client secret"))
+		}},
+		{"Unsigned", nil, func(token *jwt.Token) (string, error) {
+			token.Method = jwt.SigningMethodNone
+			token.Header["alg"] = "none"
+			return token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		}},
+	}
+
+	for _, test := range tests {
+		issuer := newTestIssuer(t)
+		issuer.tamper = test.tamper
+		issuer.sign = test.sign
+
+		if _, err := issuer.login(issuer.provider()); !errors.Is(err, domain.ErrOIDCLoginFailed) {
+			t.Errorf("%s: expected %v, got %v", test.name, domain.ErrOIDCLoginFailed, err)
+		}
+	}
+}
+
+func TestProviderReadsEmailVerifiedStrings(t *testing.T) {
+	issuer := newTestIssuer(t)
+	issuer.tamper = func(claims jwt.MapClaims) { claims["email_verified"] = "true" }
+
+	claims, err := issuer.login(issuer.provider())
+	if err != nil || !claims.EmailVerified {
+		t.Errorf("Expected the email to be verified, got %+v and %v", claims, err)
+	}
+}
+
+func TestProviderFetchesRotatedKeys(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := issuer.provider()
+	if _, err := issuer.login(provider); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	issuer.key = generateTestKey(t)
+	issuer.keyID = "key-2"
+	// Keys are only fetched again once a while after the last time
+	provider.keysFetchedAt = time.Now().Add(-keysRefreshInterval)
+
+	if _, err := issuer.login(provider); err != nil {
+		t.Fatalf("Expected the new key to be fetched, got %v", err)
+	}
+	if issuer.keyFetches != 2 {
+		t.Errorf("Expected the keys to be fetched twice, got %d", issuer.keyFetches)
+	}
+}
+
+func TestProviderChecksDiscoveredIssuer(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider, _ := NewProvider(ProviderConfig{
+		Issuer:      issuer.server.URL + "/",
+		ClientID:    "movie-collection",
+		RedirectURL: "http://localhost:8080/login/oidc/test/callback",
+	}, issuer.server.Client())
+
+	if _, err := provider.AuthorizationURL("state", "nonce", "challenge"); err == nil {
+		t.Error("Expected a provider calling itself something else to be rejected")
+	}
+}
+
+func TestNewProvidersFromEnv(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "company, other-sso")
+	t.Setenv("OIDC_COMPANY_ISSUER", "https://sso.example.com")
+	t.Setenv("OIDC_COMPANY_CLIENT_ID", "movie-collection")
+	t.Setenv("OIDC_COMPANY_REDIRECT_URL", "http://localhost:8080/login/oidc/company/callback")
+	t.Setenv("OIDC_OTHER_SSO_ISSUER", "https://other.example.com")
+	t.Setenv("OIDC_OTHER_SSO_CLIENT_ID", "movie-collection")
+	t.Setenv("OIDC_OTHER_SSO_REDIRECT_URL", "http://localhost:8080/login/oidc/other-sso/callback")
+	t.Setenv("OIDC_OTHER_SSO_SCOPES", "email")
+
+	providers, err := NewProvidersFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(providers) != 2 {
+		t.Fatalf("Expected 2 providers, got %d", len(providers))
+	}
+	if scopes := providers["other-sso"].(*Provider).config.Scopes; strings.Join(scopes, " ") != "openid email" {
+		t.Errorf("Expected the openid scope to be added, got %v", scopes)
+	}
+
+	t.Setenv("OIDC_COMPANY_CLIENT_ID", "")
+	if _, err := NewProvidersFromEnv(); err == nil {
+		t.Error("Expected a provider without a client ID to be rejected")
+	}
+}
@@ -0,0 +1,163 @@
+package postgresadapter
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// PostgresAccessToken is a personal access token, of which only the hash is
+// stored. Its scopes are kept space separated, like OAuth scopes. Tokens are
+// removed along with their user.
+type PostgresAccessToken struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"not null"`
+	TokenHash  string `gorm:"not null;uniqueIndex"`
+	Scopes     string `gorm:"not null"`
+	CreatedAt  time.Time
+	LastUsedAt *time.Time   `gorm:"default:NULL"`
+	ExpiresAt  *time.Time   `gorm:"default:NULL"`
+	RevokedAt  *time.Time   `gorm:"default:NULL"`
+	User       PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresAccessToken) TableName() string {
+	return "personal_access_token"
+}
+
+func (t *PostgresAccessToken) ToDomain() *domain.PersonalAccessToken {
+	accessToken := &domain.PersonalAccessToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Name:      t.Name,
+		TokenHash: t.TokenHash,
+		Scopes:    []domain.Scope{},
+		CreatedAt: t.CreatedAt,
+	}
+	for _, scope := range strings.Fields(t.Scopes) {
+		accessToken.Scopes = append(accessToken.Scopes, domain.Scope(scope))
+	}
+	if t.LastUsedAt != nil {
+		accessToken.LastUsedAt = *t.LastUsedAt
+	}
+	if t.ExpiresAt != nil {
+		accessToken.ExpiresAt = *t.ExpiresAt
+	}
+	if t.RevokedAt != nil {
+		accessToken.RevokedAt = *t.RevokedAt
+	}
+	return accessToken
+}
+
+func AccessTokenFromDomain(accessToken *domain.PersonalAccessToken) *PostgresAccessToken {
+	scopes := make([]string, len(accessToken.Scopes))
+	for i, scope := range accessToken.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	postgresAccessToken := &PostgresAccessToken{
+		ID:        accessToken.ID,
+		UserID:    accessToken.UserID,
+		Name:      accessToken.Name,
+		TokenHash: accessToken.TokenHash,
+		Scopes:    strings.Join(scopes, " "),
+		CreatedAt: accessToken.CreatedAt,
+	}
+	if !accessToken.LastUsedAt.IsZero() {
+		postgresAccessToken.LastUsedAt = &accessToken.LastUsedAt
+	}
+	if !accessToken.ExpiresAt.IsZero() {
+		postgresAccessToken.ExpiresAt = &accessToken.ExpiresAt
+	}
+	if !accessToken.RevokedAt.IsZero() {
+		postgresAccessToken.RevokedAt = &accessToken.RevokedAt
+	}
+	return postgresAccessToken
+}
+
+type PostgresAccessTokenRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresAccessTokenRepository(postgres *PostgresDBConnection) (*PostgresAccessTokenRepository, error) {
+	return &PostgresAccessTokenRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresAccessTokenRepository) CreateAccessToken(accessToken *domain.PersonalAccessToken) error {
+	postgresAccessToken := AccessTokenFromDomain(accessToken)
+	if result := repository.postgres.DB.Omit("User").Create(postgresAccessToken); result.Error != nil {
+		return result.Error
+	}
+	accessToken.ID = postgresAccessToken.ID
+	accessToken.CreatedAt = postgresAccessToken.CreatedAt
+	return nil
+}
+
+func (repository *PostgresAccessTokenRepository) GetAccessToken(id uint) (*domain.PersonalAccessToken, error) {
+	var postgresAccessToken PostgresAccessToken
+	result := repository.postgres.DB.First(&postgresAccessToken, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresAccessToken.ToDomain(), nil
+}
+
+func (repository *PostgresAccessTokenRepository) GetAccessTokenByHash(tokenHash string) (*domain.PersonalAccessToken, error) {
+	var postgresAccessToken PostgresAccessToken
+	result := repository.postgres.DB.Where("token_hash = ?", tokenHash).First(&postgresAccessToken)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresAccessToken.ToDomain(), nil
+}
+
+func (repository *PostgresAccessTokenRepository) ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error) {
+	var postgresAccessTokens []PostgresAccessToken
+	result := repository.postgres.DB.
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at DESC, id DESC").
+		Find(&postgresAccessTokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	accessTokens := make([]*domain.PersonalAccessToken, len(postgresAccessTokens))
+	for i, postgresAccessToken := range postgresAccessTokens {
+		accessTokens[i] = postgresAccessToken.ToDomain()
+	}
+	return accessTokens, nil
+}
+
+func (repository *PostgresAccessTokenRepository) RevokeAccessToken(id uint, revokedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresAccessToken{}).Where("id = ?", id).Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("personal access token not found")
+	}
+	return nil
+}
+
+func (repository *PostgresAccessTokenRepository) RevokeAccessTokens(userID uint, revokedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt)
+	return result.Error
+}
+
+func (repository *PostgresAccessTokenRepository) SetAccessTokenLastUsed(id uint, lastUsedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresAccessToken{}).Where("id = ?", id).Update("last_used_at", lastUsedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("personal access token not found")
+	}
+	return nil
+}
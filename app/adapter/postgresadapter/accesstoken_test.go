@@ -0,0 +1,50 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresAccessTokenReturnsTableName(t *testing.T) {
+	expectedTableName := "personal_access_token"
+	actualTableName := PostgresAccessToken{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresAccessTokenToDomain(t *testing.T) {
+	expiresAt := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	postgresAccessToken := PostgresAccessToken{ID: 1, UserID: 2, Name: "backup script", TokenHash: "hash", Scopes: "movies:read lists:read", ExpiresAt: &expiresAt}
+
+	accessToken := postgresAccessToken.ToDomain()
+
+	if accessToken.ID != 1 || accessToken.UserID != 2 || accessToken.Name != "backup script" || accessToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 named 'backup script', got %+v", accessToken)
+	}
+	if len(accessToken.Scopes) != 2 || !accessToken.HasScope(domain.ScopeMoviesRead) || !accessToken.HasScope(domain.ScopeListsRead) {
+		t.Errorf("Expected scopes movies:read and lists:read, got %v", accessToken.Scopes)
+	}
+	if !accessToken.ExpiresAt.Equal(expiresAt) || !accessToken.LastUsedAt.IsZero() || !accessToken.RevokedAt.IsZero() {
+		t.Errorf("Expected only an expiry date, got %+v", accessToken)
+	}
+}
+
+func TestPostgresAccessTokenFromDomain(t *testing.T) {
+	accessToken := &domain.PersonalAccessToken{ID: 1, UserID: 2, Name: "backup script", TokenHash: "hash", Scopes: []domain.Scope{domain.ScopeMoviesRead, domain.ScopeListsWrite}}
+
+	postgresAccessToken := AccessTokenFromDomain(accessToken)
+
+	if postgresAccessToken.ID != 1 || postgresAccessToken.UserID != 2 || postgresAccessToken.Name != "backup script" || postgresAccessToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 named 'backup script', got %+v", postgresAccessToken)
+	}
+	if postgresAccessToken.Scopes != "movies:read lists:write" {
+		t.Errorf("Expected scopes 'movies:read lists:write', got '%s'", postgresAccessToken.Scopes)
+	}
+	if postgresAccessToken.LastUsedAt != nil || postgresAccessToken.ExpiresAt != nil || postgresAccessToken.RevokedAt != nil {
+		t.Errorf("Expected no dates, got %+v", postgresAccessToken)
+	}
+}
@@ -0,0 +1,100 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresFavourite is the join table between users and the movies they have
+// marked as favourites.
+type PostgresFavourite struct {
+	UserID    uint          `gorm:"primaryKey"`
+	MovieID   uint          `gorm:"primaryKey"`
+	CreatedAt time.Time     `gorm:"not null"`
+	User      PostgresUser  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Movie     PostgresMovie `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresFavourite) TableName() string {
+	return "favourite"
+}
+
+func (f *PostgresFavourite) ToDomain() *domain.Favourite {
+	return &domain.Favourite{
+		UserID:    f.UserID,
+		MovieID:   f.MovieID,
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func FavouriteFromDomain(favourite *domain.Favourite) *PostgresFavourite {
+	return &PostgresFavourite{
+		UserID:    favourite.UserID,
+		MovieID:   favourite.MovieID,
+		CreatedAt: favourite.CreatedAt,
+	}
+}
+
+type PostgresFavouritesRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresFavouritesRepository(postgres *PostgresDBConnection) (*PostgresFavouritesRepository, error) {
+	return &PostgresFavouritesRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresFavouritesRepository) AddFavourite(favourite *domain.Favourite) error {
+	postgresFavourite := FavouriteFromDomain(favourite)
+
+	// Favouriting the same movie twice is a no-op.
+	result := repository.postgres.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(postgresFavourite)
+	return result.Error
+}
+
+func (repository *PostgresFavouritesRepository) RemoveFavourite(favourite *domain.Favourite) error {
+	result := repository.postgres.DB.
+		Where("user_id = ? AND movie_id = ?", favourite.UserID, favourite.MovieID).
+		Delete(&PostgresFavourite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("favourite not found")
+	}
+	return nil
+}
+
+func (repository *PostgresFavouritesRepository) ListFavourites(userID uint, pagination port.Pagination) ([]*domain.Movie, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresMovie{}).
+		Joins("JOIN favourite ON favourite.movie_id = movie.id").
+		Where("favourite.user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresMovies []PostgresMovie
+	result := db.Order("favourite.created_at DESC").
+		Order("movie.id").
+		Scopes(paginate(pagination), preloadGenres).
+		Find(&postgresMovies)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	movies := make([]*domain.Movie, len(postgresMovies))
+	for i, postgresMovie := range postgresMovies {
+		movies[i] = postgresMovie.ToDomain()
+	}
+
+	return movies, total, nil
+}
@@ -0,0 +1,54 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresFavouriteReturnsTableName(t *testing.T) {
+	expectedTableName := "favourite"
+	actualTableName := PostgresFavourite{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresFavouriteToDomain(t *testing.T) {
+	now := time.Now()
+	postgresFavourite := PostgresFavourite{
+		UserID:    1,
+		MovieID:   2,
+		CreatedAt: now,
+	}
+
+	domainFavourite := postgresFavourite.ToDomain()
+
+	if domainFavourite.UserID != postgresFavourite.UserID {
+		t.Errorf("Expected user ID %d, got %d", postgresFavourite.UserID, domainFavourite.UserID)
+	}
+	if domainFavourite.MovieID != postgresFavourite.MovieID {
+		t.Errorf("Expected movie ID %d, got %d", postgresFavourite.MovieID, domainFavourite.MovieID)
+	}
+	if !domainFavourite.CreatedAt.Equal(now) {
+		t.Errorf("Expected created at %s, got %s", now, domainFavourite.CreatedAt)
+	}
+}
+
+func TestPostgresFavouriteFromDomain(t *testing.T) {
+	domainFavourite := &domain.Favourite{
+		UserID:  1,
+		MovieID: 2,
+	}
+
+	postgresFavourite := FavouriteFromDomain(domainFavourite)
+
+	if postgresFavourite.UserID != domainFavourite.UserID {
+		t.Errorf("Expected user ID %d, got %d", domainFavourite.UserID, postgresFavourite.UserID)
+	}
+	if postgresFavourite.MovieID != domainFavourite.MovieID {
+		t.Errorf("Expected movie ID %d, got %d", domainFavourite.MovieID, postgresFavourite.MovieID)
+	}
+}
@@ -0,0 +1,105 @@
+package postgresadapter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type PostgresGenre struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (PostgresGenre) TableName() string {
+	return "genre"
+}
+
+func (g *PostgresGenre) ToDomain() *domain.Genre {
+	return &domain.Genre{
+		ID:   g.ID,
+		Name: g.Name,
+	}
+}
+
+func GenreFromDomain(genre *domain.Genre) *PostgresGenre {
+	return &PostgresGenre{
+		ID:   genre.ID,
+		Name: genre.Name,
+	}
+}
+
+type PostgresGenreRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresGenreRepository(postgres *PostgresDBConnection) (*PostgresGenreRepository, error) {
+	return &PostgresGenreRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresGenreRepository) CreateGenre(genre *domain.Genre) error {
+	postgresGenre := GenreFromDomain(genre)
+
+	// Names are unique regardless of case (genre_name_idx)
+	result := repository.postgres.DB.Create(postgresGenre)
+	if isUniqueViolation(result.Error) {
+		return domain.ErrGenreExists
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	genre.ID = postgresGenre.ID
+	return nil
+}
+
+// ListGenres returns every genre along with the number of (not deleted)
+// movies it is assigned to.
+func (repository *PostgresGenreRepository) ListGenres() ([]*domain.Genre, error) {
+	var rows []struct {
+		PostgresGenre
+		MovieCount int64
+	}
+	result := repository.postgres.DB.Model(&PostgresGenre{}).
+		Select("genre.*, COUNT(movie.id) AS movie_count").
+		Joins("LEFT JOIN movie_genre ON movie_genre.genre_id = genre.id").
+		Joins("LEFT JOIN movie ON movie.id = movie_genre.movie_id AND movie.deleted_at IS NULL").
+		Group("genre.id").
+		Order("genre.name").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	genres := make([]*domain.Genre, len(rows))
+	for i, row := range rows {
+		genres[i] = row.ToDomain()
+		genres[i].MovieCount = row.MovieCount
+	}
+
+	return genres, nil
+}
+
+func (repository *PostgresGenreRepository) GetGenresByName(names []string) ([]*domain.Genre, error) {
+	lowerNames := make([]string, len(names))
+	for i, name := range names {
+		lowerNames[i] = strings.ToLower(name)
+	}
+
+	var postgresGenres []PostgresGenre
+	result := repository.postgres.DB.Where("LOWER(name) IN ?", lowerNames).Find(&postgresGenres)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	genres := make([]*domain.Genre, len(postgresGenres))
+	for i, postgresGenre := range postgresGenres {
+		genres[i] = postgresGenre.ToDomain()
+	}
+
+	return genres, nil
+}
@@ -0,0 +1,58 @@
+package postgresadapter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPostgresGenreReturnsTableName(t *testing.T) {
+	expectedTableName := "genre"
+	actualTableName := PostgresGenre{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresGenreToDomain(t *testing.T) {
+	postgresGenre := PostgresGenre{ID: 1, Name: "Sci-Fi"}
+
+	domainGenre := postgresGenre.ToDomain()
+
+	if domainGenre.ID != postgresGenre.ID {
+		t.Errorf("Expected ID %d, got %d", postgresGenre.ID, domainGenre.ID)
+	}
+	if domainGenre.Name != postgresGenre.Name {
+		t.Errorf("Expected name '%s', got '%s'", postgresGenre.Name, domainGenre.Name)
+	}
+}
+
+func TestPostgresGenreFromDomain(t *testing.T) {
+	domainGenre := &domain.Genre{ID: 1, Name: "Sci-Fi"}
+
+	postgresGenre := GenreFromDomain(domainGenre)
+
+	if postgresGenre.ID != domainGenre.ID {
+		t.Errorf("Expected ID %d, got %d", domainGenre.ID, postgresGenre.ID)
+	}
+	if postgresGenre.Name != domainGenre.Name {
+		t.Errorf("Expected name '%s', got '%s'", domainGenre.Name, postgresGenre.Name)
+	}
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	uniqueViolation := &pgconn.PgError{Code: "23505", ConstraintName: "genre_name_idx"}
+
+	if !isUniqueViolation(uniqueViolation) || !isUniqueViolation(fmt.Errorf("creating genre: %w", uniqueViolation)) {
+		t.Errorf("Expected a unique violation to be recognised, even wrapped")
+	}
+	for _, err := range []error{nil, errors.New("connection refused"), &pgconn.PgError{Code: "23503"}} {
+		if isUniqueViolation(err) {
+			t.Errorf("Expected %v not to be a unique violation", err)
+		}
+	}
+}
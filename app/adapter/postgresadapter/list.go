@@ -0,0 +1,265 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+)
+
+// PostgresMovieList is a list of movies curated by a user. Lists and their
+// entries are removed along with their owner.
+type PostgresMovieList struct {
+	ID          uint                     `gorm:"primaryKey"`
+	UserID      uint                     `gorm:"not null;index"`
+	Title       string                   `gorm:"not null"`
+	Description string                   `gorm:"type:text"`
+	Visibility  string                   `gorm:"not null;default:private"`
+	ShareToken  string                   `gorm:"not null;uniqueIndex"`
+	CreatedAt   time.Time                `gorm:"not null"`
+	UpdatedAt   time.Time                `gorm:"not null"`
+	User        PostgresUser             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Entries     []PostgresMovieListEntry `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresMovieList) TableName() string {
+	return "movie_list"
+}
+
+func (l *PostgresMovieList) ToDomain() *domain.MovieList {
+	entries := make([]domain.MovieListEntry, len(l.Entries))
+	for i, entry := range l.Entries {
+		entries[i] = *entry.ToDomain()
+	}
+
+	return &domain.MovieList{
+		ID:          l.ID,
+		UserID:      l.UserID,
+		Title:       l.Title,
+		Description: l.Description,
+		Visibility:  domain.ListVisibility(l.Visibility),
+		ShareToken:  l.ShareToken,
+		Entries:     entries,
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+}
+
+func MovieListFromDomain(list *domain.MovieList) *PostgresMovieList {
+	entries := make([]PostgresMovieListEntry, len(list.Entries))
+	for i, entry := range list.Entries {
+		entries[i] = PostgresMovieListEntry{
+			ListID:   list.ID,
+			MovieID:  entry.MovieID,
+			Position: entry.Position,
+			Notes:    entry.Notes,
+		}
+	}
+
+	return &PostgresMovieList{
+		ID:          list.ID,
+		UserID:      list.UserID,
+		Title:       list.Title,
+		Description: list.Description,
+		Visibility:  string(list.Visibility),
+		ShareToken:  list.ShareToken,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+		Entries:     entries,
+	}
+}
+
+// PostgresMovieListEntry is a movie in a list. Entries are removed along with
+// the movie they point to.
+type PostgresMovieListEntry struct {
+	ListID   uint          `gorm:"primaryKey"`
+	MovieID  uint          `gorm:"primaryKey"`
+	Position int           `gorm:"not null"`
+	Notes    string        `gorm:"type:text"`
+	Movie    PostgresMovie `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresMovieListEntry) TableName() string {
+	return "movie_list_entry"
+}
+
+func (e *PostgresMovieListEntry) ToDomain() *domain.MovieListEntry {
+	entry := &domain.MovieListEntry{
+		MovieID:  e.MovieID,
+		Position: e.Position,
+		Notes:    e.Notes,
+	}
+	if e.Movie.ID != 0 {
+		entry.Movie = e.Movie.ToDomain()
+	}
+	return entry
+}
+
+type PostgresMovieListRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresMovieListRepository(postgres *PostgresDBConnection) (*PostgresMovieListRepository, error) {
+	return &PostgresMovieListRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresMovieListRepository) CreateList(list *domain.MovieList) error {
+	postgresList := MovieListFromDomain(list)
+
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Omit("User", "Entries").Create(postgresList); result.Error != nil {
+			return result.Error
+		}
+		return replaceListEntries(tx, postgresList.ID, postgresList.Entries)
+	})
+	if err != nil {
+		return err
+	}
+
+	list.ID = postgresList.ID
+	list.CreatedAt = postgresList.CreatedAt
+	list.UpdatedAt = postgresList.UpdatedAt
+	return nil
+}
+
+func (repository *PostgresMovieListRepository) GetList(id uint) (*domain.MovieList, error) {
+	postgresList := &PostgresMovieList{}
+	result := repository.postgres.DB.Scopes(preloadListEntries).First(postgresList, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresList.ToDomain(), nil
+}
+
+func (repository *PostgresMovieListRepository) GetListByShareToken(token string) (*domain.MovieList, error) {
+	postgresList := &PostgresMovieList{}
+	result := repository.postgres.DB.Scopes(preloadListEntries).Where("share_token = ?", token).First(postgresList)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresList.ToDomain(), nil
+}
+
+func (repository *PostgresMovieListRepository) UpdateList(list *domain.MovieList) error {
+	postgresList := MovieListFromDomain(list)
+
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(postgresList).
+			Select("Title", "Description", "Visibility", "ShareToken").
+			Updates(postgresList)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("list not found")
+		}
+		return replaceListEntries(tx, postgresList.ID, postgresList.Entries)
+	})
+	if err != nil {
+		return err
+	}
+
+	list.UpdatedAt = postgresList.UpdatedAt
+	return nil
+}
+
+func (repository *PostgresMovieListRepository) UpdateShareToken(list *domain.MovieList) error {
+	postgresList := &PostgresMovieList{ID: list.ID}
+	result := repository.postgres.DB.Model(postgresList).Update("share_token", list.ShareToken)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("list not found")
+	}
+
+	list.UpdatedAt = postgresList.UpdatedAt
+	return nil
+}
+
+func (repository *PostgresMovieListRepository) DeleteList(list *domain.MovieList) error {
+	result := repository.postgres.DB.Delete(&PostgresMovieList{}, list.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("list not found")
+	}
+	return nil
+}
+
+// ListLists returns the lists matching the filter, most recently updated
+// first, along with the number of (not deleted) movies in each of them.
+func (repository *PostgresMovieListRepository) ListLists(filter port.MovieListFilter, pagination port.Pagination) ([]*domain.MovieList, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresMovieList{}).
+		Where("(movie_list.user_id = ? OR movie_list.visibility = ?)", filter.VisibleTo, string(domain.ListVisibilityPublic))
+	if filter.OwnerID != nil {
+		db = db.Where("movie_list.user_id = ?", *filter.OwnerID)
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var rows []struct {
+		PostgresMovieList
+		EntryCount int64
+	}
+	result := db.Select(`movie_list.*, (
+			SELECT COUNT(*) FROM movie_list_entry
+			JOIN movie ON movie.id = movie_list_entry.movie_id AND movie.deleted_at IS NULL
+			WHERE movie_list_entry.list_id = movie_list.id
+		) AS entry_count`).
+		Order("movie_list.updated_at DESC, movie_list.id DESC").
+		Scopes(paginate(pagination)).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	lists := make([]*domain.MovieList, len(rows))
+	for i, row := range rows {
+		lists[i] = row.ToDomain()
+		lists[i].EntryCount = row.EntryCount
+	}
+
+	return lists, total, nil
+}
+
+// preloadListEntries is a gorm scope loading the entries of a list in order,
+// along with their movies. Entries of deleted movies are skipped.
+func preloadListEntries(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Select("movie_list_entry.*").
+				Joins("JOIN movie ON movie.id = movie_list_entry.movie_id AND movie.deleted_at IS NULL").
+				Order("movie_list_entry.position")
+		}).
+		Preload("Entries.Movie").
+		Preload("Entries.Movie.Genres", func(db *gorm.DB) *gorm.DB {
+			return db.Order("genre.name")
+		})
+}
+
+// replaceListEntries sets the entries of a list to exactly the given ones.
+func replaceListEntries(tx *gorm.DB, listID uint, entries []PostgresMovieListEntry) error {
+	if result := tx.Where("list_id = ?", listID).Delete(&PostgresMovieListEntry{}); result.Error != nil {
+		return result.Error
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	for i := range entries {
+		entries[i].ListID = listID
+	}
+	return tx.Omit("Movie").Create(&entries).Error
+}
@@ -0,0 +1,86 @@
+package postgresadapter
+
+import (
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresMovieListReturnsTableName(t *testing.T) {
+	expectedTableName := "movie_list"
+	actualTableName := PostgresMovieList{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresMovieListEntryReturnsTableName(t *testing.T) {
+	expectedTableName := "movie_list_entry"
+	actualTableName := PostgresMovieListEntry{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresMovieListToDomain(t *testing.T) {
+	postgresList := PostgresMovieList{
+		ID:         1,
+		UserID:     2,
+		Title:      "Comfort movies",
+		Visibility: "unlisted",
+		ShareToken: "secret",
+		Entries: []PostgresMovieListEntry{
+			{ListID: 1, MovieID: 3, Position: 1, Notes: "Every Christmas", Movie: PostgresMovie{ID: 3, Title: "Home Alone"}},
+			{ListID: 1, MovieID: 4, Position: 2},
+		},
+	}
+
+	domainList := postgresList.ToDomain()
+
+	if domainList.ID != 1 || domainList.UserID != 2 || domainList.Title != "Comfort movies" {
+		t.Errorf("Expected list 1 of user 2 named 'Comfort movies', got %+v", domainList)
+	}
+	if domainList.Visibility != domain.ListVisibilityUnlisted || domainList.ShareToken != "secret" {
+		t.Errorf("Expected an unlisted list with token 'secret', got '%s' with '%s'", domainList.Visibility, domainList.ShareToken)
+	}
+	if len(domainList.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(domainList.Entries))
+	}
+	if domainList.Entries[0].Notes != "Every Christmas" || domainList.Entries[0].Movie == nil || domainList.Entries[0].Movie.Title != "Home Alone" {
+		t.Errorf("Expected the first entry to be 'Home Alone' with notes, got %+v", domainList.Entries[0])
+	}
+	if domainList.Entries[1].Movie != nil {
+		t.Errorf("Expected no movie when it is not loaded, got %v", domainList.Entries[1].Movie)
+	}
+}
+
+func TestPostgresMovieListFromDomain(t *testing.T) {
+	domainList := &domain.MovieList{
+		ID:         1,
+		UserID:     2,
+		Title:      "Comfort movies",
+		Visibility: domain.ListVisibilityPublic,
+		ShareToken: "secret",
+		Entries: []domain.MovieListEntry{
+			{MovieID: 3, Position: 1, Notes: "Every Christmas"},
+		},
+	}
+
+	postgresList := MovieListFromDomain(domainList)
+
+	if postgresList.ID != 1 || postgresList.UserID != 2 || postgresList.Title != "Comfort movies" {
+		t.Errorf("Expected list 1 of user 2 named 'Comfort movies', got %+v", postgresList)
+	}
+	if postgresList.Visibility != "public" {
+		t.Errorf("Expected visibility 'public', got '%s'", postgresList.Visibility)
+	}
+	if len(postgresList.Entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(postgresList.Entries))
+	}
+	entry := postgresList.Entries[0]
+	if entry.ListID != 1 || entry.MovieID != 3 || entry.Position != 1 || entry.Notes != "Every Christmas" {
+		t.Errorf("Expected movie 3 first in list 1 with notes, got %+v", entry)
+	}
+}
@@ -0,0 +1,154 @@
+package postgresadapter
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresLoginThrottle counts the recent failed logins for an email or an IP
+// address. It is removed once failures stop for a while.
+type PostgresLoginThrottle struct {
+	Key           string    `gorm:"primaryKey"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null;index"`
+}
+
+func (PostgresLoginThrottle) TableName() string {
+	return "login_throttle"
+}
+
+func (t *PostgresLoginThrottle) ToDomain() *domain.LoginThrottle {
+	return &domain.LoginThrottle{
+		Key:           t.Key,
+		Failures:      t.Failures,
+		LastFailureAt: t.LastFailureAt,
+	}
+}
+
+// PostgresLoginFailure is an audit entry of a failed login. It is kept on its
+// own, as emails without an account are audited too.
+type PostgresLoginFailure struct {
+	ID        uint      `gorm:"primaryKey"`
+	Email     string    `gorm:"not null;index"`
+	IPAddress string    `gorm:"not null;index"`
+	UserAgent string    `gorm:"not null;default:''"`
+	Reason    string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (PostgresLoginFailure) TableName() string {
+	return "login_failure"
+}
+
+func (f *PostgresLoginFailure) ToDomain() *domain.LoginFailure {
+	return &domain.LoginFailure{
+		ID:        f.ID,
+		Email:     f.Email,
+		IPAddress: f.IPAddress,
+		UserAgent: f.UserAgent,
+		Reason:    domain.LoginFailureReason(f.Reason),
+		CreatedAt: f.CreatedAt,
+	}
+}
+
+func LoginFailureFromDomain(failure *domain.LoginFailure) *PostgresLoginFailure {
+	return &PostgresLoginFailure{
+		ID:        failure.ID,
+		Email:     failure.Email,
+		IPAddress: failure.IPAddress,
+		UserAgent: failure.UserAgent,
+		Reason:    string(failure.Reason),
+		CreatedAt: failure.CreatedAt,
+	}
+}
+
+type PostgresLoginThrottleRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresLoginThrottleRepository(postgres *PostgresDBConnection) (*PostgresLoginThrottleRepository, error) {
+	return &PostgresLoginThrottleRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresLoginThrottleRepository) GetLoginThrottle(key string) (*domain.LoginThrottle, error) {
+	var postgresThrottles []PostgresLoginThrottle
+	result := repository.postgres.DB.Where("key = ?", key).Limit(1).Find(&postgresThrottles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(postgresThrottles) == 0 {
+		return nil, nil
+	}
+	return postgresThrottles[0].ToDomain(), nil
+}
+
+// RecordLoginFailure counts the failure in a single statement, so that
+// concurrent failures are all counted. It also forgets the counts which have
+// started over anyway, so that the table does not keep growing.
+func (repository *PostgresLoginThrottleRepository) RecordLoginFailure(key string, at time.Time, resetBefore time.Time) (*domain.LoginThrottle, error) {
+	postgresThrottle := PostgresLoginThrottle{Key: key, Failures: 1, LastFailureAt: at}
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("last_failure_at < ?", resetBefore).Delete(&PostgresLoginThrottle{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_throttle.last_failure_at < ? THEN 1 ELSE login_throttle.failures + 1 END", resetBefore),
+				"last_failure_at": at,
+			}),
+		}, clause.Returning{}).Create(&postgresThrottle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return postgresThrottle.ToDomain(), nil
+}
+
+func (repository *PostgresLoginThrottleRepository) ResetLoginThrottle(key string) error {
+	return repository.postgres.DB.Where("key = ?", key).Delete(&PostgresLoginThrottle{}).Error
+}
+
+func (repository *PostgresLoginThrottleRepository) CreateLoginFailure(failure *domain.LoginFailure) error {
+	postgresFailure := LoginFailureFromDomain(failure)
+	if result := repository.postgres.DB.Create(postgresFailure); result.Error != nil {
+		return result.Error
+	}
+	failure.ID = postgresFailure.ID
+	return nil
+}
+
+func (repository *PostgresLoginThrottleRepository) ListLoginFailures(filter port.LoginFailureFilter, pagination port.Pagination) ([]*domain.LoginFailure, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresLoginFailure{})
+	if filter.Email != "" {
+		db = db.Where("email = ?", filter.Email)
+	}
+	if filter.IPAddress != "" {
+		db = db.Where("ip_address = ?", filter.IPAddress)
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresFailures []PostgresLoginFailure
+	result := db.Order("created_at DESC, id DESC").Scopes(paginate(pagination)).Find(&postgresFailures)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	failures := make([]*domain.LoginFailure, len(postgresFailures))
+	for i, postgresFailure := range postgresFailures {
+		failures[i] = postgresFailure.ToDomain()
+	}
+	return failures, total, nil
+}
@@ -0,0 +1,51 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresLoginThrottleReturnsTableName(t *testing.T) {
+	expectedTableName := "login_throttle"
+	actualTableName := PostgresLoginThrottle{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresLoginThrottleToDomain(t *testing.T) {
+	lastFailureAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	postgresThrottle := PostgresLoginThrottle{Key: "email:test@test.com", Failures: 3, LastFailureAt: lastFailureAt}
+
+	throttle := postgresThrottle.ToDomain()
+
+	if throttle.Key != "email:test@test.com" || throttle.Failures != 3 || !throttle.LastFailureAt.Equal(lastFailureAt) {
+		t.Errorf("Expected 3 failures for email:test@test.com, got %+v", throttle)
+	}
+}
+
+func TestPostgresLoginFailureReturnsTableName(t *testing.T) {
+	expectedTableName := "login_failure"
+	actualTableName := PostgresLoginFailure{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresLoginFailureToDomainAndBack(t *testing.T) {
+	failure := &domain.LoginFailure{ID: 1, Email: "test@test.com", IPAddress: "127.0.0.1", UserAgent: "curl/8.0", Reason: domain.LoginFailureInvalidCredentials}
+
+	postgresFailure := LoginFailureFromDomain(failure)
+	if postgresFailure.Reason != "invalid_credentials" {
+		t.Errorf("Expected reason 'invalid_credentials', got '%s'", postgresFailure.Reason)
+	}
+
+	domainFailure := postgresFailure.ToDomain()
+	if *domainFailure != *failure {
+		t.Errorf("Expected %+v, got %+v", failure, domainFailure)
+	}
+}
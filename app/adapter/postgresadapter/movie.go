@@ -1,8 +1,12 @@
 package postgresadapter
 
 import (
+	"strings"
+
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostgresMovie struct {
@@ -12,12 +16,16 @@ type PostgresMovie struct {
 	Director    string
 	ReleaseYear int
 	Cast        string
-	Genre       string
+	Genres      []PostgresGenre `gorm:"many2many:movie_genre;joinForeignKey:MovieID;joinReferences:GenreID"`
 	Synopsis    string
 	Rating      float64
 	Duration    int
 	PosterURL   string
 	UserID      uint
+	// The review statistics are read-only here, they are only written by the
+	// review repository whenever a review changes.
+	ReviewCount  int64   `gorm:"<-:false;not null;default:0"`
+	AverageScore float64 `gorm:"<-:false;not null;default:0"`
 }
 
 func (PostgresMovie) TableName() string {
@@ -25,35 +33,49 @@ func (PostgresMovie) TableName() string {
 }
 
 func (m *PostgresMovie) ToDomain() *domain.Movie {
+	genres := make([]domain.Genre, len(m.Genres))
+	for i, genre := range m.Genres {
+		genres[i] = *genre.ToDomain()
+	}
+
 	return &domain.Movie{
-		ID:          m.ID,
-		Title:       m.Title,
-		Director:    m.Director,
-		ReleaseYear: m.ReleaseYear,
-		Cast:        m.Cast,
-		Genre:       m.Genre,
-		Synopsis:    m.Synopsis,
-		Rating:      m.Rating,
-		Duration:    m.Duration,
-		PosterURL:   m.PosterURL,
-		UserID:      m.UserID,
+		ID:           m.ID,
+		Title:        m.Title,
+		Director:     m.Director,
+		ReleaseYear:  m.ReleaseYear,
+		Cast:         m.Cast,
+		Genres:       genres,
+		Synopsis:     m.Synopsis,
+		Rating:       m.Rating,
+		Duration:     m.Duration,
+		PosterURL:    m.PosterURL,
+		UserID:       m.UserID,
+		CreatedAt:    m.CreatedAt,
+		ReviewCount:  m.ReviewCount,
+		AverageScore: m.AverageScore,
 	}
 }
 
 func FromDomain(movie *domain.Movie) (*PostgresMovie, error) {
+	genres := make([]PostgresGenre, len(movie.Genres))
+	for i, genre := range movie.Genres {
+		genres[i] = *GenreFromDomain(&genre)
+	}
+
 	postgresMovie := &PostgresMovie{
 		ID:          movie.ID,
 		Title:       movie.Title,
 		Director:    movie.Director,
 		ReleaseYear: movie.ReleaseYear,
 		Cast:        movie.Cast,
-		Genre:       movie.Genre,
+		Genres:      genres,
 		Synopsis:    movie.Synopsis,
 		Rating:      movie.Rating,
 		Duration:    movie.Duration,
 		PosterURL:   movie.PosterURL,
 		UserID:      movie.UserID,
 	}
+	postgresMovie.CreatedAt = movie.CreatedAt
 
 	return postgresMovie, nil
 }
@@ -68,22 +90,127 @@ func NewPostgresMovieRepository(postgres *PostgresDBConnection) (*PostgresMovieR
 	}, nil
 }
 
-func (repository *PostgresMovieRepository) ListMovies(filters map[string]string) ([]*domain.Movie, error) {
+// movieSortColumns maps the sortable fields in port.MovieSortFields to their
+// columns in the movie table.
+var movieSortColumns = map[string]string{
+	"title":        "title",
+	"release_year": "release_year",
+	"rating":       "rating",
+	"duration":     "duration",
+	"created_at":   "created_at",
+}
+
+func (repository *PostgresMovieRepository) ListMovies(filter port.MovieFilter, pagination port.Pagination, sort port.Sort) ([]*domain.Movie, int64, error) {
 	var postgresMovies []PostgresMovie
-	db := repository.postgres.DB
-	for field, value := range filters {
-		switch field {
-		case "title":
-			db = db.Where("title ILIKE ?", value)
-		case "director":
-			db = db.Where("director ILIKE ?", value)
-		case "genre":
-			db = db.Where("genre ILIKE ?", value)
-		case "cast":
-			db = db.Where("cast ILIKE ?", value)
-		}
+	db := repository.postgres.DB.Model(&PostgresMovie{}).Scopes(filterMovies(filter)).Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	if column, ok := movieSortColumns[sort.Field]; ok {
+		db = db.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: column},
+			Desc:   sort.Direction == port.SortDescending,
+		})
+	}
+	// Always break ties by ID so that pages are stable.
+	db = db.Order("id")
+
+	result := db.Scopes(paginate(pagination), preloadGenres).Find(&postgresMovies)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	movies := make([]*domain.Movie, len(postgresMovies))
+	for i, postgresMovie := range postgresMovies {
+		movies[i] = postgresMovie.ToDomain()
+	}
+
+	return movies, total, nil
+}
+
+// postgresMovieSearchResult holds a movie row along with the rank and
+// highlights computed by the full-text search query.
+type postgresMovieSearchResult struct {
+	PostgresMovie
+	Rank              float64
+	TitleHighlight    string
+	SynopsisHighlight string
+}
+
+func (r *postgresMovieSearchResult) ToDomain() *domain.MovieSearchResult {
+	return &domain.MovieSearchResult{
+		Movie:             r.PostgresMovie.ToDomain(),
+		Rank:              r.Rank,
+		TitleHighlight:    r.TitleHighlight,
+		SynopsisHighlight: r.SynopsisHighlight,
+	}
+}
+
+// SearchMovies runs a full-text search against the search_vector column
+// maintained by the movie_search_vector_update trigger (see the migration),
+// ordering the results by relevance.
+func (repository *PostgresMovieRepository) SearchMovies(query string, pagination port.Pagination) ([]*domain.MovieSearchResult, int64, error) {
+	tsQuery := "websearch_to_tsquery('english', @query)"
+	db := repository.postgres.DB.Model(&PostgresMovie{}).
+		Where("search_vector @@ "+tsQuery, map[string]interface{}{"query": query}).
+		Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var rows []postgresMovieSearchResult
+	result := db.Select(
+		"movie.*, "+
+			"ts_rank(search_vector, "+tsQuery+") AS rank, "+
+			"ts_headline('english', title, "+tsQuery+", 'StartSel=<b>, StopSel=</b>, HighlightAll=true') AS title_highlight, "+
+			"ts_headline('english', COALESCE(synopsis, ''), "+tsQuery+", 'StartSel=<b>, StopSel=</b>, MaxFragments=2') AS synopsis_highlight",
+		map[string]interface{}{"query": query},
+	).
+		Order("rank DESC").
+		Order("id").
+		Scopes(paginate(pagination)).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	postgresMovies := make([]*PostgresMovie, len(rows))
+	for i := range rows {
+		postgresMovies[i] = &rows[i].PostgresMovie
+	}
+	if err := attachGenres(repository.postgres.DB, postgresMovies); err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.MovieSearchResult, len(rows))
+	for i, row := range rows {
+		results[i] = row.ToDomain()
 	}
-	result := db.Find(&postgresMovies)
+
+	return results, total, nil
+}
+
+// AutocompleteMovies suggests movies for a partially typed title. Titles
+// starting with the prefix come first, followed by the ones most similar to it
+// according to pg_trgm.
+func (repository *PostgresMovieRepository) AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error) {
+	startsWith := escapeLike(prefix) + "%"
+
+	var postgresMovies []PostgresMovie
+	result := repository.postgres.DB.
+		Where("title ILIKE ? OR word_similarity(?, title) >= ?", startsWith, prefix, fuzzyThreshold).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "title ILIKE ? DESC, word_similarity(?, title) DESC, title",
+			Vars:               []interface{}{startsWith, prefix},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&postgresMovies)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -102,13 +229,24 @@ func (repository *PostgresMovieRepository) CreateMovie(movie *domain.Movie) erro
 		return err
 	}
 
-	result := repository.postgres.DB.Create(&postgresMovie)
-	return result.Error
+	err = repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Omit("Genres").Create(postgresMovie); result.Error != nil {
+			return result.Error
+		}
+		return replaceGenres(tx, postgresMovie.ID, postgresMovie.Genres)
+	})
+	if err != nil {
+		return err
+	}
+
+	movie.ID = postgresMovie.ID
+	movie.CreatedAt = postgresMovie.CreatedAt
+	return nil
 }
 
 func (repository *PostgresMovieRepository) GetMovie(id uint) (*domain.Movie, error) {
 	postgresMovie := &PostgresMovie{}
-	result := repository.postgres.DB.First(postgresMovie, id)
+	result := repository.postgres.DB.Scopes(preloadGenres).First(postgresMovie, id)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -122,8 +260,12 @@ func (repository *PostgresMovieRepository) UpdateMovie(movie *domain.Movie) erro
 		return err
 	}
 
-	result := repository.postgres.DB.Save(&postgresMovie)
-	return result.Error
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Omit("Genres").Save(postgresMovie); result.Error != nil {
+			return result.Error
+		}
+		return replaceGenres(tx, postgresMovie.ID, postgresMovie.Genres)
+	})
 }
 
 func (repository *PostgresMovieRepository) DeleteMovie(movie *domain.Movie) error {
@@ -135,3 +277,144 @@ func (repository *PostgresMovieRepository) DeleteMovie(movie *domain.Movie) erro
 	result := repository.postgres.DB.Delete(&postgresMovie)
 	return result.Error
 }
+
+// fuzzyThreshold is the minimum pg_trgm word similarity for a misspelled
+// title or director to match. It is lower than the pg_trgm default so that a
+// couple of swapped letters in a short word still match.
+const fuzzyThreshold = 0.3
+
+// filterMovies is a gorm scope translating a port.MovieFilter into SQL
+// predicates on the movie table.
+func filterMovies(filter port.MovieFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Title != "" && filter.Fuzzy {
+			db = db.Where("(title ILIKE ? OR word_similarity(?, title) >= ?)", containsPattern(filter.Title), filter.Title, fuzzyThreshold)
+		} else if filter.Title != "" {
+			db = db.Where("title ILIKE ?", containsPattern(filter.Title))
+		}
+		if filter.ExactTitle != "" {
+			db = db.Where("LOWER(title) = LOWER(?)", filter.ExactTitle)
+		}
+		if filter.Director != "" && filter.Fuzzy {
+			db = db.Where("(director ILIKE ? OR word_similarity(?, director) >= ?)", containsPattern(filter.Director), filter.Director, fuzzyThreshold)
+		} else if filter.Director != "" {
+			db = db.Where("director ILIKE ?", containsPattern(filter.Director))
+		}
+		if filter.Genre != "" {
+			db = db.Where(
+				"movie.id IN (SELECT movie_genre.movie_id FROM movie_genre JOIN genre ON genre.id = movie_genre.genre_id WHERE LOWER(genre.name) = LOWER(?))",
+				filter.Genre,
+			)
+		}
+		if filter.Cast != "" {
+			db = db.Where(`"cast" ILIKE ?`, containsPattern(filter.Cast))
+		}
+		if filter.ReleaseYearFrom != nil {
+			db = db.Where("release_year >= ?", *filter.ReleaseYearFrom)
+		}
+		if filter.ReleaseYearTo != nil {
+			db = db.Where("release_year <= ?", *filter.ReleaseYearTo)
+		}
+		if filter.RatingMin != nil {
+			db = db.Where("rating >= ?", *filter.RatingMin)
+		}
+		if filter.RatingMax != nil {
+			db = db.Where("rating <= ?", *filter.RatingMax)
+		}
+		if filter.DurationMin != nil {
+			db = db.Where("duration >= ?", *filter.DurationMin)
+		}
+		if filter.DurationMax != nil {
+			db = db.Where("duration <= ?", *filter.DurationMax)
+		}
+		if filter.UserID != nil {
+			db = db.Where("user_id = ?", *filter.UserID)
+		}
+		if filter.CreatedAfter != nil {
+			db = db.Where("created_at > ?", *filter.CreatedAfter)
+		}
+		return db
+	}
+}
+
+// containsPattern builds an ILIKE pattern matching the value anywhere in the
+// column, escaping the LIKE wildcards it may contain.
+func containsPattern(value string) string {
+	return "%" + escapeLike(value) + "%"
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// PostgresMovieGenre is the join table between movies and their genres.
+type PostgresMovieGenre struct {
+	MovieID uint `gorm:"primaryKey"`
+	GenreID uint `gorm:"primaryKey"`
+}
+
+func (PostgresMovieGenre) TableName() string {
+	return "movie_genre"
+}
+
+// preloadGenres is a gorm scope loading the genres of the queried movies,
+// sorted by name.
+func preloadGenres(db *gorm.DB) *gorm.DB {
+	return db.Preload("Genres", func(db *gorm.DB) *gorm.DB {
+		return db.Order("genre.name")
+	})
+}
+
+// attachGenres loads the genres of movies fetched with a raw query, where
+// preloadGenres cannot be used.
+func attachGenres(db *gorm.DB, movies []*PostgresMovie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(movies))
+	for i, movie := range movies {
+		ids[i] = movie.ID
+	}
+
+	var rows []struct {
+		MovieID uint
+		PostgresGenre
+	}
+	result := db.Table("movie_genre").
+		Select("movie_genre.movie_id, genre.*").
+		Joins("JOIN genre ON genre.id = movie_genre.genre_id").
+		Where("movie_genre.movie_id IN ?", ids).
+		Order("genre.name").
+		Scan(&rows)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	genres := map[uint][]PostgresGenre{}
+	for _, row := range rows {
+		genres[row.MovieID] = append(genres[row.MovieID], row.PostgresGenre)
+	}
+	for _, movie := range movies {
+		movie.Genres = genres[movie.ID]
+	}
+
+	return nil
+}
+
+// replaceGenres sets the genres of a movie to exactly the given ones. The
+// genres must already exist.
+func replaceGenres(tx *gorm.DB, movieID uint, genres []PostgresGenre) error {
+	if result := tx.Where("movie_id = ?", movieID).Delete(&PostgresMovieGenre{}); result.Error != nil {
+		return result.Error
+	}
+	if len(genres) == 0 {
+		return nil
+	}
+
+	movieGenres := make([]PostgresMovieGenre, len(genres))
+	for i, genre := range genres {
+		movieGenres[i] = PostgresMovieGenre{MovieID: movieID, GenreID: genre.ID}
+	}
+	return tx.Create(&movieGenres).Error
+}
@@ -21,7 +21,7 @@ func TestPostgresMovieToDomain(t *testing.T) {
 		Director:    "Christopher Nolan",
 		ReleaseYear: 2010,
 		Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt",
-		Genre:       "Sci-Fi",
+		Genres:      []PostgresGenre{{ID: 1, Name: "Sci-Fi"}},
 		Synopsis:    "A mind-bending thriller",
 		Rating:      8.8,
 		Duration:    148,
@@ -42,8 +42,8 @@ func TestPostgresMovieToDomain(t *testing.T) {
 	if domainMovie.Cast != postgresMovie.Cast {
 		t.Errorf("Expected cast '%s', got '%s'", postgresMovie.Cast, domainMovie.Cast)
 	}
-	if domainMovie.Genre != postgresMovie.Genre {
-		t.Errorf("Expected genre '%s', got '%s'", postgresMovie.Genre, domainMovie.Genre)
+	if len(domainMovie.Genres) != 1 || domainMovie.Genres[0].ID != 1 || domainMovie.Genres[0].Name != "Sci-Fi" {
+		t.Errorf("Expected genres [Sci-Fi], got %v", domainMovie.Genres)
 	}
 	if domainMovie.Synopsis != postgresMovie.Synopsis {
 		t.Errorf("Expected synopsis '%s', got '%s'", postgresMovie.Synopsis, domainMovie.Synopsis)
@@ -65,7 +65,7 @@ func TestPostgresMovieFromDomain(t *testing.T) {
 		Director:    "Christopher Nolan",
 		ReleaseYear: 2010,
 		Cast:        "Leonardo DiCaprio, Joseph Gordon-Levitt",
-		Genre:       "Sci-Fi",
+		Genres:      []domain.Genre{{ID: 1, Name: "Sci-Fi"}},
 		Synopsis:    "A mind-bending thriller",
 		Rating:      8.8,
 		Duration:    148,
@@ -89,8 +89,8 @@ func TestPostgresMovieFromDomain(t *testing.T) {
 	if postgresMovie.Cast != domainMovie.Cast {
 		t.Errorf("Expected cast '%s', got '%s'", domainMovie.Cast, postgresMovie.Cast)
 	}
-	if postgresMovie.Genre != domainMovie.Genre {
-		t.Errorf("Expected genre '%s', got '%s'", domainMovie.Genre, postgresMovie.Genre)
+	if len(postgresMovie.Genres) != 1 || postgresMovie.Genres[0].ID != 1 || postgresMovie.Genres[0].Name != "Sci-Fi" {
+		t.Errorf("Expected genres [Sci-Fi], got %v", postgresMovie.Genres)
 	}
 	if postgresMovie.Synopsis != domainMovie.Synopsis {
 		t.Errorf("Expected synopsis '%s', got '%s'", domainMovie.Synopsis, postgresMovie.Synopsis)
@@ -105,3 +105,21 @@ func TestPostgresMovieFromDomain(t *testing.T) {
 		t.Errorf("Expected poster URL '%s', got '%s'", domainMovie.PosterURL, postgresMovie.PosterURL)
 	}
 }
+
+func TestPostgresMovieGenreReturnsTableName(t *testing.T) {
+	expectedTableName := "movie_genre"
+	actualTableName := PostgresMovieGenre{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestContainsPatternEscapesWildcards(t *testing.T) {
+	expectedPattern := `%100\% pure\_love%`
+	actualPattern := containsPattern("100% pure_love")
+
+	if actualPattern != expectedPattern {
+		t.Errorf("Expected pattern '%s', got '%s'", expectedPattern, actualPattern)
+	}
+}
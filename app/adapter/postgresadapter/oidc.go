@@ -0,0 +1,164 @@
+package postgresadapter
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"gorm.io/gorm"
+)
+
+// PostgresOIDCLoginState is a pending login with an OpenID Connect provider, of
+// which only the hash of the state is stored. It is removed once it has
+// expired.
+type PostgresOIDCLoginState struct {
+	ID           uint       `gorm:"primaryKey"`
+	StateHash    string     `gorm:"not null;uniqueIndex"`
+	Provider     string     `gorm:"not null"`
+	Nonce        string     `gorm:"not null"`
+	CodeVerifier string     `gorm:"not null"`
+	ExpiresAt    time.Time  `gorm:"not null;index"`
+	UsedAt       *time.Time `gorm:"default:NULL"`
+	CreatedAt    time.Time
+}
+
+func (PostgresOIDCLoginState) TableName() string {
+	return "oidc_login_state"
+}
+
+func (s *PostgresOIDCLoginState) ToDomain() *domain.OIDCLoginState {
+	state := &domain.OIDCLoginState{
+		ID:           s.ID,
+		StateHash:    s.StateHash,
+		Provider:     s.Provider,
+		Nonce:        s.Nonce,
+		CodeVerifier: s.CodeVerifier,
+		ExpiresAt:    s.ExpiresAt,
+		CreatedAt:    s.CreatedAt,
+	}
+	if s.UsedAt != nil {
+		state.UsedAt = *s.UsedAt
+	}
+	return state
+}
+
+func OIDCLoginStateFromDomain(state *domain.OIDCLoginState) *PostgresOIDCLoginState {
+	postgresState := &PostgresOIDCLoginState{
+		ID:           state.ID,
+		StateHash:    state.StateHash,
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		ExpiresAt:    state.ExpiresAt,
+		CreatedAt:    state.CreatedAt,
+	}
+	if !state.UsedAt.IsZero() {
+		postgresState.UsedAt = &state.UsedAt
+	}
+	return postgresState
+}
+
+// PostgresOIDCIdentity links a user to their subject at an OpenID Connect
+// provider. Each subject belongs to a single user, and it is removed along with
+// its user.
+type PostgresOIDCIdentity struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Provider  string `gorm:"not null;uniqueIndex:idx_oidc_identity_subject"`
+	Subject   string `gorm:"not null;uniqueIndex:idx_oidc_identity_subject"`
+	CreatedAt time.Time
+	User      PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresOIDCIdentity) TableName() string {
+	return "oidc_identity"
+}
+
+func (i *PostgresOIDCIdentity) ToDomain() *domain.OIDCIdentity {
+	return &domain.OIDCIdentity{
+		ID:        i.ID,
+		UserID:    i.UserID,
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func OIDCIdentityFromDomain(identity *domain.OIDCIdentity) *PostgresOIDCIdentity {
+	return &PostgresOIDCIdentity{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+type PostgresOIDCRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresOIDCRepository(postgres *PostgresDBConnection) (*PostgresOIDCRepository, error) {
+	return &PostgresOIDCRepository{
+		postgres: postgres,
+	}, nil
+}
+
+// CreateLoginState also forgets the login states which have expired, so that
+// the table does not keep growing.
+func (repository *PostgresOIDCRepository) CreateLoginState(state *domain.OIDCLoginState) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now()).Delete(&PostgresOIDCLoginState{}).Error; err != nil {
+			return err
+		}
+
+		postgresState := OIDCLoginStateFromDomain(state)
+		if err := tx.Create(postgresState).Error; err != nil {
+			return err
+		}
+		state.ID = postgresState.ID
+		return nil
+	})
+}
+
+func (repository *PostgresOIDCRepository) GetLoginState(stateHash string) (*domain.OIDCLoginState, error) {
+	var postgresState PostgresOIDCLoginState
+	result := repository.postgres.DB.Where("state_hash = ?", stateHash).First(&postgresState)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresState.ToDomain(), nil
+}
+
+func (repository *PostgresOIDCRepository) UseLoginState(id uint, usedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresOIDCLoginState{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidOIDCState
+	}
+	return nil
+}
+
+func (repository *PostgresOIDCRepository) GetIdentity(provider string, subject string) (*domain.OIDCIdentity, error) {
+	var postgresIdentities []PostgresOIDCIdentity
+	result := repository.postgres.DB.Where("provider = ? AND subject = ?", provider, subject).Limit(1).Find(&postgresIdentities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(postgresIdentities) == 0 {
+		return nil, nil
+	}
+	return postgresIdentities[0].ToDomain(), nil
+}
+
+func (repository *PostgresOIDCRepository) CreateIdentity(identity *domain.OIDCIdentity) error {
+	postgresIdentity := OIDCIdentityFromDomain(identity)
+	if err := repository.postgres.DB.Omit("User").Create(postgresIdentity).Error; err != nil {
+		return err
+	}
+	identity.ID = postgresIdentity.ID
+	return nil
+}
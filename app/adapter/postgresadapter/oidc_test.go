@@ -0,0 +1,50 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresOIDCLoginStateReturnsTableName(t *testing.T) {
+	expectedTableName := "oidc_login_state"
+	actualTableName := PostgresOIDCLoginState{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresOIDCLoginStateToDomainAndBack(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 1, 12, 10, 0, 0, time.UTC)
+	state := &domain.OIDCLoginState{ID: 1, StateHash: "hash", Provider: "company", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: expiresAt}
+
+	postgresState := OIDCLoginStateFromDomain(state)
+	if postgresState.UsedAt != nil {
+		t.Errorf("Expected an unused state to have no used_at, got %v", postgresState.UsedAt)
+	}
+
+	domainState := postgresState.ToDomain()
+	if *domainState != *state {
+		t.Errorf("Expected %+v, got %+v", state, domainState)
+	}
+}
+
+func TestPostgresOIDCIdentityReturnsTableName(t *testing.T) {
+	expectedTableName := "oidc_identity"
+	actualTableName := PostgresOIDCIdentity{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresOIDCIdentityToDomainAndBack(t *testing.T) {
+	identity := &domain.OIDCIdentity{ID: 1, UserID: 2, Provider: "company", Subject: "subject"}
+
+	domainIdentity := OIDCIdentityFromDomain(identity).ToDomain()
+	if *domainIdentity != *identity {
+		t.Errorf("Expected %+v, got %+v", identity, domainIdentity)
+	}
+}
@@ -0,0 +1,113 @@
+package postgresadapter
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresPasswordResetToken is a password reset token, of which only the
+// hash is stored. Tokens are removed along with their user.
+type PostgresPasswordResetToken struct {
+	ID        uint         `gorm:"primaryKey"`
+	UserID    uint         `gorm:"not null;index"`
+	TokenHash string       `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    *time.Time   `gorm:"default:NULL"`
+	CreatedAt time.Time    `gorm:"not null"`
+	User      PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresPasswordResetToken) TableName() string {
+	return "password_reset_token"
+}
+
+func (t *PostgresPasswordResetToken) ToDomain() *domain.PasswordResetToken {
+	token := &domain.PasswordResetToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.UsedAt != nil {
+		token.UsedAt = *t.UsedAt
+	}
+	return token
+}
+
+func PasswordResetTokenFromDomain(token *domain.PasswordResetToken) *PostgresPasswordResetToken {
+	postgresToken := &PostgresPasswordResetToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if !token.UsedAt.IsZero() {
+		postgresToken.UsedAt = &token.UsedAt
+	}
+	return postgresToken
+}
+
+type PostgresPasswordResetRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresPasswordResetRepository(postgres *PostgresDBConnection) (*PostgresPasswordResetRepository, error) {
+	return &PostgresPasswordResetRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresPasswordResetRepository) CreateResetToken(token *domain.PasswordResetToken) error {
+	postgresToken := PasswordResetTokenFromDomain(token)
+	result := repository.postgres.DB.Omit("User").Create(postgresToken)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	token.ID = postgresToken.ID
+	token.CreatedAt = postgresToken.CreatedAt
+	return nil
+}
+
+func (repository *PostgresPasswordResetRepository) GetResetToken(tokenHash string, at time.Time) (*domain.PasswordResetToken, error) {
+	postgresToken := &PostgresPasswordResetToken{}
+	result := repository.postgres.DB.
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, at).
+		Limit(1).Find(postgresToken)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrInvalidResetToken
+	}
+
+	return postgresToken.ToDomain(), nil
+}
+
+// UseResetToken marks the token as used in a single statement, so that two
+// concurrent requests cannot both use it.
+func (repository *PostgresPasswordResetRepository) UseResetToken(tokenHash string, usedAt time.Time) (*domain.PasswordResetToken, error) {
+	postgresToken := &PostgresPasswordResetToken{}
+	result := repository.postgres.DB.Model(postgresToken).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, usedAt).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrInvalidResetToken
+	}
+
+	return postgresToken.ToDomain(), nil
+}
+
+func (repository *PostgresPasswordResetRepository) InvalidateResetTokens(userID uint, at time.Time) error {
+	return repository.postgres.DB.Model(&PostgresPasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
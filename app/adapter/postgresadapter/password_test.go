@@ -0,0 +1,52 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresPasswordResetTokenReturnsTableName(t *testing.T) {
+	expectedTableName := "password_reset_token"
+	actualTableName := PostgresPasswordResetToken{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresPasswordResetTokenToDomain(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC)
+	postgresToken := PostgresPasswordResetToken{ID: 1, UserID: 2, TokenHash: "hash", ExpiresAt: expiresAt}
+
+	domainToken := postgresToken.ToDomain()
+
+	if domainToken.ID != 1 || domainToken.UserID != 2 || domainToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 with hash 'hash', got %+v", domainToken)
+	}
+	if !domainToken.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expiry %v, got %v", expiresAt, domainToken.ExpiresAt)
+	}
+	if !domainToken.UsedAt.IsZero() {
+		t.Errorf("Expected an unused token, got used at %v", domainToken.UsedAt)
+	}
+}
+
+func TestPostgresPasswordResetTokenFromDomain(t *testing.T) {
+	usedAt := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	domainToken := &domain.PasswordResetToken{ID: 1, UserID: 2, TokenHash: "hash", UsedAt: usedAt}
+
+	postgresToken := PasswordResetTokenFromDomain(domainToken)
+
+	if postgresToken.ID != 1 || postgresToken.UserID != 2 || postgresToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 with hash 'hash', got %+v", postgresToken)
+	}
+	if postgresToken.UsedAt == nil || !postgresToken.UsedAt.Equal(usedAt) {
+		t.Errorf("Expected used at %v, got %v", usedAt, postgresToken.UsedAt)
+	}
+
+	if PasswordResetTokenFromDomain(&domain.PasswordResetToken{}).UsedAt != nil {
+		t.Errorf("Expected no used date for an unused token")
+	}
+}
@@ -0,0 +1,261 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+)
+
+type PostgresPerson struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null;index"`
+	Biography string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (PostgresPerson) TableName() string {
+	return "person"
+}
+
+func (p *PostgresPerson) ToDomain() *domain.Person {
+	return &domain.Person{
+		ID:        p.ID,
+		Name:      p.Name,
+		Biography: p.Biography,
+	}
+}
+
+func PersonFromDomain(person *domain.Person) *PostgresPerson {
+	return &PostgresPerson{
+		ID:        person.ID,
+		Name:      person.Name,
+		Biography: person.Biography,
+	}
+}
+
+// PostgresMovieCredit links a person to a movie. Movies are soft-deleted, so
+// their credits are kept until the person is removed, while a person cannot be
+// removed as long as they are credited in a movie that is not deleted.
+type PostgresMovieCredit struct {
+	ID           uint   `gorm:"primaryKey"`
+	MovieID      uint   `gorm:"not null;index"`
+	PersonID     uint   `gorm:"not null;index"`
+	Role         string `gorm:"not null"`
+	Character    string
+	BillingOrder int
+	CreatedAt    time.Time
+	Movie        PostgresMovie  `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+	Person       PostgresPerson `gorm:"foreignKey:PersonID;constraint:OnDelete:RESTRICT"`
+}
+
+func (PostgresMovieCredit) TableName() string {
+	return "movie_credit"
+}
+
+func (c *PostgresMovieCredit) ToDomain() *domain.MovieCredit {
+	credit := &domain.MovieCredit{
+		ID:           c.ID,
+		MovieID:      c.MovieID,
+		PersonID:     c.PersonID,
+		Role:         domain.CreditRole(c.Role),
+		Character:    c.Character,
+		BillingOrder: c.BillingOrder,
+	}
+	if c.Movie.ID != 0 {
+		credit.Movie = c.Movie.ToDomain()
+	}
+	if c.Person.ID != 0 {
+		credit.Person = c.Person.ToDomain()
+	}
+	return credit
+}
+
+func MovieCreditFromDomain(credit *domain.MovieCredit) *PostgresMovieCredit {
+	return &PostgresMovieCredit{
+		ID:           credit.ID,
+		MovieID:      credit.MovieID,
+		PersonID:     credit.PersonID,
+		Role:         string(credit.Role),
+		Character:    credit.Character,
+		BillingOrder: credit.BillingOrder,
+	}
+}
+
+type PostgresPersonRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresPersonRepository(postgres *PostgresDBConnection) (*PostgresPersonRepository, error) {
+	return &PostgresPersonRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresPersonRepository) CreatePerson(person *domain.Person) error {
+	postgresPerson := PersonFromDomain(person)
+
+	result := repository.postgres.DB.Create(postgresPerson)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	person.ID = postgresPerson.ID
+	return nil
+}
+
+func (repository *PostgresPersonRepository) ListPeople(name string, pagination port.Pagination) ([]*domain.Person, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresPerson{})
+	if name != "" {
+		db = db.Where("name ILIKE ?", containsPattern(name))
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresPeople []PostgresPerson
+	result := db.Order("name").Order("id").Scopes(paginate(pagination)).Find(&postgresPeople)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	people := make([]*domain.Person, len(postgresPeople))
+	for i, postgresPerson := range postgresPeople {
+		people[i] = postgresPerson.ToDomain()
+	}
+
+	return people, total, nil
+}
+
+func (repository *PostgresPersonRepository) GetPerson(id uint) (*domain.Person, error) {
+	postgresPerson := &PostgresPerson{}
+	result := repository.postgres.DB.First(postgresPerson, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresPerson.ToDomain(), nil
+}
+
+func (repository *PostgresPersonRepository) UpdatePerson(person *domain.Person) error {
+	result := repository.postgres.DB.Model(&PostgresPerson{ID: person.ID}).Updates(map[string]interface{}{
+		"name":      person.Name,
+		"biography": person.Biography,
+	})
+	return result.Error
+}
+
+// DeletePerson fails with domain.ErrPersonHasCredits when the person is still
+// credited in a movie that is not deleted. Their credits in deleted movies are
+// removed along with them.
+func (repository *PostgresPersonRepository) DeletePerson(person *domain.Person) error {
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var credits int64
+		result := tx.Model(&PostgresMovieCredit{}).
+			Joins("JOIN movie ON movie.id = movie_credit.movie_id AND movie.deleted_at IS NULL").
+			Where("movie_credit.person_id = ?", person.ID).
+			Count(&credits)
+		if result.Error != nil {
+			return result.Error
+		}
+		if credits > 0 {
+			return domain.ErrPersonHasCredits
+		}
+
+		if err := tx.Where("person_id = ?", person.ID).Delete(&PostgresMovieCredit{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&PostgresPerson{}, person.ID).Error
+	})
+	if isForeignKeyViolation(err) {
+		return domain.ErrPersonHasCredits
+	}
+	return err
+}
+
+func (repository *PostgresPersonRepository) CreateCredit(credit *domain.MovieCredit) error {
+	postgresCredit := MovieCreditFromDomain(credit)
+
+	result := repository.postgres.DB.Omit("Movie", "Person").Create(postgresCredit)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	credit.ID = postgresCredit.ID
+	return nil
+}
+
+func (repository *PostgresPersonRepository) GetCredit(id uint) (*domain.MovieCredit, error) {
+	postgresCredit := &PostgresMovieCredit{}
+	result := repository.postgres.DB.Preload("Person").First(postgresCredit, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresCredit.ToDomain(), nil
+}
+
+func (repository *PostgresPersonRepository) DeleteCredit(credit *domain.MovieCredit) error {
+	result := repository.postgres.DB.Delete(&PostgresMovieCredit{}, credit.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("credit not found")
+	}
+	return nil
+}
+
+// ListMovieCredits returns the credits of a movie in billing order, with the
+// credited people loaded.
+func (repository *PostgresPersonRepository) ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error) {
+	var postgresCredits []PostgresMovieCredit
+	result := repository.postgres.DB.
+		Where("movie_id = ?", movieID).
+		Order("billing_order").
+		Order("id").
+		Preload("Person").
+		Find(&postgresCredits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	credits := make([]*domain.MovieCredit, len(postgresCredits))
+	for i, postgresCredit := range postgresCredits {
+		credits[i] = postgresCredit.ToDomain()
+	}
+
+	return credits, nil
+}
+
+// ListPersonCredits returns the filmography of a person, newest movies first,
+// skipping deleted movies.
+func (repository *PostgresPersonRepository) ListPersonCredits(personID uint) ([]*domain.MovieCredit, error) {
+	var postgresCredits []PostgresMovieCredit
+	result := repository.postgres.DB.
+		Joins("JOIN movie ON movie.id = movie_credit.movie_id AND movie.deleted_at IS NULL").
+		Where("movie_credit.person_id = ?", personID).
+		Order("movie.release_year DESC").
+		Order("movie_credit.billing_order").
+		Preload("Movie").
+		Preload("Movie.Genres", func(db *gorm.DB) *gorm.DB {
+			return db.Order("genre.name")
+		}).
+		Find(&postgresCredits)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	credits := make([]*domain.MovieCredit, len(postgresCredits))
+	for i, postgresCredit := range postgresCredits {
+		credits[i] = postgresCredit.ToDomain()
+	}
+
+	return credits, nil
+}
@@ -0,0 +1,122 @@
+package postgresadapter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPostgresPersonReturnsTableName(t *testing.T) {
+	expectedTableName := "person"
+	actualTableName := PostgresPerson{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresPersonToDomain(t *testing.T) {
+	postgresPerson := PostgresPerson{ID: 1, Name: "Al Pacino", Biography: "American actor"}
+
+	domainPerson := postgresPerson.ToDomain()
+
+	if domainPerson.ID != postgresPerson.ID {
+		t.Errorf("Expected ID %d, got %d", postgresPerson.ID, domainPerson.ID)
+	}
+	if domainPerson.Name != postgresPerson.Name {
+		t.Errorf("Expected name '%s', got '%s'", postgresPerson.Name, domainPerson.Name)
+	}
+	if domainPerson.Biography != postgresPerson.Biography {
+		t.Errorf("Expected biography '%s', got '%s'", postgresPerson.Biography, domainPerson.Biography)
+	}
+}
+
+func TestPostgresPersonFromDomain(t *testing.T) {
+	domainPerson := &domain.Person{ID: 1, Name: "Al Pacino", Biography: "American actor"}
+
+	postgresPerson := PersonFromDomain(domainPerson)
+
+	if postgresPerson.ID != domainPerson.ID {
+		t.Errorf("Expected ID %d, got %d", domainPerson.ID, postgresPerson.ID)
+	}
+	if postgresPerson.Name != domainPerson.Name {
+		t.Errorf("Expected name '%s', got '%s'", domainPerson.Name, postgresPerson.Name)
+	}
+	if postgresPerson.Biography != domainPerson.Biography {
+		t.Errorf("Expected biography '%s', got '%s'", domainPerson.Biography, postgresPerson.Biography)
+	}
+}
+
+func TestPostgresMovieCreditReturnsTableName(t *testing.T) {
+	expectedTableName := "movie_credit"
+	actualTableName := PostgresMovieCredit{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresMovieCreditToDomain(t *testing.T) {
+	postgresCredit := PostgresMovieCredit{
+		ID:           1,
+		MovieID:      2,
+		PersonID:     3,
+		Role:         "actor",
+		Character:    "Michael Corleone",
+		BillingOrder: 1,
+		Person:       PostgresPerson{ID: 3, Name: "Al Pacino"},
+	}
+
+	domainCredit := postgresCredit.ToDomain()
+
+	if domainCredit.ID != 1 || domainCredit.MovieID != 2 || domainCredit.PersonID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", domainCredit.ID, domainCredit.MovieID, domainCredit.PersonID)
+	}
+	if domainCredit.Role != domain.CreditRoleActor {
+		t.Errorf("Expected role 'actor', got '%s'", domainCredit.Role)
+	}
+	if domainCredit.Character != "Michael Corleone" {
+		t.Errorf("Expected character 'Michael Corleone', got '%s'", domainCredit.Character)
+	}
+	if domainCredit.Person == nil || domainCredit.Person.Name != "Al Pacino" {
+		t.Errorf("Expected person 'Al Pacino', got %v", domainCredit.Person)
+	}
+	if domainCredit.Movie != nil {
+		t.Errorf("Expected no movie when it is not loaded, got %v", domainCredit.Movie)
+	}
+}
+
+func TestPostgresMovieCreditFromDomain(t *testing.T) {
+	domainCredit := &domain.MovieCredit{
+		ID:           1,
+		MovieID:      2,
+		PersonID:     3,
+		Role:         domain.CreditRoleDirector,
+		BillingOrder: 0,
+	}
+
+	postgresCredit := MovieCreditFromDomain(domainCredit)
+
+	if postgresCredit.ID != 1 || postgresCredit.MovieID != 2 || postgresCredit.PersonID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", postgresCredit.ID, postgresCredit.MovieID, postgresCredit.PersonID)
+	}
+	if postgresCredit.Role != "director" {
+		t.Errorf("Expected role 'director', got '%s'", postgresCredit.Role)
+	}
+}
+
+func TestIsForeignKeyViolation(t *testing.T) {
+	foreignKeyViolation := &pgconn.PgError{Code: "23503", ConstraintName: "fk_movie_credit_person"}
+
+	if !isForeignKeyViolation(foreignKeyViolation) || !isForeignKeyViolation(fmt.Errorf("deleting person: %w", foreignKeyViolation)) {
+		t.Errorf("Expected a foreign key violation to be recognised, even wrapped")
+	}
+	for _, err := range []error{nil, errors.New("connection refused"), &pgconn.PgError{Code: "23505"}} {
+		if isForeignKeyViolation(err) {
+			t.Errorf("Expected %v not to be a foreign key violation", err)
+		}
+	}
+}
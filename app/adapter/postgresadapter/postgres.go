@@ -1,9 +1,12 @@
 package postgresadapter
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -30,3 +33,35 @@ func NewPostgresDBConnection() (*PostgresDBConnection, error) {
 		DB: db,
 	}, nil
 }
+
+// uniqueViolationCode is the SQLSTATE of a statement breaking a unique index.
+const uniqueViolationCode = "23505"
+
+// foreignKeyViolationCode is the SQLSTATE of a statement breaking a foreign
+// key, such as deleting a row still referenced.
+const foreignKeyViolationCode = "23503"
+
+// isUniqueViolation reports whether an error is a unique index refusing a row,
+// such as one inserted concurrently with the same value.
+func isUniqueViolation(err error) bool {
+	var pgError *pgconn.PgError
+	return errors.As(err, &pgError) && pgError.Code == uniqueViolationCode
+}
+
+// isForeignKeyViolation reports whether an error is a foreign key refusing a
+// statement.
+func isForeignKeyViolation(err error) bool {
+	var pgError *pgconn.PgError
+	return errors.As(err, &pgError) && pgError.Code == foreignKeyViolationCode
+}
+
+// paginate is a gorm scope limiting a query to the requested page. A zero page
+// size returns every row.
+func paginate(pagination port.Pagination) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if pagination.PageSize <= 0 {
+			return db
+		}
+		return db.Offset(pagination.Offset()).Limit(pagination.PageSize)
+	}
+}
@@ -0,0 +1,205 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+)
+
+// PostgresReview is a user's review of a movie. Reviews are removed along with
+// the user or the movie they belong to.
+type PostgresReview struct {
+	ID          uint       `gorm:"primaryKey"`
+	UserID      uint       `gorm:"not null;uniqueIndex:review_user_movie_idx"`
+	MovieID     uint       `gorm:"not null;uniqueIndex:review_user_movie_idx;index"`
+	Score       float64    `gorm:"not null"`
+	Text        string     `gorm:"type:text"`
+	WatchedDate *time.Time `gorm:"type:date"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	User        PostgresUser  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Movie       PostgresMovie `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresReview) TableName() string {
+	return "review"
+}
+
+func (r *PostgresReview) ToDomain() *domain.Review {
+	review := &domain.Review{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		MovieID:   r.MovieID,
+		Score:     r.Score,
+		Text:      r.Text,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if r.WatchedDate != nil {
+		review.WatchedDate = *r.WatchedDate
+	}
+	if r.User.ID != 0 {
+		review.User = r.User.ToDomain()
+	}
+	if r.Movie.ID != 0 {
+		review.Movie = r.Movie.ToDomain()
+	}
+	return review
+}
+
+func ReviewFromDomain(review *domain.Review) *PostgresReview {
+	postgresReview := &PostgresReview{
+		ID:        review.ID,
+		UserID:    review.UserID,
+		MovieID:   review.MovieID,
+		Score:     review.Score,
+		Text:      review.Text,
+		CreatedAt: review.CreatedAt,
+	}
+	if !review.WatchedDate.IsZero() {
+		postgresReview.WatchedDate = &review.WatchedDate
+	}
+	return postgresReview
+}
+
+type PostgresReviewRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresReviewRepository(postgres *PostgresDBConnection) (*PostgresReviewRepository, error) {
+	return &PostgresReviewRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresReviewRepository) CreateReview(review *domain.Review) error {
+	postgresReview := ReviewFromDomain(review)
+
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Omit("User", "Movie").Create(postgresReview); result.Error != nil {
+			return result.Error
+		}
+		return refreshReviewStats(tx, postgresReview.MovieID)
+	})
+	if err != nil {
+		return err
+	}
+
+	review.ID = postgresReview.ID
+	review.CreatedAt = postgresReview.CreatedAt
+	review.UpdatedAt = postgresReview.UpdatedAt
+	return nil
+}
+
+func (repository *PostgresReviewRepository) GetReview(movieID uint, userID uint) (*domain.Review, error) {
+	postgresReview := &PostgresReview{}
+	result := repository.postgres.DB.Where("movie_id = ? AND user_id = ?", movieID, userID).First(postgresReview)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresReview.ToDomain(), nil
+}
+
+func (repository *PostgresReviewRepository) UpdateReview(review *domain.Review) error {
+	postgresReview := ReviewFromDomain(review)
+
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&PostgresReview{ID: postgresReview.ID}).Updates(map[string]interface{}{
+			"score":        postgresReview.Score,
+			"text":         postgresReview.Text,
+			"watched_date": postgresReview.WatchedDate,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		return refreshReviewStats(tx, postgresReview.MovieID)
+	})
+}
+
+func (repository *PostgresReviewRepository) DeleteReview(review *domain.Review) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&PostgresReview{}, review.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("review not found")
+		}
+		return refreshReviewStats(tx, review.MovieID)
+	})
+}
+
+func (repository *PostgresReviewRepository) ListMovieReviews(movieID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresReview{}).
+		Where("movie_id = ?", movieID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresReviews []PostgresReview
+	result := db.Order("created_at DESC").Order("id DESC").
+		Scopes(paginate(pagination)).
+		Preload("User").
+		Find(&postgresReviews)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	reviews := make([]*domain.Review, len(postgresReviews))
+	for i, postgresReview := range postgresReviews {
+		reviews[i] = postgresReview.ToDomain()
+	}
+
+	return reviews, total, nil
+}
+
+// ListUserReviews returns the reviews written by a user, newest first,
+// skipping the ones of deleted movies.
+func (repository *PostgresReviewRepository) ListUserReviews(userID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresReview{}).
+		Joins("JOIN movie ON movie.id = review.movie_id AND movie.deleted_at IS NULL").
+		Where("review.user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresReviews []PostgresReview
+	result := db.Order("review.created_at DESC").Order("review.id DESC").
+		Scopes(paginate(pagination)).
+		Preload("Movie").
+		Preload("Movie.Genres", func(db *gorm.DB) *gorm.DB {
+			return db.Order("genre.name")
+		}).
+		Find(&postgresReviews)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	reviews := make([]*domain.Review, len(postgresReviews))
+	for i, postgresReview := range postgresReviews {
+		reviews[i] = postgresReview.ToDomain()
+	}
+
+	return reviews, total, nil
+}
+
+// refreshReviewStats recomputes the review count and average score stored on
+// a movie, so that they can be read along with it without any aggregation.
+func refreshReviewStats(tx *gorm.DB, movieID uint) error {
+	return tx.Exec(
+		`UPDATE movie SET review_count = stats.review_count, average_score = stats.average_score
+		FROM (SELECT COUNT(*) AS review_count, COALESCE(AVG(score), 0) AS average_score FROM review WHERE movie_id = ?) AS stats
+		WHERE movie.id = ?`,
+		movieID, movieID,
+	).Error
+}
@@ -0,0 +1,64 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresReviewReturnsTableName(t *testing.T) {
+	expectedTableName := "review"
+	actualTableName := PostgresReview{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresReviewToDomain(t *testing.T) {
+	watchedDate := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	postgresReview := PostgresReview{
+		ID:          1,
+		UserID:      2,
+		MovieID:     3,
+		Score:       8.5,
+		Text:        "Great movie",
+		WatchedDate: &watchedDate,
+		User:        PostgresUser{ID: 2, Name: "test"},
+	}
+
+	domainReview := postgresReview.ToDomain()
+
+	if domainReview.ID != 1 || domainReview.UserID != 2 || domainReview.MovieID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", domainReview.ID, domainReview.UserID, domainReview.MovieID)
+	}
+	if domainReview.Score != 8.5 {
+		t.Errorf("Expected score 8.5, got %f", domainReview.Score)
+	}
+	if !domainReview.WatchedDate.Equal(watchedDate) {
+		t.Errorf("Expected watched date %v, got %v", watchedDate, domainReview.WatchedDate)
+	}
+	if domainReview.User == nil || domainReview.User.Name != "test" {
+		t.Errorf("Expected user 'test', got %v", domainReview.User)
+	}
+	if domainReview.Movie != nil {
+		t.Errorf("Expected no movie when it is not loaded, got %v", domainReview.Movie)
+	}
+}
+
+func TestPostgresReviewFromDomain(t *testing.T) {
+	domainReview := &domain.Review{ID: 1, UserID: 2, MovieID: 3, Score: 7, Text: "Good"}
+
+	postgresReview := ReviewFromDomain(domainReview)
+
+	if postgresReview.ID != 1 || postgresReview.UserID != 2 || postgresReview.MovieID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", postgresReview.ID, postgresReview.UserID, postgresReview.MovieID)
+	}
+	if postgresReview.Text != "Good" {
+		t.Errorf("Expected text 'Good', got '%s'", postgresReview.Text)
+	}
+	if postgresReview.WatchedDate != nil {
+		t.Errorf("Expected no watched date, got %v", postgresReview.WatchedDate)
+	}
+}
@@ -0,0 +1,222 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"gorm.io/gorm"
+)
+
+// PostgresSession is a device or script a user logged in from. Sessions are
+// removed along with their user.
+type PostgresSession struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	UserAgent  string `gorm:"not null;default:''"`
+	IPAddress  string `gorm:"not null;default:''"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time    `gorm:"not null"`
+	ExpiresAt  time.Time    `gorm:"not null"`
+	RevokedAt  *time.Time   `gorm:"default:NULL"`
+	User       PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresSession) TableName() string {
+	return "user_session"
+}
+
+func (s *PostgresSession) ToDomain() *domain.Session {
+	session := &domain.Session{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		UserAgent:  s.UserAgent,
+		IPAddress:  s.IPAddress,
+		CreatedAt:  s.CreatedAt,
+		LastUsedAt: s.LastUsedAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+	if s.RevokedAt != nil {
+		session.RevokedAt = *s.RevokedAt
+	}
+	return session
+}
+
+func SessionFromDomain(session *domain.Session) *PostgresSession {
+	postgresSession := &PostgresSession{
+		ID:         session.ID,
+		UserID:     session.UserID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+	if !session.RevokedAt.IsZero() {
+		postgresSession.RevokedAt = &session.RevokedAt
+	}
+	return postgresSession
+}
+
+// PostgresRefreshToken is a refresh token of a session, of which only the hash
+// is stored. Used tokens are kept to detect their reuse, and are removed along
+// with their session.
+type PostgresRefreshToken struct {
+	ID        uint            `gorm:"primaryKey"`
+	SessionID uint            `gorm:"not null;index"`
+	TokenHash string          `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time       `gorm:"not null"`
+	UsedAt    *time.Time      `gorm:"default:NULL"`
+	Session   PostgresSession `gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresRefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+func (t *PostgresRefreshToken) ToDomain() *domain.RefreshToken {
+	refreshToken := &domain.RefreshToken{
+		ID:        t.ID,
+		SessionID: t.SessionID,
+		TokenHash: t.TokenHash,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.UsedAt != nil {
+		refreshToken.UsedAt = *t.UsedAt
+	}
+	return refreshToken
+}
+
+func RefreshTokenFromDomain(refreshToken *domain.RefreshToken) *PostgresRefreshToken {
+	postgresRefreshToken := &PostgresRefreshToken{
+		ID:        refreshToken.ID,
+		SessionID: refreshToken.SessionID,
+		TokenHash: refreshToken.TokenHash,
+		CreatedAt: refreshToken.CreatedAt,
+	}
+	if !refreshToken.UsedAt.IsZero() {
+		postgresRefreshToken.UsedAt = &refreshToken.UsedAt
+	}
+	return postgresRefreshToken
+}
+
+type PostgresSessionRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresSessionRepository(postgres *PostgresDBConnection) (*PostgresSessionRepository, error) {
+	return &PostgresSessionRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresSessionRepository) CreateSession(session *domain.Session, refreshToken *domain.RefreshToken) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		postgresSession := SessionFromDomain(session)
+		if result := tx.Omit("User").Create(postgresSession); result.Error != nil {
+			return result.Error
+		}
+		session.ID = postgresSession.ID
+
+		refreshToken.SessionID = session.ID
+		postgresRefreshToken := RefreshTokenFromDomain(refreshToken)
+		if result := tx.Omit("Session").Create(postgresRefreshToken); result.Error != nil {
+			return result.Error
+		}
+		refreshToken.ID = postgresRefreshToken.ID
+		return nil
+	})
+}
+
+func (repository *PostgresSessionRepository) GetSession(id uint) (*domain.Session, error) {
+	var postgresSession PostgresSession
+	result := repository.postgres.DB.First(&postgresSession, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresSession.ToDomain(), nil
+}
+
+func (repository *PostgresSessionRepository) ListActiveSessions(userID uint, at time.Time) ([]*domain.Session, error) {
+	var postgresSessions []PostgresSession
+	result := repository.postgres.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, at).
+		Order("last_used_at DESC").
+		Find(&postgresSessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	sessions := make([]*domain.Session, len(postgresSessions))
+	for i, postgresSession := range postgresSessions {
+		sessions[i] = postgresSession.ToDomain()
+	}
+	return sessions, nil
+}
+
+func (repository *PostgresSessionRepository) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	var postgresRefreshToken PostgresRefreshToken
+	result := repository.postgres.DB.Where("token_hash = ?", tokenHash).First(&postgresRefreshToken)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresRefreshToken.ToDomain(), nil
+}
+
+// RotateRefreshToken marks the used token as used in a single statement, so
+// that two concurrent requests cannot both use it.
+func (repository *PostgresSessionRepository) RotateRefreshToken(usedTokenID uint, next *domain.RefreshToken, session *domain.Session) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&PostgresRefreshToken{}).
+			Where("id = ? AND used_at IS NULL", usedTokenID).
+			Update("used_at", session.LastUsedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+
+		next.SessionID = session.ID
+		postgresRefreshToken := RefreshTokenFromDomain(next)
+		if result := tx.Omit("Session").Create(postgresRefreshToken); result.Error != nil {
+			return result.Error
+		}
+		next.ID = postgresRefreshToken.ID
+
+		result = tx.Model(&PostgresSession{}).Where("id = ?", session.ID).Updates(map[string]interface{}{
+			"user_agent":   session.UserAgent,
+			"ip_address":   session.IPAddress,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("session not found")
+		}
+		return nil
+	})
+}
+
+func (repository *PostgresSessionRepository) RevokeSession(id uint, revokedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresSession{}).Where("id = ?", id).Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+func (repository *PostgresSessionRepository) DeleteSessions(userID uint) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		sessionIDs := tx.Model(&PostgresSession{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("session_id IN (?)", sessionIDs).Delete(&PostgresRefreshToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&PostgresSession{}).Error
+	})
+}
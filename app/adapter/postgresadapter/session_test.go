@@ -0,0 +1,73 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresSessionReturnsTableName(t *testing.T) {
+	expectedTableName := "user_session"
+	actualTableName := PostgresSession{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresSessionToDomain(t *testing.T) {
+	now := time.Now()
+	postgresSession := PostgresSession{ID: 1, UserID: 2, UserAgent: "curl/8.0", IPAddress: "127.0.0.1", LastUsedAt: now, ExpiresAt: now.Add(time.Hour)}
+
+	session := postgresSession.ToDomain()
+
+	if session.ID != 1 || session.UserID != 2 || session.UserAgent != "curl/8.0" || session.IPAddress != "127.0.0.1" {
+		t.Errorf("Expected session 1 of user 2 from curl at 127.0.0.1, got %+v", session)
+	}
+	if !session.Active(now) {
+		t.Errorf("Expected the session to be active")
+	}
+
+	postgresSession.RevokedAt = &now
+	if postgresSession.ToDomain().Active(now) {
+		t.Errorf("Expected a revoked session not to be active")
+	}
+}
+
+func TestPostgresSessionFromDomain(t *testing.T) {
+	session := &domain.Session{ID: 1, UserID: 2, UserAgent: "curl/8.0", IPAddress: "127.0.0.1"}
+
+	postgresSession := SessionFromDomain(session)
+
+	if postgresSession.ID != 1 || postgresSession.UserID != 2 || postgresSession.UserAgent != "curl/8.0" || postgresSession.IPAddress != "127.0.0.1" {
+		t.Errorf("Expected session 1 of user 2 from curl at 127.0.0.1, got %+v", postgresSession)
+	}
+	if postgresSession.RevokedAt != nil {
+		t.Errorf("Expected no revocation date, got %v", postgresSession.RevokedAt)
+	}
+}
+
+func TestPostgresRefreshTokenReturnsTableName(t *testing.T) {
+	expectedTableName := "refresh_token"
+	actualTableName := PostgresRefreshToken{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresRefreshTokenToDomainAndBack(t *testing.T) {
+	usedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	refreshToken := &domain.RefreshToken{ID: 1, SessionID: 2, TokenHash: "hash", UsedAt: usedAt}
+
+	postgresRefreshToken := RefreshTokenFromDomain(refreshToken)
+	if postgresRefreshToken.UsedAt == nil || !postgresRefreshToken.UsedAt.Equal(usedAt) {
+		t.Errorf("Expected used at %v, got %v", usedAt, postgresRefreshToken.UsedAt)
+	}
+
+	domainRefreshToken := postgresRefreshToken.ToDomain()
+	if domainRefreshToken.ID != 1 || domainRefreshToken.SessionID != 2 || domainRefreshToken.TokenHash != "hash" || !domainRefreshToken.UsedAt.Equal(usedAt) {
+		t.Errorf("Expected %+v, got %+v", refreshToken, domainRefreshToken)
+	}
+}
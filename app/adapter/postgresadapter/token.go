@@ -0,0 +1,53 @@
+package postgresadapter
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresRevokedToken is the ID of a revoked token. It is kept until the
+// token expires.
+type PostgresRevokedToken struct {
+	TokenID   string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+func (PostgresRevokedToken) TableName() string {
+	return "revoked_token"
+}
+
+type PostgresTokenRevocationRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresTokenRevocationRepository(postgres *PostgresDBConnection) (*PostgresTokenRevocationRepository, error) {
+	return &PostgresTokenRevocationRepository{
+		postgres: postgres,
+	}, nil
+}
+
+// RevokeToken also forgets the tokens which have expired anyway, so that the
+// table does not keep growing.
+func (repository *PostgresTokenRevocationRepository) RevokeToken(tokenID string, expiresAt time.Time) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now()).Delete(&PostgresRevokedToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+		}).Create(&PostgresRevokedToken{TokenID: tokenID, ExpiresAt: expiresAt}).Error
+	})
+}
+
+func (repository *PostgresTokenRevocationRepository) IsTokenRevoked(tokenID string) (bool, error) {
+	var count int64
+	result := repository.postgres.DB.Model(&PostgresRevokedToken{}).Where("token_id = ?", tokenID).Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,12 @@
+package postgresadapter
+
+import "testing"
+
+func TestPostgresRevokedTokenReturnsTableName(t *testing.T) {
+	expectedTableName := "revoked_token"
+	actualTableName := PostgresRevokedToken{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
@@ -0,0 +1,299 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresTwoFactor is the TOTP secret of a user, kept as is since codes
+// cannot be checked against a hash. It is removed along with its user.
+type PostgresTwoFactor struct {
+	UserID       uint       `gorm:"primaryKey;autoIncrement:false"`
+	Secret       string     `gorm:"not null"`
+	EnabledAt    *time.Time `gorm:"default:NULL"`
+	LastUsedStep int64      `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+	User         PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+func (t *PostgresTwoFactor) ToDomain() *domain.TwoFactor {
+	twoFactor := &domain.TwoFactor{
+		UserID:       t.UserID,
+		Secret:       t.Secret,
+		LastUsedStep: t.LastUsedStep,
+		CreatedAt:    t.CreatedAt,
+	}
+	if t.EnabledAt != nil {
+		twoFactor.EnabledAt = *t.EnabledAt
+	}
+	return twoFactor
+}
+
+func TwoFactorFromDomain(twoFactor *domain.TwoFactor) *PostgresTwoFactor {
+	postgresTwoFactor := &PostgresTwoFactor{
+		UserID:       twoFactor.UserID,
+		Secret:       twoFactor.Secret,
+		LastUsedStep: twoFactor.LastUsedStep,
+		CreatedAt:    twoFactor.CreatedAt,
+	}
+	if !twoFactor.EnabledAt.IsZero() {
+		postgresTwoFactor.EnabledAt = &twoFactor.EnabledAt
+	}
+	return postgresTwoFactor
+}
+
+// PostgresRecoveryCode is a recovery code of a user, of which only the hash is
+// stored. Used codes are kept until the codes are replaced.
+type PostgresRecoveryCode struct {
+	ID       uint         `gorm:"primaryKey"`
+	UserID   uint         `gorm:"not null;index"`
+	CodeHash string       `gorm:"not null"`
+	UsedAt   *time.Time   `gorm:"default:NULL"`
+	User     PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresRecoveryCode) TableName() string {
+	return "recovery_code"
+}
+
+func (c *PostgresRecoveryCode) ToDomain() *domain.RecoveryCode {
+	recoveryCode := &domain.RecoveryCode{
+		ID:       c.ID,
+		UserID:   c.UserID,
+		CodeHash: c.CodeHash,
+	}
+	if c.UsedAt != nil {
+		recoveryCode.UsedAt = *c.UsedAt
+	}
+	return recoveryCode
+}
+
+func RecoveryCodeFromDomain(recoveryCode *domain.RecoveryCode) *PostgresRecoveryCode {
+	postgresRecoveryCode := &PostgresRecoveryCode{
+		ID:       recoveryCode.ID,
+		UserID:   recoveryCode.UserID,
+		CodeHash: recoveryCode.CodeHash,
+	}
+	if !recoveryCode.UsedAt.IsZero() {
+		postgresRecoveryCode.UsedAt = &recoveryCode.UsedAt
+	}
+	return postgresRecoveryCode
+}
+
+// PostgresTwoFactorChallenge is a pending two-factor login, of which only the
+// hash of the token is stored. It is removed along with its user, or once it
+// has expired.
+type PostgresTwoFactorChallenge struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"not null;uniqueIndex"`
+	Attempts  int        `gorm:"not null;default:0"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time `gorm:"default:NULL"`
+	CreatedAt time.Time
+	User      PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresTwoFactorChallenge) TableName() string {
+	return "two_factor_challenge"
+}
+
+func (c *PostgresTwoFactorChallenge) ToDomain() *domain.TwoFactorChallenge {
+	challenge := &domain.TwoFactorChallenge{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		TokenHash: c.TokenHash,
+		Attempts:  c.Attempts,
+		ExpiresAt: c.ExpiresAt,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.UsedAt != nil {
+		challenge.UsedAt = *c.UsedAt
+	}
+	return challenge
+}
+
+func TwoFactorChallengeFromDomain(challenge *domain.TwoFactorChallenge) *PostgresTwoFactorChallenge {
+	postgresChallenge := &PostgresTwoFactorChallenge{
+		ID:        challenge.ID,
+		UserID:    challenge.UserID,
+		TokenHash: challenge.TokenHash,
+		Attempts:  challenge.Attempts,
+		ExpiresAt: challenge.ExpiresAt,
+		CreatedAt: challenge.CreatedAt,
+	}
+	if !challenge.UsedAt.IsZero() {
+		postgresChallenge.UsedAt = &challenge.UsedAt
+	}
+	return postgresChallenge
+}
+
+type PostgresTwoFactorRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresTwoFactorRepository(postgres *PostgresDBConnection) (*PostgresTwoFactorRepository, error) {
+	return &PostgresTwoFactorRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresTwoFactorRepository) GetTwoFactor(userID uint) (*domain.TwoFactor, error) {
+	var postgresTwoFactors []PostgresTwoFactor
+	result := repository.postgres.DB.Where("user_id = ?", userID).Limit(1).Find(&postgresTwoFactors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(postgresTwoFactors) == 0 {
+		return nil, nil
+	}
+	return postgresTwoFactors[0].ToDomain(), nil
+}
+
+func (repository *PostgresTwoFactorRepository) SaveTwoFactor(twoFactor *domain.TwoFactor) error {
+	return repository.postgres.DB.Omit("User").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled_at", "last_used_step", "created_at"}),
+	}).Create(TwoFactorFromDomain(twoFactor)).Error
+}
+
+func (repository *PostgresTwoFactorRepository) EnableTwoFactor(userID uint, enabledAt time.Time, step int64) error {
+	result := repository.postgres.DB.Model(&PostgresTwoFactor{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"enabled_at":     enabledAt,
+		"last_used_step": step,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("two-factor authentication not found")
+	}
+	return nil
+}
+
+func (repository *PostgresTwoFactorRepository) DeleteTwoFactor(userID uint) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&PostgresRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&PostgresTwoFactorChallenge{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&PostgresTwoFactor{}).Error
+	})
+}
+
+// UseTOTPStep only moves the last used step forward, in a single statement, so
+// that two concurrent requests cannot both use the same code.
+func (repository *PostgresTwoFactorRepository) UseTOTPStep(userID uint, step int64) error {
+	result := repository.postgres.DB.Model(&PostgresTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (repository *PostgresTwoFactorRepository) ReplaceRecoveryCodes(userID uint, codes []*domain.RecoveryCode) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&PostgresRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		for _, code := range codes {
+			postgresRecoveryCode := RecoveryCodeFromDomain(code)
+			if err := tx.Omit("User").Create(postgresRecoveryCode).Error; err != nil {
+				return err
+			}
+			code.ID = postgresRecoveryCode.ID
+		}
+		return nil
+	})
+}
+
+func (repository *PostgresTwoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	result := repository.postgres.DB.Model(&PostgresRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// UseRecoveryCode marks the code as used in a single statement, so that two
+// concurrent requests cannot both use it.
+func (repository *PostgresTwoFactorRepository) UseRecoveryCode(userID uint, codeHash string, usedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// CreateChallenge also forgets the challenges which have expired, so that the
+// table does not keep growing.
+func (repository *PostgresTwoFactorRepository) CreateChallenge(challenge *domain.TwoFactorChallenge) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", time.Now()).Delete(&PostgresTwoFactorChallenge{}).Error; err != nil {
+			return err
+		}
+
+		postgresChallenge := TwoFactorChallengeFromDomain(challenge)
+		if err := tx.Omit("User").Create(postgresChallenge).Error; err != nil {
+			return err
+		}
+		challenge.ID = postgresChallenge.ID
+		return nil
+	})
+}
+
+func (repository *PostgresTwoFactorRepository) GetChallenge(tokenHash string) (*domain.TwoFactorChallenge, error) {
+	var postgresChallenge PostgresTwoFactorChallenge
+	result := repository.postgres.DB.Where("token_hash = ?", tokenHash).First(&postgresChallenge)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return postgresChallenge.ToDomain(), nil
+}
+
+func (repository *PostgresTwoFactorRepository) AddChallengeAttempt(id uint) error {
+	result := repository.postgres.DB.Model(&PostgresTwoFactorChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("two-factor challenge not found")
+	}
+	return nil
+}
+
+func (repository *PostgresTwoFactorRepository) UseChallenge(id uint, usedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresTwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidTwoFactorChallenge
+	}
+	return nil
+}
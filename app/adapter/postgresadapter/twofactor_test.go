@@ -0,0 +1,80 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresTwoFactorReturnsTableName(t *testing.T) {
+	expectedTableName := "user_two_factor"
+	actualTableName := PostgresTwoFactor{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresTwoFactorToDomainAndBack(t *testing.T) {
+	enabledAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	twoFactor := &domain.TwoFactor{UserID: 1, Secret: "SECRET", EnabledAt: enabledAt, LastUsedStep: 42}
+
+	postgresTwoFactor := TwoFactorFromDomain(twoFactor)
+	if postgresTwoFactor.EnabledAt == nil || !postgresTwoFactor.EnabledAt.Equal(enabledAt) {
+		t.Errorf("Expected enabled at %v, got %v", enabledAt, postgresTwoFactor.EnabledAt)
+	}
+
+	domainTwoFactor := postgresTwoFactor.ToDomain()
+	if domainTwoFactor.UserID != 1 || domainTwoFactor.Secret != "SECRET" || domainTwoFactor.LastUsedStep != 42 || !domainTwoFactor.Enabled() {
+		t.Errorf("Expected %+v, got %+v", twoFactor, domainTwoFactor)
+	}
+
+	pending := TwoFactorFromDomain(&domain.TwoFactor{UserID: 1, Secret: "SECRET"})
+	if pending.EnabledAt != nil || pending.ToDomain().Enabled() {
+		t.Errorf("Expected a pending secret not to be enabled")
+	}
+}
+
+func TestPostgresRecoveryCodeReturnsTableName(t *testing.T) {
+	expectedTableName := "recovery_code"
+	actualTableName := PostgresRecoveryCode{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresRecoveryCodeToDomainAndBack(t *testing.T) {
+	usedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	recoveryCode := &domain.RecoveryCode{ID: 1, UserID: 2, CodeHash: "hash", UsedAt: usedAt}
+
+	domainRecoveryCode := RecoveryCodeFromDomain(recoveryCode).ToDomain()
+	if domainRecoveryCode.ID != 1 || domainRecoveryCode.UserID != 2 || domainRecoveryCode.CodeHash != "hash" || !domainRecoveryCode.UsedAt.Equal(usedAt) {
+		t.Errorf("Expected %+v, got %+v", recoveryCode, domainRecoveryCode)
+	}
+}
+
+func TestPostgresTwoFactorChallengeReturnsTableName(t *testing.T) {
+	expectedTableName := "two_factor_challenge"
+	actualTableName := PostgresTwoFactorChallenge{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresTwoFactorChallengeToDomainAndBack(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 1, 12, 5, 0, 0, time.UTC)
+	challenge := &domain.TwoFactorChallenge{ID: 1, UserID: 2, TokenHash: "hash", Attempts: 3, ExpiresAt: expiresAt}
+
+	postgresChallenge := TwoFactorChallengeFromDomain(challenge)
+	if postgresChallenge.UsedAt != nil {
+		t.Errorf("Expected no use date, got %v", postgresChallenge.UsedAt)
+	}
+
+	domainChallenge := postgresChallenge.ToDomain()
+	if domainChallenge.ID != 1 || domainChallenge.UserID != 2 || domainChallenge.TokenHash != "hash" || domainChallenge.Attempts != 3 || !domainChallenge.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected %+v, got %+v", challenge, domainChallenge)
+	}
+}
@@ -1,20 +1,25 @@
 package postgresadapter
 
 import (
+	"errors"
 	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 	"gorm.io/gorm"
 )
 
 type PostgresUser struct {
 	gorm.Model
-	ID           uint
-	Email        string          `gorm:"not null"`
-	Name         string          `gorm:"not null"`
-	Password     string          `gorm:"not null"`
-	DisabledDate time.Time       `gorm:"default:NULL"`
-	Movies       []PostgresMovie `gorm:"foreignKey:UserID"`
+	ID              uint
+	Email           string          `gorm:"not null"`
+	Name            string          `gorm:"not null"`
+	Password        string          `gorm:"not null"`
+	Role            string          `gorm:"not null;default:user"`
+	DisabledDate    time.Time       `gorm:"default:NULL"`
+	TokensRevokedAt time.Time       `gorm:"default:NULL"`
+	EmailVerifiedAt time.Time       `gorm:"default:NULL"`
+	Movies          []PostgresMovie `gorm:"foreignKey:UserID"`
 }
 
 func (PostgresUser) TableName() string {
@@ -23,12 +28,15 @@ func (PostgresUser) TableName() string {
 
 func (u *PostgresUser) ToDomain() *domain.User {
 	return &domain.User{
-		ID:           u.ID,
-		Email:        u.Email,
-		Name:         u.Name,
-		Password:     u.Password,
-		RegisterDate: u.CreatedAt,
-		DisableDate:  u.DisabledDate,
+		ID:              u.ID,
+		Email:           u.Email,
+		Name:            u.Name,
+		Password:        u.Password,
+		Role:            domain.Role(u.Role),
+		RegisterDate:    u.CreatedAt,
+		DisableDate:     u.DisabledDate,
+		TokensRevokedAt: u.TokensRevokedAt,
+		EmailVerifiedAt: u.EmailVerifiedAt,
 	}
 }
 
@@ -42,11 +50,24 @@ func NewPostgresUserRepository(postgres *PostgresDBConnection) (*PostgresUserRep
 	}, nil
 }
 
-func (repository *PostgresUserRepository) ListUsers() ([]*domain.User, error) {
+// ListUsers returns the users whose email or name contain search, ignoring
+// case, sorted by ID.
+func (repository *PostgresUserRepository) ListUsers(search string, pagination port.Pagination) ([]*domain.User, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresUser{})
+	if search != "" {
+		db = db.Where("(email ILIKE ? OR name ILIKE ?)", containsPattern(search), containsPattern(search))
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
 	var postgresUsers []PostgresUser
-	result := repository.postgres.DB.Find(&postgresUsers)
+	result := db.Order("id").Scopes(paginate(pagination)).Find(&postgresUsers)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, 0, result.Error
 	}
 
 	users := make([]*domain.User, len(postgresUsers))
@@ -54,15 +75,179 @@ func (repository *PostgresUserRepository) ListUsers() ([]*domain.User, error) {
 		users[i] = postgresUser.ToDomain()
 	}
 
-	return users, nil
+	return users, total, nil
+}
+
+func (repository *PostgresUserRepository) GetUser(id uint) (*domain.User, error) {
+	var postgresUser PostgresUser
+	result := repository.postgres.DB.First(&postgresUser, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresUser.ToDomain(), nil
+}
+
+func (repository *PostgresUserRepository) SetUserRole(id uint, role domain.Role) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Update("role", string(role))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// DisableUser marks the user as disabled and revokes the tokens issued to them
+// until then.
+func (repository *PostgresUserRepository) DisableUser(id uint, disabledAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"disabled_date":     disabledAt,
+		"tokens_revoked_at": disabledAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (repository *PostgresUserRepository) EnableUser(id uint) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Update("disabled_date", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdateUser saves the email and name of a user, and whether their email is
+// verified.
+func (repository *PostgresUserRepository) UpdateUser(user *domain.User) error {
+	var emailVerifiedAt interface{}
+	if user.EmailVerified() {
+		emailVerifiedAt = user.EmailVerifiedAt
+	}
+
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"email":             user.Email,
+		"name":              user.Name,
+		"email_verified_at": emailVerifiedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (repository *PostgresUserRepository) UpdatePassword(id uint, hashedPassword string) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Update("password", hashedPassword)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (repository *PostgresUserRepository) VerifyEmail(id uint, verifiedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Update("email_verified_at", verifiedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (repository *PostgresUserRepository) RevokeTokens(id uint, revokedAt time.Time) error {
+	result := repository.postgres.DB.Model(&PostgresUser{}).Where("id = ?", id).Update("tokens_revoked_at", revokedAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// DeleteUser deletes a user along with everything that only makes sense for
+// them: favourites, reviews, viewings, watchlist and lists. Their movies are
+// given to the reassignMoviesTo user, including the deleted ones, or deleted
+// when it is nil. The user row itself is only soft deleted, as deleted movies
+// keep referencing it.
+func (repository *PostgresUserRepository) DeleteUser(id uint, reassignMoviesTo *uint) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if reassignMoviesTo != nil {
+			result := tx.Unscoped().Model(&PostgresMovie{}).Where("user_id = ?", id).Update("user_id", *reassignMoviesTo)
+			if result.Error != nil {
+				return result.Error
+			}
+		} else {
+			if result := tx.Where("user_id = ?", id).Delete(&PostgresMovie{}); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		var reviewedMovieIDs []uint
+		result := tx.Model(&PostgresReview{}).Where("user_id = ?", id).Pluck("movie_id", &reviewedMovieIDs)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		for _, model := range []interface{}{
+			&PostgresFavourite{},
+			&PostgresReview{},
+			&PostgresViewing{},
+			&PostgresWatchlistEntry{},
+			&PostgresMovieList{},
+			&PostgresSession{},
+			&PostgresAccessToken{},
+			&PostgresRecoveryCode{},
+			&PostgresTwoFactorChallenge{},
+			&PostgresTwoFactor{},
+			&PostgresOIDCIdentity{},
+		} {
+			if result := tx.Where("user_id = ?", id).Delete(model); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		for _, movieID := range reviewedMovieIDs {
+			if err := refreshReviewStats(tx, movieID); err != nil {
+				return err
+			}
+		}
+
+		result = tx.Delete(&PostgresUser{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("user not found")
+		}
+		return nil
+	})
 }
 
 func (repository *PostgresUserRepository) CreateUser(user *domain.User) error {
 	postgresUser := PostgresUser{
-		Email:        user.Email,
-		Name:         user.Name,
-		Password:     user.Password,
-		DisabledDate: user.DisableDate,
+		Email:           user.Email,
+		Name:            user.Name,
+		Password:        user.Password,
+		Role:            string(user.Role),
+		DisabledDate:    user.DisableDate,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}
 
 	result := repository.postgres.DB.Create(&postgresUser)
@@ -70,6 +255,8 @@ func (repository *PostgresUserRepository) CreateUser(user *domain.User) error {
 		return result.Error
 	}
 
+	user.ID = postgresUser.ID
+	user.RegisterDate = postgresUser.CreatedAt
 	return nil
 }
 
@@ -3,15 +3,20 @@ package postgresadapter
 import (
 	"testing"
 	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
 )
 
 func TestPostgresUserReturnsDoaminUser(t *testing.T) {
 	now := time.Now()
 	postgresUser := PostgresUser{
-		Email:        "test@test.es",
-		Name:         "test",
-		Password:     "test",
-		DisabledDate: now,
+		Email:           "test@test.es",
+		Name:            "test",
+		Password:        "test",
+		Role:            "admin",
+		DisabledDate:    now,
+		TokensRevokedAt: now,
+		EmailVerifiedAt: now,
 	}
 
 	domainUser := postgresUser.ToDomain()
@@ -28,7 +33,19 @@ func TestPostgresUserReturnsDoaminUser(t *testing.T) {
 		t.Errorf("Expected user password to be %s, got %s", "test", domainUser.Password)
 	}
 
+	if domainUser.Role != domain.RoleAdmin {
+		t.Errorf("Expected user role to be %s, got %s", domain.RoleAdmin, domainUser.Role)
+	}
+
 	if domainUser.DisableDate != now {
 		t.Errorf("Expected user disable date to be %s, got %s", now, domainUser.DisableDate)
 	}
+
+	if domainUser.TokensRevokedAt != now {
+		t.Errorf("Expected user tokens revoked date to be %s, got %s", now, domainUser.TokensRevokedAt)
+	}
+
+	if domainUser.EmailVerifiedAt != now {
+		t.Errorf("Expected user email verification date to be %s, got %s", now, domainUser.EmailVerifiedAt)
+	}
 }
@@ -0,0 +1,101 @@
+package postgresadapter
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresEmailVerificationToken is an email verification token, of which only
+// the hash is stored. Tokens are removed along with their user.
+type PostgresEmailVerificationToken struct {
+	ID        uint         `gorm:"primaryKey"`
+	UserID    uint         `gorm:"not null;index"`
+	Email     string       `gorm:"not null"`
+	TokenHash string       `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    *time.Time   `gorm:"default:NULL"`
+	CreatedAt time.Time    `gorm:"not null"`
+	User      PostgresUser `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresEmailVerificationToken) TableName() string {
+	return "email_verification_token"
+}
+
+func (t *PostgresEmailVerificationToken) ToDomain() *domain.EmailVerificationToken {
+	token := &domain.EmailVerificationToken{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Email:     t.Email,
+		TokenHash: t.TokenHash,
+		ExpiresAt: t.ExpiresAt,
+		CreatedAt: t.CreatedAt,
+	}
+	if t.UsedAt != nil {
+		token.UsedAt = *t.UsedAt
+	}
+	return token
+}
+
+func EmailVerificationTokenFromDomain(token *domain.EmailVerificationToken) *PostgresEmailVerificationToken {
+	postgresToken := &PostgresEmailVerificationToken{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		Email:     token.Email,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if !token.UsedAt.IsZero() {
+		postgresToken.UsedAt = &token.UsedAt
+	}
+	return postgresToken
+}
+
+type PostgresEmailVerificationRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresEmailVerificationRepository(postgres *PostgresDBConnection) (*PostgresEmailVerificationRepository, error) {
+	return &PostgresEmailVerificationRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresEmailVerificationRepository) CreateVerificationToken(token *domain.EmailVerificationToken) error {
+	postgresToken := EmailVerificationTokenFromDomain(token)
+	result := repository.postgres.DB.Omit("User").Create(postgresToken)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	token.ID = postgresToken.ID
+	token.CreatedAt = postgresToken.CreatedAt
+	return nil
+}
+
+// UseVerificationToken marks the token as used in a single statement, so that two
+// concurrent requests cannot both use it.
+func (repository *PostgresEmailVerificationRepository) UseVerificationToken(tokenHash string, usedAt time.Time) (*domain.EmailVerificationToken, error) {
+	postgresToken := &PostgresEmailVerificationToken{}
+	result := repository.postgres.DB.Model(postgresToken).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, usedAt).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrInvalidVerificationToken
+	}
+
+	return postgresToken.ToDomain(), nil
+}
+
+func (repository *PostgresEmailVerificationRepository) InvalidateVerificationTokens(userID uint, at time.Time) error {
+	return repository.postgres.DB.Model(&PostgresEmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
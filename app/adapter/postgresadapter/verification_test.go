@@ -0,0 +1,58 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresEmailVerificationTokenReturnsTableName(t *testing.T) {
+	expectedTableName := "email_verification_token"
+	actualTableName := PostgresEmailVerificationToken{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresEmailVerificationTokenToDomain(t *testing.T) {
+	expiresAt := time.Date(2026, 10, 1, 13, 0, 0, 0, time.UTC)
+	postgresToken := PostgresEmailVerificationToken{ID: 1, UserID: 2, Email: "test@test.es", TokenHash: "hash", ExpiresAt: expiresAt}
+
+	domainToken := postgresToken.ToDomain()
+
+	if domainToken.ID != 1 || domainToken.UserID != 2 || domainToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 with hash 'hash', got %+v", domainToken)
+	}
+	if domainToken.Email != "test@test.es" {
+		t.Errorf("Expected email 'test@test.es', got '%s'", domainToken.Email)
+	}
+	if !domainToken.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expiry %v, got %v", expiresAt, domainToken.ExpiresAt)
+	}
+	if !domainToken.UsedAt.IsZero() {
+		t.Errorf("Expected an unused token, got used at %v", domainToken.UsedAt)
+	}
+}
+
+func TestPostgresEmailVerificationTokenFromDomain(t *testing.T) {
+	usedAt := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	domainToken := &domain.EmailVerificationToken{ID: 1, UserID: 2, Email: "test@test.es", TokenHash: "hash", UsedAt: usedAt}
+
+	postgresToken := EmailVerificationTokenFromDomain(domainToken)
+
+	if postgresToken.ID != 1 || postgresToken.UserID != 2 || postgresToken.TokenHash != "hash" {
+		t.Errorf("Expected token 1 of user 2 with hash 'hash', got %+v", postgresToken)
+	}
+	if postgresToken.Email != "test@test.es" {
+		t.Errorf("Expected email 'test@test.es', got '%s'", postgresToken.Email)
+	}
+	if postgresToken.UsedAt == nil || !postgresToken.UsedAt.Equal(usedAt) {
+		t.Errorf("Expected used at %v, got %v", usedAt, postgresToken.UsedAt)
+	}
+
+	if EmailVerificationTokenFromDomain(&domain.EmailVerificationToken{}).UsedAt != nil {
+		t.Errorf("Expected no used date for an unused token")
+	}
+}
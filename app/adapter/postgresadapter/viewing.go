@@ -0,0 +1,167 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+)
+
+// PostgresViewing is a single time a user watched a movie. Viewings are
+// removed along with the user or the movie they belong to.
+type PostgresViewing struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index:viewing_user_watched_at_idx"`
+	MovieID   uint      `gorm:"not null;index"`
+	WatchedAt time.Time `gorm:"not null;index:viewing_user_watched_at_idx"`
+	Location  string
+	Rewatch   bool   `gorm:"not null;default:false"`
+	Notes     string `gorm:"type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	User      PostgresUser  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Movie     PostgresMovie `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresViewing) TableName() string {
+	return "viewing"
+}
+
+func (v *PostgresViewing) ToDomain() *domain.Viewing {
+	viewing := &domain.Viewing{
+		ID:        v.ID,
+		UserID:    v.UserID,
+		MovieID:   v.MovieID,
+		WatchedAt: v.WatchedAt,
+		Location:  v.Location,
+		Rewatch:   v.Rewatch,
+		Notes:     v.Notes,
+		CreatedAt: v.CreatedAt,
+	}
+	if v.Movie.ID != 0 {
+		viewing.Movie = v.Movie.ToDomain()
+	}
+	return viewing
+}
+
+func ViewingFromDomain(viewing *domain.Viewing) *PostgresViewing {
+	return &PostgresViewing{
+		ID:        viewing.ID,
+		UserID:    viewing.UserID,
+		MovieID:   viewing.MovieID,
+		WatchedAt: viewing.WatchedAt,
+		Location:  viewing.Location,
+		Rewatch:   viewing.Rewatch,
+		Notes:     viewing.Notes,
+		CreatedAt: viewing.CreatedAt,
+	}
+}
+
+type PostgresViewingRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresViewingRepository(postgres *PostgresDBConnection) (*PostgresViewingRepository, error) {
+	return &PostgresViewingRepository{
+		postgres: postgres,
+	}, nil
+}
+
+func (repository *PostgresViewingRepository) CreateViewing(viewing *domain.Viewing) error {
+	postgresViewing := ViewingFromDomain(viewing)
+
+	result := repository.postgres.DB.Omit("User", "Movie").Create(postgresViewing)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	viewing.ID = postgresViewing.ID
+	viewing.CreatedAt = postgresViewing.CreatedAt
+	return nil
+}
+
+func (repository *PostgresViewingRepository) GetViewing(id uint) (*domain.Viewing, error) {
+	postgresViewing := &PostgresViewing{}
+	result := repository.postgres.DB.First(postgresViewing, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresViewing.ToDomain(), nil
+}
+
+func (repository *PostgresViewingRepository) UpdateViewing(viewing *domain.Viewing) error {
+	result := repository.postgres.DB.Model(&PostgresViewing{ID: viewing.ID}).Updates(map[string]interface{}{
+		"watched_at": viewing.WatchedAt,
+		"location":   viewing.Location,
+		"rewatch":    viewing.Rewatch,
+		"notes":      viewing.Notes,
+	})
+	return result.Error
+}
+
+func (repository *PostgresViewingRepository) DeleteViewing(viewing *domain.Viewing) error {
+	result := repository.postgres.DB.Delete(&PostgresViewing{}, viewing.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("viewing not found")
+	}
+	return nil
+}
+
+// ListViewings returns the viewings of a user, most recently watched first,
+// skipping the ones of deleted movies.
+func (repository *PostgresViewingRepository) ListViewings(userID uint, filter port.ViewingFilter, pagination port.Pagination) ([]*domain.Viewing, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresViewing{}).
+		Joins("JOIN movie ON movie.id = viewing.movie_id AND movie.deleted_at IS NULL").
+		Where("viewing.user_id = ?", userID)
+	if filter.MovieID != nil {
+		db = db.Where("viewing.movie_id = ?", *filter.MovieID)
+	}
+	if filter.WatchedFrom != nil {
+		db = db.Where("viewing.watched_at >= ?", *filter.WatchedFrom)
+	}
+	if filter.WatchedTo != nil {
+		db = db.Where("viewing.watched_at < ?", *filter.WatchedTo)
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresViewings []PostgresViewing
+	result := db.Order("viewing.watched_at DESC").Order("viewing.id DESC").
+		Scopes(paginate(pagination)).
+		Preload("Movie").
+		Preload("Movie.Genres", func(db *gorm.DB) *gorm.DB {
+			return db.Order("genre.name")
+		}).
+		Find(&postgresViewings)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	viewings := make([]*domain.Viewing, len(postgresViewings))
+	for i, postgresViewing := range postgresViewings {
+		viewings[i] = postgresViewing.ToDomain()
+	}
+
+	return viewings, total, nil
+}
+
+func (repository *PostgresViewingRepository) HasViewedBefore(userID uint, movieID uint, before time.Time) (bool, error) {
+	var count int64
+	result := repository.postgres.DB.Model(&PostgresViewing{}).
+		Where("user_id = ? AND movie_id = ? AND watched_at < ?", userID, movieID, before).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,59 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresViewingReturnsTableName(t *testing.T) {
+	expectedTableName := "viewing"
+	actualTableName := PostgresViewing{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresViewingToDomain(t *testing.T) {
+	watchedAt := time.Date(2026, 10, 5, 21, 30, 0, 0, time.UTC)
+	postgresViewing := PostgresViewing{
+		ID:        1,
+		UserID:    2,
+		MovieID:   3,
+		WatchedAt: watchedAt,
+		Location:  "Cinema",
+		Rewatch:   true,
+		Notes:     "Front row",
+		Movie:     PostgresMovie{ID: 3, Title: "Inception"},
+	}
+
+	domainViewing := postgresViewing.ToDomain()
+
+	if domainViewing.ID != 1 || domainViewing.UserID != 2 || domainViewing.MovieID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", domainViewing.ID, domainViewing.UserID, domainViewing.MovieID)
+	}
+	if !domainViewing.WatchedAt.Equal(watchedAt) {
+		t.Errorf("Expected watched at %v, got %v", watchedAt, domainViewing.WatchedAt)
+	}
+	if domainViewing.Location != "Cinema" || !domainViewing.Rewatch || domainViewing.Notes != "Front row" {
+		t.Errorf("Expected a rewatch at 'Cinema' with notes, got %+v", domainViewing)
+	}
+	if domainViewing.Movie == nil || domainViewing.Movie.Title != "Inception" {
+		t.Errorf("Expected movie 'Inception', got %v", domainViewing.Movie)
+	}
+}
+
+func TestPostgresViewingFromDomain(t *testing.T) {
+	domainViewing := &domain.Viewing{ID: 1, UserID: 2, MovieID: 3, Location: "Netflix", Rewatch: true}
+
+	postgresViewing := ViewingFromDomain(domainViewing)
+
+	if postgresViewing.ID != 1 || postgresViewing.UserID != 2 || postgresViewing.MovieID != 3 {
+		t.Errorf("Expected IDs 1/2/3, got %d/%d/%d", postgresViewing.ID, postgresViewing.UserID, postgresViewing.MovieID)
+	}
+	if postgresViewing.Location != "Netflix" || !postgresViewing.Rewatch {
+		t.Errorf("Expected a rewatch on 'Netflix', got %+v", postgresViewing)
+	}
+}
@@ -0,0 +1,183 @@
+package postgresadapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"gorm.io/gorm"
+)
+
+// PostgresWatchlistEntry is a movie in a user's watchlist. Entries are removed
+// along with the user or the movie they belong to.
+type PostgresWatchlistEntry struct {
+	UserID    uint          `gorm:"primaryKey"`
+	MovieID   uint          `gorm:"primaryKey"`
+	Position  int           `gorm:"not null"`
+	Priority  string        `gorm:"not null;default:normal"`
+	CreatedAt time.Time     `gorm:"not null"`
+	User      PostgresUser  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Movie     PostgresMovie `gorm:"foreignKey:MovieID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostgresWatchlistEntry) TableName() string {
+	return "watchlist"
+}
+
+func (w *PostgresWatchlistEntry) ToDomain() *domain.WatchlistEntry {
+	entry := &domain.WatchlistEntry{
+		UserID:   w.UserID,
+		MovieID:  w.MovieID,
+		Position: w.Position,
+		Priority: domain.WatchlistPriority(w.Priority),
+		AddedAt:  w.CreatedAt,
+	}
+	if w.Movie.ID != 0 {
+		entry.Movie = w.Movie.ToDomain()
+	}
+	return entry
+}
+
+func WatchlistEntryFromDomain(entry *domain.WatchlistEntry) *PostgresWatchlistEntry {
+	return &PostgresWatchlistEntry{
+		UserID:    entry.UserID,
+		MovieID:   entry.MovieID,
+		Position:  entry.Position,
+		Priority:  string(entry.Priority),
+		CreatedAt: entry.AddedAt,
+	}
+}
+
+type PostgresWatchlistRepository struct {
+	postgres *PostgresDBConnection
+}
+
+func NewPostgresWatchlistRepository(postgres *PostgresDBConnection) (*PostgresWatchlistRepository, error) {
+	return &PostgresWatchlistRepository{
+		postgres: postgres,
+	}, nil
+}
+
+// AddToWatchlist appends the entry after the last one of the user's watchlist.
+func (repository *PostgresWatchlistRepository) AddToWatchlist(entry *domain.WatchlistEntry) error {
+	postgresEntry := WatchlistEntryFromDomain(entry)
+
+	err := repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var lastPosition int
+		result := tx.Model(&PostgresWatchlistEntry{}).
+			Select("COALESCE(MAX(position), 0)").
+			Where("user_id = ?", postgresEntry.UserID).
+			Scan(&lastPosition)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		postgresEntry.Position = lastPosition + 1
+		return tx.Omit("User", "Movie").Create(postgresEntry).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	entry.Position = postgresEntry.Position
+	return nil
+}
+
+func (repository *PostgresWatchlistRepository) GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	postgresEntry := &PostgresWatchlistEntry{}
+	result := repository.postgres.DB.Where("user_id = ? AND movie_id = ?", userID, movieID).First(postgresEntry)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWatchlistEntryNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return postgresEntry.ToDomain(), nil
+}
+
+func (repository *PostgresWatchlistRepository) UpdateWatchlistEntry(entry *domain.WatchlistEntry) error {
+	result := repository.postgres.DB.Model(&PostgresWatchlistEntry{}).
+		Where("user_id = ? AND movie_id = ?", entry.UserID, entry.MovieID).
+		Update("priority", string(entry.Priority))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrWatchlistEntryNotFound
+	}
+	return nil
+}
+
+// RemoveFromWatchlist deletes the entry and moves the ones after it up, so
+// that positions stay contiguous.
+func (repository *PostgresWatchlistRepository) RemoveFromWatchlist(entry *domain.WatchlistEntry) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		postgresEntry := &PostgresWatchlistEntry{}
+		result := tx.Where("user_id = ? AND movie_id = ?", entry.UserID, entry.MovieID).First(postgresEntry)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return domain.ErrWatchlistEntryNotFound
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Where("user_id = ? AND movie_id = ?", entry.UserID, entry.MovieID).Delete(&PostgresWatchlistEntry{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Model(&PostgresWatchlistEntry{}).
+			Where("user_id = ? AND position > ?", postgresEntry.UserID, postgresEntry.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// ListWatchlist returns the watchlist of a user in order, skipping deleted
+// movies.
+func (repository *PostgresWatchlistRepository) ListWatchlist(userID uint, pagination port.Pagination) ([]*domain.WatchlistEntry, int64, error) {
+	db := repository.postgres.DB.Model(&PostgresWatchlistEntry{}).
+		Joins("JOIN movie ON movie.id = watchlist.movie_id AND movie.deleted_at IS NULL").
+		Where("watchlist.user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if result := db.Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var postgresEntries []PostgresWatchlistEntry
+	result := db.Order("watchlist.position").
+		Scopes(paginate(pagination)).
+		Preload("Movie").
+		Preload("Movie.Genres", func(db *gorm.DB) *gorm.DB {
+			return db.Order("genre.name")
+		}).
+		Find(&postgresEntries)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	entries := make([]*domain.WatchlistEntry, len(postgresEntries))
+	for i, postgresEntry := range postgresEntries {
+		entries[i] = postgresEntry.ToDomain()
+	}
+
+	return entries, total, nil
+}
+
+// ReorderWatchlist gives each movie of movieIDs its index as position.
+func (repository *PostgresWatchlistRepository) ReorderWatchlist(userID uint, movieIDs []uint) error {
+	return repository.postgres.DB.Transaction(func(tx *gorm.DB) error {
+		for i, movieID := range movieIDs {
+			result := tx.Model(&PostgresWatchlistEntry{}).
+				Where("user_id = ? AND movie_id = ?", userID, movieID).
+				Update("position", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package postgresadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+func TestPostgresWatchlistEntryReturnsTableName(t *testing.T) {
+	expectedTableName := "watchlist"
+	actualTableName := PostgresWatchlistEntry{}.TableName()
+
+	if actualTableName != expectedTableName {
+		t.Errorf("Expected table name '%s', but got '%s'", expectedTableName, actualTableName)
+	}
+}
+
+func TestPostgresWatchlistEntryToDomain(t *testing.T) {
+	addedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	postgresEntry := PostgresWatchlistEntry{UserID: 1, MovieID: 2, Position: 3, Priority: "high", CreatedAt: addedAt}
+
+	domainEntry := postgresEntry.ToDomain()
+
+	if domainEntry.UserID != 1 || domainEntry.MovieID != 2 || domainEntry.Position != 3 {
+		t.Errorf("Expected user 1, movie 2 at position 3, got %+v", domainEntry)
+	}
+	if domainEntry.Priority != domain.WatchlistPriorityHigh {
+		t.Errorf("Expected priority 'high', got '%s'", domainEntry.Priority)
+	}
+	if !domainEntry.AddedAt.Equal(addedAt) {
+		t.Errorf("Expected added at %v, got %v", addedAt, domainEntry.AddedAt)
+	}
+	if domainEntry.Movie != nil {
+		t.Errorf("Expected no movie when it is not loaded, got %v", domainEntry.Movie)
+	}
+}
+
+func TestPostgresWatchlistEntryFromDomain(t *testing.T) {
+	addedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	domainEntry := &domain.WatchlistEntry{UserID: 1, MovieID: 2, Position: 3, Priority: domain.WatchlistPriorityLow, AddedAt: addedAt}
+
+	postgresEntry := WatchlistEntryFromDomain(domainEntry)
+
+	if postgresEntry.UserID != 1 || postgresEntry.MovieID != 2 || postgresEntry.Position != 3 {
+		t.Errorf("Expected user 1, movie 2 at position 3, got %+v", postgresEntry)
+	}
+	if postgresEntry.Priority != "low" {
+		t.Errorf("Expected priority 'low', got '%s'", postgresEntry.Priority)
+	}
+	if !postgresEntry.CreatedAt.Equal(addedAt) {
+		t.Errorf("Expected created at %v, got %v", addedAt, postgresEntry.CreatedAt)
+	}
+}
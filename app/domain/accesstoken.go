@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Scope is what a personal access token may be used for.
+type Scope string
+
+const (
+	ScopeMoviesRead   Scope = "movies:read"
+	ScopeMoviesWrite  Scope = "movies:write"
+	ScopeListsRead    Scope = "lists:read"
+	ScopeListsWrite   Scope = "lists:write"
+	ScopeLibraryRead  Scope = "library:read"
+	ScopeLibraryWrite Scope = "library:write"
+)
+
+// Scopes lists every scope, in the order they are documented.
+var Scopes = []Scope{
+	ScopeMoviesRead,
+	ScopeMoviesWrite,
+	ScopeListsRead,
+	ScopeListsWrite,
+	ScopeLibraryRead,
+	ScopeLibraryWrite,
+}
+
+// Valid reports whether the scope is one of the known scopes.
+func (s Scope) Valid() bool {
+	return slices.Contains(Scopes, s)
+}
+
+// AccessTokenPrefix starts every personal access token, which tells them apart
+// from JWTs and makes them easy to spot in leaked secrets.
+const AccessTokenPrefix = "mcpat_"
+
+// PersonalAccessToken lets scripts and integrations act as a user, limited to
+// its scopes, without knowing their password. Only the hash of the token is
+// stored. A zero ExpiresAt means the token does not expire.
+type PersonalAccessToken struct {
+	ID         uint
+	UserID     uint
+	Name       string
+	TokenHash  string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  time.Time
+}
+
+// Active reports whether the token can still be used at the given time.
+func (t *PersonalAccessToken) Active(at time.Time) bool {
+	return t.RevokedAt.IsZero() && (t.ExpiresAt.IsZero() || t.ExpiresAt.After(at))
+}
+
+// HasScope reports whether the token may be used for the given scope.
+func (t *PersonalAccessToken) HasScope(scope Scope) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+var (
+	// ErrInvalidScope is returned when creating a personal access token with
+	// an unknown scope, or without any.
+	ErrInvalidScope = errors.New("invalid personal access token scope")
+	// ErrInvalidAccessToken is returned for unknown, expired or revoked
+	// personal access tokens, and for the tokens of disabled users.
+	ErrInvalidAccessToken = errors.New("invalid or expired personal access token")
+	// ErrAccessTokenNotFound is returned for personal access tokens which do
+	// not exist, or do not belong to the user asking for them.
+	ErrAccessTokenNotFound = errors.New("personal access token not found")
+)
@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+type Favourite struct {
+	UserID    uint
+	MovieID   uint
+	CreatedAt time.Time
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Genre struct {
+	ID         uint
+	Name       string
+	MovieCount int64
+}
+
+// ErrGenreExists is returned when creating a genre with the name of another
+// one, whatever the case.
+var ErrGenreExists = errors.New("genre already exists")
+
+// ErrBlankGenreName is returned when creating a genre whose name is only
+// whitespace.
+var ErrBlankGenreName = errors.New("genre name must not be blank")
+
+// UnknownGenresError is returned when a movie references genres that are not
+// in the catalogue.
+type UnknownGenresError struct {
+	Names []string
+}
+
+func (e *UnknownGenresError) Error() string {
+	return fmt.Sprintf("unknown genres: %s", strings.Join(e.Names, ", "))
+}
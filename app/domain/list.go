@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type ListVisibility string
+
+const (
+	// ListVisibilityPrivate lists are only visible to their owner.
+	ListVisibilityPrivate ListVisibility = "private"
+	// ListVisibilityUnlisted lists are also visible to anyone with their
+	// secret share link.
+	ListVisibilityUnlisted ListVisibility = "unlisted"
+	// ListVisibilityPublic lists are visible to every user.
+	ListVisibilityPublic ListVisibility = "public"
+)
+
+// MovieList is a named, ordered selection of movies curated by a user.
+// EntryCount is only set when listing lists, which leaves Entries empty.
+type MovieList struct {
+	ID          uint
+	UserID      uint
+	Title       string
+	Description string
+	Visibility  ListVisibility
+	ShareToken  string
+	Entries     []MovieListEntry
+	EntryCount  int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// MovieListEntry is a movie in a list. Position orders the entries of a list,
+// starting at 1.
+type MovieListEntry struct {
+	MovieID  uint
+	Position int
+	Notes    string
+	Movie    *Movie
+}
+
+// ErrDuplicateListEntry is returned when a movie appears twice in a list.
+var ErrDuplicateListEntry = errors.New("a movie can only appear once in a list")
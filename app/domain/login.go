@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// LoginThrottle counts the recent failed logins for an email or an IP address.
+// The count starts over once failures stop for a while.
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LoginFailureReason tells why a login failed.
+type LoginFailureReason string
+
+const (
+	// LoginFailureInvalidCredentials is a wrong password, or an email without
+	// an account.
+	LoginFailureInvalidCredentials LoginFailureReason = "invalid_credentials"
+	// LoginFailureDisabled is the right password of a disabled account.
+	LoginFailureDisabled LoginFailureReason = "disabled"
+	// LoginFailureInvalidTwoFactorCode is a wrong code given to complete the
+	// two-factor challenge of a login.
+	LoginFailureInvalidTwoFactorCode LoginFailureReason = "invalid_two_factor_code"
+	// LoginFailureThrottled is an attempt made before the backoff of its email
+	// or IP address was over. Its password is not checked.
+	LoginFailureThrottled LoginFailureReason = "throttled"
+)
+
+// LoginFailure is an audit entry of a failed login.
+type LoginFailure struct {
+	ID        uint
+	Email     string
+	IPAddress string
+	UserAgent string
+	Reason    LoginFailureReason
+	CreatedAt time.Time
+}
+
+var (
+	// ErrInvalidCredentials is returned for both wrong passwords and emails
+	// without an account, so that which emails have one cannot be told.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrTooManyLoginAttempts is returned when logging in too soon after too
+	// many failures for the same email or from the same IP address.
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+)
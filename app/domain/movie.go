@@ -1,15 +1,29 @@
 package domain
 
+import "time"
+
 type Movie struct {
-	ID          uint
-	Title       string
-	Director    string
-	ReleaseYear int
-	Cast        string
-	Genre       string
-	Synopsis    string
-	Rating      float64
-	Duration    int
-	PosterURL   string
-	UserID      uint
+	ID           uint
+	Title        string
+	Director     string
+	ReleaseYear  int
+	Cast         string
+	Genres       []Genre
+	Synopsis     string
+	Rating       float64
+	Duration     int
+	PosterURL    string
+	UserID       uint
+	CreatedAt    time.Time
+	ReviewCount  int64
+	AverageScore float64
+}
+
+// MovieSearchResult is a movie matching a full-text search, along with its
+// relevance and the matching fragments of its title and synopsis.
+type MovieSearchResult struct {
+	Movie             *Movie
+	Rank              float64
+	TitleHighlight    string
+	SynopsisHighlight string
 }
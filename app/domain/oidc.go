@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// OIDCLoginState is what is kept of a login with an OpenID Connect provider
+// while the user signs in there. Only the hash of the state sent along is
+// stored, and it can only be used once, before it expires.
+type OIDCLoginState struct {
+	ID           uint
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       time.Time
+	CreatedAt    time.Time
+}
+
+// OIDCIdentity links a user to their subject at an OpenID Connect provider,
+// which unlike their email never changes.
+type OIDCIdentity struct {
+	ID        uint
+	UserID    uint
+	Provider  string
+	Subject   string
+	CreatedAt time.Time
+}
+
+// OIDCClaims are the claims of a validated ID token used to find or create the
+// user logging in.
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+var (
+	// ErrOIDCProviderNotFound is returned for providers that are not
+	// configured.
+	ErrOIDCProviderNotFound = errors.New("login provider not found")
+	// ErrInvalidOIDCState is returned for unknown, expired or already used
+	// login states, and for those of another provider.
+	ErrInvalidOIDCState = errors.New("invalid or expired login state")
+	// ErrOIDCLoginFailed is returned when the provider does not confirm the
+	// login, such as for rejected codes and invalid ID tokens.
+	ErrOIDCLoginFailed = errors.New("the login provider did not confirm the login")
+	// ErrOIDCEmailNotVerified is returned when the provider does not vouch for
+	// the email of a user logging in for the first time.
+	ErrOIDCEmailNotVerified = errors.New("the login provider has not verified the email")
+)
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// PasswordResetToken lets a user who forgot their password choose a new one.
+// Only the hash of the token is stored, and it can only be used once before it
+// expires.
+type PasswordResetToken struct {
+	ID        uint
+	UserID    uint
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
+
+// ErrInvalidResetToken is returned for unknown, expired or already used
+// password reset tokens.
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
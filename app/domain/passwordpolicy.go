@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// PasswordPolicy is what the passwords users choose must meet. Lengths are in
+// characters, and MinStrength is the lowest strength score allowed, from 0
+// (guessed at once) to 4 (very hard to guess). MaxBytes, when set, also limits
+// the length in bytes for password hashers which cannot take longer ones.
+type PasswordPolicy struct {
+	MinLength   int
+	MaxLength   int
+	MinStrength int
+	MaxBytes    int
+}
+
+// DefaultPasswordPolicy asks for at least 8 characters and a password hard to
+// guess, while allowing long passphrases.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:   8,
+		MaxLength:   128,
+		MinStrength: 3,
+	}
+}
+
+// PasswordRejectionCode tells why a password was rejected.
+type PasswordRejectionCode string
+
+const (
+	PasswordTooShort PasswordRejectionCode = "too_short"
+	PasswordTooLong  PasswordRejectionCode = "too_long"
+	// PasswordTooWeak is a password whose strength score is too low.
+	PasswordTooWeak PasswordRejectionCode = "too_weak"
+	// PasswordContainsEmail is a password containing the email of its user.
+	PasswordContainsEmail PasswordRejectionCode = "contains_email"
+	// PasswordContainsName is a password containing the name of its user.
+	PasswordContainsName PasswordRejectionCode = "contains_name"
+	// PasswordBreached is a password found in known data breaches.
+	PasswordBreached PasswordRejectionCode = "breached"
+)
+
+// PasswordRejection is a reason a password was rejected, with a message for
+// the user.
+type PasswordRejection struct {
+	Code    PasswordRejectionCode
+	Message string
+}
+
+// ErrPasswordRejected is what every PasswordPolicyError unwraps to.
+var ErrPasswordRejected = errors.New("password does not meet the password policy")
+
+// PasswordPolicyError is returned when a password does not meet the password
+// policy, with every reason it does not.
+type PasswordPolicyError struct {
+	Rejections []PasswordRejection
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Rejections))
+	for i, rejection := range e.Rejections {
+		messages[i] = rejection.Message
+	}
+	return ErrPasswordRejected.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordRejected
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "errors"
+
+type Person struct {
+	ID        uint
+	Name      string
+	Biography string
+}
+
+type CreditRole string
+
+const (
+	CreditRoleDirector CreditRole = "director"
+	CreditRoleActor    CreditRole = "actor"
+	CreditRoleWriter   CreditRole = "writer"
+	CreditRoleComposer CreditRole = "composer"
+)
+
+// MovieCredit links a person to a movie they worked on. Character is only
+// meaningful for actors, and BillingOrder ranks the credits of a movie.
+type MovieCredit struct {
+	ID           uint
+	MovieID      uint
+	PersonID     uint
+	Role         CreditRole
+	Character    string
+	BillingOrder int
+	Movie        *Movie
+	Person       *Person
+}
+
+// ErrPersonHasCredits is returned when deleting a person still credited in a
+// movie, as the credits belong to the movies of other users too.
+var ErrPersonHasCredits = errors.New("person still has credits")
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// Review is a user's personal opinion of a movie, independent from the
+// catalogue rating. A user reviews a movie at most once, and WatchedDate is
+// zero when they did not say when they watched it.
+type Review struct {
+	ID          uint
+	UserID      uint
+	MovieID     uint
+	Score       float64
+	Text        string
+	WatchedDate time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	User        *User
+	Movie       *Movie
+}
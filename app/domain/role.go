@@ -0,0 +1,45 @@
+package domain
+
+import "errors"
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Permission is something a user may do beyond managing what they own.
+type Permission string
+
+const (
+	PermissionManageUsers    Permission = "manage_users"
+	PermissionEditAnyMovie   Permission = "edit_any_movie"
+	PermissionDeleteAnyMovie Permission = "delete_any_movie"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermissionEditAnyMovie},
+	RoleAdmin:     {PermissionManageUsers, PermissionEditAnyMovie, PermissionDeleteAnyMovie},
+}
+
+// Valid reports whether the role is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Can reports whether users with the role have the given permission.
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidRole is returned when assigning a role that does not exist.
+var ErrInvalidRole = errors.New("invalid role")
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Session is a device or script a user logged in from. It lasts as long as its
+// refresh tokens keep being used, until it expires or is revoked.
+type Session struct {
+	ID         uint
+	UserID     uint
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  time.Time
+}
+
+// Active reports whether the session can still be used at the given time.
+func (s *Session) Active(at time.Time) bool {
+	return s.RevokedAt.IsZero() && s.ExpiresAt.After(at)
+}
+
+// RefreshToken gets a new access token for its session. Each refresh token
+// can only be used once, and using it gives the next one. Only the hash of the
+// token is stored.
+type RefreshToken struct {
+	ID        uint
+	SessionID uint
+	TokenHash string
+	CreatedAt time.Time
+	UsedAt    time.Time
+}
+
+var (
+	// ErrInvalidRefreshToken is returned for unknown refresh tokens, and for
+	// the refresh tokens of expired or revoked sessions.
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenReused is returned when a refresh token is used again,
+	// which means it may have been stolen. Its whole session is revoked.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+	// ErrSessionNotFound is returned for sessions which do not exist, or do
+	// not belong to the user asking for them.
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionRevoked is returned for access tokens of a revoked or expired
+	// session.
+	ErrSessionRevoked = errors.New("session has been revoked")
+)
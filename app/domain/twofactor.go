@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// TwoFactor is the TOTP secret of a user. It is pending until the user proves
+// their authenticator app works by entering a code, and only enabled ones are
+// asked for when logging in. LastUsedStep is the time step of the last code
+// accepted, so that a code cannot be used twice.
+type TwoFactor struct {
+	UserID       uint
+	Secret       string
+	EnabledAt    time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// Enabled reports whether the user has confirmed their TOTP secret.
+func (t *TwoFactor) Enabled() bool {
+	return !t.EnabledAt.IsZero()
+}
+
+// TOTPEnrolment is what an authenticator app needs to generate the codes of a
+// new TOTP secret.
+type TOTPEnrolment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// RecoveryCode logs a user in once in place of a TOTP code, for when they lose
+// their authenticator app. Only the hash of the code is stored.
+type RecoveryCode struct {
+	ID       uint
+	UserID   uint
+	CodeHash string
+	UsedAt   time.Time
+}
+
+// TwoFactorChallenge is what a user with two-factor authentication gets from
+// logging in with their password, to be exchanged along with a TOTP or
+// recovery code for a token. Only the hash of the challenge token is stored,
+// and it expires after a few minutes or a few wrong codes.
+type TwoFactorChallenge struct {
+	ID        uint
+	UserID    uint
+	TokenHash string
+	Attempts  int
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
+
+var (
+	// ErrTwoFactorNotEnabled is returned when disabling two-factor
+	// authentication, or using its recovery codes, for a user without it.
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTwoFactorAlreadyEnabled is returned when enrolling a user who already
+	// has two-factor authentication.
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotStarted is returned when confirming an enrolment that was
+	// never started.
+	ErrTwoFactorNotStarted = errors.New("two-factor authentication enrolment was not started")
+	// ErrInvalidTwoFactorCode is returned for wrong or reused TOTP codes and
+	// recovery codes.
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+	// ErrInvalidTwoFactorChallenge is returned for unknown, expired or already
+	// used challenge tokens, and for those with too many wrong codes.
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge token")
+)
@@ -1,15 +1,50 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
+// User is an account of the application. A zero DisableDate means the account
+// is enabled, and a zero EmailVerifiedAt that its email is pending
+// verification. Tokens issued before TokensRevokedAt are no longer accepted.
 type User struct {
-	ID           uint
-	Email        string
-	Name         string
-	Password     string
-	RegisterDate time.Time
-	DisableDate  time.Time
-	Movies       []Movie
+	ID              uint
+	Email           string
+	Name            string
+	Password        string
+	Role            Role
+	RegisterDate    time.Time
+	DisableDate     time.Time
+	TokensRevokedAt time.Time
+	EmailVerifiedAt time.Time
+	Movies          []Movie
 }
+
+// Disabled reports whether the account has been disabled by an admin.
+func (u *User) Disabled() bool {
+	return !u.DisableDate.IsZero()
+}
+
+// EmailVerified reports whether the user has proved they own their email.
+func (u *User) EmailVerified() bool {
+	return !u.EmailVerifiedAt.IsZero()
+}
+
+var (
+	// ErrUserDisabled is returned when a disabled user tries to log in or use
+	// a token.
+	ErrUserDisabled = errors.New("user account is disabled")
+	// ErrTokenRevoked is returned for tokens issued before the tokens of their
+	// user were revoked.
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrTokenNotRevocable is returned when revoking a token issued before
+	// tokens had an ID.
+	ErrTokenNotRevocable = errors.New("token has no ID and cannot be revoked")
+	// ErrWrongPassword is returned when the current password given to confirm
+	// a change to an account is not the right one.
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrInvalidMovieReassignment is returned when the movies of a deleted
+	// account cannot be given to the chosen user.
+	ErrInvalidMovieReassignment = errors.New("movies can only be reassigned to another existing user")
+)
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// EmailVerificationToken proves that a user owns the email it was sent to.
+// Only the hash of the token is stored, and it can only be used once before it
+// expires.
+type EmailVerificationToken struct {
+	ID        uint
+	UserID    uint
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
+
+var (
+	// ErrInvalidVerificationToken is returned for unknown, expired or already
+	// used email verification tokens, and for tokens sent to an email the user
+	// no longer has.
+	ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+	// ErrEmailAlreadyVerified is returned when asking to verify an email that
+	// is already verified.
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	// ErrEmailNotVerified is returned when a user with a pending email tries
+	// to do something that needs it verified.
+	ErrEmailNotVerified = errors.New("email is not verified")
+)
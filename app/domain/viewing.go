@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Viewing is a single time a user watched a movie. Rewatch is set when the
+// user had already seen the movie before.
+type Viewing struct {
+	ID        uint
+	UserID    uint
+	MovieID   uint
+	WatchedAt time.Time
+	Location  string
+	Rewatch   bool
+	Notes     string
+	CreatedAt time.Time
+	Movie     *Movie
+}
+
+// DiaryDay groups the viewings of a user on a single day.
+type DiaryDay struct {
+	Date     time.Time
+	Viewings []*Viewing
+}
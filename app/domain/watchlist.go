@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type WatchlistPriority string
+
+const (
+	WatchlistPriorityLow    WatchlistPriority = "low"
+	WatchlistPriorityNormal WatchlistPriority = "normal"
+	WatchlistPriorityHigh   WatchlistPriority = "high"
+)
+
+// WatchlistEntry is a movie a user wants to watch. Position orders the entries
+// of a user's watchlist, starting at 1.
+type WatchlistEntry struct {
+	UserID   uint
+	MovieID  uint
+	Position int
+	Priority WatchlistPriority
+	AddedAt  time.Time
+	Movie    *Movie
+}
+
+// ErrWatchlistEntryNotFound is returned for movies which are not in the
+// watchlist of a user.
+var ErrWatchlistEntryNotFound = errors.New("watchlist entry not found")
+
+// ErrInvalidWatchlistOrder is returned when reordering a watchlist with a
+// list of movies that is not exactly the movies in it.
+var ErrInvalidWatchlistOrder = errors.New("the new order must list every movie of the watchlist exactly once")
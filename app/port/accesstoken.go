@@ -0,0 +1,27 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type AccessTokenRepository interface {
+	CreateAccessToken(token *domain.PersonalAccessToken) error
+	GetAccessToken(id uint) (*domain.PersonalAccessToken, error)
+	GetAccessTokenByHash(tokenHash string) (*domain.PersonalAccessToken, error)
+	// ListAccessTokens returns the tokens of a user which are not revoked,
+	// newest first.
+	ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error)
+	RevokeAccessToken(id uint, revokedAt time.Time) error
+	// RevokeAccessTokens revokes every token of a user not revoked yet.
+	RevokeAccessTokens(userID uint, revokedAt time.Time) error
+	SetAccessTokenLastUsed(id uint, lastUsedAt time.Time) error
+}
+
+type AccessTokenService interface {
+	CreateAccessToken(userID uint, name string, scopes []domain.Scope, expiresAt time.Time) (*domain.PersonalAccessToken, string, error)
+	ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error)
+	RevokeAccessToken(userID uint, tokenID uint) error
+	AuthenticateAccessToken(token string) (*domain.User, *domain.PersonalAccessToken, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type FavouritesRepository interface {
+	AddFavourite(favourite *domain.Favourite) error
+	RemoveFavourite(favourite *domain.Favourite) error
+	ListFavourites(userID uint, pagination Pagination) ([]*domain.Movie, int64, error)
+}
+
+type FavouritesService interface {
+	AddFavourite(favourite *domain.Favourite) error
+	RemoveFavourite(favourite *domain.Favourite) error
+	ListFavourites(userID uint, pagination Pagination) ([]*domain.Movie, int64, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type GenreRepository interface {
+	CreateGenre(genre *domain.Genre) error
+	ListGenres() ([]*domain.Genre, error)
+	GetGenresByName(names []string) ([]*domain.Genre, error)
+}
+
+type GenreService interface {
+	CreateGenre(genre *domain.Genre) error
+	ListGenres() ([]*domain.Genre, error)
+	ResolveGenres(names []string) ([]domain.Genre, error)
+}
@@ -0,0 +1,33 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+// MovieListFilter narrows a listing of movie lists to the ones VisibleTo a
+// user can see, that is their own lists and the public ones. OwnerID further
+// restricts it to the lists of a single user.
+type MovieListFilter struct {
+	VisibleTo uint
+	OwnerID   *uint
+}
+
+type MovieListRepository interface {
+	CreateList(list *domain.MovieList) error
+	GetList(id uint) (*domain.MovieList, error)
+	GetListByShareToken(token string) (*domain.MovieList, error)
+	UpdateList(list *domain.MovieList) error
+	// UpdateShareToken saves the share token of a list, leaving the rest of it
+	// untouched.
+	UpdateShareToken(list *domain.MovieList) error
+	DeleteList(list *domain.MovieList) error
+	ListLists(filter MovieListFilter, pagination Pagination) ([]*domain.MovieList, int64, error)
+}
+
+type MovieListService interface {
+	CreateList(list *domain.MovieList) error
+	GetList(id uint) (*domain.MovieList, error)
+	GetSharedList(token string) (*domain.MovieList, error)
+	UpdateList(list *domain.MovieList) error
+	DeleteList(list *domain.MovieList) error
+	ListLists(filter MovieListFilter, pagination Pagination) ([]*domain.MovieList, int64, error)
+	RegenerateShareToken(list *domain.MovieList) error
+}
@@ -0,0 +1,40 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// LoginFailureFilter narrows down the login failures listed. Empty fields do
+// not filter.
+type LoginFailureFilter struct {
+	Email     string
+	IPAddress string
+}
+
+type LoginThrottleRepository interface {
+	// GetLoginThrottle returns the failed logins counted for a key, or nil
+	// without an error when there are none.
+	GetLoginThrottle(key string) (*domain.LoginThrottle, error)
+	// RecordLoginFailure counts a failed login for a key and returns the new
+	// count. The count starts over when the last failure was before
+	// resetBefore.
+	RecordLoginFailure(key string, at time.Time, resetBefore time.Time) (*domain.LoginThrottle, error)
+	ResetLoginThrottle(key string) error
+
+	CreateLoginFailure(failure *domain.LoginFailure) error
+	// ListLoginFailures returns the login failures matching the filter, most
+	// recent first, along with how many there are in total.
+	ListLoginFailures(filter LoginFailureFilter, pagination Pagination) ([]*domain.LoginFailure, int64, error)
+}
+
+type LoginThrottleService interface {
+	// CheckLogin fails with domain.ErrTooManyLoginAttempts, along with when to
+	// try again, while logins for the email or from the IP address are held
+	// back.
+	CheckLogin(email string, ipAddress string, userAgent string) (time.Time, error)
+	RecordFailure(email string, ipAddress string, userAgent string, reason domain.LoginFailureReason) error
+	RecordSuccess(email string) error
+	ListLoginFailures(filter LoginFailureFilter, pagination Pagination) ([]*domain.LoginFailure, int64, error)
+}
@@ -0,0 +1,7 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type Mailer interface {
+	Send(mail *domain.Mail) error
+}
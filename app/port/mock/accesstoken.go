@@ -0,0 +1,145 @@
+package mock
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockAccessTokenRepository struct {
+	AccessTokens []*domain.PersonalAccessToken
+}
+
+func (m *MockAccessTokenRepository) CreateAccessToken(token *domain.PersonalAccessToken) error {
+	token.ID = uint(len(m.AccessTokens) + 1)
+	token.CreatedAt = time.Now()
+	m.AccessTokens = append(m.AccessTokens, token)
+	return nil
+}
+
+func (m *MockAccessTokenRepository) GetAccessToken(id uint) (*domain.PersonalAccessToken, error) {
+	for _, token := range m.AccessTokens {
+		if token.ID == id {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("personal access token not found")
+}
+
+func (m *MockAccessTokenRepository) GetAccessTokenByHash(tokenHash string) (*domain.PersonalAccessToken, error) {
+	for _, token := range m.AccessTokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("personal access token not found")
+}
+
+func (m *MockAccessTokenRepository) ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error) {
+	tokens := []*domain.PersonalAccessToken{}
+	for _, token := range m.AccessTokens {
+		if token.UserID == userID && token.RevokedAt.IsZero() {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].ID > tokens[j].ID
+	})
+	return tokens, nil
+}
+
+func (m *MockAccessTokenRepository) RevokeAccessToken(id uint, revokedAt time.Time) error {
+	for _, token := range m.AccessTokens {
+		if token.ID == id {
+			token.RevokedAt = revokedAt
+			return nil
+		}
+	}
+	return errors.New("personal access token not found")
+}
+
+func (m *MockAccessTokenRepository) RevokeAccessTokens(userID uint, revokedAt time.Time) error {
+	for _, token := range m.AccessTokens {
+		if token.UserID == userID && token.RevokedAt.IsZero() {
+			token.RevokedAt = revokedAt
+		}
+	}
+	return nil
+}
+
+func (m *MockAccessTokenRepository) SetAccessTokenLastUsed(id uint, lastUsedAt time.Time) error {
+	for _, token := range m.AccessTokens {
+		if token.ID == id {
+			token.LastUsedAt = lastUsedAt
+			return nil
+		}
+	}
+	return errors.New("personal access token not found")
+}
+
+// MockAccessTokenService accepts the tokens in Tokens as they are, acting as
+// the user in Users with the same ID as the token's user.
+type MockAccessTokenService struct {
+	AccessTokens []*domain.PersonalAccessToken
+	Tokens       map[string]*domain.PersonalAccessToken
+	Users        []*domain.User
+}
+
+func (m *MockAccessTokenService) CreateAccessToken(userID uint, name string, scopes []domain.Scope, expiresAt time.Time) (*domain.PersonalAccessToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+
+	token := &domain.PersonalAccessToken{
+		ID:        uint(len(m.AccessTokens) + 1),
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	m.AccessTokens = append(m.AccessTokens, token)
+	return token, "mcpat_mock", nil
+}
+
+func (m *MockAccessTokenService) ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error) {
+	tokens := []*domain.PersonalAccessToken{}
+	for _, token := range m.AccessTokens {
+		if token.UserID == userID && token.RevokedAt.IsZero() {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *MockAccessTokenService) RevokeAccessToken(userID uint, tokenID uint) error {
+	for _, token := range m.AccessTokens {
+		if token.ID == tokenID && token.UserID == userID && token.RevokedAt.IsZero() {
+			token.RevokedAt = time.Now()
+			return nil
+		}
+	}
+	return domain.ErrAccessTokenNotFound
+}
+
+func (m *MockAccessTokenService) AuthenticateAccessToken(token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	accessToken, ok := m.Tokens[token]
+	if !ok || !accessToken.Active(time.Now()) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+	for _, user := range m.Users {
+		if user.ID == accessToken.UserID {
+			return user, accessToken, nil
+		}
+	}
+	return nil, nil, domain.ErrInvalidAccessToken
+}
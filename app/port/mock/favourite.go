@@ -0,0 +1,83 @@
+package mock
+
+import (
+	"errors"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockFavouritesRepository struct {
+	Favourites []*domain.Favourite
+	Movies     []*domain.Movie
+}
+
+func (m *MockFavouritesRepository) AddFavourite(favourite *domain.Favourite) error {
+	for _, v := range m.Favourites {
+		if v.UserID == favourite.UserID && v.MovieID == favourite.MovieID {
+			return nil
+		}
+	}
+	m.Favourites = append(m.Favourites, favourite)
+	return nil
+}
+
+func (m *MockFavouritesRepository) RemoveFavourite(favourite *domain.Favourite) error {
+	for i, v := range m.Favourites {
+		if v.UserID == favourite.UserID && v.MovieID == favourite.MovieID {
+			m.Favourites = append(m.Favourites[:i], m.Favourites[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("favourite not found")
+}
+
+func (m *MockFavouritesRepository) ListFavourites(userID uint, pagination port.Pagination) ([]*domain.Movie, int64, error) {
+	movies := favouriteMovies(m.Favourites, m.Movies, userID)
+	return paginate(movies, pagination), int64(len(movies)), nil
+}
+
+type MockFavouritesService struct {
+	Favourites []*domain.Favourite
+	Movies     []*domain.Movie
+}
+
+func (m *MockFavouritesService) AddFavourite(favourite *domain.Favourite) error {
+	for _, v := range m.Favourites {
+		if v.UserID == favourite.UserID && v.MovieID == favourite.MovieID {
+			return nil
+		}
+	}
+	m.Favourites = append(m.Favourites, favourite)
+	return nil
+}
+
+func (m *MockFavouritesService) RemoveFavourite(favourite *domain.Favourite) error {
+	for i, v := range m.Favourites {
+		if v.UserID == favourite.UserID && v.MovieID == favourite.MovieID {
+			m.Favourites = append(m.Favourites[:i], m.Favourites[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("favourite not found")
+}
+
+func (m *MockFavouritesService) ListFavourites(userID uint, pagination port.Pagination) ([]*domain.Movie, int64, error) {
+	movies := favouriteMovies(m.Favourites, m.Movies, userID)
+	return paginate(movies, pagination), int64(len(movies)), nil
+}
+
+func favouriteMovies(favourites []*domain.Favourite, movies []*domain.Movie, userID uint) []*domain.Movie {
+	result := []*domain.Movie{}
+	for _, favourite := range favourites {
+		if favourite.UserID != userID {
+			continue
+		}
+		for _, movie := range movies {
+			if movie.ID == favourite.MovieID {
+				result = append(result, movie)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package mock
+
+import "strings"
+
+// levenshtein returns the number of single rune insertions, deletions and
+// substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
+
+// maxTypos is the number of edits tolerated when fuzzy matching a query,
+// roughly one for every three characters.
+func maxTypos(query string) int {
+	return len([]rune(query)) / 3
+}
+
+// fuzzyContains reports whether the query appears in the value, ignoring case
+// and tolerating a few typos. The query is compared against every run of
+// consecutive words in the value with the same number of words.
+func fuzzyContains(value, query string) bool {
+	value, query = strings.ToLower(value), strings.ToLower(query)
+	if strings.Contains(value, query) {
+		return true
+	}
+
+	words := strings.Fields(value)
+	size := len(strings.Fields(query))
+	for start := 0; start+size <= len(words); start++ {
+		window := strings.Join(words[start:start+size], " ")
+		if levenshtein(window, query) <= maxTypos(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixDistance returns the fewest edits needed for the value, or any of its
+// words, to start with the prefix, ignoring case.
+func prefixDistance(value, prefix string) int {
+	value, prefix = strings.ToLower(value), strings.ToLower(prefix)
+	length := len([]rune(prefix))
+
+	distance := length
+	words := strings.Fields(value)
+	for start := range words {
+		candidate := []rune(strings.Join(words[start:], " "))
+		if len(candidate) > length {
+			candidate = candidate[:length]
+		}
+		distance = min(distance, levenshtein(string(candidate), prefix))
+	}
+	return distance
+}
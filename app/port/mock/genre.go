@@ -0,0 +1,104 @@
+package mock
+
+import (
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockGenreRepository struct {
+	Genres []*domain.Genre
+	Movies []*domain.Movie
+}
+
+func (m *MockGenreRepository) CreateGenre(genre *domain.Genre) error {
+	for _, v := range m.Genres {
+		if strings.EqualFold(v.Name, genre.Name) {
+			return domain.ErrGenreExists
+		}
+	}
+	genre.ID = uint(len(m.Genres) + 1)
+	m.Genres = append(m.Genres, genre)
+	return nil
+}
+
+func (m *MockGenreRepository) ListGenres() ([]*domain.Genre, error) {
+	return countGenreMovies(m.Genres, m.Movies), nil
+}
+
+func (m *MockGenreRepository) GetGenresByName(names []string) ([]*domain.Genre, error) {
+	genres := []*domain.Genre{}
+	for _, genre := range m.Genres {
+		for _, name := range names {
+			if strings.EqualFold(genre.Name, name) {
+				genres = append(genres, genre)
+				break
+			}
+		}
+	}
+	return genres, nil
+}
+
+type MockGenreService struct {
+	Genres []*domain.Genre
+	Movies []*domain.Movie
+}
+
+func (m *MockGenreService) CreateGenre(genre *domain.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" {
+		return domain.ErrBlankGenreName
+	}
+	for _, v := range m.Genres {
+		if strings.EqualFold(v.Name, genre.Name) {
+			return domain.ErrGenreExists
+		}
+	}
+	genre.ID = uint(len(m.Genres) + 1)
+	m.Genres = append(m.Genres, genre)
+	return nil
+}
+
+func (m *MockGenreService) ListGenres() ([]*domain.Genre, error) {
+	return countGenreMovies(m.Genres, m.Movies), nil
+}
+
+func (m *MockGenreService) ResolveGenres(names []string) ([]domain.Genre, error) {
+	genres := []domain.Genre{}
+	unknownNames := []string{}
+	for _, name := range names {
+		found := false
+		for _, genre := range m.Genres {
+			if strings.EqualFold(genre.Name, name) {
+				genres = append(genres, *genre)
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknownNames = append(unknownNames, name)
+		}
+	}
+
+	if len(unknownNames) > 0 {
+		return nil, &domain.UnknownGenresError{Names: unknownNames}
+	}
+	return genres, nil
+}
+
+func countGenreMovies(genres []*domain.Genre, movies []*domain.Movie) []*domain.Genre {
+	counted := make([]*domain.Genre, len(genres))
+	for i, genre := range genres {
+		count := *genre
+		count.MovieCount = 0
+		for _, movie := range movies {
+			for _, movieGenre := range movie.Genres {
+				if movieGenre.ID == genre.ID {
+					count.MovieCount++
+				}
+			}
+		}
+		counted[i] = &count
+	}
+	return counted
+}
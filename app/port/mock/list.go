@@ -0,0 +1,219 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+// MockMovieListRepository leaves out the entries of DeletedMovies when getting
+// a list, as for soft-deleted movies.
+type MockMovieListRepository struct {
+	Lists         []*domain.MovieList
+	DeletedMovies []uint
+}
+
+func (m *MockMovieListRepository) CreateList(list *domain.MovieList) error {
+	m.Lists = createList(m.Lists, list)
+	return nil
+}
+
+func (m *MockMovieListRepository) GetList(id uint) (*domain.MovieList, error) {
+	list, err := findList(m.Lists, id)
+	if err != nil {
+		return nil, err
+	}
+	return withoutMovies(list, m.DeletedMovies), nil
+}
+
+func (m *MockMovieListRepository) GetListByShareToken(token string) (*domain.MovieList, error) {
+	list, err := findListByShareToken(m.Lists, token)
+	if err != nil {
+		return nil, err
+	}
+	return withoutMovies(list, m.DeletedMovies), nil
+}
+
+func (m *MockMovieListRepository) UpdateList(list *domain.MovieList) error {
+	return updateList(m.Lists, list)
+}
+
+func (m *MockMovieListRepository) UpdateShareToken(list *domain.MovieList) error {
+	existing, err := findList(m.Lists, list.ID)
+	if err != nil {
+		return err
+	}
+	existing.ShareToken = list.ShareToken
+	return nil
+}
+
+func (m *MockMovieListRepository) DeleteList(list *domain.MovieList) error {
+	lists, err := deleteList(m.Lists, list)
+	if err != nil {
+		return err
+	}
+	m.Lists = lists
+	return nil
+}
+
+func (m *MockMovieListRepository) ListLists(filter port.MovieListFilter, pagination port.Pagination) ([]*domain.MovieList, int64, error) {
+	lists := filterLists(m.Lists, filter)
+	return paginate(lists, pagination), int64(len(lists)), nil
+}
+
+type MockMovieListService struct {
+	Lists []*domain.MovieList
+}
+
+func (m *MockMovieListService) CreateList(list *domain.MovieList) error {
+	if err := prepareList(list); err != nil {
+		return err
+	}
+	m.Lists = createList(m.Lists, list)
+	return nil
+}
+
+func (m *MockMovieListService) GetList(id uint) (*domain.MovieList, error) {
+	return findList(m.Lists, id)
+}
+
+func (m *MockMovieListService) GetSharedList(token string) (*domain.MovieList, error) {
+	list, err := findListByShareToken(m.Lists, token)
+	if err != nil {
+		return nil, err
+	}
+	if list.Visibility == domain.ListVisibilityPrivate {
+		return nil, errors.New("list not found")
+	}
+	return list, nil
+}
+
+func (m *MockMovieListService) UpdateList(list *domain.MovieList) error {
+	if err := prepareList(list); err != nil {
+		return err
+	}
+	return updateList(m.Lists, list)
+}
+
+func (m *MockMovieListService) DeleteList(list *domain.MovieList) error {
+	lists, err := deleteList(m.Lists, list)
+	if err != nil {
+		return err
+	}
+	m.Lists = lists
+	return nil
+}
+
+func (m *MockMovieListService) ListLists(filter port.MovieListFilter, pagination port.Pagination) ([]*domain.MovieList, int64, error) {
+	lists := filterLists(m.Lists, filter)
+	return paginate(lists, pagination), int64(len(lists)), nil
+}
+
+func (m *MockMovieListService) RegenerateShareToken(list *domain.MovieList) error {
+	list.ShareToken = list.ShareToken + "-regenerated"
+	return updateList(m.Lists, list)
+}
+
+// prepareList mirrors the defaults and validation of the list service.
+func prepareList(list *domain.MovieList) error {
+	if list.Visibility == "" {
+		list.Visibility = domain.ListVisibilityPrivate
+	}
+	seen := map[uint]bool{}
+	for i := range list.Entries {
+		if seen[list.Entries[i].MovieID] {
+			return domain.ErrDuplicateListEntry
+		}
+		seen[list.Entries[i].MovieID] = true
+		list.Entries[i].Position = i + 1
+	}
+	return nil
+}
+
+func createList(lists []*domain.MovieList, list *domain.MovieList) []*domain.MovieList {
+	list.ID = uint(len(lists) + 1)
+	if list.ShareToken == "" {
+		list.ShareToken = fmt.Sprintf("token-%d", list.ID)
+	}
+	return append(lists, list)
+}
+
+func findList(lists []*domain.MovieList, id uint) (*domain.MovieList, error) {
+	for _, list := range lists {
+		if list.ID == id {
+			return list, nil
+		}
+	}
+	return nil, errors.New("list not found")
+}
+
+func findListByShareToken(lists []*domain.MovieList, token string) (*domain.MovieList, error) {
+	for _, list := range lists {
+		if list.ShareToken == token {
+			return list, nil
+		}
+	}
+	return nil, errors.New("list not found")
+}
+
+// withoutMovies returns a copy of a list without the entries of the given
+// movies, or the list itself when there are none.
+func withoutMovies(list *domain.MovieList, movieIDs []uint) *domain.MovieList {
+	if len(movieIDs) == 0 {
+		return list
+	}
+
+	result := *list
+	result.Entries = []domain.MovieListEntry{}
+	for _, entry := range list.Entries {
+		if !slices.Contains(movieIDs, entry.MovieID) {
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+	return &result
+}
+
+func updateList(lists []*domain.MovieList, list *domain.MovieList) error {
+	for i, v := range lists {
+		if v.ID == list.ID {
+			lists[i] = list
+			return nil
+		}
+	}
+	return errors.New("list not found")
+}
+
+func deleteList(lists []*domain.MovieList, list *domain.MovieList) ([]*domain.MovieList, error) {
+	for i, v := range lists {
+		if v.ID == list.ID {
+			return append(lists[:i], lists[i+1:]...), nil
+		}
+	}
+	return nil, errors.New("list not found")
+}
+
+// filterLists returns the lists matching the filter, most recently updated
+// first, with their entries counted but not included.
+func filterLists(lists []*domain.MovieList, filter port.MovieListFilter) []*domain.MovieList {
+	result := []*domain.MovieList{}
+	for _, list := range lists {
+		if list.UserID != filter.VisibleTo && list.Visibility != domain.ListVisibilityPublic {
+			continue
+		}
+		if filter.OwnerID != nil && list.UserID != *filter.OwnerID {
+			continue
+		}
+		summary := *list
+		summary.EntryCount = int64(len(list.Entries))
+		summary.Entries = nil
+		result = append(result, &summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+	return result
+}
@@ -0,0 +1,121 @@
+package mock
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockLoginThrottleRepository struct {
+	Throttles map[string]*domain.LoginThrottle
+	Failures  []*domain.LoginFailure
+}
+
+func (m *MockLoginThrottleRepository) GetLoginThrottle(key string) (*domain.LoginThrottle, error) {
+	throttle, ok := m.Throttles[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *throttle
+	return &copied, nil
+}
+
+func (m *MockLoginThrottleRepository) RecordLoginFailure(key string, at time.Time, resetBefore time.Time) (*domain.LoginThrottle, error) {
+	if m.Throttles == nil {
+		m.Throttles = map[string]*domain.LoginThrottle{}
+	}
+
+	throttle, ok := m.Throttles[key]
+	if !ok || throttle.LastFailureAt.Before(resetBefore) {
+		throttle = &domain.LoginThrottle{Key: key}
+		m.Throttles[key] = throttle
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = at
+
+	copied := *throttle
+	return &copied, nil
+}
+
+func (m *MockLoginThrottleRepository) ResetLoginThrottle(key string) error {
+	delete(m.Throttles, key)
+	return nil
+}
+
+func (m *MockLoginThrottleRepository) CreateLoginFailure(failure *domain.LoginFailure) error {
+	failure.ID = uint(len(m.Failures) + 1)
+	m.Failures = append(m.Failures, failure)
+	return nil
+}
+
+func (m *MockLoginThrottleRepository) ListLoginFailures(filter port.LoginFailureFilter, pagination port.Pagination) ([]*domain.LoginFailure, int64, error) {
+	failures := []*domain.LoginFailure{}
+	for _, failure := range m.Failures {
+		if (filter.Email == "" || failure.Email == filter.Email) && (filter.IPAddress == "" || failure.IPAddress == filter.IPAddress) {
+			failures = append(failures, failure)
+		}
+	}
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].ID > failures[j].ID
+	})
+	return paginate(failures, pagination), int64(len(failures)), nil
+}
+
+// MockLoginThrottleService refuses logins for the emails in RetryAt until the
+// time given there, and keeps the failures it is told about. When
+// LockoutFailures is set, an email with that many failures since its last
+// success is added to RetryAt for 15 minutes.
+type MockLoginThrottleService struct {
+	RetryAt         map[string]time.Time
+	Failures        []*domain.LoginFailure
+	LockoutFailures int
+	counts          map[string]int
+}
+
+func (m *MockLoginThrottleService) CheckLogin(email string, ipAddress string, userAgent string) (time.Time, error) {
+	if retryAt, ok := m.RetryAt[email]; ok && retryAt.After(time.Now()) {
+		return retryAt, domain.ErrTooManyLoginAttempts
+	}
+	return time.Time{}, nil
+}
+
+func (m *MockLoginThrottleService) RecordFailure(email string, ipAddress string, userAgent string, reason domain.LoginFailureReason) error {
+	m.Failures = append(m.Failures, &domain.LoginFailure{
+		ID:        uint(len(m.Failures) + 1),
+		Email:     email,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	})
+
+	if m.counts == nil {
+		m.counts = map[string]int{}
+	}
+	m.counts[email]++
+	if m.LockoutFailures > 0 && m.counts[email] >= m.LockoutFailures {
+		if m.RetryAt == nil {
+			m.RetryAt = map[string]time.Time{}
+		}
+		m.RetryAt[email] = time.Now().Add(15 * time.Minute)
+	}
+	return nil
+}
+
+func (m *MockLoginThrottleService) RecordSuccess(email string) error {
+	delete(m.RetryAt, email)
+	delete(m.counts, email)
+	return nil
+}
+
+func (m *MockLoginThrottleService) ListLoginFailures(filter port.LoginFailureFilter, pagination port.Pagination) ([]*domain.LoginFailure, int64, error) {
+	failures := []*domain.LoginFailure{}
+	for _, failure := range m.Failures {
+		if (filter.Email == "" || failure.Email == filter.Email) && (filter.IPAddress == "" || failure.IPAddress == filter.IPAddress) {
+			failures = append(failures, failure)
+		}
+	}
+	return paginate(failures, pagination), int64(len(failures)), nil
+}
@@ -0,0 +1,13 @@
+package mock
+
+import "github.com/Acova/movie-collection/app/domain"
+
+// MockMailer keeps the mails it is asked to send.
+type MockMailer struct {
+	Sent []*domain.Mail
+}
+
+func (m *MockMailer) Send(mail *domain.Mail) error {
+	m.Sent = append(m.Sent, mail)
+	return nil
+}
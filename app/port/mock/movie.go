@@ -2,8 +2,11 @@ package mock
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 )
 
 type MockMovieRepository struct {
@@ -15,8 +18,18 @@ func (m *MockMovieRepository) CreateMovie(movie *domain.Movie) error {
 	return nil
 }
 
-func (m *MockMovieRepository) ListMovies(filters map[string]string) ([]*domain.Movie, error) {
-	return m.Movies, nil
+func (m *MockMovieRepository) ListMovies(filter port.MovieFilter, pagination port.Pagination, sort port.Sort) ([]*domain.Movie, int64, error) {
+	movies := sortMovies(filterMovies(m.Movies, filter), sort)
+	return paginate(movies, pagination), int64(len(movies)), nil
+}
+
+func (m *MockMovieRepository) SearchMovies(query string, pagination port.Pagination) ([]*domain.MovieSearchResult, int64, error) {
+	results := searchMovies(m.Movies, query)
+	return paginate(results, pagination), int64(len(results)), nil
+}
+
+func (m *MockMovieRepository) AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error) {
+	return autocompleteMovies(m.Movies, prefix, limit), nil
 }
 
 func (m *MockMovieRepository) GetMovie(id uint) (*domain.Movie, error) {
@@ -57,8 +70,18 @@ func (m *MockMovieService) CreateMovie(movie *domain.Movie) error {
 	return nil
 }
 
-func (m *MockMovieService) ListMovies(filters map[string]string) ([]*domain.Movie, error) {
-	return m.Movies, nil
+func (m *MockMovieService) ListMovies(filter port.MovieFilter, pagination port.Pagination, sort port.Sort) ([]*domain.Movie, int64, error) {
+	movies := sortMovies(filterMovies(m.Movies, filter), sort)
+	return paginate(movies, pagination), int64(len(movies)), nil
+}
+
+func (m *MockMovieService) SearchMovies(query string, pagination port.Pagination) ([]*domain.MovieSearchResult, int64, error) {
+	results := searchMovies(m.Movies, query)
+	return paginate(results, pagination), int64(len(results)), nil
+}
+
+func (m *MockMovieService) AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error) {
+	return autocompleteMovies(m.Movies, prefix, limit), nil
 }
 
 func (m *MockMovieService) GetMovie(id uint) (*domain.Movie, error) {
@@ -89,3 +112,157 @@ func (m *MockMovieService) DeleteMovie(movie *domain.Movie) error {
 	}
 	return errors.New("movie not found")
 }
+
+func sortMovies(movies []*domain.Movie, movieSort port.Sort) []*domain.Movie {
+	sorted := make([]*domain.Movie, len(movies))
+	copy(sorted, movies)
+
+	less := func(a, b *domain.Movie) bool {
+		switch movieSort.Field {
+		case "title":
+			return a.Title < b.Title
+		case "release_year":
+			return a.ReleaseYear < b.ReleaseYear
+		case "rating":
+			return a.Rating < b.Rating
+		case "duration":
+			return a.Duration < b.Duration
+		case "created_at":
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return false
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if movieSort.Direction == port.SortDescending {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+
+	return sorted
+}
+
+func filterMovies(movies []*domain.Movie, filter port.MovieFilter) []*domain.Movie {
+	contains := func(value, substring string) bool {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(substring))
+	}
+
+	filtered := []*domain.Movie{}
+	for _, movie := range movies {
+		title, director := contains, contains
+		if filter.Fuzzy {
+			title, director = fuzzyContains, fuzzyContains
+		}
+
+		switch {
+		case filter.Title != "" && !title(movie.Title, filter.Title),
+			filter.ExactTitle != "" && !strings.EqualFold(movie.Title, filter.ExactTitle),
+			filter.Director != "" && !director(movie.Director, filter.Director),
+			filter.Genre != "" && !hasGenre(movie, filter.Genre),
+			filter.Cast != "" && !contains(movie.Cast, filter.Cast),
+			filter.ReleaseYearFrom != nil && movie.ReleaseYear < *filter.ReleaseYearFrom,
+			filter.ReleaseYearTo != nil && movie.ReleaseYear > *filter.ReleaseYearTo,
+			filter.RatingMin != nil && movie.Rating < *filter.RatingMin,
+			filter.RatingMax != nil && movie.Rating > *filter.RatingMax,
+			filter.DurationMin != nil && movie.Duration < *filter.DurationMin,
+			filter.DurationMax != nil && movie.Duration > *filter.DurationMax,
+			filter.UserID != nil && movie.UserID != *filter.UserID,
+			filter.CreatedAfter != nil && !movie.CreatedAt.After(*filter.CreatedAfter):
+			continue
+		}
+		filtered = append(filtered, movie)
+	}
+	return filtered
+}
+
+// searchMovies is a naive stand-in for full-text search: a movie matches when
+// any of the query words appears in its title, director, cast or synopsis,
+// and it ranks higher the more words it matches.
+func searchMovies(movies []*domain.Movie, query string) []*domain.MovieSearchResult {
+	words := strings.Fields(strings.ToLower(query))
+
+	results := []*domain.MovieSearchResult{}
+	for _, movie := range movies {
+		text := strings.ToLower(strings.Join([]string{movie.Title, movie.Director, movie.Cast, movie.Synopsis}, " "))
+
+		rank := 0.0
+		for _, word := range words {
+			if strings.Contains(text, strings.TrimSuffix(word, "s")) {
+				rank++
+			}
+		}
+		if rank == 0 {
+			continue
+		}
+
+		results = append(results, &domain.MovieSearchResult{
+			Movie:             movie,
+			Rank:              rank,
+			TitleHighlight:    highlight(movie.Title, words),
+			SynopsisHighlight: highlight(movie.Synopsis, words),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+
+	return results
+}
+
+func highlight(text string, words []string) string {
+	highlighted := strings.Fields(text)
+	for i, field := range highlighted {
+		for _, word := range words {
+			if strings.Contains(strings.ToLower(field), strings.TrimSuffix(word, "s")) {
+				highlighted[i] = "<b>" + field + "</b>"
+				break
+			}
+		}
+	}
+	return strings.Join(highlighted, " ")
+}
+
+// autocompleteMovies returns the movies whose title starts with the prefix,
+// followed by the ones with a word starting with the prefix or, failing that,
+// with a misspelling of it, closest matches first.
+func autocompleteMovies(movies []*domain.Movie, prefix string, limit int) []*domain.Movie {
+	type suggestion struct {
+		movie *domain.Movie
+		score int
+	}
+
+	suggestions := []suggestion{}
+	for _, movie := range movies {
+		if strings.HasPrefix(strings.ToLower(movie.Title), strings.ToLower(prefix)) {
+			suggestions = append(suggestions, suggestion{movie: movie, score: 0})
+			continue
+		}
+		if distance := prefixDistance(movie.Title, prefix); distance <= maxTypos(prefix) {
+			suggestions = append(suggestions, suggestion{movie: movie, score: distance + 1})
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].score < suggestions[j].score
+	})
+
+	result := []*domain.Movie{}
+	for _, suggestion := range suggestions {
+		if limit > 0 && len(result) == limit {
+			break
+		}
+		result = append(result, suggestion.movie)
+	}
+	return result
+}
+
+func hasGenre(movie *domain.Movie, name string) bool {
+	for _, genre := range movie.Genres {
+		if strings.EqualFold(genre.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package mock
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockOIDCRepository struct {
+	LoginStates []*domain.OIDCLoginState
+	Identities  []*domain.OIDCIdentity
+}
+
+func (m *MockOIDCRepository) CreateLoginState(state *domain.OIDCLoginState) error {
+	state.ID = uint(len(m.LoginStates) + 1)
+	m.LoginStates = append(m.LoginStates, state)
+	return nil
+}
+
+func (m *MockOIDCRepository) GetLoginState(stateHash string) (*domain.OIDCLoginState, error) {
+	for _, state := range m.LoginStates {
+		if state.StateHash == stateHash {
+			copied := *state
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrInvalidOIDCState
+}
+
+func (m *MockOIDCRepository) UseLoginState(id uint, usedAt time.Time) error {
+	for _, state := range m.LoginStates {
+		if state.ID == id {
+			if !state.UsedAt.IsZero() {
+				return domain.ErrInvalidOIDCState
+			}
+			state.UsedAt = usedAt
+			return nil
+		}
+	}
+	return domain.ErrInvalidOIDCState
+}
+
+func (m *MockOIDCRepository) GetIdentity(provider string, subject string) (*domain.OIDCIdentity, error) {
+	for _, identity := range m.Identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockOIDCRepository) CreateIdentity(identity *domain.OIDCIdentity) error {
+	identity.ID = uint(len(m.Identities) + 1)
+	m.Identities = append(m.Identities, identity)
+	return nil
+}
+
+// MockOIDCProvider sends users to an example provider, and gives the Claims of
+// Code for the verifier and nonce of the last login started.
+type MockOIDCProvider struct {
+	Code          string
+	Claims        *domain.OIDCClaims
+	codeChallenge string
+	nonce         string
+}
+
+func (m *MockOIDCProvider) AuthorizationURL(state string, nonce string, codeChallenge string) (string, error) {
+	m.codeChallenge = codeChallenge
+	m.nonce = nonce
+	return "https://provider.example.com/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (m *MockOIDCProvider) Exchange(code string, codeVerifier string, nonce string) (*domain.OIDCClaims, error) {
+	if code != m.Code || nonce != m.nonce || codeVerifier == "" || m.codeChallenge == "" {
+		return nil, domain.ErrOIDCLoginFailed
+	}
+	return m.Claims, nil
+}
+
+// MockOIDCService logs in the user in Users with the ID given for a code in
+// Codes, whatever the state.
+type MockOIDCService struct {
+	Providers []string
+	Codes     map[string]uint
+	Users     []*domain.User
+}
+
+func (m *MockOIDCService) ProviderNames() []string {
+	names := append([]string{}, m.Providers...)
+	sort.Strings(names)
+	return names
+}
+
+func (m *MockOIDCService) hasProvider(provider string) bool {
+	for _, name := range m.Providers {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MockOIDCService) StartLogin(provider string) (string, error) {
+	if !m.hasProvider(provider) {
+		return "", domain.ErrOIDCProviderNotFound
+	}
+	return fmt.Sprintf("https://%s.example.com/authorize", provider), nil
+}
+
+func (m *MockOIDCService) CompleteLogin(provider string, state string, code string) (*domain.User, error) {
+	if !m.hasProvider(provider) {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+	if state == "" {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	userID, ok := m.Codes[code]
+	if !ok {
+		return nil, domain.ErrOIDCLoginFailed
+	}
+	user, err := findUser(m.Users, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+	return user, nil
+}
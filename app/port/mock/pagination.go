@@ -0,0 +1,21 @@
+package mock
+
+import "github.com/Acova/movie-collection/app/port"
+
+func paginate[T any](items []T, pagination port.Pagination) []T {
+	if pagination.PageSize <= 0 {
+		return items
+	}
+
+	start := pagination.Offset()
+	if start >= len(items) {
+		return []T{}
+	}
+
+	end := start + pagination.PageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[start:end]
+}
@@ -0,0 +1,82 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockPasswordResetRepository struct {
+	Tokens []*domain.PasswordResetToken
+}
+
+func (m *MockPasswordResetRepository) CreateResetToken(token *domain.PasswordResetToken) error {
+	token.ID = uint(len(m.Tokens) + 1)
+	token.CreatedAt = time.Now()
+	m.Tokens = append(m.Tokens, token)
+	return nil
+}
+
+func (m *MockPasswordResetRepository) GetResetToken(tokenHash string, at time.Time) (*domain.PasswordResetToken, error) {
+	for _, token := range m.Tokens {
+		if token.TokenHash == tokenHash && token.UsedAt.IsZero() && token.ExpiresAt.After(at) {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrInvalidResetToken
+}
+
+func (m *MockPasswordResetRepository) UseResetToken(tokenHash string, usedAt time.Time) (*domain.PasswordResetToken, error) {
+	for _, token := range m.Tokens {
+		if token.TokenHash == tokenHash && token.UsedAt.IsZero() && token.ExpiresAt.After(usedAt) {
+			token.UsedAt = usedAt
+			return token, nil
+		}
+	}
+	return nil, domain.ErrInvalidResetToken
+}
+
+func (m *MockPasswordResetRepository) InvalidateResetTokens(userID uint, at time.Time) error {
+	for _, token := range m.Tokens {
+		if token.UserID == userID && token.UsedAt.IsZero() {
+			token.UsedAt = at
+		}
+	}
+	return nil
+}
+
+// MockPasswordResetService accepts the tokens in Tokens, mapped to the user
+// they were issued to, and records the new passwords in Passwords. New
+// passwords are checked against PasswordPolicy when it is set.
+type MockPasswordResetService struct {
+	Requests       []string
+	Tokens         map[string]uint
+	Passwords      map[uint]string
+	PasswordPolicy port.PasswordPolicyService
+}
+
+func (m *MockPasswordResetService) RequestPasswordReset(email string) error {
+	m.Requests = append(m.Requests, email)
+	return nil
+}
+
+func (m *MockPasswordResetService) ResetPassword(token string, newPassword string) error {
+	userID, ok := m.Tokens[token]
+	if !ok {
+		return domain.ErrInvalidResetToken
+	}
+	if m.PasswordPolicy != nil {
+		if err := m.PasswordPolicy.CheckPassword(newPassword, &domain.User{ID: userID}); err != nil {
+			return err
+		}
+	}
+	delete(m.Tokens, token)
+
+	if m.Passwords == nil {
+		m.Passwords = map[uint]string{}
+	}
+	m.Passwords[userID] = newPassword
+	return nil
+}
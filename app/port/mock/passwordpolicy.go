@@ -0,0 +1,32 @@
+package mock
+
+import "github.com/Acova/movie-collection/app/domain"
+
+// MockBreachedPasswordRepository holds the breached hash suffixes of each hash
+// prefix.
+type MockBreachedPasswordRepository struct {
+	Suffixes map[string]map[string]int
+	Prefixes []string
+}
+
+func (m *MockBreachedPasswordRepository) GetBreachedHashSuffixes(prefix string) (map[string]int, error) {
+	m.Prefixes = append(m.Prefixes, prefix)
+	suffixes, ok := m.Suffixes[prefix]
+	if !ok {
+		return map[string]int{}, nil
+	}
+	return suffixes, nil
+}
+
+// MockPasswordPolicyService rejects the passwords in Rejections, for the
+// reasons they are mapped to, and accepts any other.
+type MockPasswordPolicyService struct {
+	Rejections map[string][]domain.PasswordRejection
+}
+
+func (m *MockPasswordPolicyService) CheckPassword(password string, user *domain.User) error {
+	if rejections, ok := m.Rejections[password]; ok {
+		return &domain.PasswordPolicyError{Rejections: rejections}
+	}
+	return nil
+}
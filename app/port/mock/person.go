@@ -0,0 +1,239 @@
+package mock
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockPersonRepository struct {
+	People  []*domain.Person
+	Credits []*domain.MovieCredit
+	Movies  []*domain.Movie
+}
+
+func (m *MockPersonRepository) CreatePerson(person *domain.Person) error {
+	person.ID = uint(len(m.People) + 1)
+	m.People = append(m.People, person)
+	return nil
+}
+
+func (m *MockPersonRepository) ListPeople(name string, pagination port.Pagination) ([]*domain.Person, int64, error) {
+	people := filterPeople(m.People, name)
+	return paginate(people, pagination), int64(len(people)), nil
+}
+
+func (m *MockPersonRepository) GetPerson(id uint) (*domain.Person, error) {
+	return findPerson(m.People, id)
+}
+
+func (m *MockPersonRepository) UpdatePerson(person *domain.Person) error {
+	return updatePerson(m.People, person)
+}
+
+func (m *MockPersonRepository) DeletePerson(person *domain.Person) error {
+	people, credits, err := deletePerson(m.People, m.Credits, m.Movies, person)
+	if err != nil {
+		return err
+	}
+	m.People, m.Credits = people, credits
+	return nil
+}
+
+func (m *MockPersonRepository) CreateCredit(credit *domain.MovieCredit) error {
+	credit.ID = uint(len(m.Credits) + 1)
+	m.Credits = append(m.Credits, credit)
+	return nil
+}
+
+func (m *MockPersonRepository) GetCredit(id uint) (*domain.MovieCredit, error) {
+	return findCredit(m.Credits, id)
+}
+
+func (m *MockPersonRepository) DeleteCredit(credit *domain.MovieCredit) error {
+	credits, err := deleteCredit(m.Credits, credit)
+	if err != nil {
+		return err
+	}
+	m.Credits = credits
+	return nil
+}
+
+func (m *MockPersonRepository) ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error) {
+	return movieCredits(m.Credits, m.People, movieID), nil
+}
+
+func (m *MockPersonRepository) ListPersonCredits(personID uint) ([]*domain.MovieCredit, error) {
+	return personCredits(m.Credits, m.Movies, personID), nil
+}
+
+type MockPersonService struct {
+	People  []*domain.Person
+	Credits []*domain.MovieCredit
+	Movies  []*domain.Movie
+}
+
+func (m *MockPersonService) CreatePerson(person *domain.Person) error {
+	person.ID = uint(len(m.People) + 1)
+	m.People = append(m.People, person)
+	return nil
+}
+
+func (m *MockPersonService) ListPeople(name string, pagination port.Pagination) ([]*domain.Person, int64, error) {
+	people := filterPeople(m.People, name)
+	return paginate(people, pagination), int64(len(people)), nil
+}
+
+func (m *MockPersonService) GetPerson(id uint) (*domain.Person, error) {
+	return findPerson(m.People, id)
+}
+
+func (m *MockPersonService) UpdatePerson(person *domain.Person) error {
+	return updatePerson(m.People, person)
+}
+
+func (m *MockPersonService) DeletePerson(person *domain.Person) error {
+	people, credits, err := deletePerson(m.People, m.Credits, m.Movies, person)
+	if err != nil {
+		return err
+	}
+	m.People, m.Credits = people, credits
+	return nil
+}
+
+func (m *MockPersonService) CreateCredit(credit *domain.MovieCredit) error {
+	credit.ID = uint(len(m.Credits) + 1)
+	m.Credits = append(m.Credits, credit)
+	return nil
+}
+
+func (m *MockPersonService) GetCredit(id uint) (*domain.MovieCredit, error) {
+	return findCredit(m.Credits, id)
+}
+
+func (m *MockPersonService) DeleteCredit(credit *domain.MovieCredit) error {
+	credits, err := deleteCredit(m.Credits, credit)
+	if err != nil {
+		return err
+	}
+	m.Credits = credits
+	return nil
+}
+
+func (m *MockPersonService) ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error) {
+	return movieCredits(m.Credits, m.People, movieID), nil
+}
+
+func (m *MockPersonService) ListPersonCredits(personID uint) ([]*domain.MovieCredit, error) {
+	return personCredits(m.Credits, m.Movies, personID), nil
+}
+
+func filterPeople(people []*domain.Person, name string) []*domain.Person {
+	result := []*domain.Person{}
+	for _, person := range people {
+		if strings.Contains(strings.ToLower(person.Name), strings.ToLower(name)) {
+			result = append(result, person)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func findPerson(people []*domain.Person, id uint) (*domain.Person, error) {
+	for _, person := range people {
+		if person.ID == id {
+			return person, nil
+		}
+	}
+	return nil, errors.New("person not found")
+}
+
+func updatePerson(people []*domain.Person, person *domain.Person) error {
+	for i, v := range people {
+		if v.ID == person.ID {
+			people[i] = person
+			return nil
+		}
+	}
+	return errors.New("person not found")
+}
+
+// deletePerson refuses to delete a person credited in one of the movies, and
+// removes their credits in movies missing from it, which stand for deleted
+// movies.
+func deletePerson(people []*domain.Person, credits []*domain.MovieCredit, movies []*domain.Movie, person *domain.Person) ([]*domain.Person, []*domain.MovieCredit, error) {
+	for i, v := range people {
+		if v.ID == person.ID {
+			if len(personCredits(credits, movies, person.ID)) > 0 {
+				return nil, nil, domain.ErrPersonHasCredits
+			}
+			remaining := []*domain.MovieCredit{}
+			for _, credit := range credits {
+				if credit.PersonID != person.ID {
+					remaining = append(remaining, credit)
+				}
+			}
+			return append(people[:i], people[i+1:]...), remaining, nil
+		}
+	}
+	return nil, nil, errors.New("person not found")
+}
+
+func findCredit(credits []*domain.MovieCredit, id uint) (*domain.MovieCredit, error) {
+	for _, credit := range credits {
+		if credit.ID == id {
+			return credit, nil
+		}
+	}
+	return nil, errors.New("credit not found")
+}
+
+func deleteCredit(credits []*domain.MovieCredit, credit *domain.MovieCredit) ([]*domain.MovieCredit, error) {
+	for i, v := range credits {
+		if v.ID == credit.ID {
+			return append(credits[:i], credits[i+1:]...), nil
+		}
+	}
+	return nil, errors.New("credit not found")
+}
+
+func movieCredits(credits []*domain.MovieCredit, people []*domain.Person, movieID uint) []*domain.MovieCredit {
+	result := []*domain.MovieCredit{}
+	for _, credit := range credits {
+		if credit.MovieID != movieID {
+			continue
+		}
+		withPerson := *credit
+		withPerson.Person, _ = findPerson(people, credit.PersonID)
+		result = append(result, &withPerson)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].BillingOrder < result[j].BillingOrder
+	})
+	return result
+}
+
+func personCredits(credits []*domain.MovieCredit, movies []*domain.Movie, personID uint) []*domain.MovieCredit {
+	result := []*domain.MovieCredit{}
+	for _, credit := range credits {
+		if credit.PersonID != personID {
+			continue
+		}
+		for _, movie := range movies {
+			if movie.ID == credit.MovieID {
+				withMovie := *credit
+				withMovie.Movie = movie
+				result = append(result, &withMovie)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Movie.ReleaseYear > result[j].Movie.ReleaseYear
+	})
+	return result
+}
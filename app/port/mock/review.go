@@ -0,0 +1,173 @@
+package mock
+
+import (
+	"errors"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockReviewRepository struct {
+	Reviews []*domain.Review
+	Movies  []*domain.Movie
+}
+
+func (m *MockReviewRepository) CreateReview(review *domain.Review) error {
+	for _, v := range m.Reviews {
+		if v.UserID == review.UserID && v.MovieID == review.MovieID {
+			return errors.New("review already exists")
+		}
+	}
+	review.ID = uint(len(m.Reviews) + 1)
+	m.Reviews = append(m.Reviews, review)
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewRepository) GetReview(movieID uint, userID uint) (*domain.Review, error) {
+	return findReview(m.Reviews, movieID, userID)
+}
+
+func (m *MockReviewRepository) UpdateReview(review *domain.Review) error {
+	if err := updateReview(m.Reviews, review); err != nil {
+		return err
+	}
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewRepository) DeleteReview(review *domain.Review) error {
+	reviews, err := deleteReview(m.Reviews, review)
+	if err != nil {
+		return err
+	}
+	m.Reviews = reviews
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewRepository) ListMovieReviews(movieID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews := filterReviews(m.Reviews, m.Movies, func(review *domain.Review) bool { return review.MovieID == movieID })
+	return paginate(reviews, pagination), int64(len(reviews)), nil
+}
+
+func (m *MockReviewRepository) ListUserReviews(userID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews := filterReviews(m.Reviews, m.Movies, func(review *domain.Review) bool { return review.UserID == userID })
+	return paginate(reviews, pagination), int64(len(reviews)), nil
+}
+
+type MockReviewService struct {
+	Reviews []*domain.Review
+	Movies  []*domain.Movie
+}
+
+func (m *MockReviewService) CreateReview(review *domain.Review) error {
+	for _, v := range m.Reviews {
+		if v.UserID == review.UserID && v.MovieID == review.MovieID {
+			return errors.New("review already exists")
+		}
+	}
+	review.ID = uint(len(m.Reviews) + 1)
+	m.Reviews = append(m.Reviews, review)
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewService) GetReview(movieID uint, userID uint) (*domain.Review, error) {
+	return findReview(m.Reviews, movieID, userID)
+}
+
+func (m *MockReviewService) UpdateReview(review *domain.Review) error {
+	if err := updateReview(m.Reviews, review); err != nil {
+		return err
+	}
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewService) DeleteReview(review *domain.Review) error {
+	reviews, err := deleteReview(m.Reviews, review)
+	if err != nil {
+		return err
+	}
+	m.Reviews = reviews
+	refreshReviewStats(m.Reviews, m.Movies, review.MovieID)
+	return nil
+}
+
+func (m *MockReviewService) ListMovieReviews(movieID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews := filterReviews(m.Reviews, m.Movies, func(review *domain.Review) bool { return review.MovieID == movieID })
+	return paginate(reviews, pagination), int64(len(reviews)), nil
+}
+
+func (m *MockReviewService) ListUserReviews(userID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews := filterReviews(m.Reviews, m.Movies, func(review *domain.Review) bool { return review.UserID == userID })
+	return paginate(reviews, pagination), int64(len(reviews)), nil
+}
+
+func findReview(reviews []*domain.Review, movieID uint, userID uint) (*domain.Review, error) {
+	for _, review := range reviews {
+		if review.MovieID == movieID && review.UserID == userID {
+			return review, nil
+		}
+	}
+	return nil, errors.New("review not found")
+}
+
+func updateReview(reviews []*domain.Review, review *domain.Review) error {
+	for i, v := range reviews {
+		if v.ID == review.ID {
+			reviews[i] = review
+			return nil
+		}
+	}
+	return errors.New("review not found")
+}
+
+func deleteReview(reviews []*domain.Review, review *domain.Review) ([]*domain.Review, error) {
+	for i, v := range reviews {
+		if v.ID == review.ID {
+			return append(reviews[:i], reviews[i+1:]...), nil
+		}
+	}
+	return nil, errors.New("review not found")
+}
+
+// filterReviews returns the matching reviews newest first, with their movie
+// attached.
+func filterReviews(reviews []*domain.Review, movies []*domain.Movie, match func(review *domain.Review) bool) []*domain.Review {
+	result := []*domain.Review{}
+	for i := len(reviews) - 1; i >= 0; i-- {
+		if !match(reviews[i]) {
+			continue
+		}
+		withMovie := *reviews[i]
+		for _, movie := range movies {
+			if movie.ID == withMovie.MovieID {
+				withMovie.Movie = movie
+			}
+		}
+		result = append(result, &withMovie)
+	}
+	return result
+}
+
+func refreshReviewStats(reviews []*domain.Review, movies []*domain.Movie, movieID uint) {
+	for _, movie := range movies {
+		if movie.ID != movieID {
+			continue
+		}
+		movie.ReviewCount = 0
+		movie.AverageScore = 0
+		total := 0.0
+		for _, review := range reviews {
+			if review.MovieID == movieID {
+				movie.ReviewCount++
+				total += review.Score
+			}
+		}
+		if movie.ReviewCount > 0 {
+			movie.AverageScore = total / float64(movie.ReviewCount)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockSessionRepository struct {
+	Sessions      []*domain.Session
+	RefreshTokens []*domain.RefreshToken
+}
+
+func (m *MockSessionRepository) CreateSession(session *domain.Session, refreshToken *domain.RefreshToken) error {
+	session.ID = uint(len(m.Sessions) + 1)
+	m.Sessions = append(m.Sessions, session)
+
+	refreshToken.ID = uint(len(m.RefreshTokens) + 1)
+	refreshToken.SessionID = session.ID
+	m.RefreshTokens = append(m.RefreshTokens, refreshToken)
+	return nil
+}
+
+func (m *MockSessionRepository) GetSession(id uint) (*domain.Session, error) {
+	for _, session := range m.Sessions {
+		if session.ID == id {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("session not found")
+}
+
+func (m *MockSessionRepository) ListActiveSessions(userID uint, at time.Time) ([]*domain.Session, error) {
+	sessions := []*domain.Session{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID && session.Active(at) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+func (m *MockSessionRepository) GetRefreshToken(tokenHash string) (*domain.RefreshToken, error) {
+	for _, refreshToken := range m.RefreshTokens {
+		if refreshToken.TokenHash == tokenHash {
+			copied := *refreshToken
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("refresh token not found")
+}
+
+func (m *MockSessionRepository) RotateRefreshToken(usedTokenID uint, next *domain.RefreshToken, session *domain.Session) error {
+	for _, refreshToken := range m.RefreshTokens {
+		if refreshToken.ID != usedTokenID {
+			continue
+		}
+		if !refreshToken.UsedAt.IsZero() {
+			return domain.ErrRefreshTokenReused
+		}
+		refreshToken.UsedAt = session.LastUsedAt
+
+		next.ID = uint(len(m.RefreshTokens) + 1)
+		m.RefreshTokens = append(m.RefreshTokens, next)
+
+		for i, existing := range m.Sessions {
+			if existing.ID == session.ID {
+				copied := *session
+				m.Sessions[i] = &copied
+			}
+		}
+		return nil
+	}
+	return errors.New("refresh token not found")
+}
+
+func (m *MockSessionRepository) RevokeSession(id uint, revokedAt time.Time) error {
+	for _, session := range m.Sessions {
+		if session.ID == id {
+			session.RevokedAt = revokedAt
+			return nil
+		}
+	}
+	return errors.New("session not found")
+}
+
+func (m *MockSessionRepository) DeleteSessions(userID uint) error {
+	sessions := []*domain.Session{}
+	deleted := map[uint]bool{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID {
+			deleted[session.ID] = true
+		} else {
+			sessions = append(sessions, session)
+		}
+	}
+	refreshTokens := []*domain.RefreshToken{}
+	for _, refreshToken := range m.RefreshTokens {
+		if !deleted[refreshToken.SessionID] {
+			refreshTokens = append(refreshTokens, refreshToken)
+		}
+	}
+	m.Sessions, m.RefreshTokens = sessions, refreshTokens
+	return nil
+}
+
+// MockSessionService gives out the refresh tokens "refresh-<session ID>-<n>",
+// where n counts the refresh tokens issued so far.
+type MockSessionService struct {
+	Sessions []*domain.Session
+	Tokens   map[string]uint
+	issued   int
+}
+
+func (m *MockSessionService) CreateSession(userID uint, userAgent string, ipAddress string) (*domain.Session, string, error) {
+	now := time.Now()
+	session := &domain.Session{
+		ID:         uint(len(m.Sessions) + 1),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(time.Hour),
+	}
+	m.Sessions = append(m.Sessions, session)
+	return session, m.issueToken(session), nil
+}
+
+func (m *MockSessionService) RefreshSession(refreshToken string, userAgent string, ipAddress string) (*domain.Session, string, error) {
+	sessionID, ok := m.Tokens[refreshToken]
+	if !ok {
+		return nil, "", domain.ErrInvalidRefreshToken
+	}
+	delete(m.Tokens, refreshToken)
+
+	session := m.Sessions[sessionID-1]
+	if !session.Active(time.Now()) {
+		return nil, "", domain.ErrInvalidRefreshToken
+	}
+	session.LastUsedAt = time.Now()
+	return session, m.issueToken(session), nil
+}
+
+func (m *MockSessionService) issueToken(session *domain.Session) string {
+	if m.Tokens == nil {
+		m.Tokens = map[string]uint{}
+	}
+	m.issued++
+	token := fmt.Sprintf("refresh-%d-%d", session.ID, m.issued)
+	m.Tokens[token] = session.ID
+	return token
+}
+
+func (m *MockSessionService) ListSessions(userID uint) ([]*domain.Session, error) {
+	sessions := []*domain.Session{}
+	for _, session := range m.Sessions {
+		if session.UserID == userID && session.Active(time.Now()) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *MockSessionService) RevokeSession(userID uint, sessionID uint) error {
+	for _, session := range m.Sessions {
+		if session.ID == sessionID && session.UserID == userID {
+			session.RevokedAt = time.Now()
+			return nil
+		}
+	}
+	return domain.ErrSessionNotFound
+}
+
+func (m *MockSessionService) CheckSession(sessionID uint) error {
+	for _, session := range m.Sessions {
+		if session.ID == sessionID && !session.Active(time.Now()) {
+			return domain.ErrSessionRevoked
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package mock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// MockTokenRevocationRepository keeps the revoked tokens in memory. It is safe
+// for concurrent use, so that it can back a running HTTP server in tests.
+type MockTokenRevocationRepository struct {
+	mutex   sync.Mutex
+	Revoked map[string]time.Time
+}
+
+func (m *MockTokenRevocationRepository) RevokeToken(tokenID string, expiresAt time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.Revoked == nil {
+		m.Revoked = map[string]time.Time{}
+	}
+
+	// Forget the tokens which have expired anyway
+	now := time.Now()
+	for revokedID, revokedUntil := range m.Revoked {
+		if !revokedUntil.After(now) {
+			delete(m.Revoked, revokedID)
+		}
+	}
+
+	m.Revoked[tokenID] = expiresAt
+	return nil
+}
+
+func (m *MockTokenRevocationRepository) IsTokenRevoked(tokenID string) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, revoked := m.Revoked[tokenID]
+	return revoked, nil
+}
+
+type MockTokenRevocationService struct {
+	Revoked map[string]time.Time
+}
+
+func (m *MockTokenRevocationService) RevokeToken(tokenID string, expiresAt time.Time) error {
+	if tokenID == "" {
+		return domain.ErrTokenNotRevocable
+	}
+	if m.Revoked == nil {
+		m.Revoked = map[string]time.Time{}
+	}
+	m.Revoked[tokenID] = expiresAt
+	return nil
+}
+
+func (m *MockTokenRevocationService) CheckToken(tokenID string) error {
+	if _, revoked := m.Revoked[tokenID]; revoked {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
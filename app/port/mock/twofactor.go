@@ -0,0 +1,247 @@
+package mock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockTwoFactorRepository struct {
+	TwoFactors    []*domain.TwoFactor
+	RecoveryCodes []*domain.RecoveryCode
+	Challenges    []*domain.TwoFactorChallenge
+}
+
+func (m *MockTwoFactorRepository) GetTwoFactor(userID uint) (*domain.TwoFactor, error) {
+	for _, twoFactor := range m.TwoFactors {
+		if twoFactor.UserID == userID {
+			copied := *twoFactor
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockTwoFactorRepository) SaveTwoFactor(twoFactor *domain.TwoFactor) error {
+	for i, existing := range m.TwoFactors {
+		if existing.UserID == twoFactor.UserID {
+			m.TwoFactors[i] = twoFactor
+			return nil
+		}
+	}
+	m.TwoFactors = append(m.TwoFactors, twoFactor)
+	return nil
+}
+
+func (m *MockTwoFactorRepository) EnableTwoFactor(userID uint, enabledAt time.Time, step int64) error {
+	for _, twoFactor := range m.TwoFactors {
+		if twoFactor.UserID == userID {
+			twoFactor.EnabledAt = enabledAt
+			twoFactor.LastUsedStep = step
+			return nil
+		}
+	}
+	return errors.New("two-factor authentication not found")
+}
+
+func (m *MockTwoFactorRepository) DeleteTwoFactor(userID uint) error {
+	twoFactors := []*domain.TwoFactor{}
+	for _, twoFactor := range m.TwoFactors {
+		if twoFactor.UserID != userID {
+			twoFactors = append(twoFactors, twoFactor)
+		}
+	}
+	m.TwoFactors = twoFactors
+
+	challenges := []*domain.TwoFactorChallenge{}
+	for _, challenge := range m.Challenges {
+		if challenge.UserID != userID {
+			challenges = append(challenges, challenge)
+		}
+	}
+	m.Challenges = challenges
+	return m.ReplaceRecoveryCodes(userID, nil)
+}
+
+func (m *MockTwoFactorRepository) UseTOTPStep(userID uint, step int64) error {
+	for _, twoFactor := range m.TwoFactors {
+		if twoFactor.UserID == userID {
+			if twoFactor.LastUsedStep >= step {
+				return domain.ErrInvalidTwoFactorCode
+			}
+			twoFactor.LastUsedStep = step
+			return nil
+		}
+	}
+	return errors.New("two-factor authentication not found")
+}
+
+func (m *MockTwoFactorRepository) ReplaceRecoveryCodes(userID uint, codes []*domain.RecoveryCode) error {
+	recoveryCodes := []*domain.RecoveryCode{}
+	for _, recoveryCode := range m.RecoveryCodes {
+		if recoveryCode.UserID != userID {
+			recoveryCodes = append(recoveryCodes, recoveryCode)
+		}
+	}
+	for _, code := range codes {
+		code.ID = uint(len(recoveryCodes) + 1)
+		recoveryCodes = append(recoveryCodes, code)
+	}
+	m.RecoveryCodes = recoveryCodes
+	return nil
+}
+
+func (m *MockTwoFactorRepository) CountRecoveryCodes(userID uint) (int64, error) {
+	count := int64(0)
+	for _, recoveryCode := range m.RecoveryCodes {
+		if recoveryCode.UserID == userID && recoveryCode.UsedAt.IsZero() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(userID uint, codeHash string, usedAt time.Time) error {
+	for _, recoveryCode := range m.RecoveryCodes {
+		if recoveryCode.UserID == userID && recoveryCode.CodeHash == codeHash && recoveryCode.UsedAt.IsZero() {
+			recoveryCode.UsedAt = usedAt
+			return nil
+		}
+	}
+	return domain.ErrInvalidTwoFactorCode
+}
+
+func (m *MockTwoFactorRepository) CreateChallenge(challenge *domain.TwoFactorChallenge) error {
+	challenge.ID = uint(len(m.Challenges) + 1)
+	m.Challenges = append(m.Challenges, challenge)
+	return nil
+}
+
+func (m *MockTwoFactorRepository) GetChallenge(tokenHash string) (*domain.TwoFactorChallenge, error) {
+	for _, challenge := range m.Challenges {
+		if challenge.TokenHash == tokenHash {
+			copied := *challenge
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("two-factor challenge not found")
+}
+
+func (m *MockTwoFactorRepository) AddChallengeAttempt(id uint) error {
+	for _, challenge := range m.Challenges {
+		if challenge.ID == id {
+			challenge.Attempts++
+			return nil
+		}
+	}
+	return errors.New("two-factor challenge not found")
+}
+
+func (m *MockTwoFactorRepository) UseChallenge(id uint, usedAt time.Time) error {
+	for _, challenge := range m.Challenges {
+		if challenge.ID == id {
+			if !challenge.UsedAt.IsZero() {
+				return domain.ErrInvalidTwoFactorChallenge
+			}
+			challenge.UsedAt = usedAt
+			return nil
+		}
+	}
+	return errors.New("two-factor challenge not found")
+}
+
+// MockTwoFactorService accepts Code as the only valid code. Its challenge
+// tokens are "challenge-<n>", where n counts the challenges created so far,
+// and they belong to the user in Users with the same ID.
+type MockTwoFactorService struct {
+	Enabled    map[uint]bool
+	Code       string
+	Challenges map[string]uint
+	Users      []*domain.User
+	created    int
+}
+
+func (m *MockTwoFactorService) TwoFactorEnabled(userID uint) (bool, error) {
+	return m.Enabled[userID], nil
+}
+
+func (m *MockTwoFactorService) CountRecoveryCodes(userID uint) (int64, error) {
+	if !m.Enabled[userID] {
+		return 0, nil
+	}
+	return 10, nil
+}
+
+func (m *MockTwoFactorService) StartEnrolment(userID uint) (*domain.TOTPEnrolment, error) {
+	if m.Enabled[userID] {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	return &domain.TOTPEnrolment{Secret: "SECRET", ProvisioningURI: "otpauth://totp/Test:test@test.com?secret=SECRET"}, nil
+}
+
+func (m *MockTwoFactorService) ConfirmEnrolment(userID uint, code string) ([]string, error) {
+	if m.Enabled[userID] {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	if code != m.Code {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	if m.Enabled == nil {
+		m.Enabled = map[uint]bool{}
+	}
+	m.Enabled[userID] = true
+	return []string{"aaaa-bbbb-cccc-dddd"}, nil
+}
+
+func (m *MockTwoFactorService) DisableTwoFactor(userID uint, code string) error {
+	if !m.Enabled[userID] {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	if code != m.Code {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	delete(m.Enabled, userID)
+	return nil
+}
+
+func (m *MockTwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	if !m.Enabled[userID] {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	if code != m.Code {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	return []string{"eeee-ffff-gggg-hhhh"}, nil
+}
+
+func (m *MockTwoFactorService) CreateChallenge(userID uint) (string, time.Time, error) {
+	if m.Challenges == nil {
+		m.Challenges = map[string]uint{}
+	}
+	m.created++
+	token := fmt.Sprintf("challenge-%d", m.created)
+	m.Challenges[token] = userID
+	return token, time.Now().Add(5 * time.Minute), nil
+}
+
+func (m *MockTwoFactorService) ChallengeUser(challengeToken string) (*domain.User, error) {
+	userID, ok := m.Challenges[challengeToken]
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	return findUser(m.Users, userID)
+}
+
+func (m *MockTwoFactorService) VerifyChallenge(challengeToken string, code string) (*domain.User, error) {
+	userID, ok := m.Challenges[challengeToken]
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	if code != m.Code {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	delete(m.Challenges, challengeToken)
+	return findUser(m.Users, userID)
+}
@@ -2,23 +2,37 @@ package mock
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 )
 
 type MockUserRepository struct {
 	Users []*domain.User
 }
 
-func (r *MockUserRepository) ListUsers() ([]*domain.User, error) {
-	return r.Users, nil
+func (r *MockUserRepository) ListUsers(search string, pagination port.Pagination) ([]*domain.User, int64, error) {
+	users := searchUsers(r.Users, search)
+	return paginate(users, pagination), int64(len(users)), nil
 }
 
 func (r *MockUserRepository) CreateUser(user *domain.User) error {
+	if user.ID == 0 {
+		for _, existing := range r.Users {
+			user.ID = max(user.ID, existing.ID)
+		}
+		user.ID++
+	}
 	r.Users = append(r.Users, user)
 	return nil
 }
 
+func (r *MockUserRepository) GetUser(id uint) (*domain.User, error) {
+	return findUser(r.Users, id)
+}
+
 func (r *MockUserRepository) GetUserByEmail(email string) (*domain.User, error) {
 	for _, user := range r.Users {
 		if user.Email == email {
@@ -28,26 +42,91 @@ func (r *MockUserRepository) GetUserByEmail(email string) (*domain.User, error)
 	return nil, errors.New("user not found")
 }
 
+func (r *MockUserRepository) SetUserRole(id uint, role domain.Role) error {
+	return setUserRole(r.Users, id, role)
+}
+
+func (r *MockUserRepository) DisableUser(id uint, disabledAt time.Time) error {
+	return disableUser(r.Users, id, disabledAt)
+}
+
+func (r *MockUserRepository) EnableUser(id uint) error {
+	return enableUser(r.Users, id)
+}
+
+func (r *MockUserRepository) UpdateUser(user *domain.User) error {
+	return updateUser(r.Users, user)
+}
+
+func (r *MockUserRepository) UpdatePassword(id uint, hashedPassword string) error {
+	user, err := findUser(r.Users, id)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+	return nil
+}
+
+func (r *MockUserRepository) VerifyEmail(id uint, verifiedAt time.Time) error {
+	user, err := findUser(r.Users, id)
+	if err != nil {
+		return err
+	}
+	user.EmailVerifiedAt = verifiedAt
+	return nil
+}
+
+func (r *MockUserRepository) RevokeTokens(id uint, revokedAt time.Time) error {
+	user, err := findUser(r.Users, id)
+	if err != nil {
+		return err
+	}
+	user.TokensRevokedAt = revokedAt
+	return nil
+}
+
+func (r *MockUserRepository) DeleteUser(id uint, reassignMoviesTo *uint) error {
+	users, err := deleteUser(r.Users, id)
+	if err != nil {
+		return err
+	}
+	r.Users = users
+	return nil
+}
+
+// MockUserService checks new passwords against PasswordPolicy when it is set.
 type MockUserService struct {
-	Users []*domain.User
+	Users          []*domain.User
+	PasswordPolicy port.PasswordPolicyService
 }
 
 func (m *MockUserService) CreateUser(user *domain.User) error {
+	if err := m.checkPassword(user.Password, user); err != nil {
+		return err
+	}
 	m.Users = append(m.Users, user)
 	return nil
 }
 
-func (m *MockUserService) ListUsers() ([]*domain.User, error) {
-	return m.Users, nil
+func (m *MockUserService) ListUsers(search string, pagination port.Pagination) ([]*domain.User, int64, error) {
+	users := searchUsers(m.Users, search)
+	return paginate(users, pagination), int64(len(users)), nil
 }
 
 func (m *MockUserService) GetLoginUser(email, password string) (*domain.User, error) {
 	for _, user := range m.Users {
 		if user.Email == email && user.Password == password {
+			if user.Disabled() {
+				return nil, domain.ErrUserDisabled
+			}
 			return user, nil
 		}
 	}
-	return nil, errors.New("user not found or password incorrect")
+	return nil, domain.ErrInvalidCredentials
+}
+
+func (m *MockUserService) GetUser(id uint) (*domain.User, error) {
+	return findUser(m.Users, id)
 }
 
 func (m *MockUserService) GetUserByEmail(email string) (*domain.User, error) {
@@ -58,3 +137,149 @@ func (m *MockUserService) GetUserByEmail(email string) (*domain.User, error) {
 	}
 	return nil, errors.New("user not found")
 }
+
+func (m *MockUserService) SetUserRole(id uint, role domain.Role) error {
+	if !role.Valid() {
+		return domain.ErrInvalidRole
+	}
+	return setUserRole(m.Users, id, role)
+}
+
+func (m *MockUserService) DisableUser(id uint) error {
+	return disableUser(m.Users, id, time.Now())
+}
+
+func (m *MockUserService) EnableUser(id uint) error {
+	return enableUser(m.Users, id)
+}
+
+func (m *MockUserService) AuthorizeToken(id uint, issuedAt time.Time) (*domain.User, error) {
+	user, err := findUser(m.Users, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+	if issuedAt.Before(user.TokensRevokedAt.Truncate(time.Second)) {
+		return nil, domain.ErrTokenRevoked
+	}
+	return user, nil
+}
+
+func (m *MockUserService) UpdateProfile(user *domain.User) error {
+	return updateUser(m.Users, user)
+}
+
+// ChangePassword stores passwords in plain text, like GetLoginUser expects.
+func (m *MockUserService) ChangePassword(id uint, currentPassword, newPassword string) error {
+	user, err := findUser(m.Users, id)
+	if err != nil {
+		return err
+	}
+	if user.Password != currentPassword {
+		return domain.ErrWrongPassword
+	}
+	if err := m.checkPassword(newPassword, user); err != nil {
+		return err
+	}
+	user.Password = newPassword
+	user.TokensRevokedAt = time.Now()
+	return nil
+}
+
+func (m *MockUserService) DeleteAccount(id uint, password string, reassignMoviesTo *uint) error {
+	user, err := findUser(m.Users, id)
+	if err != nil {
+		return err
+	}
+	if user.Password != password {
+		return domain.ErrWrongPassword
+	}
+	if reassignMoviesTo != nil {
+		if _, err := findUser(m.Users, *reassignMoviesTo); err != nil || *reassignMoviesTo == id {
+			return domain.ErrInvalidMovieReassignment
+		}
+	}
+	users, err := deleteUser(m.Users, id)
+	if err != nil {
+		return err
+	}
+	m.Users = users
+	return nil
+}
+
+func findUser(users []*domain.User, id uint) (*domain.User, error) {
+	for _, user := range users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func searchUsers(users []*domain.User, search string) []*domain.User {
+	search = strings.ToLower(search)
+	result := []*domain.User{}
+	for _, user := range users {
+		if strings.Contains(strings.ToLower(user.Email), search) || strings.Contains(strings.ToLower(user.Name), search) {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+func setUserRole(users []*domain.User, id uint, role domain.Role) error {
+	user, err := findUser(users, id)
+	if err != nil {
+		return err
+	}
+	user.Role = role
+	return nil
+}
+
+func disableUser(users []*domain.User, id uint, disabledAt time.Time) error {
+	user, err := findUser(users, id)
+	if err != nil {
+		return err
+	}
+	user.DisableDate = disabledAt
+	user.TokensRevokedAt = disabledAt
+	return nil
+}
+
+func enableUser(users []*domain.User, id uint) error {
+	user, err := findUser(users, id)
+	if err != nil {
+		return err
+	}
+	user.DisableDate = time.Time{}
+	return nil
+}
+
+func updateUser(users []*domain.User, user *domain.User) error {
+	existing, err := findUser(users, user.ID)
+	if err != nil {
+		return err
+	}
+	existing.Email = user.Email
+	existing.Name = user.Name
+	existing.EmailVerifiedAt = user.EmailVerifiedAt
+	return nil
+}
+
+func deleteUser(users []*domain.User, id uint) ([]*domain.User, error) {
+	for i, user := range users {
+		if user.ID == id {
+			return append(users[:i], users[i+1:]...), nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (m *MockUserService) checkPassword(password string, user *domain.User) error {
+	if m.PasswordPolicy == nil {
+		return nil
+	}
+	return m.PasswordPolicy.CheckPassword(password, user)
+}
@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type MockEmailVerificationRepository struct {
+	Tokens []*domain.EmailVerificationToken
+}
+
+func (m *MockEmailVerificationRepository) CreateVerificationToken(token *domain.EmailVerificationToken) error {
+	token.ID = uint(len(m.Tokens) + 1)
+	token.CreatedAt = time.Now()
+	m.Tokens = append(m.Tokens, token)
+	return nil
+}
+
+func (m *MockEmailVerificationRepository) UseVerificationToken(tokenHash string, usedAt time.Time) (*domain.EmailVerificationToken, error) {
+	for _, token := range m.Tokens {
+		if token.TokenHash == tokenHash && token.UsedAt.IsZero() && token.ExpiresAt.After(usedAt) {
+			token.UsedAt = usedAt
+			return token, nil
+		}
+	}
+	return nil, domain.ErrInvalidVerificationToken
+}
+
+func (m *MockEmailVerificationRepository) InvalidateVerificationTokens(userID uint, at time.Time) error {
+	for _, token := range m.Tokens {
+		if token.UserID == userID && token.UsedAt.IsZero() {
+			token.UsedAt = at
+		}
+	}
+	return nil
+}
+
+// MockEmailVerificationService records the users it sends verifications to in
+// Sent. It accepts the tokens in Tokens, mapped to the user they were sent to,
+// and records the users they verify in Verified.
+type MockEmailVerificationService struct {
+	Sent     []uint
+	Tokens   map[string]uint
+	Verified []uint
+}
+
+func (m *MockEmailVerificationService) SendVerification(user *domain.User) error {
+	if user.EmailVerified() || slices.Contains(m.Verified, user.ID) {
+		return domain.ErrEmailAlreadyVerified
+	}
+	m.Sent = append(m.Sent, user.ID)
+	return nil
+}
+
+func (m *MockEmailVerificationService) ResendVerification(userID uint) error {
+	return m.SendVerification(&domain.User{ID: userID})
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(token string) error {
+	userID, ok := m.Tokens[token]
+	if !ok {
+		return domain.ErrInvalidVerificationToken
+	}
+	delete(m.Tokens, token)
+	m.Verified = append(m.Verified, userID)
+	return nil
+}
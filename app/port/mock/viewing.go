@@ -0,0 +1,162 @@
+package mock
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockViewingRepository struct {
+	Viewings []*domain.Viewing
+	Movies   []*domain.Movie
+}
+
+func (m *MockViewingRepository) CreateViewing(viewing *domain.Viewing) error {
+	viewing.ID = uint(len(m.Viewings) + 1)
+	m.Viewings = append(m.Viewings, viewing)
+	return nil
+}
+
+func (m *MockViewingRepository) GetViewing(id uint) (*domain.Viewing, error) {
+	return findViewing(m.Viewings, id)
+}
+
+func (m *MockViewingRepository) UpdateViewing(viewing *domain.Viewing) error {
+	return updateViewing(m.Viewings, viewing)
+}
+
+func (m *MockViewingRepository) DeleteViewing(viewing *domain.Viewing) error {
+	viewings, err := deleteViewing(m.Viewings, viewing)
+	if err != nil {
+		return err
+	}
+	m.Viewings = viewings
+	return nil
+}
+
+func (m *MockViewingRepository) ListViewings(userID uint, filter port.ViewingFilter, pagination port.Pagination) ([]*domain.Viewing, int64, error) {
+	viewings := filterViewings(m.Viewings, m.Movies, userID, filter)
+	return paginate(viewings, pagination), int64(len(viewings)), nil
+}
+
+func (m *MockViewingRepository) HasViewedBefore(userID uint, movieID uint, before time.Time) (bool, error) {
+	return hasViewedBefore(m.Viewings, userID, movieID, before), nil
+}
+
+type MockViewingService struct {
+	Viewings []*domain.Viewing
+	Movies   []*domain.Movie
+}
+
+func (m *MockViewingService) LogViewing(viewing *domain.Viewing) error {
+	if !viewing.Rewatch {
+		viewing.Rewatch = hasViewedBefore(m.Viewings, viewing.UserID, viewing.MovieID, viewing.WatchedAt)
+	}
+	viewing.ID = uint(len(m.Viewings) + 1)
+	m.Viewings = append(m.Viewings, viewing)
+	return nil
+}
+
+func (m *MockViewingService) GetViewing(id uint) (*domain.Viewing, error) {
+	return findViewing(m.Viewings, id)
+}
+
+func (m *MockViewingService) UpdateViewing(viewing *domain.Viewing) error {
+	return updateViewing(m.Viewings, viewing)
+}
+
+func (m *MockViewingService) DeleteViewing(viewing *domain.Viewing) error {
+	viewings, err := deleteViewing(m.Viewings, viewing)
+	if err != nil {
+		return err
+	}
+	m.Viewings = viewings
+	return nil
+}
+
+func (m *MockViewingService) ListViewings(userID uint, filter port.ViewingFilter, pagination port.Pagination) ([]*domain.Viewing, int64, error) {
+	viewings := filterViewings(m.Viewings, m.Movies, userID, filter)
+	return paginate(viewings, pagination), int64(len(viewings)), nil
+}
+
+func (m *MockViewingService) GetDiary(userID uint, year int, month time.Month) ([]*domain.DiaryDay, error) {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	viewings := filterViewings(m.Viewings, m.Movies, userID, port.ViewingFilter{WatchedFrom: &from, WatchedTo: &to})
+
+	days := []*domain.DiaryDay{}
+	for i := len(viewings) - 1; i >= 0; i-- {
+		watchedAt := viewings[i].WatchedAt.UTC()
+		date := time.Date(watchedAt.Year(), watchedAt.Month(), watchedAt.Day(), 0, 0, 0, 0, time.UTC)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, &domain.DiaryDay{Date: date})
+		}
+		days[len(days)-1].Viewings = append(days[len(days)-1].Viewings, viewings[i])
+	}
+	return days, nil
+}
+
+func findViewing(viewings []*domain.Viewing, id uint) (*domain.Viewing, error) {
+	for _, viewing := range viewings {
+		if viewing.ID == id {
+			return viewing, nil
+		}
+	}
+	return nil, errors.New("viewing not found")
+}
+
+func updateViewing(viewings []*domain.Viewing, viewing *domain.Viewing) error {
+	for i, v := range viewings {
+		if v.ID == viewing.ID {
+			viewings[i] = viewing
+			return nil
+		}
+	}
+	return errors.New("viewing not found")
+}
+
+func deleteViewing(viewings []*domain.Viewing, viewing *domain.Viewing) ([]*domain.Viewing, error) {
+	for i, v := range viewings {
+		if v.ID == viewing.ID {
+			return append(viewings[:i], viewings[i+1:]...), nil
+		}
+	}
+	return nil, errors.New("viewing not found")
+}
+
+// filterViewings returns the matching viewings of a user, most recently
+// watched first, with their movie attached.
+func filterViewings(viewings []*domain.Viewing, movies []*domain.Movie, userID uint, filter port.ViewingFilter) []*domain.Viewing {
+	result := []*domain.Viewing{}
+	for _, viewing := range viewings {
+		if viewing.UserID != userID ||
+			filter.MovieID != nil && viewing.MovieID != *filter.MovieID ||
+			filter.WatchedFrom != nil && viewing.WatchedAt.Before(*filter.WatchedFrom) ||
+			filter.WatchedTo != nil && !viewing.WatchedAt.Before(*filter.WatchedTo) {
+			continue
+		}
+		withMovie := *viewing
+		for _, movie := range movies {
+			if movie.ID == viewing.MovieID {
+				withMovie.Movie = movie
+			}
+		}
+		result = append(result, &withMovie)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].WatchedAt.After(result[j].WatchedAt)
+	})
+	return result
+}
+
+func hasViewedBefore(viewings []*domain.Viewing, userID uint, movieID uint, before time.Time) bool {
+	for _, viewing := range viewings {
+		if viewing.UserID == userID && viewing.MovieID == movieID && viewing.WatchedAt.Before(before) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package mock
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type MockWatchlistRepository struct {
+	Entries []*domain.WatchlistEntry
+	Movies  []*domain.Movie
+}
+
+func (m *MockWatchlistRepository) AddToWatchlist(entry *domain.WatchlistEntry) error {
+	entries, err := addToWatchlist(m.Entries, entry)
+	if err != nil {
+		return err
+	}
+	m.Entries = entries
+	return nil
+}
+
+func (m *MockWatchlistRepository) GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	return findWatchlistEntry(m.Entries, userID, movieID)
+}
+
+func (m *MockWatchlistRepository) UpdateWatchlistEntry(entry *domain.WatchlistEntry) error {
+	existing, err := findWatchlistEntry(m.Entries, entry.UserID, entry.MovieID)
+	if err != nil {
+		return err
+	}
+	existing.Priority = entry.Priority
+	return nil
+}
+
+func (m *MockWatchlistRepository) RemoveFromWatchlist(entry *domain.WatchlistEntry) error {
+	entries, err := removeFromWatchlist(m.Entries, entry)
+	if err != nil {
+		return err
+	}
+	m.Entries = entries
+	return nil
+}
+
+func (m *MockWatchlistRepository) ListWatchlist(userID uint, pagination port.Pagination) ([]*domain.WatchlistEntry, int64, error) {
+	entries := userWatchlist(m.Entries, m.Movies, userID)
+	return paginate(entries, pagination), int64(len(entries)), nil
+}
+
+func (m *MockWatchlistRepository) ReorderWatchlist(userID uint, movieIDs []uint) error {
+	reorderWatchlist(m.Entries, userID, movieIDs)
+	return nil
+}
+
+type MockWatchlistService struct {
+	Entries []*domain.WatchlistEntry
+	Movies  []*domain.Movie
+}
+
+func (m *MockWatchlistService) AddToWatchlist(entry *domain.WatchlistEntry) error {
+	if entry.Priority == "" {
+		entry.Priority = domain.WatchlistPriorityNormal
+	}
+	entries, err := addToWatchlist(m.Entries, entry)
+	if err != nil {
+		return err
+	}
+	m.Entries = entries
+	return nil
+}
+
+func (m *MockWatchlistService) GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	return findWatchlistEntry(m.Entries, userID, movieID)
+}
+
+func (m *MockWatchlistService) UpdateWatchlistEntry(entry *domain.WatchlistEntry) error {
+	existing, err := findWatchlistEntry(m.Entries, entry.UserID, entry.MovieID)
+	if err != nil {
+		return err
+	}
+	existing.Priority = entry.Priority
+	return nil
+}
+
+func (m *MockWatchlistService) RemoveFromWatchlist(entry *domain.WatchlistEntry) error {
+	entries, err := removeFromWatchlist(m.Entries, entry)
+	if err != nil {
+		return err
+	}
+	m.Entries = entries
+	return nil
+}
+
+func (m *MockWatchlistService) ListWatchlist(userID uint, pagination port.Pagination) ([]*domain.WatchlistEntry, int64, error) {
+	entries := userWatchlist(m.Entries, m.Movies, userID)
+	return paginate(entries, pagination), int64(len(entries)), nil
+}
+
+func (m *MockWatchlistService) ReorderWatchlist(userID uint, movieIDs []uint) error {
+	entries := userWatchlist(m.Entries, m.Movies, userID)
+	if len(entries) != len(movieIDs) {
+		return domain.ErrInvalidWatchlistOrder
+	}
+	for _, movieID := range movieIDs {
+		if _, err := findWatchlistEntry(m.Entries, userID, movieID); err != nil {
+			return domain.ErrInvalidWatchlistOrder
+		}
+	}
+	reorderWatchlist(m.Entries, userID, movieIDs)
+	return nil
+}
+
+func addToWatchlist(entries []*domain.WatchlistEntry, entry *domain.WatchlistEntry) ([]*domain.WatchlistEntry, error) {
+	position := 0
+	for _, v := range entries {
+		if v.UserID != entry.UserID {
+			continue
+		}
+		if v.MovieID == entry.MovieID {
+			return nil, errors.New("movie already in watchlist")
+		}
+		position = max(position, v.Position)
+	}
+	entry.Position = position + 1
+	return append(entries, entry), nil
+}
+
+func findWatchlistEntry(entries []*domain.WatchlistEntry, userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	for _, entry := range entries {
+		if entry.UserID == userID && entry.MovieID == movieID {
+			return entry, nil
+		}
+	}
+	return nil, domain.ErrWatchlistEntryNotFound
+}
+
+// removeFromWatchlist removes an entry and moves the ones after it up.
+func removeFromWatchlist(entries []*domain.WatchlistEntry, entry *domain.WatchlistEntry) ([]*domain.WatchlistEntry, error) {
+	for i, v := range entries {
+		if v.UserID == entry.UserID && v.MovieID == entry.MovieID {
+			remaining := append(entries[:i], entries[i+1:]...)
+			for _, other := range remaining {
+				if other.UserID == v.UserID && other.Position > v.Position {
+					other.Position--
+				}
+			}
+			return remaining, nil
+		}
+	}
+	return nil, domain.ErrWatchlistEntryNotFound
+}
+
+func userWatchlist(entries []*domain.WatchlistEntry, movies []*domain.Movie, userID uint) []*domain.WatchlistEntry {
+	result := []*domain.WatchlistEntry{}
+	for _, entry := range entries {
+		if entry.UserID != userID {
+			continue
+		}
+		withMovie := *entry
+		for _, movie := range movies {
+			if movie.ID == entry.MovieID {
+				withMovie.Movie = movie
+			}
+		}
+		result = append(result, &withMovie)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Position < result[j].Position
+	})
+	return result
+}
+
+func reorderWatchlist(entries []*domain.WatchlistEntry, userID uint, movieIDs []uint) {
+	for position, movieID := range movieIDs {
+		for _, entry := range entries {
+			if entry.UserID == userID && entry.MovieID == movieID {
+				entry.Position = position + 1
+			}
+		}
+	}
+}
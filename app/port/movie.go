@@ -1,10 +1,41 @@
 package port
 
-import "github.com/Acova/movie-collection/app/domain"
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// MovieSortFields are the fields movie listings can be sorted by.
+var MovieSortFields = []string{"title", "release_year", "rating", "duration", "created_at"}
+
+// MovieFilter narrows down a movie listing. Text fields match case-insensitive
+// substrings, except ExactTitle which matches the whole title and Genre which
+// matches the name of one of the movie genres. With Fuzzy set, Title and
+// Director also match misspelled values. Nil bounds and empty strings are
+// ignored, and range bounds are inclusive.
+type MovieFilter struct {
+	Title           string
+	ExactTitle      string
+	Director        string
+	Fuzzy           bool
+	Genre           string
+	Cast            string
+	ReleaseYearFrom *int
+	ReleaseYearTo   *int
+	RatingMin       *float64
+	RatingMax       *float64
+	DurationMin     *int
+	DurationMax     *int
+	UserID          *uint
+	CreatedAfter    *time.Time
+}
 
 type MovieRepository interface {
 	CreateMovie(movie *domain.Movie) error
-	ListMovies(filters map[string]string) ([]*domain.Movie, error)
+	ListMovies(filter MovieFilter, pagination Pagination, sort Sort) ([]*domain.Movie, int64, error)
+	SearchMovies(query string, pagination Pagination) ([]*domain.MovieSearchResult, int64, error)
+	AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error)
 	GetMovie(id uint) (*domain.Movie, error)
 	UpdateMovie(movie *domain.Movie) error
 	DeleteMovie(movie *domain.Movie) error
@@ -12,7 +43,9 @@ type MovieRepository interface {
 
 type MovieService interface {
 	CreateMovie(movie *domain.Movie) error
-	ListMovies(filters map[string]string) ([]*domain.Movie, error)
+	ListMovies(filter MovieFilter, pagination Pagination, sort Sort) ([]*domain.Movie, int64, error)
+	SearchMovies(query string, pagination Pagination) ([]*domain.MovieSearchResult, int64, error)
+	AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error)
 	GetMovie(id uint) (*domain.Movie, error)
 	UpdateMovie(movie *domain.Movie) error
 	DeleteMovie(movie *domain.Movie) error
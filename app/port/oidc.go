@@ -0,0 +1,43 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// OIDCProvider logs users in through an OpenID Connect provider with the
+// authorization code flow.
+type OIDCProvider interface {
+	// AuthorizationURL returns where to send the user to sign in, with the
+	// state, nonce and PKCE code challenge of the login.
+	AuthorizationURL(state string, nonce string, codeChallenge string) (string, error)
+	// Exchange redeems an authorization code, along with the PKCE code
+	// verifier of its login, and returns the claims of the ID token given for
+	// it. It fails with domain.ErrOIDCLoginFailed when the code is rejected,
+	// or the ID token is not valid or not issued for the nonce.
+	Exchange(code string, codeVerifier string, nonce string) (*domain.OIDCClaims, error)
+}
+
+type OIDCRepository interface {
+	CreateLoginState(state *domain.OIDCLoginState) error
+	GetLoginState(stateHash string) (*domain.OIDCLoginState, error)
+	// UseLoginState marks a login state as used, failing with
+	// domain.ErrInvalidOIDCState when it already was.
+	UseLoginState(id uint, usedAt time.Time) error
+
+	// GetIdentity returns the identity of a subject at a provider, or nil
+	// without an error when no user has it.
+	GetIdentity(provider string, subject string) (*domain.OIDCIdentity, error)
+	CreateIdentity(identity *domain.OIDCIdentity) error
+}
+
+type OIDCService interface {
+	// ProviderNames returns the names of the configured providers, sorted.
+	ProviderNames() []string
+	// StartLogin returns where to send the user to sign in with a provider.
+	StartLogin(provider string) (string, error)
+	// CompleteLogin returns the user signed in with a provider, given the code
+	// and state the provider sent them back with.
+	CompleteLogin(provider string, state string, code string) (*domain.User, error)
+}
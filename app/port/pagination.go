@@ -0,0 +1,33 @@
+package port
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Offset returns the number of rows to skip to reach the current page.
+func (p Pagination) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
+
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+// Sort describes the field a listing is ordered by. An empty Field leaves the
+// order up to the repository.
+type Sort struct {
+	Field     string
+	Direction SortDirection
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type PasswordResetRepository interface {
+	CreateResetToken(token *domain.PasswordResetToken) error
+	// GetResetToken returns the unused and unexpired token with the given
+	// hash. It fails with domain.ErrInvalidResetToken when there is no such
+	// token.
+	GetResetToken(tokenHash string, at time.Time) (*domain.PasswordResetToken, error)
+	// UseResetToken marks the unused and unexpired token with the given hash
+	// as used, and returns it. It fails with domain.ErrInvalidResetToken when
+	// there is no such token.
+	UseResetToken(tokenHash string, usedAt time.Time) (*domain.PasswordResetToken, error)
+	InvalidateResetTokens(userID uint, at time.Time) error
+}
+
+type PasswordResetService interface {
+	RequestPasswordReset(email string) error
+	ResetPassword(token string, newPassword string) error
+}
@@ -0,0 +1,20 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+// BreachedPasswordRepository is a dataset of the SHA-1 hashes of passwords
+// found in data breaches, looked up by range as with k-anonymity: only the
+// first 5 characters of a hash are given, and every hash starting with them is
+// returned, so that the whole hash of a password never leaves the app.
+type BreachedPasswordRepository interface {
+	// GetBreachedHashSuffixes returns how many times each hash starting with
+	// prefix was seen in breaches, by the remaining 35 characters of the hash.
+	// Hashes are in upper case hexadecimal.
+	GetBreachedHashSuffixes(prefix string) (map[string]int, error)
+}
+
+type PasswordPolicyService interface {
+	// CheckPassword fails with a domain.PasswordPolicyError when a password
+	// does not meet the password policy for the user choosing it.
+	CheckPassword(password string, user *domain.User) error
+}
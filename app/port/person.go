@@ -0,0 +1,29 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type PersonRepository interface {
+	CreatePerson(person *domain.Person) error
+	ListPeople(name string, pagination Pagination) ([]*domain.Person, int64, error)
+	GetPerson(id uint) (*domain.Person, error)
+	UpdatePerson(person *domain.Person) error
+	DeletePerson(person *domain.Person) error
+	CreateCredit(credit *domain.MovieCredit) error
+	GetCredit(id uint) (*domain.MovieCredit, error)
+	DeleteCredit(credit *domain.MovieCredit) error
+	ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error)
+	ListPersonCredits(personID uint) ([]*domain.MovieCredit, error)
+}
+
+type PersonService interface {
+	CreatePerson(person *domain.Person) error
+	ListPeople(name string, pagination Pagination) ([]*domain.Person, int64, error)
+	GetPerson(id uint) (*domain.Person, error)
+	UpdatePerson(person *domain.Person) error
+	DeletePerson(person *domain.Person) error
+	CreateCredit(credit *domain.MovieCredit) error
+	GetCredit(id uint) (*domain.MovieCredit, error)
+	DeleteCredit(credit *domain.MovieCredit) error
+	ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error)
+	ListPersonCredits(personID uint) ([]*domain.MovieCredit, error)
+}
@@ -0,0 +1,21 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type ReviewRepository interface {
+	CreateReview(review *domain.Review) error
+	GetReview(movieID uint, userID uint) (*domain.Review, error)
+	UpdateReview(review *domain.Review) error
+	DeleteReview(review *domain.Review) error
+	ListMovieReviews(movieID uint, pagination Pagination) ([]*domain.Review, int64, error)
+	ListUserReviews(userID uint, pagination Pagination) ([]*domain.Review, int64, error)
+}
+
+type ReviewService interface {
+	CreateReview(review *domain.Review) error
+	GetReview(movieID uint, userID uint) (*domain.Review, error)
+	UpdateReview(review *domain.Review) error
+	DeleteReview(review *domain.Review) error
+	ListMovieReviews(movieID uint, pagination Pagination) ([]*domain.Review, int64, error)
+	ListUserReviews(userID uint, pagination Pagination) ([]*domain.Review, int64, error)
+}
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type SessionRepository interface {
+	// CreateSession saves a new session along with its first refresh token.
+	CreateSession(session *domain.Session, refreshToken *domain.RefreshToken) error
+	GetSession(id uint) (*domain.Session, error)
+	// ListActiveSessions returns the sessions of a user which are not revoked
+	// and have not expired at the given time, most recently used first.
+	ListActiveSessions(userID uint, at time.Time) ([]*domain.Session, error)
+	GetRefreshToken(tokenHash string) (*domain.RefreshToken, error)
+	// RotateRefreshToken marks the used token as used and saves the next one
+	// of its session, along with the last use and expiry of the session. It
+	// fails with domain.ErrRefreshTokenReused when the token was already used.
+	RotateRefreshToken(usedTokenID uint, next *domain.RefreshToken, session *domain.Session) error
+	RevokeSession(id uint, revokedAt time.Time) error
+	// DeleteSessions removes every session of a user along with their refresh
+	// tokens.
+	DeleteSessions(userID uint) error
+}
+
+type SessionService interface {
+	CreateSession(userID uint, userAgent string, ipAddress string) (*domain.Session, string, error)
+	RefreshSession(refreshToken string, userAgent string, ipAddress string) (*domain.Session, string, error)
+	ListSessions(userID uint) ([]*domain.Session, error)
+	RevokeSession(userID uint, sessionID uint) error
+	CheckSession(sessionID uint) error
+}
@@ -0,0 +1,15 @@
+package port
+
+import "time"
+
+// TokenRevocationRepository keeps the IDs (the `jti` claim) of the tokens
+// revoked before they expire.
+type TokenRevocationRepository interface {
+	RevokeToken(tokenID string, expiresAt time.Time) error
+	IsTokenRevoked(tokenID string) (bool, error)
+}
+
+type TokenRevocationService interface {
+	RevokeToken(tokenID string, expiresAt time.Time) error
+	CheckToken(tokenID string) error
+}
@@ -0,0 +1,51 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type TwoFactorRepository interface {
+	// GetTwoFactor returns the TOTP secret of a user, or nil without an error
+	// when they have none, so that failing to read it cannot be mistaken for
+	// two-factor authentication being off.
+	GetTwoFactor(userID uint) (*domain.TwoFactor, error)
+	// SaveTwoFactor creates or replaces the TOTP secret of a user.
+	SaveTwoFactor(twoFactor *domain.TwoFactor) error
+	EnableTwoFactor(userID uint, enabledAt time.Time, step int64) error
+	// DeleteTwoFactor removes the TOTP secret of a user along with their
+	// recovery codes and challenges.
+	DeleteTwoFactor(userID uint) error
+	// UseTOTPStep saves the time step of an accepted code. It fails with
+	// domain.ErrInvalidTwoFactorCode when a code of that step or a later one
+	// was already accepted.
+	UseTOTPStep(userID uint, step int64) error
+
+	// ReplaceRecoveryCodes removes the recovery codes of a user and saves the
+	// given ones.
+	ReplaceRecoveryCodes(userID uint, codes []*domain.RecoveryCode) error
+	CountRecoveryCodes(userID uint) (int64, error)
+	// UseRecoveryCode marks an unused recovery code as used. It fails with
+	// domain.ErrInvalidTwoFactorCode when the user has no such unused code.
+	UseRecoveryCode(userID uint, codeHash string, usedAt time.Time) error
+
+	CreateChallenge(challenge *domain.TwoFactorChallenge) error
+	GetChallenge(tokenHash string) (*domain.TwoFactorChallenge, error)
+	AddChallengeAttempt(id uint) error
+	// UseChallenge marks a challenge as used. It fails with
+	// domain.ErrInvalidTwoFactorChallenge when it was already used.
+	UseChallenge(id uint, usedAt time.Time) error
+}
+
+type TwoFactorService interface {
+	TwoFactorEnabled(userID uint) (bool, error)
+	CountRecoveryCodes(userID uint) (int64, error)
+	StartEnrolment(userID uint) (*domain.TOTPEnrolment, error)
+	ConfirmEnrolment(userID uint, code string) ([]string, error)
+	DisableTwoFactor(userID uint, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	CreateChallenge(userID uint) (string, time.Time, error)
+	ChallengeUser(challengeToken string) (*domain.User, error)
+	VerifyChallenge(challengeToken string, code string) (*domain.User, error)
+}
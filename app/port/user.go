@@ -1,18 +1,37 @@
 package port
 
 import (
+	"time"
+
 	"github.com/Acova/movie-collection/app/domain"
 )
 
 type UserRepository interface {
 	CreateUser(user *domain.User) error
-	ListUsers() ([]*domain.User, error)
+	ListUsers(search string, pagination Pagination) ([]*domain.User, int64, error)
+	GetUser(id uint) (*domain.User, error)
 	GetUserByEmail(email string) (*domain.User, error)
+	SetUserRole(id uint, role domain.Role) error
+	DisableUser(id uint, disabledAt time.Time) error
+	EnableUser(id uint) error
+	UpdateUser(user *domain.User) error
+	UpdatePassword(id uint, hashedPassword string) error
+	VerifyEmail(id uint, verifiedAt time.Time) error
+	RevokeTokens(id uint, revokedAt time.Time) error
+	DeleteUser(id uint, reassignMoviesTo *uint) error
 }
 
 type UserService interface {
 	CreateUser(user *domain.User) error
-	ListUsers() ([]*domain.User, error)
+	ListUsers(search string, pagination Pagination) ([]*domain.User, int64, error)
 	GetLoginUser(email, password string) (*domain.User, error)
+	GetUser(id uint) (*domain.User, error)
 	GetUserByEmail(email string) (*domain.User, error)
+	SetUserRole(id uint, role domain.Role) error
+	DisableUser(id uint) error
+	EnableUser(id uint) error
+	AuthorizeToken(id uint, issuedAt time.Time) (*domain.User, error)
+	UpdateProfile(user *domain.User) error
+	ChangePassword(id uint, currentPassword, newPassword string) error
+	DeleteAccount(id uint, password string, reassignMoviesTo *uint) error
 }
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+type EmailVerificationRepository interface {
+	CreateVerificationToken(token *domain.EmailVerificationToken) error
+	// UseVerificationToken marks the unused and unexpired token with the given
+	// hash as used, and returns it. It fails with
+	// domain.ErrInvalidVerificationToken when there is no such token.
+	UseVerificationToken(tokenHash string, usedAt time.Time) (*domain.EmailVerificationToken, error)
+	InvalidateVerificationTokens(userID uint, at time.Time) error
+}
+
+type EmailVerificationService interface {
+	SendVerification(user *domain.User) error
+	ResendVerification(userID uint) error
+	VerifyEmail(token string) error
+}
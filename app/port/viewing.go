@@ -0,0 +1,33 @@
+package port
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+)
+
+// ViewingFilter narrows the viewings of a user. WatchedFrom is inclusive and
+// WatchedTo exclusive.
+type ViewingFilter struct {
+	MovieID     *uint
+	WatchedFrom *time.Time
+	WatchedTo   *time.Time
+}
+
+type ViewingRepository interface {
+	CreateViewing(viewing *domain.Viewing) error
+	GetViewing(id uint) (*domain.Viewing, error)
+	UpdateViewing(viewing *domain.Viewing) error
+	DeleteViewing(viewing *domain.Viewing) error
+	ListViewings(userID uint, filter ViewingFilter, pagination Pagination) ([]*domain.Viewing, int64, error)
+	HasViewedBefore(userID uint, movieID uint, before time.Time) (bool, error)
+}
+
+type ViewingService interface {
+	LogViewing(viewing *domain.Viewing) error
+	GetViewing(id uint) (*domain.Viewing, error)
+	UpdateViewing(viewing *domain.Viewing) error
+	DeleteViewing(viewing *domain.Viewing) error
+	ListViewings(userID uint, filter ViewingFilter, pagination Pagination) ([]*domain.Viewing, int64, error)
+	GetDiary(userID uint, year int, month time.Month) ([]*domain.DiaryDay, error)
+}
@@ -0,0 +1,21 @@
+package port
+
+import "github.com/Acova/movie-collection/app/domain"
+
+type WatchlistRepository interface {
+	AddToWatchlist(entry *domain.WatchlistEntry) error
+	GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error)
+	UpdateWatchlistEntry(entry *domain.WatchlistEntry) error
+	RemoveFromWatchlist(entry *domain.WatchlistEntry) error
+	ListWatchlist(userID uint, pagination Pagination) ([]*domain.WatchlistEntry, int64, error)
+	ReorderWatchlist(userID uint, movieIDs []uint) error
+}
+
+type WatchlistService interface {
+	AddToWatchlist(entry *domain.WatchlistEntry) error
+	GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error)
+	UpdateWatchlistEntry(entry *domain.WatchlistEntry) error
+	RemoveFromWatchlist(entry *domain.WatchlistEntry) error
+	ListWatchlist(userID uint, pagination Pagination) ([]*domain.WatchlistEntry, int64, error)
+	ReorderWatchlist(userID uint, movieIDs []uint) error
+}
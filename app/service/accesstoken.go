@@ -0,0 +1,110 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// accessTokenBytes is the amount of randomness in a personal access token.
+	accessTokenBytes = 32
+	// accessTokenUseInterval is how often the last use of a personal access
+	// token is saved, so that busy scripts do not write on every request.
+	accessTokenUseInterval = time.Minute
+)
+
+type AccessTokenService struct {
+	Repo     port.AccessTokenRepository
+	UserRepo port.UserRepository
+}
+
+func NewAccessTokenService(repo port.AccessTokenRepository, userRepo port.UserRepository) *AccessTokenService {
+	return &AccessTokenService{
+		Repo:     repo,
+		UserRepo: userRepo,
+	}
+}
+
+// CreateAccessToken creates a personal access token for a user, limited to the
+// given scopes, and returns it. Only its hash is kept, so the token cannot be
+// shown again. A zero expiresAt creates a token which does not expire.
+func (s *AccessTokenService) CreateAccessToken(userID uint, name string, scopes []domain.Scope, expiresAt time.Time) (*domain.PersonalAccessToken, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+	uniqueScopes := []domain.Scope{}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, "", domain.ErrInvalidScope
+		}
+		if !slices.Contains(uniqueScopes, scope) {
+			uniqueScopes = append(uniqueScopes, scope)
+		}
+	}
+
+	random, err := util.GenerateToken(accessTokenBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	token := domain.AccessTokenPrefix + random
+
+	accessToken := &domain.PersonalAccessToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		TokenHash: util.HashToken(token),
+		Scopes:    uniqueScopes,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.Repo.CreateAccessToken(accessToken); err != nil {
+		return nil, "", err
+	}
+
+	return accessToken, token, nil
+}
+
+// ListAccessTokens returns the personal access tokens of a user which have not
+// been revoked, including the expired ones.
+func (s *AccessTokenService) ListAccessTokens(userID uint) ([]*domain.PersonalAccessToken, error) {
+	return s.Repo.ListAccessTokens(userID)
+}
+
+func (s *AccessTokenService) RevokeAccessToken(userID uint, tokenID uint) error {
+	accessToken, err := s.Repo.GetAccessToken(tokenID)
+	if err != nil || accessToken.UserID != userID || !accessToken.RevokedAt.IsZero() {
+		return domain.ErrAccessTokenNotFound
+	}
+	return s.Repo.RevokeAccessToken(tokenID, time.Now())
+}
+
+// AuthenticateAccessToken returns the user a personal access token acts as,
+// along with the token, provided it can still be used.
+func (s *AccessTokenService) AuthenticateAccessToken(token string) (*domain.User, *domain.PersonalAccessToken, error) {
+	if !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	now := time.Now()
+	accessToken, err := s.Repo.GetAccessTokenByHash(util.HashToken(token))
+	if err != nil || !accessToken.Active(now) {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	user, err := s.UserRepo.GetUser(accessToken.UserID)
+	if err != nil || user.Disabled() {
+		return nil, nil, domain.ErrInvalidAccessToken
+	}
+
+	if now.Sub(accessToken.LastUsedAt) >= accessTokenUseInterval {
+		if err := s.Repo.SetAccessTokenLastUsed(accessToken.ID, now); err != nil {
+			return nil, nil, err
+		}
+		accessToken.LastUsedAt = now
+	}
+
+	return user, accessToken, nil
+}
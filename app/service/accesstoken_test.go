@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+func newTestAccessTokenService() (*AccessTokenService, *mock.MockAccessTokenRepository, *mock.MockUserRepository) {
+	mockRepository := &mock.MockAccessTokenRepository{}
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User"},
+			{ID: 2, Email: "other@test.com", Name: "Other User"},
+		},
+	}
+	return NewAccessTokenService(mockRepository, mockUserRepository), mockRepository, mockUserRepository
+}
+
+func TestCreateAccessToken(t *testing.T) {
+	accessTokenService, mockRepository, _ := newTestAccessTokenService()
+
+	accessToken, token, err := accessTokenService.CreateAccessToken(1, " backup script ", []domain.Scope{domain.ScopeMoviesRead, domain.ScopeListsRead, domain.ScopeMoviesRead}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !strings.HasPrefix(token, domain.AccessTokenPrefix) {
+		t.Errorf("Expected the token to start with %s, got %s", domain.AccessTokenPrefix, token)
+	}
+	if accessToken.UserID != 1 || accessToken.Name != "backup script" || len(accessToken.Scopes) != 2 {
+		t.Errorf("Expected token 'backup script' of user 1 with 2 scopes, got %+v", accessToken)
+	}
+	if len(mockRepository.AccessTokens) != 1 || mockRepository.AccessTokens[0].TokenHash != util.HashToken(token) {
+		t.Errorf("Expected only the hash of the token to be stored")
+	}
+}
+
+func TestCreateAccessTokenRejectsInvalidScopes(t *testing.T) {
+	accessTokenService, mockRepository, _ := newTestAccessTokenService()
+
+	for _, scopes := range [][]domain.Scope{
+		nil,
+		{domain.ScopeMoviesRead, "users:write"},
+	} {
+		if _, _, err := accessTokenService.CreateAccessToken(1, "script", scopes, time.Time{}); !errors.Is(err, domain.ErrInvalidScope) {
+			t.Errorf("Expected ErrInvalidScope for %v, got %v", scopes, err)
+		}
+	}
+	if len(mockRepository.AccessTokens) != 0 {
+		t.Errorf("Expected no token to be stored, got %d", len(mockRepository.AccessTokens))
+	}
+}
+
+func TestAuthenticateAccessToken(t *testing.T) {
+	accessTokenService, mockRepository, _ := newTestAccessTokenService()
+	accessToken, token, _ := accessTokenService.CreateAccessToken(1, "script", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+
+	user, authenticatedToken, err := accessTokenService.AuthenticateAccessToken(token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.ID != 1 || authenticatedToken.ID != accessToken.ID {
+		t.Errorf("Expected token %d of user 1, got token %d of user %d", accessToken.ID, authenticatedToken.ID, user.ID)
+	}
+	if mockRepository.AccessTokens[0].LastUsedAt.IsZero() {
+		t.Errorf("Expected the last use of the token to be saved")
+	}
+
+	for _, invalidToken := range []string{"", "mcpat_unknown", strings.TrimPrefix(token, domain.AccessTokenPrefix)} {
+		if _, _, err := accessTokenService.AuthenticateAccessToken(invalidToken); !errors.Is(err, domain.ErrInvalidAccessToken) {
+			t.Errorf("Expected ErrInvalidAccessToken for '%s', got %v", invalidToken, err)
+		}
+	}
+}
+
+func TestAuthenticateAccessTokenRejectsUnusableTokens(t *testing.T) {
+	accessTokenService, _, mockUserRepository := newTestAccessTokenService()
+	_, expiredToken, _ := accessTokenService.CreateAccessToken(1, "expired", []domain.Scope{domain.ScopeMoviesRead}, time.Now().Add(-time.Minute))
+	revokedAccessToken, revokedToken, _ := accessTokenService.CreateAccessToken(1, "revoked", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	_, disabledUserToken, _ := accessTokenService.CreateAccessToken(2, "disabled", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+
+	accessTokenService.RevokeAccessToken(1, revokedAccessToken.ID)
+	mockUserRepository.Users[1].DisableDate = time.Now()
+
+	for _, token := range []string{expiredToken, revokedToken, disabledUserToken} {
+		if _, _, err := accessTokenService.AuthenticateAccessToken(token); !errors.Is(err, domain.ErrInvalidAccessToken) {
+			t.Errorf("Expected ErrInvalidAccessToken, got %v", err)
+		}
+	}
+}
+
+func TestAccessTokensSurvivePasswordChanges(t *testing.T) {
+	accessTokenService, _, mockUserRepository := newTestAccessTokenService()
+	_, token, _ := accessTokenService.CreateAccessToken(1, "script", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+
+	mockUserRepository.RevokeTokens(1, time.Now())
+
+	if _, _, err := accessTokenService.AuthenticateAccessToken(token); err != nil {
+		t.Errorf("Expected the token to keep working, got %v", err)
+	}
+}
+
+func TestListAndRevokeAccessTokens(t *testing.T) {
+	accessTokenService, _, _ := newTestAccessTokenService()
+	first, _, _ := accessTokenService.CreateAccessToken(1, "first", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	second, _, _ := accessTokenService.CreateAccessToken(1, "second", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+	other, _, _ := accessTokenService.CreateAccessToken(2, "other", []domain.Scope{domain.ScopeMoviesRead}, time.Time{})
+
+	if err := accessTokenService.RevokeAccessToken(1, other.ID); !errors.Is(err, domain.ErrAccessTokenNotFound) {
+		t.Errorf("Expected ErrAccessTokenNotFound for the token of another user, got %v", err)
+	}
+	if err := accessTokenService.RevokeAccessToken(1, first.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := accessTokenService.RevokeAccessToken(1, first.ID); !errors.Is(err, domain.ErrAccessTokenNotFound) {
+		t.Errorf("Expected ErrAccessTokenNotFound for a revoked token, got %v", err)
+	}
+
+	accessTokens, err := accessTokenService.ListAccessTokens(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(accessTokens) != 1 || accessTokens[0].ID != second.ID {
+		t.Errorf("Expected only token %d, got %v", second.ID, accessTokens)
+	}
+}
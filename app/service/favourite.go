@@ -0,0 +1,37 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type FavouritesService struct {
+	Repo port.FavouritesRepository
+}
+
+func NewFavouritesService(repo port.FavouritesRepository) *FavouritesService {
+	return &FavouritesService{
+		Repo: repo,
+	}
+}
+
+func (f *FavouritesService) AddFavourite(favourite *domain.Favourite) error {
+	if favourite.CreatedAt.IsZero() {
+		favourite.CreatedAt = time.Now()
+	}
+	return f.Repo.AddFavourite(favourite)
+}
+
+func (f *FavouritesService) RemoveFavourite(favourite *domain.Favourite) error {
+	return f.Repo.RemoveFavourite(favourite)
+}
+
+func (f *FavouritesService) ListFavourites(userID uint, pagination port.Pagination) ([]*domain.Movie, int64, error) {
+	movies, total, err := f.Repo.ListFavourites(userID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return movies, total, nil
+}
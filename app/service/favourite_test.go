@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestAddFavourite(t *testing.T) {
+	mockRepository := &mock.MockFavouritesRepository{
+		Favourites: []*domain.Favourite{},
+	}
+
+	favouritesService := NewFavouritesService(mockRepository)
+	err := favouritesService.AddFavourite(&domain.Favourite{UserID: 1, MovieID: 2})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Favourites) != 1 {
+		t.Fatalf("Expected 1 favourite in repository, got %d", len(mockRepository.Favourites))
+	}
+	if mockRepository.Favourites[0].CreatedAt.IsZero() {
+		t.Errorf("Expected favourite creation date to be set")
+	}
+}
+
+func TestRemoveFavourite(t *testing.T) {
+	mockRepository := &mock.MockFavouritesRepository{
+		Favourites: []*domain.Favourite{
+			{UserID: 1, MovieID: 2},
+		},
+	}
+
+	favouritesService := NewFavouritesService(mockRepository)
+	err := favouritesService.RemoveFavourite(&domain.Favourite{UserID: 1, MovieID: 2})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Favourites) != 0 {
+		t.Errorf("Expected 0 favourites in repository, got %d", len(mockRepository.Favourites))
+	}
+
+	err = favouritesService.RemoveFavourite(&domain.Favourite{UserID: 1, MovieID: 2})
+	if err == nil {
+		t.Errorf("Expected an error removing a missing favourite")
+	}
+}
+
+func TestListFavourites(t *testing.T) {
+	mockRepository := &mock.MockFavouritesRepository{
+		Favourites: []*domain.Favourite{
+			{UserID: 1, MovieID: 1},
+			{UserID: 2, MovieID: 2},
+			{UserID: 1, MovieID: 3},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+			{ID: 2, Title: "The Matrix"},
+			{ID: 3, Title: "The Godfather"},
+		},
+	}
+
+	favouritesService := NewFavouritesService(mockRepository)
+	movies, total, err := favouritesService.ListFavourites(1, port.Pagination{Page: 1, PageSize: 1})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 favourites in total, got %d", total)
+	}
+	if len(movies) != 1 {
+		t.Fatalf("Expected 1 movie in page, got %d", len(movies))
+	}
+	if movies[0].Title != "Inception" {
+		t.Errorf("Expected movie title 'Inception', got %s", movies[0].Title)
+	}
+}
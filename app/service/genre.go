@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type GenreService struct {
+	Repo port.GenreRepository
+}
+
+func NewGenreService(repo port.GenreRepository) *GenreService {
+	return &GenreService{
+		Repo: repo,
+	}
+}
+
+func (g *GenreService) CreateGenre(genre *domain.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" {
+		return domain.ErrBlankGenreName
+	}
+	return g.Repo.CreateGenre(genre)
+}
+
+func (g *GenreService) ListGenres() ([]*domain.Genre, error) {
+	genres, err := g.Repo.ListGenres()
+	if err != nil {
+		return nil, err
+	}
+	return genres, nil
+}
+
+// ResolveGenres looks up the genres with the given names, ignoring case and
+// duplicates, and fails with a domain.UnknownGenresError if any of them is not
+// in the catalogue.
+func (g *GenreService) ResolveGenres(names []string) ([]domain.Genre, error) {
+	uniqueNames := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		uniqueNames = append(uniqueNames, name)
+	}
+
+	if len(uniqueNames) == 0 {
+		return []domain.Genre{}, nil
+	}
+
+	existingGenres, err := g.Repo.GetGenresByName(uniqueNames)
+	if err != nil {
+		return nil, err
+	}
+
+	genresByName := map[string]*domain.Genre{}
+	for _, genre := range existingGenres {
+		genresByName[strings.ToLower(genre.Name)] = genre
+	}
+
+	genres := []domain.Genre{}
+	unknownNames := []string{}
+	for _, name := range uniqueNames {
+		genre, ok := genresByName[strings.ToLower(name)]
+		if !ok {
+			unknownNames = append(unknownNames, name)
+			continue
+		}
+		genres = append(genres, *genre)
+	}
+
+	if len(unknownNames) > 0 {
+		return nil, &domain.UnknownGenresError{Names: unknownNames}
+	}
+
+	return genres, nil
+}
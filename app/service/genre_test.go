@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestCreateGenre(t *testing.T) {
+	mockRepository := &mock.MockGenreRepository{
+		Genres: []*domain.Genre{},
+	}
+
+	genreService := NewGenreService(mockRepository)
+	err := genreService.CreateGenre(&domain.Genre{Name: " Thriller "})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Genres) != 1 {
+		t.Fatalf("Expected 1 genre in repository, got %d", len(mockRepository.Genres))
+	}
+	if mockRepository.Genres[0].Name != "Thriller" {
+		t.Errorf("Expected genre name 'Thriller', got '%s'", mockRepository.Genres[0].Name)
+	}
+}
+
+func TestCreateGenreWithBlankName(t *testing.T) {
+	mockRepository := &mock.MockGenreRepository{}
+
+	genreService := NewGenreService(mockRepository)
+	if err := genreService.CreateGenre(&domain.Genre{Name: " \t "}); !errors.Is(err, domain.ErrBlankGenreName) {
+		t.Errorf("Expected ErrBlankGenreName, got %v", err)
+	}
+	if len(mockRepository.Genres) != 0 {
+		t.Errorf("Expected no genre in repository, got %d", len(mockRepository.Genres))
+	}
+}
+
+func TestListGenres(t *testing.T) {
+	mockRepository := &mock.MockGenreRepository{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Drama"},
+			{ID: 2, Name: "Sci-Fi"},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", Genres: []domain.Genre{{ID: 1, Name: "Drama"}}},
+			{ID: 2, Title: "Inception", Genres: []domain.Genre{{ID: 1, Name: "Drama"}, {ID: 2, Name: "Sci-Fi"}}},
+		},
+	}
+
+	genreService := NewGenreService(mockRepository)
+	genres, err := genreService.ListGenres()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(genres) != 2 {
+		t.Fatalf("Expected 2 genres, got %d", len(genres))
+	}
+	if genres[0].MovieCount != 2 {
+		t.Errorf("Expected 2 drama movies, got %d", genres[0].MovieCount)
+	}
+	if genres[1].MovieCount != 1 {
+		t.Errorf("Expected 1 sci-fi movie, got %d", genres[1].MovieCount)
+	}
+}
+
+func TestResolveGenres(t *testing.T) {
+	mockRepository := &mock.MockGenreRepository{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Drama"},
+			{ID: 2, Name: "Sci-Fi"},
+		},
+	}
+
+	genreService := NewGenreService(mockRepository)
+	genres, err := genreService.ResolveGenres([]string{"sci-fi", " Drama", "SCI-FI"})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(genres) != 2 {
+		t.Fatalf("Expected 2 genres, got %d", len(genres))
+	}
+	if genres[0].ID != 2 || genres[1].ID != 1 {
+		t.Errorf("Expected genres [2 1], got [%d %d]", genres[0].ID, genres[1].ID)
+	}
+}
+
+func TestResolveUnknownGenres(t *testing.T) {
+	mockRepository := &mock.MockGenreRepository{
+		Genres: []*domain.Genre{
+			{ID: 1, Name: "Drama"},
+		},
+	}
+
+	genreService := NewGenreService(mockRepository)
+	_, err := genreService.ResolveGenres([]string{"Drama", "Sci-Fy"})
+
+	var unknownGenresError *domain.UnknownGenresError
+	if !errors.As(err, &unknownGenresError) {
+		t.Fatalf("Expected an unknown genres error, got %v", err)
+	}
+	if len(unknownGenresError.Names) != 1 || unknownGenresError.Names[0] != "Sci-Fy" {
+		t.Errorf("Expected unknown genres [Sci-Fy], got %v", unknownGenresError.Names)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+// shareTokenBytes is the amount of randomness in the secret share link of a
+// list.
+const shareTokenBytes = 16
+
+type MovieListService struct {
+	Repo port.MovieListRepository
+}
+
+func NewMovieListService(repo port.MovieListRepository) *MovieListService {
+	return &MovieListService{
+		Repo: repo,
+	}
+}
+
+func (m *MovieListService) CreateList(list *domain.MovieList) error {
+	if err := prepareList(list); err != nil {
+		return err
+	}
+
+	token, err := util.GenerateToken(shareTokenBytes)
+	if err != nil {
+		return err
+	}
+	list.ShareToken = token
+
+	return m.Repo.CreateList(list)
+}
+
+func (m *MovieListService) GetList(id uint) (*domain.MovieList, error) {
+	list, err := m.Repo.GetList(id)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetSharedList returns the list behind a secret share link. Private lists
+// cannot be shared, even with their link.
+func (m *MovieListService) GetSharedList(token string) (*domain.MovieList, error) {
+	list, err := m.Repo.GetListByShareToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if list.Visibility == domain.ListVisibilityPrivate {
+		return nil, errors.New("list not found")
+	}
+	return list, nil
+}
+
+func (m *MovieListService) UpdateList(list *domain.MovieList) error {
+	if err := prepareList(list); err != nil {
+		return err
+	}
+	return m.Repo.UpdateList(list)
+}
+
+func (m *MovieListService) DeleteList(list *domain.MovieList) error {
+	return m.Repo.DeleteList(list)
+}
+
+func (m *MovieListService) ListLists(filter port.MovieListFilter, pagination port.Pagination) ([]*domain.MovieList, int64, error) {
+	lists, total, err := m.Repo.ListLists(filter, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return lists, total, nil
+}
+
+// RegenerateShareToken replaces the secret share link of a list, so that the
+// previous one stops working. Only the token is saved, as the list may have
+// been loaded without the entries of deleted movies.
+func (m *MovieListService) RegenerateShareToken(list *domain.MovieList) error {
+	token, err := util.GenerateToken(shareTokenBytes)
+	if err != nil {
+		return err
+	}
+	list.ShareToken = token
+
+	return m.Repo.UpdateShareToken(list)
+}
+
+// prepareList cleans up a list before saving it, and numbers its entries in
+// the order they were given.
+func prepareList(list *domain.MovieList) error {
+	list.Title = strings.TrimSpace(list.Title)
+	list.Description = strings.TrimSpace(list.Description)
+	if list.Visibility == "" {
+		list.Visibility = domain.ListVisibilityPrivate
+	}
+
+	seen := map[uint]bool{}
+	for i := range list.Entries {
+		if seen[list.Entries[i].MovieID] {
+			return domain.ErrDuplicateListEntry
+		}
+		seen[list.Entries[i].MovieID] = true
+
+		list.Entries[i].Position = i + 1
+		list.Entries[i].Notes = strings.TrimSpace(list.Entries[i].Notes)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestCreateList(t *testing.T) {
+	mockRepository := &mock.MockMovieListRepository{}
+
+	listService := NewMovieListService(mockRepository)
+	list := &domain.MovieList{
+		UserID: 1,
+		Title:  "  Comfort movies ",
+		Entries: []domain.MovieListEntry{
+			{MovieID: 3, Notes: " Every Christmas "},
+			{MovieID: 1},
+		},
+	}
+	if err := listService.CreateList(list); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if list.Title != "Comfort movies" {
+		t.Errorf("Expected the title to be trimmed, got '%s'", list.Title)
+	}
+	if list.Visibility != domain.ListVisibilityPrivate {
+		t.Errorf("Expected lists to be private by default, got '%s'", list.Visibility)
+	}
+	if list.ShareToken == "" {
+		t.Errorf("Expected a share token to be generated")
+	}
+	if list.Entries[0].Position != 1 || list.Entries[1].Position != 2 {
+		t.Errorf("Expected entries to be numbered in order, got %+v", list.Entries)
+	}
+	if list.Entries[0].Notes != "Every Christmas" {
+		t.Errorf("Expected the notes to be trimmed, got '%s'", list.Entries[0].Notes)
+	}
+}
+
+func TestCreateListWithDuplicateMovie(t *testing.T) {
+	mockRepository := &mock.MockMovieListRepository{}
+
+	listService := NewMovieListService(mockRepository)
+	list := &domain.MovieList{
+		UserID:  1,
+		Title:   "Comfort movies",
+		Entries: []domain.MovieListEntry{{MovieID: 3}, {MovieID: 3}},
+	}
+	if err := listService.CreateList(list); !errors.Is(err, domain.ErrDuplicateListEntry) {
+		t.Errorf("Expected a duplicate entry error, got %v", err)
+	}
+	if len(mockRepository.Lists) != 0 {
+		t.Errorf("Expected no list to be created, got %d", len(mockRepository.Lists))
+	}
+}
+
+func TestGetSharedList(t *testing.T) {
+	mockRepository := &mock.MockMovieListRepository{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 1, Visibility: domain.ListVisibilityUnlisted, ShareToken: "unlisted"},
+			{ID: 2, UserID: 1, Visibility: domain.ListVisibilityPrivate, ShareToken: "private"},
+		},
+	}
+
+	listService := NewMovieListService(mockRepository)
+	if list, err := listService.GetSharedList("unlisted"); err != nil || list.ID != 1 {
+		t.Errorf("Expected the unlisted list, got %v, %v", list, err)
+	}
+	if _, err := listService.GetSharedList("private"); err == nil {
+		t.Errorf("Expected private lists not to be shared")
+	}
+	if _, err := listService.GetSharedList("unknown"); err == nil {
+		t.Errorf("Expected an error for an unknown token")
+	}
+}
+
+func TestRegenerateShareToken(t *testing.T) {
+	list := &domain.MovieList{ID: 1, UserID: 1, Visibility: domain.ListVisibilityUnlisted, ShareToken: "old"}
+	mockRepository := &mock.MockMovieListRepository{
+		Lists: []*domain.MovieList{list},
+	}
+
+	listService := NewMovieListService(mockRepository)
+	if err := listService.RegenerateShareToken(list); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if list.ShareToken == "old" || list.ShareToken == "" {
+		t.Errorf("Expected a new share token, got '%s'", list.ShareToken)
+	}
+	if _, err := listService.GetSharedList("old"); err == nil {
+		t.Errorf("Expected the old share token to stop working")
+	}
+}
+
+func TestRegenerateShareTokenKeepsEntriesOfDeletedMovies(t *testing.T) {
+	mockRepository := &mock.MockMovieListRepository{
+		Lists: []*domain.MovieList{{
+			ID:         1,
+			UserID:     1,
+			Visibility: domain.ListVisibilityUnlisted,
+			ShareToken: "old",
+			Entries:    []domain.MovieListEntry{{MovieID: 1, Position: 1}, {MovieID: 2, Position: 2}},
+		}},
+		DeletedMovies: []uint{2},
+	}
+
+	listService := NewMovieListService(mockRepository)
+	list, err := listService.GetList(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.Entries) != 1 {
+		t.Fatalf("Expected the entry of the deleted movie to be hidden, got %d entries", len(list.Entries))
+	}
+
+	if err := listService.RegenerateShareToken(list); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved := mockRepository.Lists[0]; saved.ShareToken != list.ShareToken || len(saved.Entries) != 2 {
+		t.Errorf("Expected the new share token saved along with both entries, got '%s' and %d entries", saved.ShareToken, len(saved.Entries))
+	}
+}
+
+func TestListLists(t *testing.T) {
+	mockRepository := &mock.MockMovieListRepository{
+		Lists: []*domain.MovieList{
+			{ID: 1, UserID: 1, Visibility: domain.ListVisibilityPrivate},
+			{ID: 2, UserID: 2, Visibility: domain.ListVisibilityPublic},
+			{ID: 3, UserID: 2, Visibility: domain.ListVisibilityUnlisted},
+			{ID: 4, UserID: 2, Visibility: domain.ListVisibilityPrivate},
+		},
+	}
+
+	listService := NewMovieListService(mockRepository)
+	lists, total, err := listService.ListLists(port.MovieListFilter{VisibleTo: 1}, port.Pagination{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 2 || len(lists) != 2 {
+		t.Errorf("Expected the own and the public list, got %d lists", total)
+	}
+
+	ownerID := uint(2)
+	_, total, _ = listService.ListLists(port.MovieListFilter{VisibleTo: 1, OwnerID: &ownerID}, port.Pagination{})
+	if total != 1 {
+		t.Errorf("Expected only the public list of user 2, got %d lists", total)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+const (
+	// loginFailureWindow is how long without failures it takes for the count
+	// of an email or IP address to start over.
+	loginFailureWindow = time.Hour
+	// loginBackoffBase is how long logins are held back after the first
+	// failure past the free ones. It doubles with every further failure.
+	loginBackoffBase = time.Second
+	// loginLockout is how long logins are refused once there have been too
+	// many failures. It is also the longest backoff.
+	loginLockout = 15 * time.Minute
+)
+
+// loginPolicy is how many failures are allowed before logins are held back,
+// and before they are locked out.
+type loginPolicy struct {
+	keyPrefix       string
+	freeFailures    int
+	lockoutFailures int
+}
+
+var (
+	// accountLoginPolicy applies to each email, whether it has an account or
+	// not, so that throttling does not tell them apart.
+	accountLoginPolicy = loginPolicy{keyPrefix: "email:", freeFailures: 5, lockoutFailures: 10}
+	// ipLoginPolicy applies to each IP address across emails, and is looser
+	// as several users can share an address.
+	ipLoginPolicy = loginPolicy{keyPrefix: "ip:", freeFailures: 20, lockoutFailures: 100}
+)
+
+// retryAt returns when logins are allowed again after the counted failures.
+func (p loginPolicy) retryAt(throttle *domain.LoginThrottle) time.Time {
+	if throttle == nil || throttle.Failures < p.freeFailures {
+		return time.Time{}
+	}
+	if throttle.Failures >= p.lockoutFailures {
+		return throttle.LastFailureAt.Add(loginLockout)
+	}
+
+	backoff := loginLockout
+	if doublings := throttle.Failures - p.freeFailures; doublings < 20 {
+		backoff = min(loginBackoffBase<<doublings, loginLockout)
+	}
+	return throttle.LastFailureAt.Add(backoff)
+}
+
+// loginThrottleKey is a key failed logins are counted under, along with the
+// policy that applies to it.
+type loginThrottleKey struct {
+	policy loginPolicy
+	key    string
+}
+
+func loginThrottleKeys(email string, ipAddress string) []loginThrottleKey {
+	return []loginThrottleKey{
+		{accountLoginPolicy, accountLoginPolicy.keyPrefix + normalizeLoginEmail(email)},
+		{ipLoginPolicy, ipLoginPolicy.keyPrefix + ipAddress},
+	}
+}
+
+type LoginThrottleService struct {
+	Repo port.LoginThrottleRepository
+}
+
+func NewLoginThrottleService(repo port.LoginThrottleRepository) *LoginThrottleService {
+	return &LoginThrottleService{
+		Repo: repo,
+	}
+}
+
+// CheckLogin holds back logins for an email, or from an IP address, with too
+// many recent failures. Refused attempts are audited, but not counted.
+func (s *LoginThrottleService) CheckLogin(email string, ipAddress string, userAgent string) (time.Time, error) {
+	now := time.Now()
+	retryAt := time.Time{}
+	for _, throttleKey := range loginThrottleKeys(email, ipAddress) {
+		throttle, err := s.Repo.GetLoginThrottle(throttleKey.key)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if policyRetryAt := throttleKey.policy.retryAt(throttle); policyRetryAt.After(retryAt) {
+			retryAt = policyRetryAt
+		}
+	}
+
+	if !retryAt.After(now) {
+		return time.Time{}, nil
+	}
+	if err := s.audit(email, ipAddress, userAgent, domain.LoginFailureThrottled, now); err != nil {
+		return time.Time{}, err
+	}
+	return retryAt, domain.ErrTooManyLoginAttempts
+}
+
+// RecordFailure counts a failed login against both its email and IP address,
+// and audits it.
+func (s *LoginThrottleService) RecordFailure(email string, ipAddress string, userAgent string, reason domain.LoginFailureReason) error {
+	now := time.Now()
+	for _, throttleKey := range loginThrottleKeys(email, ipAddress) {
+		if _, err := s.Repo.RecordLoginFailure(throttleKey.key, now, now.Add(-loginFailureWindow)); err != nil {
+			return err
+		}
+	}
+	return s.audit(email, ipAddress, userAgent, reason, now)
+}
+
+// RecordSuccess starts the count of an email over. The count of the IP address
+// is kept, so that logging into an account of their own does not let someone
+// keep guessing the passwords of others.
+func (s *LoginThrottleService) RecordSuccess(email string) error {
+	return s.Repo.ResetLoginThrottle(accountLoginPolicy.keyPrefix + normalizeLoginEmail(email))
+}
+
+func (s *LoginThrottleService) ListLoginFailures(filter port.LoginFailureFilter, pagination port.Pagination) ([]*domain.LoginFailure, int64, error) {
+	filter.Email = normalizeLoginEmail(filter.Email)
+	return s.Repo.ListLoginFailures(filter, pagination)
+}
+
+func (s *LoginThrottleService) audit(email string, ipAddress string, userAgent string, reason domain.LoginFailureReason, at time.Time) error {
+	return s.Repo.CreateLoginFailure(&domain.LoginFailure{
+		Email:     normalizeLoginEmail(email),
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Reason:    reason,
+		CreatedAt: at,
+	})
+}
+
+// normalizeLoginEmail counts failures for an email however it is typed.
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
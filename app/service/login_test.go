@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestCheckLoginBacksOffAndLocksOut(t *testing.T) {
+	mockRepository := &mock.MockLoginThrottleRepository{}
+	loginThrottleService := NewLoginThrottleService(mockRepository)
+
+	for range 4 {
+		loginThrottleService.RecordFailure("Test@Test.com ", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+	if _, err := loginThrottleService.CheckLogin("test@test.com", "127.0.0.2", "curl/8.0"); err != nil {
+		t.Errorf("Expected the first failures to be free, got %v", err)
+	}
+
+	loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	retryAt, err := loginThrottleService.CheckLogin("TEST@test.com", "127.0.0.2", "curl/8.0")
+	if !errors.Is(err, domain.ErrTooManyLoginAttempts) {
+		t.Fatalf("Expected ErrTooManyLoginAttempts, got %v", err)
+	}
+	if backoff := time.Until(retryAt); backoff <= 0 || backoff > time.Second {
+		t.Errorf("Expected to retry within a second, got %v", backoff)
+	}
+
+	for range 4 {
+		loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+	retryAt, _ = loginThrottleService.CheckLogin("test@test.com", "127.0.0.2", "curl/8.0")
+	if backoff := time.Until(retryAt); backoff <= 8*time.Second || backoff > 16*time.Second {
+		t.Errorf("Expected the backoff to double with every failure, got %v", backoff)
+	}
+
+	loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	retryAt, _ = loginThrottleService.CheckLogin("test@test.com", "127.0.0.2", "curl/8.0")
+	if lockout := time.Until(retryAt); lockout <= 14*time.Minute || lockout > 15*time.Minute {
+		t.Errorf("Expected a 15 minutes lockout after 10 failures, got %v", lockout)
+	}
+
+	if _, err := loginThrottleService.CheckLogin("other@test.com", "127.0.0.2", "curl/8.0"); err != nil {
+		t.Errorf("Expected other emails not to be held back, got %v", err)
+	}
+}
+
+func TestCheckLoginThrottlesIPAddresses(t *testing.T) {
+	mockRepository := &mock.MockLoginThrottleRepository{}
+	loginThrottleService := NewLoginThrottleService(mockRepository)
+
+	for i := range 20 {
+		loginThrottleService.RecordFailure(fmt.Sprintf("user%d@test.com", i), "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+
+	if _, err := loginThrottleService.CheckLogin("new@test.com", "127.0.0.1", "curl/8.0"); !errors.Is(err, domain.ErrTooManyLoginAttempts) {
+		t.Errorf("Expected the IP address to be held back, got %v", err)
+	}
+	if _, err := loginThrottleService.CheckLogin("new@test.com", "127.0.0.2", "curl/8.0"); err != nil {
+		t.Errorf("Expected other IP addresses not to be held back, got %v", err)
+	}
+}
+
+func TestCheckLoginStartsOverAfterAWhile(t *testing.T) {
+	mockRepository := &mock.MockLoginThrottleRepository{}
+	loginThrottleService := NewLoginThrottleService(mockRepository)
+
+	for range 10 {
+		loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+	for _, throttle := range mockRepository.Throttles {
+		throttle.LastFailureAt = time.Now().Add(-2 * time.Hour)
+	}
+
+	if _, err := loginThrottleService.CheckLogin("test@test.com", "127.0.0.1", "curl/8.0"); err != nil {
+		t.Errorf("Expected the lockout to be over, got %v", err)
+	}
+	loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	if throttle := mockRepository.Throttles["email:test@test.com"]; throttle.Failures != 1 {
+		t.Errorf("Expected the count to start over, got %d failures", throttle.Failures)
+	}
+}
+
+func TestRecordSuccessOnlyResetsTheEmail(t *testing.T) {
+	mockRepository := &mock.MockLoginThrottleRepository{}
+	loginThrottleService := NewLoginThrottleService(mockRepository)
+
+	for range 5 {
+		loginThrottleService.RecordFailure("test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+	loginThrottleService.RecordSuccess("Test@test.com")
+
+	if _, ok := mockRepository.Throttles["email:test@test.com"]; ok {
+		t.Errorf("Expected the count of the email to start over")
+	}
+	if throttle := mockRepository.Throttles["ip:127.0.0.1"]; throttle == nil || throttle.Failures != 5 {
+		t.Errorf("Expected the count of the IP address to be kept, got %+v", throttle)
+	}
+}
+
+func TestLoginFailuresAreAudited(t *testing.T) {
+	mockRepository := &mock.MockLoginThrottleRepository{}
+	loginThrottleService := NewLoginThrottleService(mockRepository)
+
+	for range 5 {
+		loginThrottleService.RecordFailure("Test@test.com", "127.0.0.1", "curl/8.0", domain.LoginFailureInvalidCredentials)
+	}
+	loginThrottleService.RecordFailure("other@test.com", "127.0.0.2", "curl/8.0", domain.LoginFailureDisabled)
+	loginThrottleService.CheckLogin("test@test.com", "127.0.0.3", "curl/8.0")
+
+	failures, total, err := loginThrottleService.ListLoginFailures(port.LoginFailureFilter{Email: "TEST@test.com"}, port.Pagination{Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 6 || len(failures) != 2 {
+		t.Fatalf("Expected 2 of 6 failures, got %d of %d", len(failures), total)
+	}
+	if failures[0].Reason != domain.LoginFailureThrottled || failures[0].IPAddress != "127.0.0.3" || failures[1].Reason != domain.LoginFailureInvalidCredentials {
+		t.Errorf("Expected the throttled attempt first, then the wrong passwords, got %+v and %+v", failures[0], failures[1])
+	}
+
+	failures, _, _ = loginThrottleService.ListLoginFailures(port.LoginFailureFilter{IPAddress: "127.0.0.2"}, port.Pagination{Page: 1, PageSize: 20})
+	if len(failures) != 1 || failures[0].Email != "other@test.com" || failures[0].Reason != domain.LoginFailureDisabled {
+		t.Errorf("Expected the failure of the disabled account, got %+v", failures)
+	}
+}
@@ -19,8 +19,24 @@ func (m *MovieService) CreateMovie(movie *domain.Movie) error {
 	return m.Repo.CreateMovie(movie)
 }
 
-func (m *MovieService) ListMovies(filters map[string]string) ([]*domain.Movie, error) {
-	movies, err := m.Repo.ListMovies(filters)
+func (m *MovieService) ListMovies(filter port.MovieFilter, pagination port.Pagination, sort port.Sort) ([]*domain.Movie, int64, error) {
+	movies, total, err := m.Repo.ListMovies(filter, pagination, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	return movies, total, nil
+}
+
+func (m *MovieService) SearchMovies(query string, pagination port.Pagination) ([]*domain.MovieSearchResult, int64, error) {
+	results, total, err := m.Repo.SearchMovies(query, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, total, nil
+}
+
+func (m *MovieService) AutocompleteMovies(prefix string, limit int) ([]*domain.Movie, error) {
+	movies, err := m.Repo.AutocompleteMovies(prefix, limit)
 	if err != nil {
 		return nil, err
 	}
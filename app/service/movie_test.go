@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 	"github.com/Acova/movie-collection/app/port/mock"
 )
 
@@ -69,10 +70,13 @@ func TestListMovies(t *testing.T) {
 	}
 
 	movieService := NewMovieService(mockRepository)
-	movies, err := movieService.ListMovies(make(map[string]string))
+	movies, total, err := movieService.ListMovies(port.MovieFilter{}, port.Pagination{}, port.Sort{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
+	if total != 2 {
+		t.Errorf("Expected 2 movies in total, got %d", total)
+	}
 	if len(movies) != 2 {
 		t.Errorf("Expected 2 movies, got %d", len(movies))
 	}
@@ -95,6 +99,106 @@ func TestListMovies(t *testing.T) {
 	}
 }
 
+func TestListMoviesSortedAndPaginated(t *testing.T) {
+	mockRepository := &mock.MockMovieRepository{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", ReleaseYear: 2010},
+			{ID: 2, Title: "The Matrix", ReleaseYear: 1999},
+			{ID: 3, Title: "The Godfather", ReleaseYear: 1972},
+		},
+	}
+
+	movieService := NewMovieService(mockRepository)
+	movies, total, err := movieService.ListMovies(
+		port.MovieFilter{},
+		port.Pagination{Page: 1, PageSize: 2},
+		port.Sort{Field: "release_year", Direction: port.SortAscending},
+	)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Expected 3 movies in total, got %d", total)
+	}
+	if len(movies) != 2 {
+		t.Fatalf("Expected 2 movies, got %d", len(movies))
+	}
+	if movies[0].Title != "The Godfather" || movies[1].Title != "The Matrix" {
+		t.Errorf("Expected 'The Godfather' and 'The Matrix', got %s and %s", movies[0].Title, movies[1].Title)
+	}
+}
+
+func TestListMoviesFiltered(t *testing.T) {
+	mockRepository := &mock.MockMovieRepository{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", ReleaseYear: 2010, Rating: 8.8, UserID: 1},
+			{ID: 2, Title: "The Matrix", ReleaseYear: 1999, Rating: 8.7, UserID: 2},
+			{ID: 3, Title: "The Godfather", ReleaseYear: 1972, Rating: 9.2, UserID: 1},
+		},
+	}
+
+	yearFrom := 1990
+	ratingMin := 8.75
+	movieService := NewMovieService(mockRepository)
+	movies, total, err := movieService.ListMovies(
+		port.MovieFilter{ReleaseYearFrom: &yearFrom, RatingMin: &ratingMin},
+		port.Pagination{},
+		port.Sort{},
+	)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("Expected 1 movie in total, got %d", total)
+	}
+	if movies[0].Title != "Inception" {
+		t.Errorf("Expected movie title 'Inception', got %s", movies[0].Title)
+	}
+}
+
+func TestSearchMovies(t *testing.T) {
+	mockRepository := &mock.MockMovieRepository{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", Synopsis: "A mind-bending thriller"},
+			{ID: 2, Title: "The Matrix", Synopsis: "A sci-fi classic"},
+		},
+	}
+
+	movieService := NewMovieService(mockRepository)
+	results, total, err := movieService.SearchMovies("thrillers", port.Pagination{})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("Expected 1 result in total, got %d", total)
+	}
+	if results[0].Movie.Title != "Inception" {
+		t.Errorf("Expected movie title 'Inception', got %s", results[0].Movie.Title)
+	}
+}
+
+func TestAutocompleteMovies(t *testing.T) {
+	mockRepository := &mock.MockMovieRepository{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+			{ID: 2, Title: "Interstellar"},
+			{ID: 3, Title: "The Matrix"},
+		},
+	}
+
+	movieService := NewMovieService(mockRepository)
+	movies, err := movieService.AutocompleteMovies("Inter", 1)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(movies) != 1 {
+		t.Fatalf("Expected 1 movie, got %d", len(movies))
+	}
+	if movies[0].Title != "Interstellar" {
+		t.Errorf("Expected movie title 'Interstellar', got %s", movies[0].Title)
+	}
+}
+
 func TestGetMovie(t *testing.T) {
 	mockRepository := &mock.MockMovieRepository{
 		Movies: []*domain.Movie{
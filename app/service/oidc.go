@@ -0,0 +1,238 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// oidcStateBytes is the amount of randomness in the state and nonce of a
+	// login.
+	oidcStateBytes = 32
+	// oidcCodeVerifierBytes is the amount of randomness in a PKCE code
+	// verifier, which gives the 43 characters RFC 7636 asks for at least.
+	oidcCodeVerifierBytes = 32
+	// oidcLoginLifetime is how long a user has to sign in with the provider.
+	oidcLoginLifetime = 10 * time.Minute
+	// oidcPasswordBytes is the amount of randomness in the password of users
+	// created by logging in with a provider, which nobody knows.
+	oidcPasswordBytes = 32
+)
+
+type OIDCService struct {
+	Repo            port.OIDCRepository
+	UserRepo        port.UserRepository
+	AccessTokenRepo port.AccessTokenRepository
+	TwoFactorRepo   port.TwoFactorRepository
+	SessionRepo     port.SessionRepository
+	Providers       map[string]port.OIDCProvider
+}
+
+func NewOIDCService(repo port.OIDCRepository, userRepo port.UserRepository, accessTokenRepo port.AccessTokenRepository, twoFactorRepo port.TwoFactorRepository, sessionRepo port.SessionRepository, providers map[string]port.OIDCProvider) *OIDCService {
+	return &OIDCService{
+		Repo:            repo,
+		UserRepo:        userRepo,
+		AccessTokenRepo: accessTokenRepo,
+		TwoFactorRepo:   twoFactorRepo,
+		SessionRepo:     sessionRepo,
+		Providers:       providers,
+	}
+}
+
+func (s *OIDCService) ProviderNames() []string {
+	names := []string{}
+	for name := range s.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin keeps a new login with a provider, and returns where to send the
+// user to sign in there.
+func (s *OIDCService) StartLogin(providerName string) (string, error) {
+	provider, ok := s.Providers[providerName]
+	if !ok {
+		return "", domain.ErrOIDCProviderNotFound
+	}
+
+	state, err := util.GenerateToken(oidcStateBytes)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := util.GenerateToken(oidcStateBytes)
+	if err != nil {
+		return "", err
+	}
+	codeVerifier, err := util.GenerateToken(oidcCodeVerifierBytes)
+	if err != nil {
+		return "", err
+	}
+
+	authorizationURL, err := provider.AuthorizationURL(state, nonce, util.PKCECodeChallenge(codeVerifier))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	loginState := &domain.OIDCLoginState{
+		StateHash:    util.HashToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(oidcLoginLifetime),
+		CreatedAt:    now,
+	}
+	if err := s.Repo.CreateLoginState(loginState); err != nil {
+		return "", err
+	}
+
+	return authorizationURL, nil
+}
+
+// CompleteLogin redeems the code a provider sent the user back with, and
+// returns the user of the subject of the ID token. A subject logging in for the
+// first time is linked to the user with its email, or to a new user, as long as
+// the provider has verified the email.
+func (s *OIDCService) CompleteLogin(providerName string, state string, code string) (*domain.User, error) {
+	provider, ok := s.Providers[providerName]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	now := time.Now()
+	loginState, err := s.Repo.GetLoginState(util.HashToken(state))
+	if err != nil || loginState.Provider != providerName || !loginState.UsedAt.IsZero() || !loginState.ExpiresAt.After(now) {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	if err := s.Repo.UseLoginState(loginState.ID, now); err != nil {
+		return nil, err
+	}
+
+	claims, err := provider.Exchange(code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.identityUser(providerName, claims, now)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+	return user, nil
+}
+
+// identityUser returns the user linked to the subject of the claims, linking
+// it first if it is new.
+func (s *OIDCService) identityUser(providerName string, claims *domain.OIDCClaims, now time.Time) (*domain.User, error) {
+	identity, err := s.Repo.GetIdentity(providerName, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		return s.UserRepo.GetUser(identity.UserID)
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" || !claims.EmailVerified {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.UserRepo.GetUserByEmail(email)
+	if err != nil {
+		user, err = s.createUser(email, claims.Name, now)
+	} else if !user.EmailVerified() {
+		err = s.claimUser(user, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	identity = &domain.OIDCIdentity{
+		UserID:    user.ID,
+		Provider:  providerName,
+		Subject:   claims.Subject,
+		CreatedAt: now,
+	}
+	if err := s.Repo.CreateIdentity(identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser creates a user for an email verified by a provider. Their password
+// is random, so they can only log in with the provider until they reset it.
+func (s *OIDCService) createUser(email string, name string, now time.Time) (*domain.User, error) {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	user := &domain.User{
+		Email:           email,
+		Name:            name,
+		Password:        hashedPassword,
+		Role:            domain.RoleUser,
+		EmailVerifiedAt: now,
+	}
+	if err := s.UserRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// claimUser hands a user whose email was never verified to whoever the provider
+// verified it for. As anyone could have registered with the email, whatever
+// they set up to keep access is removed: the password is replaced, every token
+// and session issued so far is revoked, and two-factor authentication is
+// turned off.
+func (s *OIDCService) claimUser(user *domain.User, now time.Time) error {
+	hashedPassword, err := randomPasswordHash()
+	if err != nil {
+		return err
+	}
+	if err := s.UserRepo.UpdatePassword(user.ID, hashedPassword); err != nil {
+		return err
+	}
+	if err := s.UserRepo.RevokeTokens(user.ID, now); err != nil {
+		return err
+	}
+	if err := s.AccessTokenRepo.RevokeAccessTokens(user.ID, now); err != nil {
+		return err
+	}
+	if err := s.SessionRepo.DeleteSessions(user.ID); err != nil {
+		return err
+	}
+	if err := s.TwoFactorRepo.DeleteTwoFactor(user.ID); err != nil {
+		return err
+	}
+	if err := s.UserRepo.VerifyEmail(user.ID, now); err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	user.TokensRevokedAt = now
+	user.EmailVerifiedAt = now
+	return nil
+}
+
+// randomPasswordHash returns the hash of a random password nobody knows.
+func randomPasswordHash() (string, error) {
+	password, err := util.GenerateToken(oidcPasswordBytes)
+	if err != nil {
+		return "", err
+	}
+	return util.HashPassword(password)
+}
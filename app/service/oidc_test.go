@@ -0,0 +1,203 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func newTestOIDCService() (*OIDCService, *mock.MockOIDCRepository, *mock.MockUserRepository, *mock.MockOIDCProvider) {
+	mockRepository := &mock.MockOIDCRepository{}
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "hash", EmailVerifiedAt: time.Now()},
+			{ID: 2, Email: "unverified@test.com", Name: "Unverified User", Password: "hash"},
+			{ID: 3, Email: "disabled@test.com", Name: "Disabled User", Password: "hash", EmailVerifiedAt: time.Now(), DisableDate: time.Now()},
+		},
+	}
+	mockProvider := &mock.MockOIDCProvider{Code: "code"}
+	oidcService := NewOIDCService(mockRepository, mockUserRepository, &mock.MockAccessTokenRepository{}, &mock.MockTwoFactorRepository{}, &mock.MockSessionRepository{}, map[string]port.OIDCProvider{"company": mockProvider})
+	return oidcService, mockRepository, mockUserRepository, mockProvider
+}
+
+// startTestLogin starts a login with the company provider and returns its
+// state.
+func startTestLogin(t *testing.T, oidcService *OIDCService) string {
+	authorizationURL, err := oidcService.StartLogin("company")
+	if err != nil {
+		t.Fatalf("Failed to start the login: %v", err)
+	}
+	parsedURL, _ := url.Parse(authorizationURL)
+	return parsedURL.Query().Get("state")
+}
+
+func TestStartOIDCLogin(t *testing.T) {
+	oidcService, mockRepository, _, _ := newTestOIDCService()
+
+	if _, err := oidcService.StartLogin("other"); !errors.Is(err, domain.ErrOIDCProviderNotFound) {
+		t.Errorf("Expected %v for an unknown provider, got %v", domain.ErrOIDCProviderNotFound, err)
+	}
+
+	state := startTestLogin(t, oidcService)
+	if len(mockRepository.LoginStates) != 1 {
+		t.Fatalf("Expected the login to be kept, got %d logins", len(mockRepository.LoginStates))
+	}
+	loginState := mockRepository.LoginStates[0]
+	if state == "" || loginState.StateHash == state || loginState.Provider != "company" || loginState.Nonce == "" || len(loginState.CodeVerifier) < 43 {
+		t.Errorf("Expected a hashed state, a nonce and a code verifier for the company provider, got %+v", loginState)
+	}
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name           string
+		claims         *domain.OIDCClaims
+		expectedUserID uint
+		expectedError  error
+	}{
+		{"Existing user", &domain.OIDCClaims{Subject: "1", Email: "test@test.com", EmailVerified: true}, 1, nil},
+		{"Unverified email", &domain.OIDCClaims{Subject: "1", Email: "test@test.com"}, 0, domain.ErrOIDCEmailNotVerified},
+		{"New user", &domain.OIDCClaims{Subject: "4", Email: "new@test.com", EmailVerified: true, Name: "New User"}, 4, nil},
+		{"Disabled user", &domain.OIDCClaims{Subject: "3", Email: "disabled@test.com", EmailVerified: true}, 0, domain.ErrUserDisabled},
+	}
+
+	for _, test := range tests {
+		oidcService, mockRepository, _, mockProvider := newTestOIDCService()
+		mockProvider.Claims = test.claims
+
+		user, err := oidcService.CompleteLogin("company", startTestLogin(t, oidcService), "code")
+		if !errors.Is(err, test.expectedError) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedError, err)
+			continue
+		}
+		if test.expectedError != nil {
+			continue
+		}
+		if user.ID != test.expectedUserID || user.Email != test.claims.Email {
+			t.Errorf("%s: expected user %d, got %+v", test.name, test.expectedUserID, user)
+		}
+		if len(mockRepository.Identities) != 1 || mockRepository.Identities[0].UserID != user.ID || mockRepository.Identities[0].Subject != test.claims.Subject {
+			t.Errorf("%s: expected the subject to be linked to the user, got %v", test.name, mockRepository.Identities)
+		}
+	}
+}
+
+func TestCompleteOIDCLoginCreatesUser(t *testing.T) {
+	oidcService, _, mockUserRepository, mockProvider := newTestOIDCService()
+	mockProvider.Claims = &domain.OIDCClaims{Subject: "4", Email: "new@test.com", EmailVerified: true}
+
+	user, err := oidcService.CompleteLogin("company", startTestLogin(t, oidcService), "code")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.Name != "new" || user.Role != domain.RoleUser || !user.EmailVerified() || user.Password == "" {
+		t.Errorf("Expected a verified user named after their email with a random password, got %+v", user)
+	}
+	if len(mockUserRepository.Users) != 4 {
+		t.Errorf("Expected the user to be created, got %d users", len(mockUserRepository.Users))
+	}
+}
+
+func TestCompleteOIDCLoginUsesLinkedIdentity(t *testing.T) {
+	oidcService, mockRepository, _, mockProvider := newTestOIDCService()
+	mockRepository.Identities = []*domain.OIDCIdentity{{ID: 1, UserID: 1, Provider: "company", Subject: "1"}}
+	mockProvider.Claims = &domain.OIDCClaims{Subject: "1", Email: "renamed@test.com"}
+
+	user, err := oidcService.CompleteLogin("company", startTestLogin(t, oidcService), "code")
+	if err != nil || user.ID != 1 {
+		t.Fatalf("Expected the linked user, got %+v and %v", user, err)
+	}
+	if len(mockRepository.Identities) != 1 {
+		t.Errorf("Expected no new identity, got %d", len(mockRepository.Identities))
+	}
+}
+
+func TestCompleteOIDCLoginClaimsUnverifiedUser(t *testing.T) {
+	oidcService, _, mockUserRepository, mockProvider := newTestOIDCService()
+	mockProvider.Claims = &domain.OIDCClaims{Subject: "2", Email: "unverified@test.com", EmailVerified: true}
+
+	user, err := oidcService.CompleteLogin("company", startTestLogin(t, oidcService), "code")
+	if err != nil || user.ID != 2 {
+		t.Fatalf("Expected the user with the email, got %+v and %v", user, err)
+	}
+
+	storedUser, _ := mockUserRepository.GetUser(2)
+	if storedUser.Password == "hash" || storedUser.TokensRevokedAt.IsZero() || !storedUser.EmailVerified() {
+		t.Errorf("Expected the password to be replaced, the tokens revoked and the email verified, got %+v", storedUser)
+	}
+}
+
+func TestCompleteOIDCLoginClaimRemovesAccessOfSquatter(t *testing.T) {
+	oidcService, _, _, mockProvider := newTestOIDCService()
+	mockAccessTokenRepository := &mock.MockAccessTokenRepository{
+		AccessTokens: []*domain.PersonalAccessToken{
+			{ID: 1, UserID: 2, Name: "Squatter token"},
+			{ID: 2, UserID: 1, Name: "Other token"},
+		},
+	}
+	mockTwoFactorRepository := &mock.MockTwoFactorRepository{
+		TwoFactors:    []*domain.TwoFactor{{UserID: 2, Secret: "secret", EnabledAt: time.Now()}},
+		RecoveryCodes: []*domain.RecoveryCode{{ID: 1, UserID: 2, CodeHash: "code"}},
+		Challenges:    []*domain.TwoFactorChallenge{{ID: 1, UserID: 2, TokenHash: "challenge", ExpiresAt: time.Now().Add(time.Minute)}},
+	}
+	mockSessionRepository := &mock.MockSessionRepository{
+		Sessions:      []*domain.Session{{ID: 1, UserID: 2}, {ID: 2, UserID: 1}},
+		RefreshTokens: []*domain.RefreshToken{{ID: 1, SessionID: 1}, {ID: 2, SessionID: 2}},
+	}
+	oidcService.AccessTokenRepo = mockAccessTokenRepository
+	oidcService.TwoFactorRepo = mockTwoFactorRepository
+	oidcService.SessionRepo = mockSessionRepository
+	mockProvider.Claims = &domain.OIDCClaims{Subject: "2", Email: "unverified@test.com", EmailVerified: true}
+
+	if _, err := oidcService.CompleteLogin("company", startTestLogin(t, oidcService), "code"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if tokens, _ := mockAccessTokenRepository.ListAccessTokens(2); len(tokens) != 0 {
+		t.Errorf("Expected every access token of the user to be revoked, got %d left", len(tokens))
+	}
+	if tokens, _ := mockAccessTokenRepository.ListAccessTokens(1); len(tokens) != 1 {
+		t.Errorf("Expected the access token of another user to be kept, got %d", len(tokens))
+	}
+	if twoFactor, _ := mockTwoFactorRepository.GetTwoFactor(2); twoFactor != nil {
+		t.Errorf("Expected two-factor authentication to be turned off, got %+v", twoFactor)
+	}
+	if len(mockTwoFactorRepository.RecoveryCodes) != 0 || len(mockTwoFactorRepository.Challenges) != 0 {
+		t.Errorf("Expected no recovery codes nor challenges left, got %d and %d", len(mockTwoFactorRepository.RecoveryCodes), len(mockTwoFactorRepository.Challenges))
+	}
+	if len(mockSessionRepository.Sessions) != 1 || mockSessionRepository.Sessions[0].UserID != 1 || len(mockSessionRepository.RefreshTokens) != 1 {
+		t.Errorf("Expected only the session of another user left, got %v", mockSessionRepository.Sessions)
+	}
+}
+
+func TestCompleteOIDCLoginChecksState(t *testing.T) {
+	oidcService, mockRepository, _, mockProvider := newTestOIDCService()
+	mockProvider.Claims = &domain.OIDCClaims{Subject: "1", Email: "test@test.com", EmailVerified: true}
+
+	if _, err := oidcService.CompleteLogin("company", "unknown", "code"); !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("Expected %v for an unknown state, got %v", domain.ErrInvalidOIDCState, err)
+	}
+
+	state := startTestLogin(t, oidcService)
+	if _, err := oidcService.CompleteLogin("company", state, "wrong"); !errors.Is(err, domain.ErrOIDCLoginFailed) {
+		t.Errorf("Expected %v for a rejected code, got %v", domain.ErrOIDCLoginFailed, err)
+	}
+	if _, err := oidcService.CompleteLogin("company", state, "code"); !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("Expected %v for a used state, got %v", domain.ErrInvalidOIDCState, err)
+	}
+
+	state = startTestLogin(t, oidcService)
+	mockRepository.LoginStates[len(mockRepository.LoginStates)-1].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := oidcService.CompleteLogin("company", state, "code"); !errors.Is(err, domain.ErrInvalidOIDCState) {
+		t.Errorf("Expected %v for an expired state, got %v", domain.ErrInvalidOIDCState, err)
+	}
+
+	if _, err := oidcService.CompleteLogin("other", startTestLogin(t, oidcService), "code"); !errors.Is(err, domain.ErrOIDCProviderNotFound) {
+		t.Errorf("Expected %v for an unknown provider, got %v", domain.ErrOIDCProviderNotFound, err)
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// resetTokenBytes is the amount of randomness in a password reset token.
+	resetTokenBytes = 32
+	// resetTokenLifetime is how long a password reset token can be used for.
+	resetTokenLifetime = time.Hour
+)
+
+type PasswordResetService struct {
+	Repo           port.PasswordResetRepository
+	UserRepo       port.UserRepository
+	PasswordPolicy port.PasswordPolicyService
+	Mailer         port.Mailer
+	// ResetURL is the page where users choose their new password. The token
+	// is appended to it as the `token` query parameter.
+	ResetURL string
+}
+
+func NewPasswordResetService(repo port.PasswordResetRepository, userRepo port.UserRepository, passwordPolicy port.PasswordPolicyService, mailer port.Mailer, resetURL string) *PasswordResetService {
+	return &PasswordResetService{
+		Repo:           repo,
+		UserRepo:       userRepo,
+		PasswordPolicy: passwordPolicy,
+		Mailer:         mailer,
+		ResetURL:       resetURL,
+	}
+}
+
+// RequestPasswordReset emails a password reset link to the user with the given
+// email. Unknown emails and disabled users are silently ignored, so that the
+// response does not reveal which emails are registered.
+func (s *PasswordResetService) RequestPasswordReset(email string) error {
+	user, err := s.UserRepo.GetUserByEmail(strings.TrimSpace(email))
+	if err != nil || user == nil || user.ID == 0 || user.Disabled() {
+		return nil
+	}
+
+	token, err := util.GenerateToken(resetTokenBytes)
+	if err != nil {
+		return err
+	}
+
+	resetToken := &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(resetTokenLifetime),
+	}
+	if err := s.Repo.CreateResetToken(resetToken); err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(&domain.Mail{
+		To:      user.Email,
+		Subject: "Reset your Movie Collection password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\n"+
+				"Someone asked to reset the password of your Movie Collection account. "+
+				"To choose a new password, follow this link within the next hour:\n\n"+
+				"%s\n\n"+
+				"If it was not you, you can ignore this email and your password will not change.\n",
+			user.Name, tokenLink(s.ResetURL, token, "Reset token"),
+		),
+	})
+}
+
+// ResetPassword sets a new password for the user the token was issued to,
+// provided it meets the password policy. The token, and any other reset token
+// of the user, cannot be used again, and every token issued to the user until
+// now is revoked. A rejected password leaves the token unused, so that the
+// user can choose another one.
+func (s *PasswordResetService) ResetPassword(token string, newPassword string) error {
+	now := time.Now()
+	tokenHash := util.HashToken(token)
+	resetToken, err := s.Repo.GetResetToken(tokenHash, now)
+	if err != nil {
+		return err
+	}
+	user, err := s.UserRepo.GetUser(resetToken.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.PasswordPolicy.CheckPassword(newPassword, user); err != nil {
+		return err
+	}
+
+	resetToken, err = s.Repo.UseResetToken(tokenHash, now)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := util.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := s.UserRepo.UpdatePassword(resetToken.UserID, hashedPassword); err != nil {
+		return err
+	}
+	if err := s.UserRepo.RevokeTokens(resetToken.UserID, now); err != nil {
+		return err
+	}
+
+	return s.Repo.InvalidateResetTokens(resetToken.UserID, now)
+}
+
+// tokenLink adds the token to baseURL as the `token` query parameter. Without
+// a base URL the token is given on its own, after the label.
+func tokenLink(baseURL string, token string, label string) string {
+	if baseURL == "" {
+		return label + ": " + token
+	}
+
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return baseURL + separator + "token=" + token
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+func TestRequestPasswordReset(t *testing.T) {
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User"},
+			{ID: 2, Email: "disabled@test.com", Name: "Disabled User", DisableDate: time.Now()},
+		},
+	}
+	mockRepository := &mock.MockPasswordResetRepository{}
+	mockMailer := &mock.MockMailer{}
+
+	passwordResetService := NewPasswordResetService(mockRepository, mockUserRepository, &mock.MockPasswordPolicyService{}, mockMailer, "https://movies.test/reset")
+
+	for _, email := range []string{"test@test.com", "unknown@test.com", "disabled@test.com"} {
+		if err := passwordResetService.RequestPasswordReset(email); err != nil {
+			t.Errorf("Expected no error for %s, got %v", email, err)
+		}
+	}
+
+	if len(mockMailer.Sent) != 1 || mockMailer.Sent[0].To != "test@test.com" {
+		t.Fatalf("Expected a single mail to test@test.com, got %v", mockMailer.Sent)
+	}
+	if len(mockRepository.Tokens) != 1 {
+		t.Fatalf("Expected a single reset token, got %d", len(mockRepository.Tokens))
+	}
+
+	resetToken := mockRepository.Tokens[0]
+	if resetToken.UserID != 1 {
+		t.Errorf("Expected the token to be issued to user 1, got %d", resetToken.UserID)
+	}
+	if resetToken.ExpiresAt.Before(time.Now()) || resetToken.ExpiresAt.After(time.Now().Add(resetTokenLifetime)) {
+		t.Errorf("Expected the token to expire within %v, got %v", resetTokenLifetime, resetToken.ExpiresAt)
+	}
+
+	_, link, found := strings.Cut(mockMailer.Sent[0].Body, "https://movies.test/reset?token=")
+	if !found {
+		t.Fatalf("Expected the mail to contain the reset link, got %q", mockMailer.Sent[0].Body)
+	}
+	token := strings.Fields(link)[0]
+	if resetToken.TokenHash != util.HashToken(token) || resetToken.TokenHash == token {
+		t.Errorf("Expected only the hash of the token to be stored")
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "oldhash"},
+		},
+	}
+	mockRepository := &mock.MockPasswordResetRepository{
+		Tokens: []*domain.PasswordResetToken{
+			{ID: 1, UserID: 1, TokenHash: util.HashToken("expired-token"), ExpiresAt: time.Now().Add(-time.Minute)},
+			{ID: 2, UserID: 1, TokenHash: util.HashToken("valid-token"), ExpiresAt: time.Now().Add(time.Hour)},
+			{ID: 3, UserID: 1, TokenHash: util.HashToken("other-token"), ExpiresAt: time.Now().Add(time.Hour)},
+		},
+	}
+
+	passwordResetService := NewPasswordResetService(mockRepository, mockUserRepository, &mock.MockPasswordPolicyService{}, &mock.MockMailer{}, "")
+
+	for _, token := range []string{"unknown-token", "expired-token"} {
+		err := passwordResetService.ResetPassword(token, "newpassword")
+		if !errors.Is(err, domain.ErrInvalidResetToken) {
+			t.Errorf("Expected ErrInvalidResetToken for %s, got %v", token, err)
+		}
+	}
+
+	if err := passwordResetService.ResetPassword("valid-token", "newpassword"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if util.ComparePasswords("newpassword", mockUserRepository.Users[0].Password) != nil {
+		t.Errorf("Expected the new password to be stored hashed")
+	}
+	if mockUserRepository.Users[0].TokensRevokedAt.IsZero() {
+		t.Errorf("Expected the tokens of the user to be revoked")
+	}
+
+	// Both the used token and the other token of the user are now invalid
+	for _, token := range []string{"valid-token", "other-token"} {
+		err := passwordResetService.ResetPassword(token, "otherpassword")
+		if !errors.Is(err, domain.ErrInvalidResetToken) {
+			t.Errorf("Expected ErrInvalidResetToken for %s, got %v", token, err)
+		}
+	}
+}
+
+func TestResetPasswordRejectsPasswordsAgainstThePolicy(t *testing.T) {
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User", Password: "oldhash"},
+		},
+	}
+	mockRepository := &mock.MockPasswordResetRepository{
+		Tokens: []*domain.PasswordResetToken{
+			{ID: 1, UserID: 1, TokenHash: util.HashToken("valid-token"), ExpiresAt: time.Now().Add(time.Hour)},
+		},
+	}
+	mockPasswordPolicy := &mock.MockPasswordPolicyService{
+		Rejections: map[string][]domain.PasswordRejection{
+			"testuser1": {{Code: domain.PasswordContainsName, Message: "password must not contain your name"}},
+		},
+	}
+
+	passwordResetService := NewPasswordResetService(mockRepository, mockUserRepository, mockPasswordPolicy, &mock.MockMailer{}, "")
+
+	if err := passwordResetService.ResetPassword("valid-token", "testuser1"); !errors.Is(err, domain.ErrPasswordRejected) {
+		t.Fatalf("Expected the password to be rejected, got %v", err)
+	}
+	if mockUserRepository.Users[0].Password != "oldhash" {
+		t.Errorf("Expected the password not to change")
+	}
+
+	// The token can still be used with another password
+	if err := passwordResetService.ResetPassword("valid-token", "newpassword"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if util.ComparePasswords("newpassword", mockUserRepository.Users[0].Password) != nil {
+		t.Errorf("Expected the new password to be stored hashed")
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+// minPersonalWordRunes is how long a word of the email or name of a user must
+// be for passwords containing it to be rejected, so that short ones do not
+// rule out too many passwords.
+const minPersonalWordRunes = 4
+
+// PasswordPolicyService checks the passwords users choose against a password
+// policy, and against a dataset of breached passwords when Breaches is set.
+type PasswordPolicyService struct {
+	Policy   domain.PasswordPolicy
+	Breaches port.BreachedPasswordRepository
+}
+
+func NewPasswordPolicyService(policy domain.PasswordPolicy, breaches port.BreachedPasswordRepository) *PasswordPolicyService {
+	return &PasswordPolicyService{
+		Policy:   policy,
+		Breaches: breaches,
+	}
+}
+
+// CheckPassword fails with a domain.PasswordPolicyError giving every reason the
+// password does not meet the policy, except for passwords too long, which are
+// not looked at further.
+func (s *PasswordPolicyService) CheckPassword(password string, user *domain.User) error {
+	length := utf8.RuneCountInString(password)
+	if s.Policy.MaxLength > 0 && length > s.Policy.MaxLength {
+		return &domain.PasswordPolicyError{Rejections: []domain.PasswordRejection{{
+			Code:    domain.PasswordTooLong,
+			Message: fmt.Sprintf("password must be at most %d characters long", s.Policy.MaxLength),
+		}}}
+	}
+	if s.Policy.MaxBytes > 0 && len(password) > s.Policy.MaxBytes {
+		return &domain.PasswordPolicyError{Rejections: []domain.PasswordRejection{{
+			Code:    domain.PasswordTooLong,
+			Message: fmt.Sprintf("password must be at most %d bytes long, which is fewer characters with accents or symbols", s.Policy.MaxBytes),
+		}}}
+	}
+
+	var rejections []domain.PasswordRejection
+	if length < s.Policy.MinLength {
+		rejections = append(rejections, domain.PasswordRejection{
+			Code:    domain.PasswordTooShort,
+			Message: fmt.Sprintf("password must be at least %d characters long", s.Policy.MinLength),
+		})
+	}
+
+	// Passwords containing the whole email contain its local part too, while
+	// the words of its domain are left to the strength estimate
+	lowerPassword := strings.ToLower(password)
+	emailLocalPart, _, _ := strings.Cut(user.Email, "@")
+	if containsPersonalWord(lowerPassword, emailLocalPart) {
+		rejections = append(rejections, domain.PasswordRejection{
+			Code:    domain.PasswordContainsEmail,
+			Message: "password must not contain your email",
+		})
+	}
+	if containsPersonalWord(lowerPassword, user.Name) {
+		rejections = append(rejections, domain.PasswordRejection{
+			Code:    domain.PasswordContainsName,
+			Message: "password must not contain your name",
+		})
+	}
+
+	if util.PasswordStrength(password, user.Email, user.Name) < s.Policy.MinStrength {
+		rejections = append(rejections, domain.PasswordRejection{
+			Code:    domain.PasswordTooWeak,
+			Message: "password is too easy to guess, make it longer or less predictable",
+		})
+	}
+
+	breaches, err := s.breachCount(password)
+	if err != nil {
+		return err
+	}
+	if breaches > 0 {
+		rejections = append(rejections, domain.PasswordRejection{
+			Code:    domain.PasswordBreached,
+			Message: "password has appeared in a data breach, choose another one",
+		})
+	}
+
+	if len(rejections) > 0 {
+		return &domain.PasswordPolicyError{Rejections: rejections}
+	}
+	return nil
+}
+
+// breachCount returns how many times the password was seen in breaches. The
+// dataset is only given the first 5 characters of the SHA-1 hash of the
+// password, and the rest of it is looked for among the hashes it returns.
+func (s *PasswordPolicyService) breachCount(password string) (int, error) {
+	if s.Breaches == nil {
+		return 0, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := s.Breaches.GetBreachedHashSuffixes(hash[:5])
+	if err != nil {
+		return 0, err
+	}
+	return suffixes[hash[5:]], nil
+}
+
+// containsPersonalWord reports whether a lower case password contains a piece
+// of personal information, whole or any word of it long enough.
+func containsPersonalWord(lowerPassword string, information string) bool {
+	information = strings.ToLower(strings.TrimSpace(information))
+	words := strings.FieldsFunc(information, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range append(words, information) {
+		if utf8.RuneCountInString(word) >= minPersonalWordRunes && strings.Contains(lowerPassword, word) {
+			return true
+		}
+	}
+	return false
+}
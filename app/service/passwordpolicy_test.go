@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func rejectionCodes(err error) []domain.PasswordRejectionCode {
+	var policyError *domain.PasswordPolicyError
+	if !errors.As(err, &policyError) {
+		return nil
+	}
+	codes := make([]domain.PasswordRejectionCode, len(policyError.Rejections))
+	for i, rejection := range policyError.Rejections {
+		codes[i] = rejection.Code
+	}
+	return codes
+}
+
+func TestCheckPassword(t *testing.T) {
+	user := &domain.User{Email: "alice.smith@example.com", Name: "Alice Wonder"}
+	passwordPolicyService := NewPasswordPolicyService(domain.DefaultPasswordPolicy(), nil)
+
+	for _, testCase := range []struct {
+		password      string
+		expectedCodes []domain.PasswordRejectionCode
+	}{
+		{"correct horse battery staple", nil},
+		{"r9#Lk2!vQz@8", nil},
+		{"Qx7#", []domain.PasswordRejectionCode{domain.PasswordTooShort, domain.PasswordTooWeak}},
+		{"password", []domain.PasswordRejectionCode{domain.PasswordTooWeak}},
+		{"qwertyuiop", []domain.PasswordRejectionCode{domain.PasswordTooWeak}},
+		{"my-SMITH-horse-battery", []domain.PasswordRejectionCode{domain.PasswordContainsEmail}},
+		{"wonderland staple battery", []domain.PasswordRejectionCode{domain.PasswordContainsName}},
+		{"alice.smith@example.com", []domain.PasswordRejectionCode{domain.PasswordContainsEmail, domain.PasswordContainsName, domain.PasswordTooWeak}},
+		{strings.Repeat("x", 129), []domain.PasswordRejectionCode{domain.PasswordTooLong}},
+	} {
+		err := passwordPolicyService.CheckPassword(testCase.password, user)
+		if testCase.expectedCodes == nil {
+			if err != nil {
+				t.Errorf("Expected %q to be accepted, got %v", testCase.password, err)
+			}
+			continue
+		}
+		if !errors.Is(err, domain.ErrPasswordRejected) {
+			t.Errorf("Expected %q to be rejected, got %v", testCase.password, err)
+		}
+		if codes := rejectionCodes(err); !slices.Equal(codes, testCase.expectedCodes) {
+			t.Errorf("Expected %q to be rejected with %v, got %v", testCase.password, testCase.expectedCodes, codes)
+		}
+	}
+}
+
+func TestCheckPasswordFollowsThePolicy(t *testing.T) {
+	user := &domain.User{Email: "test@test.com", Name: "Test User"}
+	passwordPolicyService := NewPasswordPolicyService(domain.PasswordPolicy{MinLength: 4, MaxLength: 10, MinStrength: 0}, nil)
+
+	if err := passwordPolicyService.CheckPassword("password", user); err != nil {
+		t.Errorf("Expected any password to be strong enough, got %v", err)
+	}
+	if codes := rejectionCodes(passwordPolicyService.CheckPassword("abc", user)); !slices.Equal(codes, []domain.PasswordRejectionCode{domain.PasswordTooShort}) {
+		t.Errorf("Expected a short password to be rejected as too short, got %v", codes)
+	}
+	if codes := rejectionCodes(passwordPolicyService.CheckPassword("abcdefghijk", user)); !slices.Equal(codes, []domain.PasswordRejectionCode{domain.PasswordTooLong}) {
+		t.Errorf("Expected a long password to be rejected as too long, got %v", codes)
+	}
+
+	// "é" is two bytes long in UTF-8
+	passwordPolicyService.Policy.MaxBytes = 8
+	if err := passwordPolicyService.CheckPassword("abcdefgh", user); err != nil {
+		t.Errorf("Expected a password within the byte limit to be accepted, got %v", err)
+	}
+	if codes := rejectionCodes(passwordPolicyService.CheckPassword("abcdéfgh", user)); !slices.Equal(codes, []domain.PasswordRejectionCode{domain.PasswordTooLong}) {
+		t.Errorf("Expected a password over the byte limit to be rejected as too long, got %v", codes)
+	}
+}
+
+func TestCheckPasswordAgainstBreaches(t *testing.T) {
+	// The SHA-1 hash of "correct horse battery staple" is
+	// ABF7AAD6438836DBE526AA231ABDE2D0EEF74D42
+	mockBreaches := &mock.MockBreachedPasswordRepository{
+		Suffixes: map[string]map[string]int{
+			"ABF7A": {
+				"AD6438836DBE526AA231ABDE2D0EEF74D42": 384,
+				"00000000000000000000000000000000000": 2,
+			},
+		},
+	}
+	user := &domain.User{Email: "test@test.com", Name: "Test User"}
+	passwordPolicyService := NewPasswordPolicyService(domain.DefaultPasswordPolicy(), mockBreaches)
+
+	err := passwordPolicyService.CheckPassword("correct horse battery staple", user)
+	if codes := rejectionCodes(err); !slices.Equal(codes, []domain.PasswordRejectionCode{domain.PasswordBreached}) {
+		t.Errorf("Expected the password to be rejected as breached, got %v", err)
+	}
+	if err := passwordPolicyService.CheckPassword("r9#Lk2!vQz@8", user); err != nil {
+		t.Errorf("Expected a password not in the dataset to be accepted, got %v", err)
+	}
+
+	// Only hash prefixes are looked up
+	if !slices.Equal(mockBreaches.Prefixes, []string{"ABF7A", "577EC"}) {
+		t.Errorf("Expected only the hash prefixes to be looked up, got %v", mockBreaches.Prefixes)
+	}
+}
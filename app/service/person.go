@@ -0,0 +1,84 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type PersonService struct {
+	Repo port.PersonRepository
+}
+
+func NewPersonService(repo port.PersonRepository) *PersonService {
+	return &PersonService{
+		Repo: repo,
+	}
+}
+
+func (p *PersonService) CreatePerson(person *domain.Person) error {
+	person.Name = strings.TrimSpace(person.Name)
+	return p.Repo.CreatePerson(person)
+}
+
+func (p *PersonService) ListPeople(name string, pagination port.Pagination) ([]*domain.Person, int64, error) {
+	people, total, err := p.Repo.ListPeople(name, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return people, total, nil
+}
+
+func (p *PersonService) GetPerson(id uint) (*domain.Person, error) {
+	person, err := p.Repo.GetPerson(id)
+	if err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+func (p *PersonService) UpdatePerson(person *domain.Person) error {
+	person.Name = strings.TrimSpace(person.Name)
+	return p.Repo.UpdatePerson(person)
+}
+
+func (p *PersonService) DeletePerson(person *domain.Person) error {
+	return p.Repo.DeletePerson(person)
+}
+
+func (p *PersonService) CreateCredit(credit *domain.MovieCredit) error {
+	// Only actors play a character.
+	if credit.Role != domain.CreditRoleActor {
+		credit.Character = ""
+	}
+	return p.Repo.CreateCredit(credit)
+}
+
+func (p *PersonService) GetCredit(id uint) (*domain.MovieCredit, error) {
+	credit, err := p.Repo.GetCredit(id)
+	if err != nil {
+		return nil, err
+	}
+	return credit, nil
+}
+
+func (p *PersonService) DeleteCredit(credit *domain.MovieCredit) error {
+	return p.Repo.DeleteCredit(credit)
+}
+
+func (p *PersonService) ListMovieCredits(movieID uint) ([]*domain.MovieCredit, error) {
+	credits, err := p.Repo.ListMovieCredits(movieID)
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+func (p *PersonService) ListPersonCredits(personID uint) ([]*domain.MovieCredit, error) {
+	credits, err := p.Repo.ListPersonCredits(personID)
+	if err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
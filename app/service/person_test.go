@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestCreatePerson(t *testing.T) {
+	mockRepository := &mock.MockPersonRepository{}
+
+	personService := NewPersonService(mockRepository)
+	err := personService.CreatePerson(&domain.Person{Name: " Al Pacino "})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.People) != 1 {
+		t.Fatalf("Expected 1 person in repository, got %d", len(mockRepository.People))
+	}
+	if mockRepository.People[0].Name != "Al Pacino" {
+		t.Errorf("Expected person name 'Al Pacino', got '%s'", mockRepository.People[0].Name)
+	}
+}
+
+func TestListPeople(t *testing.T) {
+	mockRepository := &mock.MockPersonRepository{
+		People: []*domain.Person{
+			{ID: 1, Name: "Marlon Brando"},
+			{ID: 2, Name: "Al Pacino"},
+			{ID: 3, Name: "Francis Ford Coppola"},
+		},
+	}
+
+	personService := NewPersonService(mockRepository)
+	people, total, err := personService.ListPeople("o", port.Pagination{Page: 1, PageSize: 2})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 3 {
+		t.Errorf("Expected 3 people in total, got %d", total)
+	}
+	if len(people) != 2 {
+		t.Fatalf("Expected 2 people, got %d", len(people))
+	}
+	if people[0].Name != "Al Pacino" {
+		t.Errorf("Expected people sorted by name, got '%s' first", people[0].Name)
+	}
+}
+
+func TestDeletePersonWithCredits(t *testing.T) {
+	mockRepository := &mock.MockPersonRepository{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+			{ID: 2, Name: "Marlon Brando"},
+			{ID: 3, Name: "Robert Duvall"},
+		},
+		Credits: []*domain.MovieCredit{
+			{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor},
+			{ID: 2, MovieID: 2, PersonID: 3, Role: domain.CreditRoleActor},
+		},
+		// Movie 2 is deleted
+		Movies: []*domain.Movie{{ID: 1, Title: "The Godfather"}},
+	}
+
+	personService := NewPersonService(mockRepository)
+	if err := personService.DeletePerson(&domain.Person{ID: 1}); !errors.Is(err, domain.ErrPersonHasCredits) {
+		t.Errorf("Expected ErrPersonHasCredits, got %v", err)
+	}
+	if err := personService.DeletePerson(&domain.Person{ID: 2}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := personService.DeletePerson(&domain.Person{ID: 3}); err != nil {
+		t.Errorf("Expected no error for a person only credited in deleted movies, got %v", err)
+	}
+	if len(mockRepository.People) != 1 || mockRepository.People[0].ID != 1 {
+		t.Errorf("Expected only the credited person left, got %v", mockRepository.People)
+	}
+	if len(mockRepository.Credits) != 1 || mockRepository.Credits[0].PersonID != 1 {
+		t.Errorf("Expected only the credit in a movie that is not deleted to be kept, got %v", mockRepository.Credits)
+	}
+}
+
+func TestCreateCreditDropsCharacterForCrew(t *testing.T) {
+	mockRepository := &mock.MockPersonRepository{}
+
+	personService := NewPersonService(mockRepository)
+	err := personService.CreateCredit(&domain.MovieCredit{
+		MovieID:   1,
+		PersonID:  1,
+		Role:      domain.CreditRoleDirector,
+		Character: "Michael Corleone",
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Credits) != 1 {
+		t.Fatalf("Expected 1 credit in repository, got %d", len(mockRepository.Credits))
+	}
+	if mockRepository.Credits[0].Character != "" {
+		t.Errorf("Expected no character for a director, got '%s'", mockRepository.Credits[0].Character)
+	}
+}
+
+func TestListPersonCredits(t *testing.T) {
+	mockRepository := &mock.MockPersonRepository{
+		People: []*domain.Person{
+			{ID: 1, Name: "Al Pacino"},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "The Godfather", ReleaseYear: 1972},
+			{ID: 2, Title: "Heat", ReleaseYear: 1995},
+		},
+		Credits: []*domain.MovieCredit{
+			{ID: 1, MovieID: 1, PersonID: 1, Role: domain.CreditRoleActor, Character: "Michael Corleone"},
+			{ID: 2, MovieID: 2, PersonID: 1, Role: domain.CreditRoleActor, Character: "Vincent Hanna"},
+		},
+	}
+
+	personService := NewPersonService(mockRepository)
+	credits, err := personService.ListPersonCredits(1)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(credits) != 2 {
+		t.Fatalf("Expected 2 credits, got %d", len(credits))
+	}
+	if credits[0].Movie.Title != "Heat" {
+		t.Errorf("Expected the newest movie first, got '%s'", credits[0].Movie.Title)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type ReviewService struct {
+	Repo port.ReviewRepository
+}
+
+func NewReviewService(repo port.ReviewRepository) *ReviewService {
+	return &ReviewService{
+		Repo: repo,
+	}
+}
+
+func (r *ReviewService) CreateReview(review *domain.Review) error {
+	review.Text = strings.TrimSpace(review.Text)
+	return r.Repo.CreateReview(review)
+}
+
+func (r *ReviewService) GetReview(movieID uint, userID uint) (*domain.Review, error) {
+	review, err := r.Repo.GetReview(movieID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *ReviewService) UpdateReview(review *domain.Review) error {
+	review.Text = strings.TrimSpace(review.Text)
+	return r.Repo.UpdateReview(review)
+}
+
+func (r *ReviewService) DeleteReview(review *domain.Review) error {
+	return r.Repo.DeleteReview(review)
+}
+
+func (r *ReviewService) ListMovieReviews(movieID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews, total, err := r.Repo.ListMovieReviews(movieID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *ReviewService) ListUserReviews(userID uint, pagination port.Pagination) ([]*domain.Review, int64, error) {
+	reviews, total, err := r.Repo.ListUserReviews(userID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
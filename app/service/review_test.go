@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestCreateReview(t *testing.T) {
+	mockRepository := &mock.MockReviewRepository{
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+		},
+	}
+
+	reviewService := NewReviewService(mockRepository)
+	err := reviewService.CreateReview(&domain.Review{UserID: 1, MovieID: 1, Score: 8, Text: " Loved it "})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	err = reviewService.CreateReview(&domain.Review{UserID: 2, MovieID: 1, Score: 6})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Reviews) != 2 {
+		t.Fatalf("Expected 2 reviews in repository, got %d", len(mockRepository.Reviews))
+	}
+	if mockRepository.Reviews[0].Text != "Loved it" {
+		t.Errorf("Expected review text 'Loved it', got '%s'", mockRepository.Reviews[0].Text)
+	}
+	if mockRepository.Movies[0].ReviewCount != 2 || mockRepository.Movies[0].AverageScore != 7 {
+		t.Errorf("Expected 2 reviews averaging 7, got %d averaging %f", mockRepository.Movies[0].ReviewCount, mockRepository.Movies[0].AverageScore)
+	}
+}
+
+func TestCreateReviewTwice(t *testing.T) {
+	mockRepository := &mock.MockReviewRepository{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 1, MovieID: 1, Score: 8},
+		},
+	}
+
+	reviewService := NewReviewService(mockRepository)
+	err := reviewService.CreateReview(&domain.Review{UserID: 1, MovieID: 1, Score: 5})
+	if err == nil {
+		t.Errorf("Expected an error when reviewing a movie twice")
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	mockRepository := &mock.MockReviewRepository{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 1, MovieID: 1, Score: 8},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception", ReviewCount: 1, AverageScore: 8},
+		},
+	}
+
+	reviewService := NewReviewService(mockRepository)
+	if err := reviewService.DeleteReview(&domain.Review{ID: 1, MovieID: 1}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Reviews) != 0 {
+		t.Errorf("Expected no reviews left, got %d", len(mockRepository.Reviews))
+	}
+	if mockRepository.Movies[0].ReviewCount != 0 || mockRepository.Movies[0].AverageScore != 0 {
+		t.Errorf("Expected no review statistics, got %d averaging %f", mockRepository.Movies[0].ReviewCount, mockRepository.Movies[0].AverageScore)
+	}
+}
+
+func TestListUserReviews(t *testing.T) {
+	mockRepository := &mock.MockReviewRepository{
+		Reviews: []*domain.Review{
+			{ID: 1, UserID: 1, MovieID: 1, Score: 8},
+			{ID: 2, UserID: 2, MovieID: 1, Score: 6},
+			{ID: 3, UserID: 1, MovieID: 2, Score: 9},
+		},
+		Movies: []*domain.Movie{
+			{ID: 1, Title: "Inception"},
+			{ID: 2, Title: "The Godfather"},
+		},
+	}
+
+	reviewService := NewReviewService(mockRepository)
+	reviews, total, err := reviewService.ListUserReviews(1, port.Pagination{Page: 1, PageSize: 20})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 2 || len(reviews) != 2 {
+		t.Fatalf("Expected 2 reviews, got %d", len(reviews))
+	}
+	if reviews[0].Movie == nil || reviews[0].Movie.Title != "The Godfather" {
+		t.Errorf("Expected the newest review first, got %v", reviews[0].Movie)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// refreshTokenBytes is the amount of randomness in a refresh token.
+	refreshTokenBytes = 32
+	// sessionLifetime is how long a session lasts without being used. Every
+	// refresh extends it.
+	sessionLifetime = 30 * 24 * time.Hour
+)
+
+type SessionService struct {
+	Repo     port.SessionRepository
+	UserRepo port.UserRepository
+}
+
+func NewSessionService(repo port.SessionRepository, userRepo port.UserRepository) *SessionService {
+	return &SessionService{
+		Repo:     repo,
+		UserRepo: userRepo,
+	}
+}
+
+// CreateSession starts a session for a user who just logged in, and returns
+// its first refresh token.
+func (s *SessionService) CreateSession(userID uint, userAgent string, ipAddress string) (*domain.Session, string, error) {
+	token, err := util.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &domain.Session{
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(sessionLifetime),
+	}
+	refreshToken := &domain.RefreshToken{TokenHash: util.HashToken(token), CreatedAt: now}
+	if err := s.Repo.CreateSession(session, refreshToken); err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+// RefreshSession exchanges a refresh token for the next one of its session.
+// Using a refresh token twice revokes its whole session, as one of the uses
+// was not from its owner. Sessions started before the tokens of their user
+// were revoked cannot be refreshed either.
+func (s *SessionService) RefreshSession(refreshToken string, userAgent string, ipAddress string) (*domain.Session, string, error) {
+	now := time.Now()
+	usedToken, err := s.Repo.GetRefreshToken(util.HashToken(refreshToken))
+	if err != nil {
+		return nil, "", domain.ErrInvalidRefreshToken
+	}
+
+	session, err := s.Repo.GetSession(usedToken.SessionID)
+	if err != nil || !session.Active(now) {
+		return nil, "", domain.ErrInvalidRefreshToken
+	}
+
+	if !usedToken.UsedAt.IsZero() {
+		return nil, "", s.revokeReusedSession(session.ID, now)
+	}
+
+	user, err := s.UserRepo.GetUser(session.UserID)
+	if err != nil || user.Disabled() || session.CreatedAt.Before(user.TokensRevokedAt.Truncate(time.Second)) {
+		return nil, "", domain.ErrInvalidRefreshToken
+	}
+
+	token, err := util.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	session.UserAgent = userAgent
+	session.IPAddress = ipAddress
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(sessionLifetime)
+	nextToken := &domain.RefreshToken{SessionID: session.ID, TokenHash: util.HashToken(token), CreatedAt: now}
+
+	err = s.Repo.RotateRefreshToken(usedToken.ID, nextToken, session)
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		// Another request used the token at the same time
+		return nil, "", s.revokeReusedSession(session.ID, now)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+func (s *SessionService) revokeReusedSession(sessionID uint, now time.Time) error {
+	if err := s.Repo.RevokeSession(sessionID, now); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
+}
+
+// ListSessions returns the sessions of a user which can still be used, leaving
+// out the ones started before the tokens of the user were revoked.
+func (s *SessionService) ListSessions(userID uint) ([]*domain.Session, error) {
+	user, err := s.UserRepo.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.Repo.ListActiveSessions(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	activeSessions := []*domain.Session{}
+	for _, session := range sessions {
+		if !session.CreatedAt.Before(user.TokensRevokedAt.Truncate(time.Second)) {
+			activeSessions = append(activeSessions, session)
+		}
+	}
+	return activeSessions, nil
+}
+
+// RevokeSession ends a session of a user. Neither its refresh tokens nor the
+// access tokens issued for it can be used anymore.
+func (s *SessionService) RevokeSession(userID uint, sessionID uint) error {
+	session, err := s.Repo.GetSession(sessionID)
+	if err != nil || session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+	if !session.RevokedAt.IsZero() {
+		return nil
+	}
+	return s.Repo.RevokeSession(sessionID, time.Now())
+}
+
+// CheckSession fails with domain.ErrSessionRevoked when the session an access
+// token was issued for can no longer be used. Access tokens issued before
+// sessions existed have no session, and are always accepted.
+func (s *SessionService) CheckSession(sessionID uint) error {
+	if sessionID == 0 {
+		return nil
+	}
+
+	session, err := s.Repo.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if !session.Active(time.Now()) {
+		return domain.ErrSessionRevoked
+	}
+	return nil
+}
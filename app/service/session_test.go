@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+func newTestSessionService() (*SessionService, *mock.MockSessionRepository, *mock.MockUserRepository) {
+	mockRepository := &mock.MockSessionRepository{}
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User"},
+			{ID: 2, Email: "other@test.com", Name: "Other User"},
+		},
+	}
+	return NewSessionService(mockRepository, mockUserRepository), mockRepository, mockUserRepository
+}
+
+func TestCreateSession(t *testing.T) {
+	sessionService, mockRepository, _ := newTestSessionService()
+
+	session, refreshToken, err := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if session.UserID != 1 || session.UserAgent != "curl/8.0" || session.IPAddress != "127.0.0.1" || !session.Active(time.Now()) {
+		t.Errorf("Expected an active session of user 1 from curl at 127.0.0.1, got %+v", session)
+	}
+	if len(mockRepository.RefreshTokens) != 1 || mockRepository.RefreshTokens[0].TokenHash != util.HashToken(refreshToken) {
+		t.Errorf("Expected only the hash of the refresh token to be stored")
+	}
+}
+
+func TestRefreshSessionRotatesTokens(t *testing.T) {
+	sessionService, _, _ := newTestSessionService()
+	session, firstToken, _ := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+
+	refreshedSession, secondToken, err := sessionService.RefreshSession(firstToken, "curl/8.1", "127.0.0.2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if refreshedSession.ID != session.ID || refreshedSession.UserAgent != "curl/8.1" || refreshedSession.IPAddress != "127.0.0.2" {
+		t.Errorf("Expected session %d from curl/8.1 at 127.0.0.2, got %+v", session.ID, refreshedSession)
+	}
+	if secondToken == firstToken {
+		t.Errorf("Expected a new refresh token")
+	}
+
+	if _, _, err := sessionService.RefreshSession(secondToken, "curl/8.1", "127.0.0.2"); err != nil {
+		t.Errorf("Expected the new refresh token to work, got %v", err)
+	}
+	if _, _, err := sessionService.RefreshSession("unknown-token", "curl/8.1", "127.0.0.2"); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+	}
+}
+
+func TestRefreshSessionReuseRevokesSession(t *testing.T) {
+	sessionService, _, _ := newTestSessionService()
+	session, firstToken, _ := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	otherSession, otherToken, _ := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	_, secondToken, _ := sessionService.RefreshSession(firstToken, "curl/8.0", "127.0.0.1")
+
+	if _, _, err := sessionService.RefreshSession(firstToken, "curl/8.0", "127.0.0.1"); !errors.Is(err, domain.ErrRefreshTokenReused) {
+		t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	// The whole family of the reused token is revoked
+	if _, _, err := sessionService.RefreshSession(secondToken, "curl/8.0", "127.0.0.1"); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken for the latest token of the session, got %v", err)
+	}
+	if err := sessionService.CheckSession(session.ID); !errors.Is(err, domain.ErrSessionRevoked) {
+		t.Errorf("Expected ErrSessionRevoked, got %v", err)
+	}
+
+	// Other sessions are not affected
+	if err := sessionService.CheckSession(otherSession.ID); err != nil {
+		t.Errorf("Expected the other session to be active, got %v", err)
+	}
+	if _, _, err := sessionService.RefreshSession(otherToken, "curl/8.0", "127.0.0.1"); err != nil {
+		t.Errorf("Expected the other session to be refreshed, got %v", err)
+	}
+}
+
+func TestRefreshSessionRejectsRevokedUserTokens(t *testing.T) {
+	sessionService, _, mockUserRepository := newTestSessionService()
+	_, revokedToken, _ := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	_, disabledToken, _ := sessionService.CreateSession(2, "curl/8.0", "127.0.0.1")
+
+	mockUserRepository.RevokeTokens(1, time.Now().Add(time.Second))
+	mockUserRepository.DisableUser(2, time.Now())
+
+	for _, refreshToken := range []string{revokedToken, disabledToken} {
+		if _, _, err := sessionService.RefreshSession(refreshToken, "curl/8.0", "127.0.0.1"); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Errorf("Expected ErrInvalidRefreshToken, got %v", err)
+		}
+	}
+
+	if sessions, _ := sessionService.ListSessions(1); len(sessions) != 0 {
+		t.Errorf("Expected the sessions started before the tokens were revoked to be left out, got %d", len(sessions))
+	}
+}
+
+func TestListAndRevokeSessions(t *testing.T) {
+	sessionService, _, _ := newTestSessionService()
+	session, refreshToken, _ := sessionService.CreateSession(1, "curl/8.0", "127.0.0.1")
+	sessionService.CreateSession(1, "Firefox", "127.0.0.2")
+	sessionService.CreateSession(2, "curl/8.0", "127.0.0.3")
+
+	sessions, err := sessionService.ListSessions(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+
+	if err := sessionService.RevokeSession(2, session.ID); !errors.Is(err, domain.ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound when revoking the session of another user, got %v", err)
+	}
+	if err := sessionService.RevokeSession(1, session.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sessions, _ = sessionService.ListSessions(1)
+	if len(sessions) != 1 || sessions[0].UserAgent != "Firefox" {
+		t.Errorf("Expected only the Firefox session to be left, got %v", sessions)
+	}
+	if _, _, err := sessionService.RefreshSession(refreshToken, "curl/8.0", "127.0.0.1"); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+		t.Errorf("Expected ErrInvalidRefreshToken for a revoked session, got %v", err)
+	}
+	if err := sessionService.CheckSession(0); err != nil {
+		t.Errorf("Expected tokens without session to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type TokenRevocationService struct {
+	Repo port.TokenRevocationRepository
+}
+
+func NewTokenRevocationService(repo port.TokenRevocationRepository) *TokenRevocationService {
+	return &TokenRevocationService{
+		Repo: repo,
+	}
+}
+
+// RevokeToken stops the token with the given ID from being used, including
+// for refreshing it. It only needs to be remembered until the token, and any
+// refresh of it, expires.
+func (s *TokenRevocationService) RevokeToken(tokenID string, expiresAt time.Time) error {
+	if tokenID == "" {
+		return domain.ErrTokenNotRevocable
+	}
+	return s.Repo.RevokeToken(tokenID, expiresAt)
+}
+
+// CheckToken fails with domain.ErrTokenRevoked when the token with the given
+// ID has been revoked. Tokens issued before tokens had an ID cannot be
+// revoked, and are always accepted.
+func (s *TokenRevocationService) CheckToken(tokenID string) error {
+	if tokenID == "" {
+		return nil
+	}
+
+	revoked, err := s.Repo.IsTokenRevoked(tokenID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return domain.ErrTokenRevoked
+	}
+	return nil
+}
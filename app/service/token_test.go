@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestRevokeToken(t *testing.T) {
+	mockRepository := &mock.MockTokenRevocationRepository{}
+	tokenRevocationService := NewTokenRevocationService(mockRepository)
+
+	if err := tokenRevocationService.CheckToken("token-id"); err != nil {
+		t.Errorf("Expected the token to be accepted, got %v", err)
+	}
+
+	if err := tokenRevocationService.RevokeToken("token-id", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := tokenRevocationService.CheckToken("token-id"); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+	if err := tokenRevocationService.CheckToken("other-token-id"); err != nil {
+		t.Errorf("Expected other tokens to be accepted, got %v", err)
+	}
+}
+
+func TestRevokeTokenWithoutID(t *testing.T) {
+	tokenRevocationService := NewTokenRevocationService(&mock.MockTokenRevocationRepository{})
+
+	if err := tokenRevocationService.RevokeToken("", time.Now().Add(time.Hour)); !errors.Is(err, domain.ErrTokenNotRevocable) {
+		t.Errorf("Expected ErrTokenNotRevocable, got %v", err)
+	}
+	if err := tokenRevocationService.CheckToken(""); err != nil {
+		t.Errorf("Expected tokens without ID to be accepted, got %v", err)
+	}
+}
+
+func TestRevokeTokenForgetsExpiredTokens(t *testing.T) {
+	mockRepository := &mock.MockTokenRevocationRepository{}
+	tokenRevocationService := NewTokenRevocationService(mockRepository)
+
+	tokenRevocationService.RevokeToken("expired-token-id", time.Now().Add(-time.Minute))
+	tokenRevocationService.RevokeToken("token-id", time.Now().Add(time.Hour))
+
+	if _, kept := mockRepository.Revoked["expired-token-id"]; kept {
+		t.Errorf("Expected the expired token to be forgotten")
+	}
+	if _, kept := mockRepository.Revoked["token-id"]; !kept {
+		t.Errorf("Expected the token to be revoked")
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// defaultTOTPIssuer names the app in authenticator apps when no issuer is
+	// configured.
+	defaultTOTPIssuer = "Movie Collection"
+	// recoveryCodeCount is how many recovery codes a user gets at a time.
+	recoveryCodeCount = 10
+	// recoveryCodeBytes is the amount of randomness in a recovery code.
+	recoveryCodeBytes = 10
+	// challengeTokenBytes is the amount of randomness in a challenge token.
+	challengeTokenBytes = 32
+	// challengeLifetime is how long a user has to enter their code after
+	// logging in with their password.
+	challengeLifetime = 5 * time.Minute
+	// maxChallengeAttempts is how many wrong codes a challenge takes before
+	// the user has to log in with their password again.
+	maxChallengeAttempts = 5
+)
+
+var (
+	totpCodePattern      = regexp.MustCompile(`^[0-9]{6}$`)
+	recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+type TwoFactorService struct {
+	Repo     port.TwoFactorRepository
+	UserRepo port.UserRepository
+	Issuer   string
+}
+
+func NewTwoFactorService(repo port.TwoFactorRepository, userRepo port.UserRepository, issuer string) *TwoFactorService {
+	if issuer == "" {
+		issuer = defaultTOTPIssuer
+	}
+	return &TwoFactorService{
+		Repo:     repo,
+		UserRepo: userRepo,
+		Issuer:   issuer,
+	}
+}
+
+func (s *TwoFactorService) TwoFactorEnabled(userID uint) (bool, error) {
+	twoFactor, err := s.Repo.GetTwoFactor(userID)
+	if err != nil {
+		return false, err
+	}
+	return twoFactor != nil && twoFactor.Enabled(), nil
+}
+
+// CountRecoveryCodes returns how many unused recovery codes a user has left.
+func (s *TwoFactorService) CountRecoveryCodes(userID uint) (int64, error) {
+	return s.Repo.CountRecoveryCodes(userID)
+}
+
+// StartEnrolment gives a user a new TOTP secret to add to their authenticator
+// app. It is not asked for until the user confirms it with a code. Starting
+// again replaces the pending secret.
+func (s *TwoFactorService) StartEnrolment(userID uint) (*domain.TOTPEnrolment, error) {
+	twoFactor, err := s.Repo.GetTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Enabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	user, err := s.UserRepo.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SaveTwoFactor(&domain.TwoFactor{UserID: userID, Secret: secret, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+
+	return &domain.TOTPEnrolment{
+		Secret:          secret,
+		ProvisioningURI: util.TOTPProvisioningURI(s.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrolment enables two-factor authentication once the user enters a
+// code of their pending secret, and returns their recovery codes. They cannot
+// be shown again.
+func (s *TwoFactorService) ConfirmEnrolment(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.Repo.GetTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, domain.ErrTwoFactorNotStarted
+	}
+	if twoFactor.Enabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	now := time.Now()
+	step, ok := util.VerifyTOTP(twoFactor.Secret, code, now)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	if err := s.Repo.EnableTwoFactor(userID, now, step); err != nil {
+		return nil, err
+	}
+
+	return s.replaceRecoveryCodes(userID)
+}
+
+// DisableTwoFactor turns two-factor authentication off, provided the user can
+// still give a TOTP or recovery code.
+func (s *TwoFactorService) DisableTwoFactor(userID uint, code string) error {
+	twoFactor, err := s.enabledTwoFactor(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCode(twoFactor, code, time.Now()); err != nil {
+		return err
+	}
+	return s.Repo.DeleteTwoFactor(userID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user, provided they
+// can give a TOTP or recovery code, and returns the new ones.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	twoFactor, err := s.enabledTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCode(twoFactor, code, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(userID)
+}
+
+// CreateChallenge returns the challenge token a user exchanges, along with a
+// code, for a token after logging in with their password, and when it expires.
+func (s *TwoFactorService) CreateChallenge(userID uint) (string, time.Time, error) {
+	token, err := util.GenerateToken(challengeTokenBytes)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	challenge := &domain.TwoFactorChallenge{
+		UserID:    userID,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: now.Add(challengeLifetime),
+		CreatedAt: now,
+	}
+	if err := s.Repo.CreateChallenge(challenge); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, challenge.ExpiresAt, nil
+}
+
+// ChallengeUser returns the user a challenge token was given to, as long as
+// the challenge can still be completed.
+func (s *TwoFactorService) ChallengeUser(challengeToken string) (*domain.User, error) {
+	challenge, err := s.pendingChallenge(challengeToken, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.GetUser(challenge.UserID)
+	if err != nil {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	return user, nil
+}
+
+// VerifyChallenge returns the user of a challenge token when the code is one
+// of their TOTP or recovery codes. Each challenge can only be used once, and
+// only takes a few wrong codes.
+func (s *TwoFactorService) VerifyChallenge(challengeToken string, code string) (*domain.User, error) {
+	now := time.Now()
+	challenge, err := s.pendingChallenge(challengeToken, now)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.Repo.GetTwoFactor(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled() {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+
+	if err := s.checkCode(twoFactor, code, now); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			if err := s.Repo.AddChallengeAttempt(challenge.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+	if err := s.Repo.UseChallenge(challenge.ID, now); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.GetUser(challenge.UserID)
+	if err != nil || user.Disabled() {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	return user, nil
+}
+
+// pendingChallenge finds a challenge that is neither used, expired nor out of
+// attempts.
+func (s *TwoFactorService) pendingChallenge(challengeToken string, at time.Time) (*domain.TwoFactorChallenge, error) {
+	challenge, err := s.Repo.GetChallenge(util.HashToken(challengeToken))
+	if err != nil || !challenge.UsedAt.IsZero() || !challenge.ExpiresAt.After(at) || challenge.Attempts >= maxChallengeAttempts {
+		return nil, domain.ErrInvalidTwoFactorChallenge
+	}
+	return challenge, nil
+}
+
+func (s *TwoFactorService) enabledTwoFactor(userID uint) (*domain.TwoFactor, error) {
+	twoFactor, err := s.Repo.GetTwoFactor(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.Enabled() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	return twoFactor, nil
+}
+
+// checkCode accepts six digits as a TOTP code, which cannot be used twice, and
+// anything else as a recovery code, which is used up.
+func (s *TwoFactorService) checkCode(twoFactor *domain.TwoFactor, code string, at time.Time) error {
+	code = strings.TrimSpace(code)
+	if totpCodePattern.MatchString(code) {
+		step, ok := util.VerifyTOTP(twoFactor.Secret, code, at)
+		if !ok || step <= twoFactor.LastUsedStep {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return s.Repo.UseTOTPStep(twoFactor.UserID, step)
+	}
+
+	return s.Repo.UseRecoveryCode(twoFactor.UserID, util.HashToken(normalizeRecoveryCode(code)), at)
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	recoveryCodes := make([]*domain.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		recoveryCodes[i] = &domain.RecoveryCode{UserID: userID, CodeHash: util.HashToken(normalizeRecoveryCode(code))}
+	}
+
+	if err := s.Repo.ReplaceRecoveryCodes(userID, recoveryCodes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode returns a random code in groups of four letters and
+// digits, such as "abcd-efgh-ijkl-mnop", which is easy to write down.
+func generateRecoveryCode() (string, error) {
+	random := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	// Base32 only uses letters and digits which are hard to mix up
+	encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(random))
+
+	groups := []string{}
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:min(i+4, len(encoded))])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeRecoveryCode ignores case, dashes and spaces, however the user
+// typed the code.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
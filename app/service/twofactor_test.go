@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+func newTestTwoFactorService() (*TwoFactorService, *mock.MockTwoFactorRepository, *mock.MockUserRepository) {
+	mockRepository := &mock.MockTwoFactorRepository{}
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User"},
+			{ID: 2, Email: "other@test.com", Name: "Other User"},
+		},
+	}
+	return NewTwoFactorService(mockRepository, mockUserRepository, ""), mockRepository, mockUserRepository
+}
+
+// currentTOTPCode returns the code of a secret for the given number of periods
+// from now.
+func currentTOTPCode(t *testing.T, secret string, periods int64) string {
+	code, err := util.TOTPCode(secret, util.TOTPStep(time.Now())+periods)
+	if err != nil {
+		t.Fatalf("Failed to generate a TOTP code: %v", err)
+	}
+	return code
+}
+
+// enrolTestUser enables two-factor authentication for a user and returns their
+// secret and recovery codes.
+func enrolTestUser(t *testing.T, twoFactorService *TwoFactorService, userID uint) (string, []string) {
+	enrolment, err := twoFactorService.StartEnrolment(userID)
+	if err != nil {
+		t.Fatalf("Failed to start the enrolment: %v", err)
+	}
+	recoveryCodes, err := twoFactorService.ConfirmEnrolment(userID, currentTOTPCode(t, enrolment.Secret, -1))
+	if err != nil {
+		t.Fatalf("Failed to confirm the enrolment: %v", err)
+	}
+	return enrolment.Secret, recoveryCodes
+}
+
+func TestStartEnrolment(t *testing.T) {
+	twoFactorService, mockRepository, _ := newTestTwoFactorService()
+
+	enrolment, err := twoFactorService.StartEnrolment(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(enrolment.ProvisioningURI, "otpauth://totp/Movie%20Collection:test@test.com?") || !strings.Contains(enrolment.ProvisioningURI, enrolment.Secret) {
+		t.Errorf("Expected a provisioning URI for test@test.com with the secret, got %s", enrolment.ProvisioningURI)
+	}
+
+	enabled, _ := twoFactorService.TwoFactorEnabled(1)
+	if enabled {
+		t.Errorf("Expected two-factor authentication to stay off until confirmed")
+	}
+
+	again, _ := twoFactorService.StartEnrolment(1)
+	if len(mockRepository.TwoFactors) != 1 || mockRepository.TwoFactors[0].Secret != again.Secret {
+		t.Errorf("Expected starting again to replace the pending secret")
+	}
+}
+
+func TestConfirmEnrolment(t *testing.T) {
+	twoFactorService, mockRepository, _ := newTestTwoFactorService()
+
+	if _, err := twoFactorService.ConfirmEnrolment(1, "123456"); !errors.Is(err, domain.ErrTwoFactorNotStarted) {
+		t.Errorf("Expected ErrTwoFactorNotStarted, got %v", err)
+	}
+
+	enrolment, _ := twoFactorService.StartEnrolment(1)
+	if _, err := twoFactorService.ConfirmEnrolment(1, currentTOTPCode(t, enrolment.Secret, 5)); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+
+	recoveryCodes, err := twoFactorService.ConfirmEnrolment(1, currentTOTPCode(t, enrolment.Secret, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recoveryCodes) != 10 || len(mockRepository.RecoveryCodes) != 10 {
+		t.Fatalf("Expected 10 recovery codes, got %d", len(recoveryCodes))
+	}
+	for i, recoveryCode := range recoveryCodes {
+		if len(recoveryCode) != 19 || mockRepository.RecoveryCodes[i].CodeHash == recoveryCode {
+			t.Errorf("Expected a code like abcd-efgh-ijkl-mnop stored hashed, got %s", recoveryCode)
+		}
+	}
+
+	enabled, _ := twoFactorService.TwoFactorEnabled(1)
+	if !enabled {
+		t.Errorf("Expected two-factor authentication to be enabled")
+	}
+	if _, err := twoFactorService.StartEnrolment(1); !errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("Expected ErrTwoFactorAlreadyEnabled, got %v", err)
+	}
+}
+
+func TestVerifyChallengeWithTOTPCode(t *testing.T) {
+	twoFactorService, _, _ := newTestTwoFactorService()
+	secret, _ := enrolTestUser(t, twoFactorService, 1)
+
+	challengeToken, expiresAt, err := twoFactorService.CreateChallenge(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !expiresAt.After(time.Now()) || expiresAt.After(time.Now().Add(5*time.Minute)) {
+		t.Errorf("Expected the challenge to expire within 5 minutes, got %v", expiresAt)
+	}
+
+	user, err := twoFactorService.VerifyChallenge(challengeToken, currentTOTPCode(t, secret, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.ID != 1 {
+		t.Errorf("Expected user 1, got %d", user.ID)
+	}
+
+	if _, err := twoFactorService.VerifyChallenge(challengeToken, currentTOTPCode(t, secret, 1)); !errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		t.Errorf("Expected a used challenge to be rejected, got %v", err)
+	}
+
+	otherChallengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	if _, err := twoFactorService.VerifyChallenge(otherChallengeToken, currentTOTPCode(t, secret, 0)); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected a reused code to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyChallengeWithRecoveryCode(t *testing.T) {
+	twoFactorService, _, _ := newTestTwoFactorService()
+	_, recoveryCodes := enrolTestUser(t, twoFactorService, 1)
+
+	challengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	if _, err := twoFactorService.VerifyChallenge(challengeToken, " "+strings.ToUpper(strings.ReplaceAll(recoveryCodes[0], "-", ""))+" "); err != nil {
+		t.Fatalf("Expected the recovery code to be accepted however it is typed, got %v", err)
+	}
+	if count, _ := twoFactorService.CountRecoveryCodes(1); count != 9 {
+		t.Errorf("Expected 9 recovery codes left, got %d", count)
+	}
+
+	otherChallengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	if _, err := twoFactorService.VerifyChallenge(otherChallengeToken, recoveryCodes[0]); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected a used recovery code to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyChallengeLimitsAttempts(t *testing.T) {
+	twoFactorService, mockRepository, _ := newTestTwoFactorService()
+	secret, _ := enrolTestUser(t, twoFactorService, 1)
+
+	challengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	if user, err := twoFactorService.ChallengeUser(challengeToken); err != nil || user.ID != 1 {
+		t.Errorf("Expected the challenge to belong to user 1, got %v and %v", user, err)
+	}
+	for range 5 {
+		if _, err := twoFactorService.VerifyChallenge(challengeToken, "wrong-code"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+		}
+	}
+	if _, err := twoFactorService.VerifyChallenge(challengeToken, currentTOTPCode(t, secret, 0)); !errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		t.Errorf("Expected the challenge to be rejected after 5 wrong codes, got %v", err)
+	}
+	if _, err := twoFactorService.ChallengeUser(challengeToken); !errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		t.Errorf("Expected the user of a challenge out of attempts to be hidden, got %v", err)
+	}
+
+	expiredChallengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	mockRepository.Challenges[1].ExpiresAt = time.Now().Add(-time.Second)
+	if _, err := twoFactorService.VerifyChallenge(expiredChallengeToken, currentTOTPCode(t, secret, 0)); !errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		t.Errorf("Expected an expired challenge to be rejected, got %v", err)
+	}
+	if _, err := twoFactorService.VerifyChallenge("unknown", currentTOTPCode(t, secret, 0)); !errors.Is(err, domain.ErrInvalidTwoFactorChallenge) {
+		t.Errorf("Expected an unknown challenge to be rejected, got %v", err)
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	twoFactorService, mockRepository, _ := newTestTwoFactorService()
+	if err := twoFactorService.DisableTwoFactor(1, "123456"); !errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		t.Errorf("Expected ErrTwoFactorNotEnabled, got %v", err)
+	}
+
+	_, recoveryCodes := enrolTestUser(t, twoFactorService, 1)
+	if err := twoFactorService.DisableTwoFactor(1, "wrong-code"); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+	if err := twoFactorService.DisableTwoFactor(1, recoveryCodes[0]); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	enabled, _ := twoFactorService.TwoFactorEnabled(1)
+	if enabled || len(mockRepository.RecoveryCodes) != 0 {
+		t.Errorf("Expected two-factor authentication to be off without recovery codes")
+	}
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	twoFactorService, _, _ := newTestTwoFactorService()
+	secret, oldRecoveryCodes := enrolTestUser(t, twoFactorService, 1)
+
+	recoveryCodes, err := twoFactorService.RegenerateRecoveryCodes(1, currentTOTPCode(t, secret, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recoveryCodes) != 10 || recoveryCodes[0] == oldRecoveryCodes[0] {
+		t.Errorf("Expected 10 new recovery codes")
+	}
+
+	challengeToken, _, _ := twoFactorService.CreateChallenge(1)
+	if _, err := twoFactorService.VerifyChallenge(challengeToken, oldRecoveryCodes[1]); !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		t.Errorf("Expected the old recovery codes to be rejected, got %v", err)
+	}
+}
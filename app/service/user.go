@@ -1,51 +1,111 @@
 package service
 
 import (
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/Acova/movie-collection/app/domain"
 	"github.com/Acova/movie-collection/app/port"
 	"github.com/Acova/movie-collection/app/util"
 )
 
+var (
+	dummyPasswordHashOnce  sync.Once
+	dummyPasswordHashValue string
+)
+
+// dummyPasswordHash returns the hash logins for emails without an account are
+// checked against, so that they take as long as for existing ones.
+func dummyPasswordHash() string {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHashValue, _ = util.HashPassword("not the password of anyone")
+	})
+	return dummyPasswordHashValue
+}
+
 type UserPort struct {
-	Repo port.UserRepository
+	Repo           port.UserRepository
+	PasswordPolicy port.PasswordPolicyService
 }
 
-func NewUserService(repo port.UserRepository) *UserPort {
+func NewUserService(repo port.UserRepository, passwordPolicy port.PasswordPolicyService) *UserPort {
 	return &UserPort{
-		Repo: repo,
+		Repo:           repo,
+		PasswordPolicy: passwordPolicy,
 	}
 }
 
-func (c *UserPort) ListUsers() ([]*domain.User, error) {
-	return c.Repo.ListUsers()
+// ListUsers returns the users whose email or name contain search, or every
+// user when it is empty.
+func (c *UserPort) ListUsers(search string, pagination port.Pagination) ([]*domain.User, int64, error) {
+	return c.Repo.ListUsers(strings.TrimSpace(search), pagination)
 }
 
+// CreateUser registers a user, provided their password meets the password
+// policy.
 func (c *UserPort) CreateUser(user *domain.User) error {
+	if err := c.PasswordPolicy.CheckPassword(user.Password, user); err != nil {
+		return err
+	}
+
 	hashedPassword, err := util.HashPassword(user.Password)
 	if err != nil {
 		return err
 	}
 
 	user.Password = hashedPassword
-	c.Repo.CreateUser(user)
-
-	return nil
+	user.Role = domain.RoleUser // Admins are only promoted by hand
+	return c.Repo.CreateUser(user)
 }
 
+// GetLoginUser returns the user with the given email and password, rehashing
+// the password when its hash is outdated. Emails without an account take as
+// long to check as wrong passwords, and fail with the same error, so that which
+// emails have an account cannot be told.
 func (c *UserPort) GetLoginUser(email, password string) (*domain.User, error) {
 	user, err := c.Repo.GetUserByEmail(email)
 	if err != nil {
-		return &domain.User{}, err
+		util.ComparePasswords(password, dummyPasswordHash())
+		return &domain.User{}, domain.ErrInvalidCredentials
 	}
 
 	err = util.ComparePasswords(password, user.Password)
 	if err != nil {
-		return &domain.User{}, err
+		return &domain.User{}, domain.ErrInvalidCredentials
+	}
+
+	if user.Disabled() {
+		return &domain.User{}, domain.ErrUserDisabled
+	}
+
+	// Hashes made with an older algorithm or parameters are replaced while the
+	// password is at hand. The login goes on if that fails, as the old hash
+	// still works.
+	if util.PasswordNeedsRehash(user.Password) {
+		if hashedPassword, err := util.HashPassword(password); err == nil && c.Repo.UpdatePassword(user.ID, hashedPassword) == nil {
+			user.Password = hashedPassword
+		}
 	}
 
 	return user, nil
 }
 
+func (c *UserPort) GetUser(id uint) (*domain.User, error) {
+	user, err := c.Repo.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *UserPort) SetUserRole(id uint, role domain.Role) error {
+	if !role.Valid() {
+		return domain.ErrInvalidRole
+	}
+	return c.Repo.SetUserRole(id, role)
+}
+
 func (c *UserPort) GetUserByEmail(email string) (*domain.User, error) {
 	user, err := c.Repo.GetUserByEmail(email)
 	if err != nil {
@@ -53,3 +113,95 @@ func (c *UserPort) GetUserByEmail(email string) (*domain.User, error) {
 	}
 	return user, nil
 }
+
+// DisableUser prevents a user from logging in, and revokes every token issued
+// to them so far.
+func (c *UserPort) DisableUser(id uint) error {
+	return c.Repo.DisableUser(id, time.Now())
+}
+
+// EnableUser lets a disabled user log in again. The tokens revoked when they
+// were disabled stay revoked.
+func (c *UserPort) EnableUser(id uint) error {
+	return c.Repo.EnableUser(id)
+}
+
+// AuthorizeToken checks that a token issued to a user at issuedAt can still be
+// used, and returns the current state of the user.
+func (c *UserPort) AuthorizeToken(id uint, issuedAt time.Time) (*domain.User, error) {
+	user, err := c.Repo.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Disabled() {
+		return nil, domain.ErrUserDisabled
+	}
+
+	// Token timestamps only have a precision of seconds.
+	if issuedAt.Before(user.TokensRevokedAt.Truncate(time.Second)) {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	return user, nil
+}
+
+// UpdateProfile saves the email and name of a user.
+func (c *UserPort) UpdateProfile(user *domain.User) error {
+	user.Email = strings.TrimSpace(user.Email)
+	user.Name = strings.TrimSpace(user.Name)
+	return c.Repo.UpdateUser(user)
+}
+
+// ChangePassword replaces the password of a user, provided they know their
+// current one and the new one meets the password policy. Every token issued to
+// the user until now is revoked.
+func (c *UserPort) ChangePassword(id uint, currentPassword, newPassword string) error {
+	user, err := c.checkPassword(id, currentPassword)
+	if err != nil {
+		return err
+	}
+	if err := c.PasswordPolicy.CheckPassword(newPassword, user); err != nil {
+		return err
+	}
+
+	hashedPassword, err := util.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := c.Repo.UpdatePassword(id, hashedPassword); err != nil {
+		return err
+	}
+	return c.Repo.RevokeTokens(id, time.Now())
+}
+
+// DeleteAccount deletes a user, provided they confirm it with their password.
+// Their movies are given to the reassignMoviesTo user, or deleted when it is
+// nil.
+func (c *UserPort) DeleteAccount(id uint, password string, reassignMoviesTo *uint) error {
+	if _, err := c.checkPassword(id, password); err != nil {
+		return err
+	}
+
+	if reassignMoviesTo != nil {
+		if *reassignMoviesTo == id {
+			return domain.ErrInvalidMovieReassignment
+		}
+		if _, err := c.Repo.GetUser(*reassignMoviesTo); err != nil {
+			return domain.ErrInvalidMovieReassignment
+		}
+	}
+
+	return c.Repo.DeleteUser(id, reassignMoviesTo)
+}
+
+func (c *UserPort) checkPassword(id uint, password string) (*domain.User, error) {
+	user, err := c.Repo.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := util.ComparePasswords(password, user.Password); err != nil {
+		return nil, domain.ErrWrongPassword
+	}
+	return user, nil
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
 	"github.com/Acova/movie-collection/app/port/mock"
 	"github.com/Acova/movie-collection/app/util"
 )
@@ -16,12 +20,12 @@ func TestListUsers(t *testing.T) {
 		},
 	}
 
-	userService := NewUserService(mockRepository)
-	users, err := userService.ListUsers()
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	users, total, err := userService.ListUsers("", port.Pagination{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
-	if len(users) != 2 {
+	if total != 2 || len(users) != 2 {
 		t.Errorf("Expected 2 users, got %d", len(users))
 	}
 	if users[0].Email != "user1@example.com" {
@@ -38,12 +42,30 @@ func TestListUsers(t *testing.T) {
 	}
 }
 
+func TestListUsersWithSearch(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{Email: "user1@example.com", Name: "Alice"},
+			{Email: "user2@example.com", Name: "Bob"},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	users, total, err := userService.ListUsers("  bob ", port.Pagination{})
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if total != 1 || users[0].Name != "Bob" {
+		t.Errorf("Expected only Bob, got %d users", total)
+	}
+}
+
 func TestCreateUser(t *testing.T) {
 	mockRepository := &mock.MockUserRepository{
 		Users: []*domain.User{},
 	}
 
-	userService := NewUserService(mockRepository)
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
 	newUser := &domain.User{Email: "user3@example.com", Password: "password3"}
 	userService.CreateUser(newUser)
 
@@ -58,6 +80,55 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+// unavailableUserRepository fails to save new users.
+type unavailableUserRepository struct {
+	mock.MockUserRepository
+}
+
+func (r *unavailableUserRepository) CreateUser(user *domain.User) error {
+	return errors.New("connection refused")
+}
+
+func TestCreateUserReturnsRepositoryErrors(t *testing.T) {
+	userService := NewUserService(&unavailableUserRepository{}, &mock.MockPasswordPolicyService{})
+
+	if err := userService.CreateUser(&domain.User{Email: "user3@example.com", Password: "password3"}); err == nil {
+		t.Errorf("Expected the repository error, got none")
+	}
+}
+
+func TestCreateUserRejectsPasswordsAgainstThePolicy(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{}
+	mockPasswordPolicy := &mock.MockPasswordPolicyService{
+		Rejections: map[string][]domain.PasswordRejection{
+			"password": {{Code: domain.PasswordBreached, Message: "password has appeared in a data breach"}},
+		},
+	}
+
+	userService := NewUserService(mockRepository, mockPasswordPolicy)
+	err := userService.CreateUser(&domain.User{Email: "user3@example.com", Password: "password"})
+
+	var policyError *domain.PasswordPolicyError
+	if !errors.As(err, &policyError) || policyError.Rejections[0].Code != domain.PasswordBreached {
+		t.Errorf("Expected the password to be rejected as breached, got %v", err)
+	}
+	if len(mockRepository.Users) != 0 {
+		t.Errorf("Expected no user to be created, got %d", len(mockRepository.Users))
+	}
+}
+
+func TestCreateUserCannotBeAdmin(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	newUser := &domain.User{Email: "user3@example.com", Password: "password3", Role: domain.RoleAdmin}
+	userService.CreateUser(newUser)
+
+	if mockRepository.Users[0].Role != domain.RoleUser {
+		t.Errorf("Expected new users to be regular users, got %s", mockRepository.Users[0].Role)
+	}
+}
+
 func TestGetLoginUser(t *testing.T) {
 	password, ok := util.HashPassword("longpassword")
 	if ok != nil {
@@ -70,7 +141,7 @@ func TestGetLoginUser(t *testing.T) {
 		},
 	}
 
-	userService := NewUserService(mockRepository)
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
 	user, err := userService.GetLoginUser("user1@example.com", "longpassword")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -83,6 +154,52 @@ func TestGetLoginUser(t *testing.T) {
 	}
 }
 
+func TestGetLoginUserHidesWhichEmailsExist(t *testing.T) {
+	password, _ := util.HashPassword("longpassword")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{Email: "user1@example.com", Password: password},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	_, wrongPasswordErr := userService.GetLoginUser("user1@example.com", "wrongpassword")
+	_, unknownEmailErr := userService.GetLoginUser("unknown@example.com", "wrongpassword")
+
+	if !errors.Is(wrongPasswordErr, domain.ErrInvalidCredentials) || !errors.Is(unknownEmailErr, domain.ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for both, got %v and %v", wrongPasswordErr, unknownEmailErr)
+	}
+}
+
+func TestGetLoginUserRehashesOutdatedPasswords(t *testing.T) {
+	legacyPassword, _ := util.NewBcryptHasher().Hash("longpassword")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Password: legacyPassword},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	if _, err := userService.GetLoginUser("user1@example.com", "wrongpassword"); !errors.Is(err, domain.ErrInvalidCredentials) {
+		t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if mockRepository.Users[0].Password != legacyPassword {
+		t.Fatalf("Expected the hash to be kept after a failed login")
+	}
+
+	if _, err := userService.GetLoginUser("user1@example.com", "longpassword"); err != nil {
+		t.Fatalf("Expected the bcrypt hash to still work, got %v", err)
+	}
+	rehashedPassword := mockRepository.Users[0].Password
+	if !strings.HasPrefix(rehashedPassword, "$argon2id$") || util.PasswordNeedsRehash(rehashedPassword) {
+		t.Errorf("Expected the password to be rehashed with argon2id, got %s", rehashedPassword)
+	}
+
+	if _, err := userService.GetLoginUser("user1@example.com", "longpassword"); err != nil || mockRepository.Users[0].Password != rehashedPassword {
+		t.Errorf("Expected the new hash to work and be kept, got %v", err)
+	}
+}
+
 func TestGetUserByEmail(t *testing.T) {
 	mockRepository := &mock.MockUserRepository{
 		Users: []*domain.User{
@@ -90,7 +207,7 @@ func TestGetUserByEmail(t *testing.T) {
 		},
 	}
 
-	userService := NewUserService(mockRepository)
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
 	user, err := userService.GetUserByEmail("user1@example.com")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
@@ -102,3 +219,166 @@ func TestGetUserByEmail(t *testing.T) {
 		t.Errorf("Expected password, got %s", user.Password)
 	}
 }
+
+func TestSetUserRole(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Role: domain.RoleUser},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	if err := userService.SetUserRole(1, domain.RoleModerator); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if mockRepository.Users[0].Role != domain.RoleModerator {
+		t.Errorf("Expected moderator, got %s", mockRepository.Users[0].Role)
+	}
+	if err := userService.SetUserRole(1, "superuser"); !errors.Is(err, domain.ErrInvalidRole) {
+		t.Errorf("Expected an invalid role error, got %v", err)
+	}
+	if err := userService.SetUserRole(2, domain.RoleAdmin); err == nil {
+		t.Errorf("Expected an error for an unknown user")
+	}
+}
+
+func TestGetLoginUserDisabled(t *testing.T) {
+	password, _ := util.HashPassword("longpassword")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Password: password, DisableDate: time.Now()},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	if _, err := userService.GetLoginUser("user1@example.com", "longpassword"); !errors.Is(err, domain.ErrUserDisabled) {
+		t.Errorf("Expected a disabled user error, got %v", err)
+	}
+}
+
+func TestDisableUserRevokesTokens(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com"},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	issuedAt := time.Now().Add(-time.Minute)
+	if _, err := userService.AuthorizeToken(1, issuedAt); err != nil {
+		t.Fatalf("Expected the token to be valid, got %v", err)
+	}
+
+	if err := userService.DisableUser(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := userService.AuthorizeToken(1, issuedAt); !errors.Is(err, domain.ErrUserDisabled) {
+		t.Errorf("Expected a disabled user error, got %v", err)
+	}
+
+	if err := userService.EnableUser(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := userService.AuthorizeToken(1, issuedAt); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Errorf("Expected the old token to stay revoked, got %v", err)
+	}
+	if _, err := userService.AuthorizeToken(1, time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("Expected a new token to be valid, got %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	password, _ := util.HashPassword("oldpassword")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Password: password},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	if err := userService.ChangePassword(1, "wrongpassword", "newpassword"); !errors.Is(err, domain.ErrWrongPassword) {
+		t.Errorf("Expected a wrong password error, got %v", err)
+	}
+	if err := userService.ChangePassword(1, "oldpassword", "newpassword"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := util.ComparePasswords("newpassword", mockRepository.Users[0].Password); err != nil {
+		t.Errorf("Expected the new password to be stored hashed, got %s", mockRepository.Users[0].Password)
+	}
+	if _, err := userService.AuthorizeToken(1, time.Now().Add(-time.Minute)); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Errorf("Expected tokens issued before the password change to be revoked, got %v", err)
+	}
+}
+
+func TestChangePasswordRejectsPasswordsAgainstThePolicy(t *testing.T) {
+	password, _ := util.HashPassword("oldpassword")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Password: password},
+		},
+	}
+	mockPasswordPolicy := &mock.MockPasswordPolicyService{
+		Rejections: map[string][]domain.PasswordRejection{
+			"short": {{Code: domain.PasswordTooShort, Message: "password must be at least 8 characters long"}},
+		},
+	}
+
+	userService := NewUserService(mockRepository, mockPasswordPolicy)
+	if err := userService.ChangePassword(1, "oldpassword", "short"); !errors.Is(err, domain.ErrPasswordRejected) {
+		t.Errorf("Expected the password to be rejected, got %v", err)
+	}
+	if err := util.ComparePasswords("oldpassword", mockRepository.Users[0].Password); err != nil {
+		t.Errorf("Expected the old password to be kept")
+	}
+	if !mockRepository.Users[0].TokensRevokedAt.IsZero() {
+		t.Errorf("Expected the tokens not to be revoked")
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Name: "User One"},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	if err := userService.UpdateProfile(&domain.User{ID: 1, Email: " new@example.com ", Name: " New Name "}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockRepository.Users[0].Email != "new@example.com" || mockRepository.Users[0].Name != "New Name" {
+		t.Errorf("Expected the trimmed email and name to be saved, got %+v", mockRepository.Users[0])
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	password, _ := util.HashPassword("password")
+	mockRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "user1@example.com", Password: password},
+			{ID: 2, Email: "user2@example.com", Password: password},
+		},
+	}
+
+	userService := NewUserService(mockRepository, &mock.MockPasswordPolicyService{})
+	self, unknown, other := uint(1), uint(3), uint(2)
+	if err := userService.DeleteAccount(1, "wrongpassword", nil); !errors.Is(err, domain.ErrWrongPassword) {
+		t.Errorf("Expected a wrong password error, got %v", err)
+	}
+	if err := userService.DeleteAccount(1, "password", &self); !errors.Is(err, domain.ErrInvalidMovieReassignment) {
+		t.Errorf("Expected an invalid reassignment error for the same user, got %v", err)
+	}
+	if err := userService.DeleteAccount(1, "password", &unknown); !errors.Is(err, domain.ErrInvalidMovieReassignment) {
+		t.Errorf("Expected an invalid reassignment error for an unknown user, got %v", err)
+	}
+	if len(mockRepository.Users) != 2 {
+		t.Fatalf("Expected no user to be deleted yet, got %d users", len(mockRepository.Users))
+	}
+
+	if err := userService.DeleteAccount(1, "password", &other); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(mockRepository.Users) != 1 || mockRepository.Users[0].ID != 2 {
+		t.Errorf("Expected only user 2 to be left, got %d users", len(mockRepository.Users))
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+const (
+	// verificationTokenBytes is the amount of randomness in an email
+	// verification token.
+	verificationTokenBytes = 32
+	// verificationTokenLifetime is how long an email verification token can
+	// be used for.
+	verificationTokenLifetime = 24 * time.Hour
+)
+
+type EmailVerificationService struct {
+	Repo     port.EmailVerificationRepository
+	UserRepo port.UserRepository
+	Mailer   port.Mailer
+	// VerifyURL is where the verification link points to, usually the
+	// `/user/verify` endpoint. The token is appended to it as the `token`
+	// query parameter.
+	VerifyURL string
+}
+
+func NewEmailVerificationService(repo port.EmailVerificationRepository, userRepo port.UserRepository, mailer port.Mailer, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{
+		Repo:      repo,
+		UserRepo:  userRepo,
+		Mailer:    mailer,
+		VerifyURL: verifyURL,
+	}
+}
+
+// SendVerification emails a verification link to the current email of the
+// user.
+func (s *EmailVerificationService) SendVerification(user *domain.User) error {
+	if user.EmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	token, err := util.GenerateToken(verificationTokenBytes)
+	if err != nil {
+		return err
+	}
+
+	verificationToken := &domain.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(verificationTokenLifetime),
+	}
+	if err := s.Repo.CreateVerificationToken(verificationToken); err != nil {
+		return err
+	}
+
+	return s.Mailer.Send(&domain.Mail{
+		To:      user.Email,
+		Subject: "Verify your Movie Collection email",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\n"+
+				"To verify the email of your Movie Collection account, follow this link within the next 24 hours:\n\n"+
+				"%s\n\n"+
+				"If you did not create an account, you can ignore this email.\n",
+			user.Name, tokenLink(s.VerifyURL, token, "Verification token"),
+		),
+	})
+}
+
+// ResendVerification sends a new verification link to a user whose email is
+// still pending verification. Previous links keep working until they expire.
+func (s *EmailVerificationService) ResendVerification(userID uint) error {
+	user, err := s.UserRepo.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.SendVerification(user)
+}
+
+// VerifyEmail verifies the email a token was sent to, provided the user still
+// has it. The token, and any other verification token of the user, cannot be
+// used again.
+func (s *EmailVerificationService) VerifyEmail(token string) error {
+	now := time.Now()
+	verificationToken, err := s.Repo.UseVerificationToken(util.HashToken(token), now)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.UserRepo.GetUser(verificationToken.UserID)
+	if err != nil || user.Email != verificationToken.Email {
+		return domain.ErrInvalidVerificationToken
+	}
+
+	if err := s.UserRepo.VerifyEmail(user.ID, now); err != nil {
+		return err
+	}
+
+	return s.Repo.InvalidateVerificationTokens(user.ID, now)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+	"github.com/Acova/movie-collection/app/util"
+)
+
+func TestSendVerification(t *testing.T) {
+	mockRepository := &mock.MockEmailVerificationRepository{}
+	mockMailer := &mock.MockMailer{}
+
+	verificationService := NewEmailVerificationService(mockRepository, &mock.MockUserRepository{}, mockMailer, "https://movies.test/user/verify")
+
+	user := &domain.User{ID: 1, Email: "test@test.com", Name: "Test User"}
+	if err := verificationService.SendVerification(user); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockMailer.Sent) != 1 || mockMailer.Sent[0].To != "test@test.com" {
+		t.Fatalf("Expected a single mail to test@test.com, got %v", mockMailer.Sent)
+	}
+	if len(mockRepository.Tokens) != 1 {
+		t.Fatalf("Expected a single verification token, got %d", len(mockRepository.Tokens))
+	}
+
+	verificationToken := mockRepository.Tokens[0]
+	if verificationToken.UserID != 1 || verificationToken.Email != "test@test.com" {
+		t.Errorf("Expected the token to be sent to test@test.com of user 1, got %+v", verificationToken)
+	}
+
+	_, link, found := strings.Cut(mockMailer.Sent[0].Body, "https://movies.test/user/verify?token=")
+	if !found {
+		t.Fatalf("Expected the mail to contain the verification link, got %q", mockMailer.Sent[0].Body)
+	}
+	if verificationToken.TokenHash != util.HashToken(strings.Fields(link)[0]) {
+		t.Errorf("Expected only the hash of the token to be stored")
+	}
+
+	user.EmailVerifiedAt = time.Now()
+	if err := verificationService.SendVerification(user); !errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "pending@test.com", Name: "Pending User"},
+			{ID: 2, Email: "verified@test.com", Name: "Verified User", EmailVerifiedAt: time.Now()},
+		},
+	}
+	mockMailer := &mock.MockMailer{}
+
+	verificationService := NewEmailVerificationService(&mock.MockEmailVerificationRepository{}, mockUserRepository, mockMailer, "")
+
+	if err := verificationService.ResendVerification(1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := verificationService.ResendVerification(2); !errors.Is(err, domain.ErrEmailAlreadyVerified) {
+		t.Errorf("Expected ErrEmailAlreadyVerified, got %v", err)
+	}
+
+	if len(mockMailer.Sent) != 1 || !strings.Contains(mockMailer.Sent[0].Body, "Verification token: ") {
+		t.Errorf("Expected a single mail with the verification token, got %v", mockMailer.Sent)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	mockUserRepository := &mock.MockUserRepository{
+		Users: []*domain.User{
+			{ID: 1, Email: "test@test.com", Name: "Test User"},
+		},
+	}
+	mockRepository := &mock.MockEmailVerificationRepository{
+		Tokens: []*domain.EmailVerificationToken{
+			{ID: 1, UserID: 1, Email: "test@test.com", TokenHash: util.HashToken("expired-token"), ExpiresAt: time.Now().Add(-time.Minute)},
+			{ID: 2, UserID: 1, Email: "old@test.com", TokenHash: util.HashToken("old-email-token"), ExpiresAt: time.Now().Add(time.Hour)},
+			{ID: 3, UserID: 1, Email: "test@test.com", TokenHash: util.HashToken("valid-token"), ExpiresAt: time.Now().Add(time.Hour)},
+			{ID: 4, UserID: 1, Email: "test@test.com", TokenHash: util.HashToken("other-token"), ExpiresAt: time.Now().Add(time.Hour)},
+		},
+	}
+
+	verificationService := NewEmailVerificationService(mockRepository, mockUserRepository, &mock.MockMailer{}, "")
+
+	for _, token := range []string{"unknown-token", "expired-token", "old-email-token"} {
+		err := verificationService.VerifyEmail(token)
+		if !errors.Is(err, domain.ErrInvalidVerificationToken) {
+			t.Errorf("Expected ErrInvalidVerificationToken for %s, got %v", token, err)
+		}
+	}
+	if mockUserRepository.Users[0].EmailVerified() {
+		t.Fatalf("Expected the email to be pending verification")
+	}
+
+	if err := verificationService.VerifyEmail("valid-token"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !mockUserRepository.Users[0].EmailVerified() {
+		t.Errorf("Expected the email to be verified")
+	}
+
+	// The other token of the user is now invalid
+	if err := verificationService.VerifyEmail("other-token"); !errors.Is(err, domain.ErrInvalidVerificationToken) {
+		t.Errorf("Expected ErrInvalidVerificationToken, got %v", err)
+	}
+}
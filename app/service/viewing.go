@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type ViewingService struct {
+	Repo          port.ViewingRepository
+	WatchlistRepo port.WatchlistRepository
+}
+
+func NewViewingService(repo port.ViewingRepository, watchlistRepo port.WatchlistRepository) *ViewingService {
+	return &ViewingService{
+		Repo:          repo,
+		WatchlistRepo: watchlistRepo,
+	}
+}
+
+// LogViewing saves a viewing, and takes the movie off the user's watchlist
+// now that they have watched it.
+func (v *ViewingService) LogViewing(viewing *domain.Viewing) error {
+	if err := v.prepareViewing(viewing); err != nil {
+		return err
+	}
+	if err := v.Repo.CreateViewing(viewing); err != nil {
+		return err
+	}
+
+	entry, err := v.WatchlistRepo.GetWatchlistEntry(viewing.UserID, viewing.MovieID)
+	if errors.Is(err, domain.ErrWatchlistEntryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return v.WatchlistRepo.RemoveFromWatchlist(entry)
+}
+
+func (v *ViewingService) GetViewing(id uint) (*domain.Viewing, error) {
+	viewing, err := v.Repo.GetViewing(id)
+	if err != nil {
+		return nil, err
+	}
+	return viewing, nil
+}
+
+func (v *ViewingService) UpdateViewing(viewing *domain.Viewing) error {
+	if err := v.prepareViewing(viewing); err != nil {
+		return err
+	}
+	return v.Repo.UpdateViewing(viewing)
+}
+
+func (v *ViewingService) DeleteViewing(viewing *domain.Viewing) error {
+	return v.Repo.DeleteViewing(viewing)
+}
+
+func (v *ViewingService) ListViewings(userID uint, filter port.ViewingFilter, pagination port.Pagination) ([]*domain.Viewing, int64, error) {
+	viewings, total, err := v.Repo.ListViewings(userID, filter, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return viewings, total, nil
+}
+
+// GetDiary returns the viewings of a user during a month, grouped by day in
+// chronological order. Days without viewings are left out, and days are
+// delimited in UTC.
+func (v *ViewingService) GetDiary(userID uint, year int, month time.Month) ([]*domain.DiaryDay, error) {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	viewings, _, err := v.Repo.ListViewings(userID, port.ViewingFilter{WatchedFrom: &from, WatchedTo: &to}, port.Pagination{})
+	if err != nil {
+		return nil, err
+	}
+
+	days := []*domain.DiaryDay{}
+	// Viewings are listed newest first, so walk them backwards.
+	for i := len(viewings) - 1; i >= 0; i-- {
+		watchedAt := viewings[i].WatchedAt.UTC()
+		date := time.Date(watchedAt.Year(), watchedAt.Month(), watchedAt.Day(), 0, 0, 0, 0, time.UTC)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, &domain.DiaryDay{Date: date})
+		}
+		day := days[len(days)-1]
+		day.Viewings = append(day.Viewings, viewings[i])
+	}
+
+	return days, nil
+}
+
+// prepareViewing cleans up a viewing before saving it, and flags it as a
+// rewatch when the user had already watched the movie earlier.
+func (v *ViewingService) prepareViewing(viewing *domain.Viewing) error {
+	viewing.Location = strings.TrimSpace(viewing.Location)
+	viewing.Notes = strings.TrimSpace(viewing.Notes)
+	if viewing.WatchedAt.IsZero() {
+		viewing.WatchedAt = time.Now()
+	}
+
+	if !viewing.Rewatch {
+		viewedBefore, err := v.Repo.HasViewedBefore(viewing.UserID, viewing.MovieID, viewing.WatchedAt)
+		if err != nil {
+			return err
+		}
+		viewing.Rewatch = viewedBefore
+	}
+	return nil
+}
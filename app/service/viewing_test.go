@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestLogViewingDetectsRewatch(t *testing.T) {
+	mockRepository := &mock.MockViewingRepository{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 1, MovieID: 1, WatchedAt: time.Date(2025, 1, 1, 20, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	viewingService := NewViewingService(mockRepository, &mock.MockWatchlistRepository{})
+
+	rewatch := &domain.Viewing{UserID: 1, MovieID: 1, WatchedAt: time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC), Location: " Cinema "}
+	if err := viewingService.LogViewing(rewatch); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if !rewatch.Rewatch {
+		t.Errorf("Expected the second viewing to be a rewatch")
+	}
+	if rewatch.Location != "Cinema" {
+		t.Errorf("Expected location 'Cinema', got '%s'", rewatch.Location)
+	}
+
+	firstViewing := &domain.Viewing{UserID: 2, MovieID: 1, WatchedAt: time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)}
+	if err := viewingService.LogViewing(firstViewing); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if firstViewing.Rewatch {
+		t.Errorf("Expected another user's first viewing not to be a rewatch")
+	}
+}
+
+func TestLogViewingRemovesFromWatchlist(t *testing.T) {
+	mockRepository := &mock.MockViewingRepository{}
+	mockWatchlistRepository := &mock.MockWatchlistRepository{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1},
+			{UserID: 1, MovieID: 2, Position: 2},
+			{UserID: 2, MovieID: 1, Position: 1},
+		},
+	}
+
+	viewingService := NewViewingService(mockRepository, mockWatchlistRepository)
+	if err := viewingService.LogViewing(&domain.Viewing{UserID: 1, MovieID: 1}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(mockWatchlistRepository.Entries) != 2 {
+		t.Fatalf("Expected 2 watchlist entries left, got %d", len(mockWatchlistRepository.Entries))
+	}
+	if _, err := mockWatchlistRepository.GetWatchlistEntry(1, 1); err == nil {
+		t.Errorf("Expected the watched movie to be off the watchlist")
+	}
+	if entry, _ := mockWatchlistRepository.GetWatchlistEntry(1, 2); entry.Position != 1 {
+		t.Errorf("Expected the next movie to move up to position 1, got %d", entry.Position)
+	}
+
+	if err := viewingService.LogViewing(&domain.Viewing{UserID: 1, MovieID: 3}); err != nil {
+		t.Errorf("Expected no error for a movie out of the watchlist, got %v", err)
+	}
+}
+
+// unavailableWatchlistRepository fails to look up watchlist entries.
+type unavailableWatchlistRepository struct {
+	mock.MockWatchlistRepository
+}
+
+func (r *unavailableWatchlistRepository) GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestLogViewingReturnsWatchlistErrors(t *testing.T) {
+	mockRepository := &mock.MockViewingRepository{}
+
+	viewingService := NewViewingService(mockRepository, &unavailableWatchlistRepository{})
+	if err := viewingService.LogViewing(&domain.Viewing{UserID: 1, MovieID: 1}); err == nil || errors.Is(err, domain.ErrWatchlistEntryNotFound) {
+		t.Errorf("Expected the watchlist error, got %v", err)
+	}
+}
+
+func TestLogViewingDefaultsToNow(t *testing.T) {
+	mockRepository := &mock.MockViewingRepository{}
+
+	viewingService := NewViewingService(mockRepository, &mock.MockWatchlistRepository{})
+	viewing := &domain.Viewing{UserID: 1, MovieID: 1}
+	if err := viewingService.LogViewing(viewing); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if time.Since(viewing.WatchedAt) > time.Minute {
+		t.Errorf("Expected the viewing to be watched now, got %v", viewing.WatchedAt)
+	}
+}
+
+func TestGetDiary(t *testing.T) {
+	mockRepository := &mock.MockViewingRepository{
+		Viewings: []*domain.Viewing{
+			{ID: 1, UserID: 1, MovieID: 1, WatchedAt: time.Date(2026, 10, 5, 21, 0, 0, 0, time.UTC)},
+			{ID: 2, UserID: 1, MovieID: 2, WatchedAt: time.Date(2026, 10, 3, 18, 0, 0, 0, time.UTC)},
+			{ID: 3, UserID: 1, MovieID: 3, WatchedAt: time.Date(2026, 10, 5, 15, 0, 0, 0, time.UTC)},
+			{ID: 4, UserID: 1, MovieID: 4, WatchedAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: 5, UserID: 2, MovieID: 1, WatchedAt: time.Date(2026, 10, 5, 21, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	viewingService := NewViewingService(mockRepository, &mock.MockWatchlistRepository{})
+	days, err := viewingService.GetDiary(1, 2026, time.October)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(days))
+	}
+	if days[0].Date.Day() != 3 || len(days[0].Viewings) != 1 {
+		t.Errorf("Expected 1 viewing on the 3rd first, got %d on the %d", len(days[0].Viewings), days[0].Date.Day())
+	}
+	if days[1].Date.Day() != 5 || len(days[1].Viewings) != 2 {
+		t.Fatalf("Expected 2 viewings on the 5th, got %d on the %d", len(days[1].Viewings), days[1].Date.Day())
+	}
+	if days[1].Viewings[0].ID != 3 {
+		t.Errorf("Expected the viewings of a day in chronological order, got %d first", days[1].Viewings[0].ID)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+)
+
+type WatchlistService struct {
+	Repo port.WatchlistRepository
+}
+
+func NewWatchlistService(repo port.WatchlistRepository) *WatchlistService {
+	return &WatchlistService{
+		Repo: repo,
+	}
+}
+
+// AddToWatchlist appends a movie at the end of the user's watchlist.
+func (w *WatchlistService) AddToWatchlist(entry *domain.WatchlistEntry) error {
+	if entry.Priority == "" {
+		entry.Priority = domain.WatchlistPriorityNormal
+	}
+	if entry.AddedAt.IsZero() {
+		entry.AddedAt = time.Now()
+	}
+	return w.Repo.AddToWatchlist(entry)
+}
+
+func (w *WatchlistService) GetWatchlistEntry(userID uint, movieID uint) (*domain.WatchlistEntry, error) {
+	entry, err := w.Repo.GetWatchlistEntry(userID, movieID)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (w *WatchlistService) UpdateWatchlistEntry(entry *domain.WatchlistEntry) error {
+	return w.Repo.UpdateWatchlistEntry(entry)
+}
+
+func (w *WatchlistService) RemoveFromWatchlist(entry *domain.WatchlistEntry) error {
+	return w.Repo.RemoveFromWatchlist(entry)
+}
+
+func (w *WatchlistService) ListWatchlist(userID uint, pagination port.Pagination) ([]*domain.WatchlistEntry, int64, error) {
+	entries, total, err := w.Repo.ListWatchlist(userID, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ReorderWatchlist moves the movies of a user's watchlist to the order of
+// movieIDs, which must list every movie in it exactly once.
+func (w *WatchlistService) ReorderWatchlist(userID uint, movieIDs []uint) error {
+	entries, _, err := w.Repo.ListWatchlist(userID, port.Pagination{})
+	if err != nil {
+		return err
+	}
+	if len(entries) != len(movieIDs) {
+		return domain.ErrInvalidWatchlistOrder
+	}
+
+	remaining := map[uint]bool{}
+	for _, entry := range entries {
+		remaining[entry.MovieID] = true
+	}
+	for _, movieID := range movieIDs {
+		if !remaining[movieID] {
+			return domain.ErrInvalidWatchlistOrder
+		}
+		delete(remaining, movieID)
+	}
+
+	return w.Repo.ReorderWatchlist(userID, movieIDs)
+}
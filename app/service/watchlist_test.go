@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Acova/movie-collection/app/domain"
+	"github.com/Acova/movie-collection/app/port"
+	"github.com/Acova/movie-collection/app/port/mock"
+)
+
+func TestAddToWatchlist(t *testing.T) {
+	mockRepository := &mock.MockWatchlistRepository{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1, Priority: domain.WatchlistPriorityHigh},
+			{UserID: 2, MovieID: 1, Position: 1, Priority: domain.WatchlistPriorityNormal},
+			{UserID: 2, MovieID: 2, Position: 2, Priority: domain.WatchlistPriorityNormal},
+		},
+	}
+
+	watchlistService := NewWatchlistService(mockRepository)
+	entry := &domain.WatchlistEntry{UserID: 1, MovieID: 2}
+	if err := watchlistService.AddToWatchlist(entry); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if entry.Position != 2 {
+		t.Errorf("Expected the movie at the end of the watchlist, got position %d", entry.Position)
+	}
+	if entry.Priority != domain.WatchlistPriorityNormal {
+		t.Errorf("Expected the normal priority by default, got '%s'", entry.Priority)
+	}
+	if entry.AddedAt.IsZero() {
+		t.Errorf("Expected the date added to be set")
+	}
+}
+
+func TestRemoveFromWatchlist(t *testing.T) {
+	mockRepository := &mock.MockWatchlistRepository{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1},
+			{UserID: 1, MovieID: 2, Position: 2},
+			{UserID: 1, MovieID: 3, Position: 3},
+		},
+	}
+
+	watchlistService := NewWatchlistService(mockRepository)
+	if err := watchlistService.RemoveFromWatchlist(&domain.WatchlistEntry{UserID: 1, MovieID: 2}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	entries, _, _ := watchlistService.ListWatchlist(1, port.Pagination{})
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].MovieID != 3 || entries[1].Position != 2 {
+		t.Errorf("Expected movie 3 to move up to position 2, got movie %d at %d", entries[1].MovieID, entries[1].Position)
+	}
+}
+
+func TestReorderWatchlist(t *testing.T) {
+	mockRepository := &mock.MockWatchlistRepository{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1},
+			{UserID: 1, MovieID: 2, Position: 2},
+			{UserID: 1, MovieID: 3, Position: 3},
+		},
+	}
+
+	watchlistService := NewWatchlistService(mockRepository)
+	if err := watchlistService.ReorderWatchlist(1, []uint{3, 1, 2}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	entries, _, _ := watchlistService.ListWatchlist(1, port.Pagination{})
+	for i, expectedMovieID := range []uint{3, 1, 2} {
+		if entries[i].MovieID != expectedMovieID || entries[i].Position != i+1 {
+			t.Errorf("Expected movie %d at position %d, got movie %d at %d", expectedMovieID, i+1, entries[i].MovieID, entries[i].Position)
+		}
+	}
+}
+
+func TestReorderWatchlistRejectsOtherMovies(t *testing.T) {
+	mockRepository := &mock.MockWatchlistRepository{
+		Entries: []*domain.WatchlistEntry{
+			{UserID: 1, MovieID: 1, Position: 1},
+			{UserID: 1, MovieID: 2, Position: 2},
+		},
+	}
+
+	watchlistService := NewWatchlistService(mockRepository)
+	for _, movieIDs := range [][]uint{{1}, {1, 3}, {1, 1}, {2, 1, 3}} {
+		err := watchlistService.ReorderWatchlist(1, movieIDs)
+		if !errors.Is(err, domain.ErrInvalidWatchlistOrder) {
+			t.Errorf("Expected an invalid order error for %v, got %v", movieIDs, err)
+		}
+	}
+	if mockRepository.Entries[0].Position != 1 || mockRepository.Entries[1].Position != 2 {
+		t.Errorf("Expected the watchlist order to be unchanged")
+	}
+}
@@ -0,0 +1,279 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrPasswordMismatch is returned when a password does not match a hash.
+	ErrPasswordMismatch = errors.New("password does not match")
+	// ErrUnsupportedPasswordHash is returned for hashes no hasher can check.
+	ErrUnsupportedPasswordHash = errors.New("unsupported password hash")
+)
+
+// PasswordHasher hashes passwords into strings which record the algorithm and
+// parameters used, so that hashes made with older ones can still be checked.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify fails with ErrPasswordMismatch when the password does not match
+	// the hash, and with ErrUnsupportedPasswordHash when the hash is not in a
+	// format the hasher knows.
+	Verify(password string, hash string) error
+	// NeedsRehash reports whether a hash was not made with the algorithm and
+	// parameters the hasher uses for new hashes.
+	NeedsRehash(hash string) bool
+}
+
+// Argon2idHasher hashes passwords with argon2id (RFC 9106), in the PHC string
+// format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+// Hashes are checked with the parameters recorded in them.
+type Argon2idHasher struct {
+	// Memory is in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// NewArgon2idHasher returns a hasher with the parameters OWASP recommends at
+// least: 19 MiB of memory, 2 iterations and a single thread.
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Memory:      19 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password string, hash string) error {
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Memory || params.Iterations != h.Iterations || params.Parallelism != h.Parallelism ||
+		uint32(len(salt)) != h.SaltLength || uint32(len(key)) != h.KeyLength
+}
+
+// decodeArgon2idHash returns the parameters, salt and key of an argon2id hash
+// in the PHC string format.
+func decodeArgon2idHash(hash string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrUnsupportedPasswordHash
+	}
+	if parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return nil, nil, nil, fmt.Errorf("%w: argon2 version %s", ErrUnsupportedPasswordHash, parts[2])
+	}
+
+	params := &Argon2idHasher{}
+	var parallelism uint32
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedPasswordHash, err)
+	}
+	if params.Memory == 0 || params.Iterations == 0 || parallelism == 0 || parallelism > 255 {
+		return nil, nil, nil, fmt.Errorf("%w: invalid argon2 parameters %s", ErrUnsupportedPasswordHash, parts[3])
+	}
+	params.Parallelism = uint8(parallelism)
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedPasswordHash, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: invalid argon2 key", ErrUnsupportedPasswordHash)
+	}
+	return params, salt, key, nil
+}
+
+// BcryptMaxPasswordBytes is the longest password bcrypt hashes, in bytes.
+const BcryptMaxPasswordBytes = 72
+
+// BcryptHasher hashes passwords with bcrypt, which records its cost in the
+// hash. It was the only algorithm used before argon2id.
+type BcryptHasher struct {
+	Cost int
+}
+
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{Cost: bcrypt.DefaultCost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+func (h *BcryptHasher) Verify(password string, hash string) error {
+	if !isBcryptHash(hash) {
+		return ErrUnsupportedPasswordHash
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrPasswordMismatch
+	}
+	return err
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// MultiPasswordHasher hashes new passwords with its Default hasher, and checks
+// hashes made by it or by any of its Legacy hashers, whatever the parameters.
+// Hashes not made by the default hasher with its current parameters need a
+// rehash.
+type MultiPasswordHasher struct {
+	Default PasswordHasher
+	Legacy  []PasswordHasher
+}
+
+func NewMultiPasswordHasher(defaultHasher PasswordHasher, legacyHashers ...PasswordHasher) *MultiPasswordHasher {
+	return &MultiPasswordHasher{
+		Default: defaultHasher,
+		Legacy:  legacyHashers,
+	}
+}
+
+func (h *MultiPasswordHasher) Hash(password string) (string, error) {
+	return h.Default.Hash(password)
+}
+
+func (h *MultiPasswordHasher) Verify(password string, hash string) error {
+	for _, hasher := range append([]PasswordHasher{h.Default}, h.Legacy...) {
+		if err := hasher.Verify(password, hash); !errors.Is(err, ErrUnsupportedPasswordHash) {
+			return err
+		}
+	}
+	return ErrUnsupportedPasswordHash
+}
+
+func (h *MultiPasswordHasher) NeedsRehash(hash string) bool {
+	return h.Default.NeedsRehash(hash)
+}
+
+// MaxPasswordBytes returns the longest password, in bytes, the default hasher
+// can hash, or 0 when it has no limit.
+func (h *MultiPasswordHasher) MaxPasswordBytes() int {
+	if _, ok := h.Default.(*BcryptHasher); ok {
+		return BcryptMaxPasswordBytes
+	}
+	return 0
+}
+
+// passwordHasher is the hasher HashPassword, ComparePasswords and
+// PasswordNeedsRehash use.
+var passwordHasher PasswordHasher = NewMultiPasswordHasher(NewArgon2idHasher(), NewBcryptHasher())
+
+// SetPasswordHasher changes the hasher used for passwords. It is meant to be
+// called once, when the app starts.
+func SetPasswordHasher(hasher PasswordHasher) {
+	passwordHasher = hasher
+}
+
+// PasswordNeedsRehash reports whether a password hash was made with another
+// algorithm or parameters than new hashes are, so that it should be replaced
+// the next time the password is at hand.
+func PasswordNeedsRehash(hashedPassword string) bool {
+	return passwordHasher.NeedsRehash(hashedPassword)
+}
+
+// NewPasswordHasherFromEnv hashes new passwords with the algorithm in
+// PASSWORD_HASHER, argon2id or bcrypt, defaulting to argon2id. Its parameters
+// are read from ARGON2ID_MEMORY_KIB, ARGON2ID_ITERATIONS and
+// ARGON2ID_PARALLELISM, or from BCRYPT_COST, and default to the recommended
+// ones. Hashes made with either algorithm can be checked whatever the choice.
+func NewPasswordHasherFromEnv() (*MultiPasswordHasher, error) {
+	switch algorithm := os.Getenv("PASSWORD_HASHER"); algorithm {
+	case "", "argon2id":
+		hasher := NewArgon2idHasher()
+		memory, err := uintFromEnv("ARGON2ID_MEMORY_KIB", uint64(hasher.Memory), 8, 1<<32-1)
+		if err != nil {
+			return nil, err
+		}
+		iterations, err := uintFromEnv("ARGON2ID_ITERATIONS", uint64(hasher.Iterations), 1, 1<<32-1)
+		if err != nil {
+			return nil, err
+		}
+		parallelism, err := uintFromEnv("ARGON2ID_PARALLELISM", uint64(hasher.Parallelism), 1, 255)
+		if err != nil {
+			return nil, err
+		}
+		hasher.Memory = uint32(memory)
+		hasher.Iterations = uint32(iterations)
+		hasher.Parallelism = uint8(parallelism)
+		return NewMultiPasswordHasher(hasher, NewBcryptHasher()), nil
+	case "bcrypt":
+		hasher := NewBcryptHasher()
+		cost, err := uintFromEnv("BCRYPT_COST", uint64(hasher.Cost), uint64(bcrypt.MinCost), uint64(bcrypt.MaxCost))
+		if err != nil {
+			return nil, err
+		}
+		hasher.Cost = int(cost)
+		return NewMultiPasswordHasher(hasher, NewArgon2idHasher()), nil
+	default:
+		return nil, fmt.Errorf("unknown password hasher %q", algorithm)
+	}
+}
+
+// uintFromEnv returns the unsigned integer in an environment variable, or
+// defaultValue when it is not set.
+func uintFromEnv(name string, defaultValue uint64, minValue uint64, maxValue uint64) (uint64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || parsed < minValue || parsed > maxValue {
+		return 0, fmt.Errorf("invalid %s %q, must be between %d and %d", name, value, minValue, maxValue)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher()
+
+	hash, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Unexpected error hashing password: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("Expected a PHC string with the parameters of the hasher, got %s", hash)
+	}
+	if otherHash, _ := hasher.Hash("password"); otherHash == hash {
+		t.Error("Hashing the same password twice should give different salts")
+	}
+
+	if err := hasher.Verify("password", hash); err != nil {
+		t.Errorf("Expected the password to match, got %v", err)
+	}
+	if err := hasher.Verify("other password", hash); !errors.Is(err, ErrPasswordMismatch) {
+		t.Errorf("Expected %v, got %v", ErrPasswordMismatch, err)
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("A hash made with the current parameters should not need a rehash")
+	}
+}
+
+func TestArgon2idHasherVerifiesOtherParameters(t *testing.T) {
+	// The test vector of the reference implementation, made with 64 MiB
+	hash := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	hasher := NewArgon2idHasher()
+
+	if err := hasher.Verify("password", hash); err != nil {
+		t.Errorf("Expected the password to match, got %v", err)
+	}
+	if !hasher.NeedsRehash(hash) {
+		t.Error("A hash made with other parameters should need a rehash")
+	}
+}
+
+func TestArgon2idHasherRejectsInvalidHashes(t *testing.T) {
+	hasher := NewArgon2idHasher()
+
+	for _, hash := range []string{
+		"",
+		"$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		"$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=0,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+		"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$",
+	} {
+		if err := hasher.Verify("password", hash); !errors.Is(err, ErrUnsupportedPasswordHash) {
+			t.Errorf("Expected %v for %q, got %v", ErrUnsupportedPasswordHash, hash, err)
+		}
+	}
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := &BcryptHasher{Cost: 4}
+
+	hash, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Unexpected error hashing password: %v", err)
+	}
+	if err := hasher.Verify("password", hash); err != nil {
+		t.Errorf("Expected the password to match, got %v", err)
+	}
+	if err := hasher.Verify("other password", hash); !errors.Is(err, ErrPasswordMismatch) {
+		t.Errorf("Expected %v, got %v", ErrPasswordMismatch, err)
+	}
+	if hasher.NeedsRehash(hash) {
+		t.Error("A hash made with the current cost should not need a rehash")
+	}
+	if !NewBcryptHasher().NeedsRehash(hash) {
+		t.Error("A hash made with another cost should need a rehash")
+	}
+}
+
+func TestMultiPasswordHasher(t *testing.T) {
+	bcryptHasher := &BcryptHasher{Cost: 4}
+	hasher := NewMultiPasswordHasher(NewArgon2idHasher(), bcryptHasher)
+	legacyHash, _ := bcryptHasher.Hash("password")
+
+	hash, _ := hasher.Hash("password")
+	if !strings.HasPrefix(hash, "$argon2id$") {
+		t.Errorf("Expected new hashes to use the default hasher, got %s", hash)
+	}
+
+	for _, hash := range []string{hash, legacyHash} {
+		if err := hasher.Verify("password", hash); err != nil {
+			t.Errorf("Expected the password to match %s, got %v", hash, err)
+		}
+		if err := hasher.Verify("other password", hash); !errors.Is(err, ErrPasswordMismatch) {
+			t.Errorf("Expected %v for %s, got %v", ErrPasswordMismatch, hash, err)
+		}
+	}
+	if err := hasher.Verify("password", "$scrypt$ln=16,r=8,p=1$c29tZXNhbHQ$aGFzaA"); !errors.Is(err, ErrUnsupportedPasswordHash) {
+		t.Errorf("Expected %v for an unknown algorithm, got %v", ErrUnsupportedPasswordHash, err)
+	}
+
+	if hasher.NeedsRehash(hash) || !hasher.NeedsRehash(legacyHash) {
+		t.Error("Expected only the legacy hash to need a rehash")
+	}
+}
+
+func TestNewPasswordHasherFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_HASHER", "")
+	t.Setenv("ARGON2ID_MEMORY_KIB", "65536")
+	t.Setenv("ARGON2ID_ITERATIONS", "3")
+	hasher, err := NewPasswordHasherFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if argon2idHasher, ok := hasher.Default.(*Argon2idHasher); !ok || argon2idHasher.Memory != 65536 || argon2idHasher.Iterations != 3 || argon2idHasher.Parallelism != 1 {
+		t.Errorf("Expected argon2id with 64 MiB and 3 iterations, got %+v", hasher.Default)
+	}
+	if maxBytes := hasher.MaxPasswordBytes(); maxBytes != 0 {
+		t.Errorf("Expected argon2id to take passwords of any length, got %d", maxBytes)
+	}
+
+	t.Setenv("PASSWORD_HASHER", "bcrypt")
+	t.Setenv("BCRYPT_COST", "12")
+	hasher, err = NewPasswordHasherFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if bcryptHasher, ok := hasher.Default.(*BcryptHasher); !ok || bcryptHasher.Cost != 12 {
+		t.Errorf("Expected bcrypt with a cost of 12, got %+v", hasher.Default)
+	}
+	if maxBytes := hasher.MaxPasswordBytes(); maxBytes != BcryptMaxPasswordBytes {
+		t.Errorf("Expected bcrypt to take passwords of up to %d bytes, got %d", BcryptMaxPasswordBytes, maxBytes)
+	}
+
+	t.Setenv("BCRYPT_COST", "50")
+	if _, err := NewPasswordHasherFromEnv(); err == nil {
+		t.Error("Expected an out of range cost to be rejected")
+	}
+	t.Setenv("PASSWORD_HASHER", "md5")
+	if _, err := NewPasswordHasherFromEnv(); err == nil {
+		t.Error("Expected an unknown algorithm to be rejected")
+	}
+}
@@ -1,16 +1,45 @@
 package util
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
 
+// HashPassword hashes a password with the hasher set with SetPasswordHasher,
+// argon2id by default.
 func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+	return passwordHasher.Hash(password)
+}
+
+// ComparePasswords checks a password against a hash made by any of the
+// algorithms the password hasher knows, such as older bcrypt hashes.
+func ComparePasswords(password, hashedPassword string) error {
+	return passwordHasher.Verify(password, hashedPassword)
+}
+
+// GenerateToken returns a random URL-safe token built from n random bytes.
+func GenerateToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	return string(hashedPassword), nil
+// HashToken returns the SHA-256 hex digest of a random token, so that it can
+// be stored and looked up without keeping the token itself. Unlike passwords,
+// random tokens do not need a slow, salted hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func ComparePasswords(password, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// PKCECodeChallenge returns the S256 code challenge of a PKCE code verifier,
+// sent when starting an OAuth authorization so that only whoever holds the
+// verifier can redeem its code.
+func PKCECodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
 }
@@ -23,6 +23,45 @@ func TestHashPassword(t *testing.T) {
 	}
 }
 
+func TestGenerateToken(t *testing.T) {
+	token, err := GenerateToken(16)
+	if err != nil {
+		t.Errorf("Unexpected error generating token: %v", err)
+	}
+
+	if len(token) != 22 {
+		t.Errorf("Expected a 22 characters token, got %d", len(token))
+	}
+
+	otherToken, _ := GenerateToken(16)
+	if token == otherToken {
+		t.Error("Two generated tokens should not be equal")
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	hash := HashToken("token")
+	if len(hash) != 64 {
+		t.Errorf("Expected a 64 characters hash, got %d", len(hash))
+	}
+
+	if hash != HashToken("token") {
+		t.Error("Hashing the same token twice should give the same hash")
+	}
+
+	if hash == HashToken("other token") {
+		t.Error("Hashing different tokens should give different hashes")
+	}
+}
+
+func TestPKCECodeChallenge(t *testing.T) {
+	// The example of RFC 7636, appendix B
+	challenge := PKCECodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("Expected the code challenge of RFC 7636, got %s", challenge)
+	}
+}
+
 // RandomString generates a random string of the given length.
 func RandomString(n int) string {
 	letters := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
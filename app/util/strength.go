@@ -0,0 +1,570 @@
+package util
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Password strength is estimated the way zxcvbn does: the password is split
+// into the patterns attackers try first, such as common passwords, words of
+// the user, sequences, repeats, keyboard rows and dates, and the guesses each
+// of them takes are multiplied along the split taking the fewest guesses.
+// What no pattern covers is brute forced.
+
+const (
+	// maxStrengthRunes is how much of a password is looked at. Longer ones
+	// are strong enough with their first characters already.
+	maxStrengthRunes = 100
+	// bruteforceCardinality is how many guesses each brute forced character
+	// takes.
+	bruteforceCardinality = 10
+	// minSubmatchGuesses is the fewest guesses a pattern covering part of the
+	// password takes, so that splitting it into tiny patterns does not pay.
+	minSubmatchGuesses = 50
+	// minGuessesBeforeGrowingSequence is what each extra pattern of a split
+	// costs, as attackers try fewer patterns first.
+	minGuessesBeforeGrowingSequence = 10000
+	// minYearSpace is the fewest years guessed around the current one.
+	minYearSpace = 20
+	// maxWordRunes is the longest common password or user input looked for.
+	maxWordRunes = 40
+	// keyboardGuessesPerKey is how many guesses each key of a keyboard row
+	// takes: about 94 starting keys, times 4.6 neighbours on average.
+	keyboardGuessesPerKey = 94 * 4.6
+)
+
+// strengthThresholds are the guesses each strength score from 1 to 4 takes at
+// least. A password taking fewer than 10^3 guesses scores 0.
+var strengthThresholds = []float64{1e3 + 5, 1e6 + 5, 1e8 + 5, 1e10 + 5}
+
+// commonPasswordsFile lists common passwords and words, most common first.
+//
+//go:embed commonpasswords.txt
+var commonPasswordsFile string
+
+var commonPasswordRanks = sync.OnceValue(func() map[string]int {
+	ranks := map[string]int{}
+	for _, word := range strings.Fields(commonPasswordsFile) {
+		if _, ok := ranks[word]; !ok {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+	return ranks
+})
+
+// l33tSubstitutions are the letters each symbol commonly stands for.
+var l33tSubstitutions = map[rune][]rune{
+	'4': {'a'}, '@': {'a'}, '8': {'b'}, '(': {'c'}, '3': {'e'}, '6': {'g'},
+	'1': {'i', 'l'}, '!': {'i'}, '|': {'i', 'l'}, '0': {'o'}, '$': {'s'},
+	'5': {'s'}, '7': {'t'}, '+': {'t'}, '2': {'z'}, '%': {'x'},
+}
+
+// keyboardRows are the rows of a QWERTY keyboard, whose neighbouring keys are
+// typed in a row.
+var keyboardRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+// PasswordStrength scores how hard a password is to guess, from 0 (guessed at
+// once) to 4 (very hard to guess). Words from userInputs, such as the email
+// and name of the user, are guessed first.
+func PasswordStrength(password string, userInputs ...string) int {
+	guesses := PasswordGuesses(password, userInputs...)
+	score := 0
+	for _, threshold := range strengthThresholds {
+		if guesses >= threshold {
+			score++
+		}
+	}
+	return score
+}
+
+// PasswordGuesses estimates how many guesses finding a password takes.
+func PasswordGuesses(password string, userInputs ...string) float64 {
+	runes := []rune(password)
+	if len(runes) > maxStrengthRunes {
+		runes = runes[:maxStrengthRunes]
+	}
+	if len(runes) == 0 {
+		return 1
+	}
+
+	estimator := &strengthEstimator{
+		ranks:    userInputRanks(userInputs),
+		guesses:  map[string]float64{},
+		thisYear: time.Now().Year(),
+	}
+	return estimator.estimate(runes)
+}
+
+// userInputRanks ranks the user inputs and the words in them, such as the
+// parts of an email, after one another.
+func userInputRanks(userInputs []string) map[string]int {
+	ranks := map[string]int{}
+	add := func(word string) {
+		if _, ok := ranks[word]; !ok && len([]rune(word)) >= 3 {
+			ranks[word] = len(ranks) + 1
+		}
+	}
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		add(input)
+		for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			add(word)
+		}
+	}
+	return ranks
+}
+
+// passwordMatch is a pattern covering the runes from i to j of a password,
+// both included.
+type passwordMatch struct {
+	i, j    int
+	guesses float64
+}
+
+type strengthEstimator struct {
+	ranks    map[string]int
+	guesses  map[string]float64
+	thisYear int
+}
+
+// estimate returns the guesses the split of the password into patterns taking
+// the fewest guesses takes.
+func (e *strengthEstimator) estimate(password []rune) float64 {
+	if guesses, ok := e.guesses[string(password)]; ok {
+		return guesses
+	}
+
+	n := len(password)
+	lower := []rune(strings.ToLower(string(password)))
+	if len(lower) != n {
+		lower = password
+	}
+	matchesByEnd := make([][]passwordMatch, n)
+	for _, match := range e.matches(password, lower) {
+		if match.j-match.i+1 < n {
+			match.guesses = max(match.guesses, minSubmatchGuesses)
+		}
+		matchesByEnd[match.j] = append(matchesByEnd[match.j], match)
+	}
+	for j := range n {
+		for i := 0; i <= j; i++ {
+			matchesByEnd[j] = append(matchesByEnd[j], passwordMatch{i: i, j: j, guesses: bruteforceGuesses(j - i + 1)})
+		}
+	}
+
+	// products[k][l] is the lowest product of guesses of a split of the
+	// first k+1 runes into l patterns.
+	products := make([]map[int]float64, n)
+	for k := range n {
+		products[k] = map[int]float64{}
+		for _, match := range matchesByEnd[k] {
+			if match.i == 0 {
+				keepLowest(products[k], 1, match.guesses)
+				continue
+			}
+			for l, product := range products[match.i-1] {
+				keepLowest(products[k], l+1, product*match.guesses)
+			}
+		}
+	}
+
+	guesses := math.Inf(1)
+	for l, product := range products[n-1] {
+		guesses = min(guesses, factorial(l)*product+math.Pow(minGuessesBeforeGrowingSequence, float64(l-1)))
+	}
+	e.guesses[string(password)] = guesses
+	return guesses
+}
+
+func keepLowest(products map[int]float64, l int, product float64) {
+	if current, ok := products[l]; !ok || product < current {
+		products[l] = product
+	}
+}
+
+func (e *strengthEstimator) matches(password []rune, lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	matches = append(matches, e.dictionaryMatches(password, lower)...)
+	matches = append(matches, sequenceMatches(lower)...)
+	matches = append(matches, e.repeatMatches(lower)...)
+	matches = append(matches, keyboardMatches(lower)...)
+	matches = append(matches, e.dateMatches(lower)...)
+	return matches
+}
+
+// dictionaryMatches finds the common passwords and user inputs in the
+// password, also reversed or with symbols standing for letters.
+func (e *strengthEstimator) dictionaryMatches(password []rune, lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	translations := l33tTranslations(lower)
+	for i := range lower {
+		for j := i + 2; j < min(len(lower), i+maxWordRunes); j++ {
+			word := string(lower[i : j+1])
+			variations := uppercaseVariations(password[i : j+1])
+
+			if rank := e.rank(word); rank > 0 {
+				matches = append(matches, passwordMatch{i: i, j: j, guesses: float64(rank) * variations})
+			}
+			if rank := e.rank(reverse(word)); rank > 0 {
+				matches = append(matches, passwordMatch{i: i, j: j, guesses: float64(rank) * variations * 2})
+			}
+			for _, translated := range translations {
+				translatedWord := string(translated[i : j+1])
+				if translatedWord == word {
+					continue
+				}
+				if rank := e.rank(translatedWord); rank > 0 {
+					guesses := float64(rank) * variations * l33tVariations(lower[i:j+1], translated[i:j+1])
+					matches = append(matches, passwordMatch{i: i, j: j, guesses: guesses})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// rank returns the rank of a word among the user inputs, or else among the
+// common passwords, and 0 for other words.
+func (e *strengthEstimator) rank(word string) int {
+	if rank, ok := e.ranks[word]; ok {
+		return rank
+	}
+	return commonPasswordRanks()[word]
+}
+
+// uppercaseVariations is how many ways of capitalizing a word are tried before
+// the way it is. Capitalizing the first letter, the last one or every letter
+// is tried right after leaving it in lower case.
+func uppercaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1]))) {
+		return 2
+	}
+
+	variations := 0.0
+	for k := 1; k <= min(upper, lower); k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return variations
+}
+
+// l33tTranslations returns the password with its symbols replaced with the
+// letters they may stand for, once for each choice of letters for the symbols
+// standing for several. Passwords without such symbols have none.
+func l33tTranslations(lower []rune) [][]rune {
+	translations := [][]rune{}
+	choices := map[rune]rune{}
+	var symbols []rune
+	for _, r := range lower {
+		if _, ok := l33tSubstitutions[r]; ok && !containsRune(symbols, r) {
+			symbols = append(symbols, r)
+		}
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	var choose func(k int)
+	choose = func(k int) {
+		if k == len(symbols) {
+			translated := make([]rune, len(lower))
+			for i, r := range lower {
+				if letter, ok := choices[r]; ok {
+					translated[i] = letter
+				} else {
+					translated[i] = r
+				}
+			}
+			translations = append(translations, translated)
+			return
+		}
+		for _, letter := range l33tSubstitutions[symbols[k]] {
+			choices[symbols[k]] = letter
+			choose(k + 1)
+		}
+	}
+	choose(0)
+	return translations
+}
+
+// l33tVariations is how many ways of replacing letters with symbols are tried
+// before the way they are in the word.
+func l33tVariations(word []rune, translated []rune) float64 {
+	variations := 1.0
+	seen := map[rune]bool{}
+	for k, symbol := range word {
+		letter := translated[k]
+		if letter == symbol || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		substituted, unsubstituted := 0, 0
+		for _, r := range word {
+			switch r {
+			case symbol:
+				substituted++
+			case letter:
+				unsubstituted++
+			}
+		}
+		if unsubstituted == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for k := 1; k <= min(substituted, unsubstituted); k++ {
+			possibilities += binomial(substituted+unsubstituted, k)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+// sequenceMatches finds runs of at least 3 letters or digits following one
+// another, such as abcd or 9876.
+func sequenceMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := 0; i < len(lower)-2; {
+		delta := lower[i+1] - lower[i]
+		j := i + 1
+		if delta == 1 || delta == -1 {
+			for j+1 < len(lower) && lower[j+1]-lower[j] == delta && sameSequenceClass(lower[i], lower[j+1]) {
+				j++
+			}
+		}
+		if j-i >= 2 && sameSequenceClass(lower[i], lower[j]) {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("az019", lower[i]):
+				base = 4
+			case unicode.IsDigit(lower[i]):
+				base = 10
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			matches = append(matches, passwordMatch{i: i, j: j, guesses: base * float64(j-i+1)})
+			i = j
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+func sameSequenceClass(a rune, b rune) bool {
+	return (a >= 'a' && a <= 'z' && b >= 'a' && b <= 'z') || (a >= '0' && a <= '9' && b >= '0' && b <= '9')
+}
+
+// repeatMatches finds a character repeated at least 3 times, or a longer part
+// repeated at least twice. Guessing them takes guessing the repeated part once
+// for each repetition.
+func (e *strengthEstimator) repeatMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := range lower {
+		for length := 1; i+2*length <= len(lower); length++ {
+			unit := string(lower[i : i+length])
+			if i >= length && string(lower[i-length:i]) == unit {
+				continue // Found already from the previous repetition
+			}
+			count := 1
+			for i+(count+1)*length <= len(lower) && string(lower[i+count*length:i+(count+1)*length]) == unit {
+				count++
+			}
+			if count < 2 || (length == 1 && count < 3) {
+				continue
+			}
+			guesses := e.estimate(lower[i:i+length]) * float64(count)
+			matches = append(matches, passwordMatch{i: i, j: i + count*length - 1, guesses: guesses})
+		}
+	}
+	return matches
+}
+
+// keyboardMatches finds runs of at least 3 neighbouring keys of a keyboard
+// row, such as qwerty or lkjh.
+func keyboardMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := 0; i < len(lower)-2; {
+		j := i
+		direction := 0
+		for j+1 < len(lower) {
+			step := keyboardStep(lower[j], lower[j+1])
+			if step == 0 || (direction != 0 && step != direction) {
+				break
+			}
+			direction = step
+			j++
+		}
+		if j-i >= 2 {
+			matches = append(matches, passwordMatch{i: i, j: j, guesses: keyboardGuessesPerKey * float64(j-i)})
+			i = j
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// keyboardStep returns 1 when b is the key right of a in a keyboard row, -1
+// when it is the key left of it, and 0 otherwise.
+func keyboardStep(a rune, b rune) int {
+	for _, row := range keyboardRows {
+		ia, ib := strings.IndexRune(row, a), strings.IndexRune(row, b)
+		if ia < 0 || ib < 0 {
+			continue
+		}
+		switch ib - ia {
+		case 1:
+			return 1
+		case -1:
+			return -1
+		}
+	}
+	return 0
+}
+
+// dateMatches finds years from 1900 to 2099, and dates written as 6 or 8
+// digits, day, month and year in any usual order. Guessing them takes
+// guessing the year, counting from the current one.
+func (e *strengthEstimator) dateMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := range lower {
+		for _, length := range []int{4, 6, 8} {
+			if i+length > len(lower) || !allDigits(lower[i:i+length]) {
+				continue
+			}
+			digits := string(lower[i : i+length])
+			if length == 4 {
+				if year := atoi(digits); year >= 1900 && year <= 2099 {
+					matches = append(matches, passwordMatch{i: i, j: i + 3, guesses: e.yearSpace(year)})
+				}
+				continue
+			}
+			if year, ok := dateYear(digits); ok {
+				matches = append(matches, passwordMatch{i: i, j: i + length - 1, guesses: 365 * e.yearSpace(year)})
+			}
+		}
+	}
+	return matches
+}
+
+func (e *strengthEstimator) yearSpace(year int) float64 {
+	return float64(max(abs(year-e.thisYear), minYearSpace))
+}
+
+// dateYear returns the year of a date written as 6 or 8 digits, when they are
+// one.
+func dateYear(digits string) (int, bool) {
+	yearLength := len(digits) - 4
+	// The year comes either first or last, with the day and the month in
+	// either order
+	splits := [][2]string{
+		{digits[:yearLength], digits[yearLength:]},
+		{digits[4:], digits[:4]},
+	}
+	for _, split := range splits {
+		year := atoi(split[0])
+		if yearLength == 2 {
+			if year < 50 {
+				year += 2000
+			} else {
+				year += 1900
+			}
+		} else if year < 1900 || year > 2099 {
+			continue
+		}
+		first, second := atoi(split[1][:2]), atoi(split[1][2:])
+		if validDayMonth(first, second) || validDayMonth(second, first) {
+			return year, true
+		}
+	}
+	return 0, false
+}
+
+func validDayMonth(day int, month int) bool {
+	return day >= 1 && day <= 31 && month >= 1 && month <= 12
+}
+
+func bruteforceGuesses(length int) float64 {
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+	if length == 1 {
+		return max(guesses, bruteforceCardinality+1)
+	}
+	return max(guesses, minSubmatchGuesses+1)
+}
+
+func factorial(n int) float64 {
+	result := 1.0
+	for i := 2; i <= n; i++ {
+		result *= float64(i)
+	}
+	return result
+}
+
+func binomial(n int, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+func reverse(word string) string {
+	runes := []rune(word)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, other := range runes {
+		if other == r {
+			return true
+		}
+	}
+	return false
+}
+
+func allDigits(runes []rune) bool {
+	for _, r := range runes {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(digits string) int {
+	value := 0
+	for _, r := range digits {
+		value = value*10 + int(r-'0')
+	}
+	return value
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
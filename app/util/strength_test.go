@@ -0,0 +1,57 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPasswordStrength(t *testing.T) {
+	for _, testCase := range []struct {
+		password      string
+		expectedScore int
+	}{
+		{"", 0},
+		{"password", 0},
+		{"Password1", 0},
+		{"qwerty123", 0},
+		{"abcdefgh", 0},
+		{"aaaaaaaaaaaa", 0},
+		{"drowssap", 0},
+		{"1l0v3y0u", 0},
+		{"P@ssw0rd!", 1},
+		{"19901231", 1},
+		{"hunter2hunter2", 1},
+		{"correct horse battery staple", 4},
+		{"r9#Lk2!vQz@8", 4},
+	} {
+		if score := PasswordStrength(testCase.password); score != testCase.expectedScore {
+			t.Errorf("Expected %q to score %d, got %d", testCase.password, testCase.expectedScore, score)
+		}
+	}
+}
+
+func TestPasswordStrengthGuessesUserInputsFirst(t *testing.T) {
+	password := "wondersmith"
+	if score := PasswordStrength(password); score < 3 {
+		t.Fatalf("Expected %q to be strong on its own, got %d", password, score)
+	}
+	if score := PasswordStrength(password, "alice.smith@example.com", "Alice Wonder"); score > 1 {
+		t.Errorf("Expected %q to be weak for Alice Wonder Smith, got %d", password, score)
+	}
+}
+
+func TestPasswordGuessesGrowWithLength(t *testing.T) {
+	previous := 0.0
+	for length := 1; length <= 12; length++ {
+		guesses := PasswordGuesses("kx9#vq2!mz7@"[:length])
+		if guesses <= previous {
+			t.Errorf("Expected more guesses for %d characters than %v, got %v", length, previous, guesses)
+		}
+		previous = guesses
+	}
+
+	// Only the first characters of long passwords are looked at
+	if guesses := PasswordGuesses(strings.Repeat("a", 10000)); guesses != PasswordGuesses(strings.Repeat("a", maxStrengthRunes)) {
+		t.Errorf("Expected long passwords to be cut, got %v", guesses)
+	}
+}
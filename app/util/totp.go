@@ -0,0 +1,99 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod is how long a TOTP code lasts, as assumed by authenticator
+	// apps.
+	totpPeriod = 30 * time.Second
+	// totpDigits is how many digits TOTP codes have.
+	totpDigits = 6
+	// totpSkew is how many periods before and after the current one a code is
+	// accepted from, to make up for clock drift and slow typing.
+	totpSkew = 1
+	// totpSecretBytes is the size of TOTP secrets, as recommended by RFC 4226
+	// for HMAC-SHA1.
+	totpSecretBytes = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random TOTP secret, base32 encoded as
+// authenticator apps expect it.
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPStep returns the RFC 6238 time step of the given time.
+func TOTPStep(at time.Time) int64 {
+	return at.Unix() / int64(totpPeriod/time.Second)
+}
+
+// TOTPCode returns the code of a base32 encoded secret for a time step.
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// VerifyTOTP checks a code against a base32 encoded secret at the given time,
+// and returns the time step it matched, so that callers can refuse to accept
+// the same code twice.
+func VerifyTOTP(secret string, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(at)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPProvisioningURI returns the otpauth:// URI authenticator apps enrol a
+// secret from, usually shown as a QR code.
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod/time.Second)))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + query.Encode()
+}
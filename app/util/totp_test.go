@@ -0,0 +1,80 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 secret of the RFC 6238 test vectors, "12345678901234567890".
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	for _, testCase := range []struct {
+		unix         int64
+		expectedCode string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(testCase.unix, 0)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if code != testCase.expectedCode {
+			t.Errorf("Expected code %s at %d, got %s", testCase.expectedCode, testCase.unix, code)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	at := time.Unix(1111111109, 0)
+
+	step, ok := VerifyTOTP(rfcSecret, "081804", at)
+	if !ok || step != TOTPStep(at) {
+		t.Errorf("Expected the current code to match step %d, got %d, %v", TOTPStep(at), step, ok)
+	}
+	if _, ok := VerifyTOTP(rfcSecret, "081804", at.Add(30*time.Second)); !ok {
+		t.Errorf("Expected the previous code to still be accepted")
+	}
+	if _, ok := VerifyTOTP(rfcSecret, "081804", at.Add(90*time.Second)); ok {
+		t.Errorf("Expected an old code to be rejected")
+	}
+	if _, ok := VerifyTOTP(rfcSecret, "000000", at); ok {
+		t.Errorf("Expected a wrong code to be rejected")
+	}
+	if _, ok := VerifyTOTP(rfcSecret, "0818", at); ok {
+		t.Errorf("Expected a short code to be rejected")
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("Unexpected error generating a secret: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("Expected a 32 characters secret, got %d", len(secret))
+	}
+
+	code, err := TOTPCode(secret, TOTPStep(time.Now()))
+	if err != nil || len(code) != 6 {
+		t.Errorf("Expected a 6 digits code for the secret, got '%s', %v", code, err)
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Movie Collection", "test@test.com", rfcSecret)
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Movie%20Collection:test@test.com?") {
+		t.Errorf("Expected the issuer and account as label, got %s", uri)
+	}
+	for _, parameter := range []string{"secret=" + rfcSecret, "issuer=Movie+Collection", "digits=6", "period=30"} {
+		if !strings.Contains(uri, parameter) {
+			t.Errorf("Expected %s in %s", parameter, uri)
+		}
+	}
+}
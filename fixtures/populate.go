@@ -1,6 +1,9 @@
 package main
 
 import (
+	"strings"
+	"time"
+
 	"github.com/Acova/movie-collection/app/adapter/postgresadapter"
 	"github.com/Acova/movie-collection/app/util"
 	"github.com/joho/godotenv"
@@ -26,16 +29,19 @@ func main() {
 
 	users := []postgresadapter.PostgresUser{
 		{
-			ID:       1,
-			Email:    "test1@test.es",
-			Name:     "test1",
-			Password: testPassword,
+			ID:              1,
+			Email:           "test1@test.es",
+			Name:            "test1",
+			Password:        testPassword,
+			Role:            "admin",
+			EmailVerifiedAt: time.Now(),
 		},
 		{
-			ID:       2,
-			Email:    "test2@test.es",
-			Name:     "test2",
-			Password: testPassword,
+			ID:              2,
+			Email:           "test2@test.es",
+			Name:            "test2",
+			Password:        testPassword,
+			EmailVerifiedAt: time.Now(),
 		},
 	}
 
@@ -46,13 +52,23 @@ func main() {
 		}
 	}
 
+	genres := map[string]postgresadapter.PostgresGenre{}
+	for _, name := range []string{"Action", "Adventure", "Crime", "Drama", "Sci-Fi"} {
+		genre := postgresadapter.PostgresGenre{Name: name}
+		result := postgresDbConnection.DB.Create(&genre)
+		if result.Error != nil {
+			panic("Error creating genre: " + result.Error.Error())
+		}
+		genres[name] = genre
+	}
+
 	movies := []postgresadapter.PostgresMovie{
 		{
 			Title:       "The Godfather",
 			Director:    "Francis Ford Coppola",
 			ReleaseYear: 1972,
 			Cast:        "Marlon Brando, Al Pacino, James Caan",
-			Genre:       "Crime, Drama",
+			Genres:      []postgresadapter.PostgresGenre{genres["Crime"], genres["Drama"]},
 			Synopsis:    "The aging patriarch of an organized crime dynasty transfers control of his clandestine empire to his reluctant son.",
 			Rating:      9.2,
 			Duration:    175,
@@ -64,7 +80,7 @@ func main() {
 			Director:    "The Wachowskis",
 			ReleaseYear: 1999,
 			Cast:        "Keanu Reeves, Laurence Fishburne, Carrie-Anne Moss",
-			Genre:       "Action, Sci-Fi",
+			Genres:      []postgresadapter.PostgresGenre{genres["Action"], genres["Sci-Fi"]},
 			Synopsis:    "A computer hacker learns from mysterious rebels about the true nature of his reality and his role in the war against its controllers.",
 			Rating:      8.7,
 			Duration:    136,
@@ -76,7 +92,7 @@ func main() {
 			Director:    "Joss Whedon",
 			ReleaseYear: 2012,
 			Cast:        "Robert Downey Jr., Chris Evans, Scarlett Johansson",
-			Genre:       "Action, Adventure, Sci-Fi",
+			Genres:      []postgresadapter.PostgresGenre{genres["Action"], genres["Adventure"], genres["Sci-Fi"]},
 			Synopsis:    "The Avengers assemble to save the world from Loki and his army of aliens.",
 			Rating:      8.0,
 			Duration:    143,
@@ -88,7 +104,7 @@ func main() {
 			Director:    "Peter Jackson",
 			ReleaseYear: 2003,
 			Cast:        "Elijah Wood, Viggo Mortensen, Ian McKellen",
-			Genre:       "Action, Adventure, Drama",
+			Genres:      []postgresadapter.PostgresGenre{genres["Action"], genres["Adventure"], genres["Drama"]},
 			Synopsis:    "The final battle for Middle-earth begins.",
 			Rating:      8.9,
 			Duration:    201,
@@ -97,10 +113,36 @@ func main() {
 		},
 	}
 
+	people := map[string]postgresadapter.PostgresPerson{}
 	for _, movie := range movies {
 		result := postgresDbConnection.DB.Create(&movie)
 		if result.Error != nil {
 			panic("Error creating movie: " + result.Error.Error())
 		}
+
+		credits := map[string]string{"director": movie.Director, "actor": movie.Cast}
+		for role, names := range credits {
+			for billingOrder, name := range strings.Split(names, ",") {
+				name = strings.TrimSpace(name)
+				person, ok := people[name]
+				if !ok {
+					person = postgresadapter.PostgresPerson{Name: name}
+					if result := postgresDbConnection.DB.Create(&person); result.Error != nil {
+						panic("Error creating person: " + result.Error.Error())
+					}
+					people[name] = person
+				}
+
+				credit := postgresadapter.PostgresMovieCredit{
+					MovieID:      movie.ID,
+					PersonID:     person.ID,
+					Role:         role,
+					BillingOrder: billingOrder,
+				}
+				if result := postgresDbConnection.DB.Omit("Movie", "Person").Create(&credit); result.Error != nil {
+					panic("Error creating credit: " + result.Error.Error())
+				}
+			}
+		}
 	}
 }
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Acova/movie-collection/app/adapter/breachadapter"
 	"github.com/Acova/movie-collection/app/adapter/httpadapter"
+	"github.com/Acova/movie-collection/app/adapter/mailadapter"
+	"github.com/Acova/movie-collection/app/adapter/oidcadapter"
 	"github.com/Acova/movie-collection/app/adapter/postgresadapter"
+	"github.com/Acova/movie-collection/app/domain"
 	"github.com/Acova/movie-collection/app/service"
+	"github.com/Acova/movie-collection/app/util"
 	"github.com/joho/godotenv"
 )
 
@@ -21,6 +30,19 @@ func main() {
 		panic("Error loading .env file")
 	}
 
+	// Choose how passwords are hashed
+	passwordHasher, err := util.NewPasswordHasherFromEnv()
+	if err != nil {
+		panic("Error creating password hasher: " + err.Error())
+	}
+	util.SetPasswordHasher(passwordHasher)
+
+	// Choose what passwords users can choose
+	passwordPolicy, err := passwordPolicyFromEnv(passwordHasher.MaxPasswordBytes())
+	if err != nil {
+		panic("Error reading the password policy: " + err.Error())
+	}
+
 	// Initialize the PostgreSQL database adapter
 	dbConnection, err := postgresadapter.NewPostgresDBConnection()
 	if err != nil {
@@ -38,14 +60,179 @@ func main() {
 		panic("Error creating movie repository: " + err.Error())
 	}
 
+	postgresFavouritesRepository, err := postgresadapter.NewPostgresFavouritesRepository(dbConnection)
+	if err != nil {
+		panic("Error creating favourites repository: " + err.Error())
+	}
+
+	postgresGenreRepository, err := postgresadapter.NewPostgresGenreRepository(dbConnection)
+	if err != nil {
+		panic("Error creating genre repository: " + err.Error())
+	}
+
+	postgresPersonRepository, err := postgresadapter.NewPostgresPersonRepository(dbConnection)
+	if err != nil {
+		panic("Error creating person repository: " + err.Error())
+	}
+
+	postgresReviewRepository, err := postgresadapter.NewPostgresReviewRepository(dbConnection)
+	if err != nil {
+		panic("Error creating review repository: " + err.Error())
+	}
+
+	postgresViewingRepository, err := postgresadapter.NewPostgresViewingRepository(dbConnection)
+	if err != nil {
+		panic("Error creating viewing repository: " + err.Error())
+	}
+
+	postgresWatchlistRepository, err := postgresadapter.NewPostgresWatchlistRepository(dbConnection)
+	if err != nil {
+		panic("Error creating watchlist repository: " + err.Error())
+	}
+
+	postgresMovieListRepository, err := postgresadapter.NewPostgresMovieListRepository(dbConnection)
+	if err != nil {
+		panic("Error creating movie list repository: " + err.Error())
+	}
+
+	postgresPasswordResetRepository, err := postgresadapter.NewPostgresPasswordResetRepository(dbConnection)
+	if err != nil {
+		panic("Error creating password reset repository: " + err.Error())
+	}
+
+	postgresEmailVerificationRepository, err := postgresadapter.NewPostgresEmailVerificationRepository(dbConnection)
+	if err != nil {
+		panic("Error creating email verification repository: " + err.Error())
+	}
+
+	postgresTokenRevocationRepository, err := postgresadapter.NewPostgresTokenRevocationRepository(dbConnection)
+	if err != nil {
+		panic("Error creating token revocation repository: " + err.Error())
+	}
+
+	postgresSessionRepository, err := postgresadapter.NewPostgresSessionRepository(dbConnection)
+	if err != nil {
+		panic("Error creating session repository: " + err.Error())
+	}
+
+	postgresAccessTokenRepository, err := postgresadapter.NewPostgresAccessTokenRepository(dbConnection)
+	if err != nil {
+		panic("Error creating personal access token repository: " + err.Error())
+	}
+
+	postgresTwoFactorRepository, err := postgresadapter.NewPostgresTwoFactorRepository(dbConnection)
+	if err != nil {
+		panic("Error creating two-factor authentication repository: " + err.Error())
+	}
+
+	postgresLoginThrottleRepository, err := postgresadapter.NewPostgresLoginThrottleRepository(dbConnection)
+	if err != nil {
+		panic("Error creating login throttle repository: " + err.Error())
+	}
+
+	postgresOIDCRepository, err := postgresadapter.NewPostgresOIDCRepository(dbConnection)
+	if err != nil {
+		panic("Error creating OpenID Connect repository: " + err.Error())
+	}
+
+	// Initialize the mailer
+	mailer, err := mailadapter.NewMailerFromEnv()
+	if err != nil {
+		panic("Error creating mailer: " + err.Error())
+	}
+
+	// Initialize the OpenID Connect providers
+	oidcProviders, err := oidcadapter.NewProvidersFromEnv()
+	if err != nil {
+		panic("Error creating OpenID Connect providers: " + err.Error())
+	}
+
+	// Initialize the breached passwords dataset
+	breachedPasswords, err := breachadapter.NewBreachedPasswordsFromEnv()
+	if err != nil {
+		panic("Error opening the breached passwords dataset: " + err.Error())
+	}
+
 	// Initialize the controllers
-	userService := service.NewUserService(postgresUserRepository)
+	passwordPolicyService := service.NewPasswordPolicyService(passwordPolicy, breachedPasswords)
+	userService := service.NewUserService(postgresUserRepository, passwordPolicyService)
 	movieService := service.NewMovieService(postgresMovieRepository)
+	favouritesService := service.NewFavouritesService(postgresFavouritesRepository)
+	genreService := service.NewGenreService(postgresGenreRepository)
+	personService := service.NewPersonService(postgresPersonRepository)
+	reviewService := service.NewReviewService(postgresReviewRepository)
+	viewingService := service.NewViewingService(postgresViewingRepository, postgresWatchlistRepository)
+	watchlistService := service.NewWatchlistService(postgresWatchlistRepository)
+	movieListService := service.NewMovieListService(postgresMovieListRepository)
+	passwordResetService := service.NewPasswordResetService(postgresPasswordResetRepository, postgresUserRepository, passwordPolicyService, mailer, os.Getenv("PASSWORD_RESET_URL"))
+	tokenRevocationService := service.NewTokenRevocationService(postgresTokenRevocationRepository)
+	sessionService := service.NewSessionService(postgresSessionRepository, postgresUserRepository)
+	accessTokenService := service.NewAccessTokenService(postgresAccessTokenRepository, postgresUserRepository)
+	twoFactorService := service.NewTwoFactorService(postgresTwoFactorRepository, postgresUserRepository, os.Getenv("TOTP_ISSUER"))
+	loginThrottleService := service.NewLoginThrottleService(postgresLoginThrottleRepository)
+	oidcService := service.NewOIDCService(postgresOIDCRepository, postgresUserRepository, postgresAccessTokenRepository, postgresTwoFactorRepository, postgresSessionRepository, oidcProviders)
+	emailVerificationService := service.NewEmailVerificationService(postgresEmailVerificationRepository, postgresUserRepository, mailer, os.Getenv("EMAIL_VERIFICATION_URL"))
 
 	// Initialize the HTTP adapter
 	services := &httpadapter.HttpServices{
-		UserService:  userService,
-		MovieService: movieService,
+		UserService:            userService,
+		MovieService:           movieService,
+		FavouritesService:      favouritesService,
+		GenreService:           genreService,
+		PersonService:          personService,
+		ReviewService:          reviewService,
+		ViewingService:         viewingService,
+		WatchlistService:       watchlistService,
+		MovieListService:       movieListService,
+		PasswordService:        passwordResetService,
+		VerificationService:    emailVerificationService,
+		TokenRevocationService: tokenRevocationService,
+		SessionService:         sessionService,
+		AccessTokenService:     accessTokenService,
+		TwoFactorService:       twoFactorService,
+		LoginThrottleService:   loginThrottleService,
+		OIDCService:            oidcService,
 	}
 	httpadapter.StartHttpServer(services)
 }
+
+// passwordPolicyFromEnv reads the password policy from PASSWORD_MIN_LENGTH,
+// PASSWORD_MAX_LENGTH and PASSWORD_MIN_STRENGTH, keeping the default for those
+// not set. When the password hasher only takes up to maxBytes bytes, longer
+// passwords are rejected, and so are policies asking for them.
+func passwordPolicyFromEnv(maxBytes int) (domain.PasswordPolicy, error) {
+	policy := domain.DefaultPasswordPolicy()
+	settings := []struct {
+		name     string
+		value    *int
+		minValue int
+		maxValue int
+	}{
+		{"PASSWORD_MIN_LENGTH", &policy.MinLength, 1, 1024},
+		{"PASSWORD_MAX_LENGTH", &policy.MaxLength, 1, 1024},
+		{"PASSWORD_MIN_STRENGTH", &policy.MinStrength, 0, 4},
+	}
+	for _, setting := range settings {
+		value := os.Getenv(setting.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < setting.minValue || parsed > setting.maxValue {
+			return policy, fmt.Errorf("invalid %s %q, must be between %d and %d", setting.name, value, setting.minValue, setting.maxValue)
+		}
+		*setting.value = parsed
+	}
+
+	if maxBytes > 0 {
+		if policy.MinLength > maxBytes {
+			return policy, fmt.Errorf("PASSWORD_MIN_LENGTH %d is over the %d bytes the password hasher can hash", policy.MinLength, maxBytes)
+		}
+		policy.MaxBytes = maxBytes
+		policy.MaxLength = min(policy.MaxLength, maxBytes)
+	}
+	if policy.MaxLength < policy.MinLength {
+		return policy, fmt.Errorf("PASSWORD_MAX_LENGTH %d is below PASSWORD_MIN_LENGTH %d", policy.MaxLength, policy.MinLength)
+	}
+	return policy, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/Acova/movie-collection/app/adapter/postgresadapter"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -17,5 +18,180 @@ func main() {
 		panic("Error connecting to the database: " + err.Error())
 	}
 
-	postgresDbConnection.DB.AutoMigrate(&postgresadapter.PostgresUser{}, &postgresadapter.PostgresMovie{})
+	// Credits are imported from the free-text director and cast columns only
+	// when their table is first created.
+	importCredits := !postgresDbConnection.DB.Migrator().HasTable(&postgresadapter.PostgresMovieCredit{})
+
+	// Users registered before emails were verified are trusted with theirs.
+	verifyExistingUsers := !postgresDbConnection.DB.Migrator().HasColumn(&postgresadapter.PostgresUser{}, "email_verified_at")
+
+	postgresDbConnection.DB.AutoMigrate(
+		&postgresadapter.PostgresUser{},
+		&postgresadapter.PostgresGenre{},
+		&postgresadapter.PostgresMovie{},
+		&postgresadapter.PostgresFavourite{},
+		&postgresadapter.PostgresPerson{},
+		&postgresadapter.PostgresMovieCredit{},
+		&postgresadapter.PostgresReview{},
+		&postgresadapter.PostgresViewing{},
+		&postgresadapter.PostgresWatchlistEntry{},
+		&postgresadapter.PostgresMovieList{},
+		&postgresadapter.PostgresMovieListEntry{},
+		&postgresadapter.PostgresPasswordResetToken{},
+		&postgresadapter.PostgresEmailVerificationToken{},
+		&postgresadapter.PostgresRevokedToken{},
+		&postgresadapter.PostgresSession{},
+		&postgresadapter.PostgresRefreshToken{},
+		&postgresadapter.PostgresAccessToken{},
+		&postgresadapter.PostgresTwoFactor{},
+		&postgresadapter.PostgresRecoveryCode{},
+		&postgresadapter.PostgresTwoFactorChallenge{},
+		&postgresadapter.PostgresLoginThrottle{},
+		&postgresadapter.PostgresLoginFailure{},
+		&postgresadapter.PostgresOIDCLoginState{},
+		&postgresadapter.PostgresOIDCIdentity{},
+	)
+
+	if verifyExistingUsers {
+		if err := execStatements(postgresDbConnection.DB, verifyExistingUsersStatements); err != nil {
+			panic("Error verifying existing users: " + err.Error())
+		}
+	}
+
+	if err := execStatements(postgresDbConnection.DB, genreStatements); err != nil {
+		panic("Error setting up genres: " + err.Error())
+	}
+
+	if err := splitMovieGenres(postgresDbConnection.DB); err != nil {
+		panic("Error splitting movie genres: " + err.Error())
+	}
+
+	if importCredits {
+		if err := importMovieCredits(postgresDbConnection.DB); err != nil {
+			panic("Error importing movie credits: " + err.Error())
+		}
+	}
+
+	if err := execStatements(postgresDbConnection.DB, movieSearchStatements); err != nil {
+		panic("Error setting up movie search: " + err.Error())
+	}
+
+	if err := execStatements(postgresDbConnection.DB, movieFuzzySearchStatements); err != nil {
+		panic("Error setting up fuzzy movie search: " + err.Error())
+	}
+}
+
+func execStatements(db *gorm.DB, statements []string) error {
+	for _, statement := range statements {
+		if result := db.Exec(statement); result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// genreStatements make genre names unique regardless of case, so that
+// "sci-fi" and "Sci-Fi" cannot coexist.
+var genreStatements = []string{
+	`CREATE UNIQUE INDEX IF NOT EXISTS genre_name_idx ON genre (LOWER(name))`,
+}
+
+// verifyExistingUsersStatements mark the emails of the users registered before
+// verification existed as verified on their registration date.
+var verifyExistingUsersStatements = []string{
+	`UPDATE "user" SET email_verified_at = created_at WHERE email_verified_at IS NULL`,
+}
+
+// splitMovieGenres moves the comma-separated genres of the old movie.genre
+// column into the genre and movie_genre tables, and then drops the column. It
+// does nothing once the column is gone.
+func splitMovieGenres(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&postgresadapter.PostgresMovie{}, "genre") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		return execStatements(tx, []string{
+			`INSERT INTO genre (name, created_at)
+				SELECT DISTINCT ON (LOWER(TRIM(genre_name))) TRIM(genre_name), NOW()
+				FROM movie
+				CROSS JOIN LATERAL unnest(string_to_array(movie.genre, ',')) AS genre_name
+				WHERE TRIM(genre_name) <> ''
+				ON CONFLICT DO NOTHING`,
+			`INSERT INTO movie_genre (movie_id, genre_id)
+				SELECT DISTINCT movie.id, genre.id
+				FROM movie
+				CROSS JOIN LATERAL unnest(string_to_array(movie.genre, ',')) AS genre_name
+				JOIN genre ON LOWER(genre.name) = LOWER(TRIM(genre_name))
+				ON CONFLICT DO NOTHING`,
+			`ALTER TABLE movie DROP COLUMN genre`,
+		})
+	})
+}
+
+// importMovieCredits creates a person for every distinct name found in the
+// comma-separated director and cast columns of the existing movies, and
+// credits them as directors and actors in the order they were listed.
+func importMovieCredits(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return execStatements(tx, []string{
+			`INSERT INTO person (name, created_at, updated_at)
+				SELECT DISTINCT ON (LOWER(person_name)) person_name, NOW(), NOW()
+				FROM (
+					SELECT TRIM(director_name) AS person_name
+					FROM movie
+					CROSS JOIN LATERAL unnest(string_to_array(movie.director, ',')) AS director_name
+					WHERE movie.deleted_at IS NULL
+					UNION ALL
+					SELECT TRIM(cast_name) AS person_name
+					FROM movie
+					CROSS JOIN LATERAL unnest(string_to_array(movie."cast", ',')) AS cast_name
+					WHERE movie.deleted_at IS NULL
+				) AS names
+				WHERE person_name <> ''`,
+			`INSERT INTO movie_credit (movie_id, person_id, role, billing_order, created_at)
+				SELECT movie.id, person.id, 'director', names.position - 1, NOW()
+				FROM movie
+				CROSS JOIN LATERAL unnest(string_to_array(movie.director, ',')) WITH ORDINALITY AS names(person_name, position)
+				JOIN person ON LOWER(person.name) = LOWER(TRIM(names.person_name))
+				WHERE movie.deleted_at IS NULL`,
+			`INSERT INTO movie_credit (movie_id, person_id, role, billing_order, created_at)
+				SELECT movie.id, person.id, 'actor', names.position - 1, NOW()
+				FROM movie
+				CROSS JOIN LATERAL unnest(string_to_array(movie."cast", ',')) WITH ORDINALITY AS names(person_name, position)
+				JOIN person ON LOWER(person.name) = LOWER(TRIM(names.person_name))
+				WHERE movie.deleted_at IS NULL`,
+		})
+	})
+}
+
+// movieSearchStatements maintain the full-text search vector over the title,
+// director, cast and synopsis of every movie. The trigger keeps it up to date
+// on every write, and the last statement backfills the existing rows.
+var movieSearchStatements = []string{
+	`ALTER TABLE movie ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`CREATE OR REPLACE FUNCTION movie_search_vector_update() RETURNS trigger AS $$
+	BEGIN
+		NEW.search_vector :=
+			setweight(to_tsvector('english', COALESCE(NEW.title, '')), 'A') ||
+			setweight(to_tsvector('english', COALESCE(NEW.director, '')), 'B') ||
+			setweight(to_tsvector('english', COALESCE(NEW."cast", '')), 'B') ||
+			setweight(to_tsvector('english', COALESCE(NEW.synopsis, '')), 'C');
+		RETURN NEW;
+	END
+	$$ LANGUAGE plpgsql`,
+	`DROP TRIGGER IF EXISTS movie_search_vector_trigger ON movie`,
+	`CREATE TRIGGER movie_search_vector_trigger
+		BEFORE INSERT OR UPDATE OF title, director, "cast", synopsis ON movie
+		FOR EACH ROW EXECUTE FUNCTION movie_search_vector_update()`,
+	`CREATE INDEX IF NOT EXISTS movie_search_vector_idx ON movie USING GIN (search_vector)`,
+	`UPDATE movie SET title = title WHERE search_vector IS NULL`,
+}
+
+// movieFuzzySearchStatements enable trigram similarity, used for typo-tolerant
+// title and director matching and for title autocompletion.
+var movieFuzzySearchStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`CREATE INDEX IF NOT EXISTS movie_title_trgm_idx ON movie USING GIN (title gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS movie_director_trgm_idx ON movie USING GIN (director gin_trgm_ops)`,
 }